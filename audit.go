@@ -0,0 +1,35 @@
+package library
+
+import "context"
+
+type auditKey int
+
+const (
+	actorKey auditKey = iota
+	requestIDKey
+)
+
+// WithActor returns a context for changes made by actor, who is recorded in
+// the audit log.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// Actor returns who is making changes in ctx, or "" if nobody was set.
+func Actor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey).(string)
+	return actor
+}
+
+// WithRequestID returns a context for changes made while handling the request
+// id, which is recorded in the audit log.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID returns the id of the request being handled in ctx, or "" if
+// there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
@@ -0,0 +1,60 @@
+// Package bookio reads and writes books in the file formats the catalog is
+// exchanged in.
+package bookio
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/slcjordan/library"
+)
+
+// A Format is a file format for a list of books.
+type Format string
+
+const (
+	CSV     Format = "csv"
+	NDJSON  Format = "ndjson"
+	Parquet Format = "parquet"
+)
+
+// NewReader returns a reader for books in the given format. Parquet can only
+// be written.
+func NewReader(format Format, r io.Reader) (library.BookReader, error) {
+	switch format {
+	case CSV:
+		return NewCSVReader(r), nil
+	case NDJSON:
+		return NewNDJSONReader(r), nil
+	}
+	return nil, &library.Error{
+		Type:   library.BadInput,
+		Actual: fmt.Errorf("unknown format %q", format),
+		Desc:   "while choosing a book reader",
+	}
+}
+
+// NewWriter returns a writer for books in the given format.
+func NewWriter(format Format, w io.Writer) (library.BookWriter, error) {
+	switch format {
+	case CSV:
+		return NewCSVWriter(w), nil
+	case NDJSON:
+		return NewNDJSONWriter(w), nil
+	case Parquet:
+		return NewParquetWriter(w), nil
+	}
+	return nil, &library.Error{
+		Type:   library.BadInput,
+		Actual: fmt.Errorf("unknown format %q", format),
+		Desc:   "while choosing a book writer",
+	}
+}
+
+func parseRow(isbn string, title string) (library.Book, error) {
+	parsed, err := library.ParseISBN(isbn)
+	if err != nil {
+		return library.Book{}, err
+	}
+	return library.Book{ISBN: parsed, Title: title}, nil
+}
@@ -0,0 +1,199 @@
+package bookio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+
+	"github.com/slcjordan/library"
+)
+
+// readAll returns the books of r in order and the type of the error of each
+// bad row.
+func readAll(t *testing.T, r library.BookReader) ([]string, []int) {
+	var rows []string
+	var bad []int
+	for row := 1; ; row++ {
+		book, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, bad
+		}
+		var libErr *library.Error
+		if errors.As(err, &libErr) && libErr.Type == library.BadInput {
+			bad = append(bad, row)
+			continue
+		}
+		if err != nil {
+			t.Fatalf("while reading row %d: %s", row, err)
+		}
+		rows = append(rows, book.ISBN.String()+" "+book.Title)
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	input := "Title,ISBN,Notes\n" +
+		"\"Moby Dick, or The Whale\",978-0-306-40615-7,\n" +
+		"Bad Check Digit,978-0-306-40615-8,\n" +
+		"Short Row\n" +
+		"Ten Digits,1-4028-9462-7,x\n"
+	rows, bad := readAll(t, NewCSVReader(strings.NewReader(input)))
+	expected := []string{
+		"978-0-306-40615-7 Moby Dick, or The Whale",
+		"978-1-4028-9462-6 Ten Digits",
+	}
+	if strings.Join(rows, "|") != strings.Join(expected, "|") {
+		t.Fatalf("expected %q but got %q", expected, rows)
+	}
+	if len(bad) != 2 || bad[0] != 2 || bad[1] != 3 {
+		t.Fatalf("expected rows 2 and 3 to be bad but got %v", bad)
+	}
+}
+
+func TestCSVReaderMissingColumn(t *testing.T) {
+	_, err := NewCSVReader(strings.NewReader("isbn,name\n")).Read()
+	var libErr *library.Error
+	if err == nil || errors.Is(err, io.EOF) || errors.As(err, &libErr) {
+		t.Fatalf("expected a header without a title to end the import but got %v", err)
+	}
+}
+
+func TestNDJSONReader(t *testing.T) {
+	input := `{"isbn": "978-0-306-40615-7", "title": "First"}` + "\n" +
+		"\n" +
+		`{"isbn": "978-0-306-40615-7", "title": ` + "\n" +
+		`{"isbn": "0-306-40615-3", "title": "Bad Check Digit"}` + "\n" +
+		`{"isbn": "9781402894626", "title": "Last"}`
+	rows, bad := readAll(t, NewNDJSONReader(strings.NewReader(input)))
+	expected := []string{
+		"978-0-306-40615-7 First",
+		"978-1-4028-9462-6 Last",
+	}
+	if strings.Join(rows, "|") != strings.Join(expected, "|") {
+		t.Fatalf("expected %q but got %q", expected, rows)
+	}
+	if len(bad) != 2 || bad[0] != 2 || bad[1] != 3 {
+		t.Fatalf("expected rows 2 and 3 to be bad but got %v", bad)
+	}
+}
+
+func TestWriteAndReadBack(t *testing.T) {
+	isbn, err := library.ParseISBN("978-0-306-40615-7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	books := []library.Book{
+		{ISBN: isbn, Title: `Quotes "and", commas`, CreatedAt: created, UpdatedAt: created},
+		{ISBN: 9781402894626, Title: "Second", CreatedAt: created, UpdatedAt: created.Add(time.Hour)},
+	}
+	for _, format := range []Format{CSV, NDJSON} {
+		var buf bytes.Buffer
+		writer, err := NewWriter(format, &buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, b := range books {
+			err = writer.Write(b)
+			if err != nil {
+				t.Fatalf("%s: while writing: %s", format, err)
+			}
+		}
+		err = writer.Close()
+		if err != nil {
+			t.Fatalf("%s: while closing: %s", format, err)
+		}
+		reader, err := NewReader(format, &buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows, bad := readAll(t, reader)
+		expected := []string{
+			"978-0-306-40615-7 " + books[0].Title,
+			"978-1-4028-9462-6 Second",
+		}
+		if strings.Join(rows, "|") != strings.Join(expected, "|") || len(bad) != 0 {
+			t.Fatalf("%s: expected %q but got %q and bad rows %v", format, expected, rows, bad)
+		}
+	}
+}
+
+// parquetBook is a row of a ParquetWriter's file as an independent reader
+// sees it.
+type parquetBook struct {
+	ISBN      string    `parquet:"isbn"`
+	Title     string    `parquet:"title"`
+	CreatedAt time.Time `parquet:"created_at,timestamp(microsecond)"`
+	UpdatedAt time.Time `parquet:"updated_at,timestamp(microsecond)"`
+}
+
+func TestParquetWriter(t *testing.T) {
+	created := time.Date(2026, 10, 18, 9, 30, 0, 123456000, time.UTC)
+	for _, n := range []int{0, 1, parquetRowGroupSize + 1} {
+		var buf bytes.Buffer
+		writer := NewParquetWriter(&buf)
+		var expected []parquetBook
+		for i := 0; i < n; i++ {
+			book := library.Book{
+				ISBN:      9780306406157,
+				Title:     fmt.Sprintf("Title %d", i),
+				CreatedAt: created.Add(time.Duration(i) * time.Second),
+				UpdatedAt: created.Add(time.Duration(i) * time.Minute),
+			}
+			err := writer.Write(book)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected = append(expected, parquetBook{
+				ISBN:      book.ISBN.String(),
+				Title:     book.Title,
+				CreatedAt: book.CreatedAt,
+				UpdatedAt: book.UpdatedAt,
+			})
+		}
+		err := writer.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		file, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatalf("%d rows: while opening the file: %s", n, err)
+		}
+		if len(file.RowGroups()) != (n+parquetRowGroupSize-1)/parquetRowGroupSize || file.NumRows() != int64(n) {
+			t.Fatalf("%d rows: unexpected %d row groups of %d rows", n, len(file.RowGroups()), file.NumRows())
+		}
+		for _, field := range file.Schema().Fields() {
+			logical := field.Type().LogicalType()
+			switch {
+			case field.Name() == "isbn" || field.Name() == "title":
+				if logical == nil || logical.UTF8 == nil {
+					t.Fatalf("%d rows: expected %s to be a string but got %v", n, field.Name(), logical)
+				}
+			case logical == nil || logical.Timestamp == nil || logical.Timestamp.Unit.Micros == nil || !logical.Timestamp.IsAdjustedToUTC:
+				t.Fatalf("%d rows: expected %s to be a UTC timestamp in microseconds but got %v", n, field.Name(), logical)
+			}
+		}
+		rows := make([]parquetBook, n)
+		count, err := parquet.NewGenericReader[parquetBook](file).Read(rows)
+		if err != nil && !errors.Is(err, io.EOF) {
+			t.Fatalf("%d rows: while reading: %s", n, err)
+		}
+		if count != n {
+			t.Fatalf("%d rows: read %d books", n, count)
+		}
+		for i, row := range rows {
+			row.CreatedAt = row.CreatedAt.UTC()
+			row.UpdatedAt = row.UpdatedAt.UTC()
+			if !reflect.DeepEqual(row, expected[i]) {
+				t.Fatalf("%d rows: expected %+v but read %+v", n, expected[i], row)
+			}
+		}
+	}
+}
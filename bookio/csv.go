@@ -0,0 +1,136 @@
+package bookio
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/slcjordan/library"
+)
+
+// A CSVReader reads books from CSV with a header row. The header must name an
+// isbn and a title column, in any order. Other columns are ignored.
+type CSVReader struct {
+	reader *csv.Reader
+	isbn   int
+	title  int
+	header bool
+}
+
+// NewCSVReader returns a reader that reads books from r.
+func NewCSVReader(r io.Reader) *CSVReader {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+	reader.FieldsPerRecord = -1
+	return &CSVReader{reader: reader}
+}
+
+func (c *CSVReader) readHeader() error {
+	header, err := c.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return io.EOF
+	}
+	if err != nil {
+		return &library.Error{
+			Type:   library.BadInput,
+			Actual: err,
+			Desc:   "while reading the csv header",
+		}
+	}
+	c.isbn, c.title = -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "isbn":
+			c.isbn = i
+		case "title":
+			c.title = i
+		}
+	}
+	if c.isbn < 0 || c.title < 0 {
+		// no row can be read without the columns so this ends the import.
+		return fmt.Errorf("the csv header %q must have an isbn and a title column", header)
+	}
+	c.header = true
+	return nil
+}
+
+// Read returns the book on the next row.
+func (c *CSVReader) Read() (library.Book, error) {
+	if !c.header {
+		err := c.readHeader()
+		if err != nil {
+			return library.Book{}, err
+		}
+	}
+	record, err := c.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return library.Book{}, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return library.Book{}, &library.Error{
+			Type:   library.BadInput,
+			Actual: err,
+			Desc:   "while reading a csv row",
+		}
+	}
+	if err != nil {
+		return library.Book{}, err
+	}
+	if len(record) <= c.isbn || len(record) <= c.title {
+		return library.Book{}, &library.Error{
+			Type:   library.BadInput,
+			Actual: fmt.Errorf("the row has %d fields", len(record)),
+			Desc:   "while reading a csv row",
+		}
+	}
+	return parseRow(record[c.isbn], record[c.title])
+}
+
+// A CSVWriter writes books as CSV with an isbn, title, created_at and
+// updated_at header. Times are RFC 3339 in UTC.
+type CSVWriter struct {
+	writer *csv.Writer
+	header bool
+}
+
+// NewCSVWriter returns a writer that writes books to w.
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{writer: csv.NewWriter(w)}
+}
+
+func (c *CSVWriter) writeHeader() error {
+	c.header = true
+	return c.writer.Write([]string{"isbn", "title", "created_at", "updated_at"})
+}
+
+// Write writes a book as a row.
+func (c *CSVWriter) Write(book library.Book) error {
+	if !c.header {
+		err := c.writeHeader()
+		if err != nil {
+			return err
+		}
+	}
+	return c.writer.Write([]string{
+		book.ISBN.String(),
+		book.Title,
+		book.CreatedAt.UTC().Format(time.RFC3339Nano),
+		book.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// Close writes the header if there were no books and flushes the output.
+func (c *CSVWriter) Close() error {
+	if !c.header {
+		err := c.writeHeader()
+		if err != nil {
+			return err
+		}
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
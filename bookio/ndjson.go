@@ -0,0 +1,91 @@
+package bookio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/slcjordan/library"
+)
+
+// maxLineSize bounds a single NDJSON line so a stream without newlines can't
+// take up all memory.
+const maxLineSize = 1 << 20
+
+type ndjsonBook struct {
+	ISBN  string `json:"isbn"`
+	Title string `json:"title"`
+}
+
+type ndjsonExport struct {
+	ISBN      string    `json:"isbn"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// An NDJSONReader reads books from newline delimited JSON objects with isbn
+// and title fields. Blank lines are skipped.
+type NDJSONReader struct {
+	scanner *bufio.Scanner
+}
+
+// NewNDJSONReader returns a reader that reads books from r.
+func NewNDJSONReader(r io.Reader) *NDJSONReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &NDJSONReader{scanner: scanner}
+}
+
+// Read returns the book on the next line.
+func (n *NDJSONReader) Read() (library.Book, error) {
+	for n.scanner.Scan() {
+		line := bytes.TrimSpace(n.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var row ndjsonBook
+		err := json.Unmarshal(line, &row)
+		if err != nil {
+			return library.Book{}, &library.Error{
+				Type:   library.BadInput,
+				Actual: err,
+				Desc:   "while reading an ndjson line",
+			}
+		}
+		return parseRow(row.ISBN, row.Title)
+	}
+	err := n.scanner.Err()
+	if err != nil {
+		return library.Book{}, err
+	}
+	return library.Book{}, io.EOF
+}
+
+// An NDJSONWriter writes books as newline delimited JSON objects with isbn,
+// title, created_at and updated_at fields.
+type NDJSONWriter struct {
+	encoder *json.Encoder
+}
+
+// NewNDJSONWriter returns a writer that writes books to w.
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	return &NDJSONWriter{encoder: json.NewEncoder(w)}
+}
+
+// Write writes a book on its own line.
+func (n *NDJSONWriter) Write(book library.Book) error {
+	return n.encoder.Encode(ndjsonExport{
+		ISBN:      book.ISBN.String(),
+		Title:     book.Title,
+		CreatedAt: book.CreatedAt.UTC(),
+		UpdatedAt: book.UpdatedAt.UTC(),
+	})
+}
+
+// Close does nothing since every line is written right away.
+func (n *NDJSONWriter) Close() error {
+	return nil
+}
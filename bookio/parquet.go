@@ -0,0 +1,334 @@
+package bookio
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/slcjordan/library"
+)
+
+// The parts of the Parquet format a ParquetWriter uses. The numbers are
+// fixed by the format's Thrift definitions.
+const (
+	parquetMagic = "PAR1"
+
+	parquetInt64     = 2
+	parquetByteArray = 6
+
+	parquetRequired = 0
+
+	parquetUTF8            = 0
+	parquetTimestampMicros = 10
+
+	parquetPlain = 0
+	parquetRLE   = 3
+
+	parquetUncompressed = 0
+	parquetDataPage     = 0
+)
+
+// Thrift compact protocol types.
+const (
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// parquetRowGroupSize is how many books a ParquetWriter holds before writing
+// them out as a row group.
+const parquetRowGroupSize = 10000
+
+// A parquetColumn collects the PLAIN encoded values of a column for the
+// current row group.
+type parquetColumn struct {
+	name      string
+	kind      int32
+	converted int32
+	values    []byte
+}
+
+// A parquetChunk is where a column of a row group was written.
+type parquetChunk struct {
+	offset int64
+	size   int64
+}
+
+type parquetRowGroup struct {
+	rows   int64
+	chunks []parquetChunk
+}
+
+// A ParquetWriter writes books as an uncompressed Parquet file with isbn,
+// title, created_at and updated_at columns. Books are written out every
+// parquetRowGroupSize rows so memory use doesn't grow with the export. The
+// file footer is written by Close.
+type ParquetWriter struct {
+	w         io.Writer
+	offset    int64
+	columns   []*parquetColumn
+	rows      int64
+	rowGroups []parquetRowGroup
+	err       error
+}
+
+// NewParquetWriter returns a writer that writes books to w.
+func NewParquetWriter(w io.Writer) *ParquetWriter {
+	return &ParquetWriter{
+		w: w,
+		columns: []*parquetColumn{
+			{name: "isbn", kind: parquetByteArray, converted: parquetUTF8},
+			{name: "title", kind: parquetByteArray, converted: parquetUTF8},
+			{name: "created_at", kind: parquetInt64, converted: parquetTimestampMicros},
+			{name: "updated_at", kind: parquetInt64, converted: parquetTimestampMicros},
+		},
+	}
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendByteArray(b []byte, s string) []byte {
+	b = appendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+// Write adds a book to the current row group.
+func (p *ParquetWriter) Write(book library.Book) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.columns[0].values = appendByteArray(p.columns[0].values, book.ISBN.String())
+	p.columns[1].values = appendByteArray(p.columns[1].values, book.Title)
+	p.columns[2].values = appendUint64(p.columns[2].values, uint64(book.CreatedAt.UnixMicro()))
+	p.columns[3].values = appendUint64(p.columns[3].values, uint64(book.UpdatedAt.UnixMicro()))
+	p.rows++
+	if p.rows == parquetRowGroupSize {
+		p.flush()
+	}
+	return p.err
+}
+
+func (p *ParquetWriter) write(b []byte) {
+	if p.err != nil {
+		return
+	}
+	var n int
+	n, p.err = p.w.Write(b)
+	p.offset += int64(n)
+}
+
+// flush writes the current row group with one data page per column.
+func (p *ParquetWriter) flush() {
+	if p.rows == 0 {
+		return
+	}
+	if p.offset == 0 {
+		p.write([]byte(parquetMagic))
+	}
+	group := parquetRowGroup{rows: p.rows}
+	for _, c := range p.columns {
+		var header thriftWriter
+		header.beginStruct()
+		header.i32(1, parquetDataPage)
+		header.i32(2, int32(len(c.values)))
+		header.i32(3, int32(len(c.values)))
+		header.structField(5)
+		header.i32(1, int32(p.rows))
+		header.i32(2, parquetPlain)
+		header.i32(3, parquetRLE)
+		header.i32(4, parquetRLE)
+		header.endStruct()
+		header.endStruct()
+
+		chunk := parquetChunk{offset: p.offset}
+		p.write(header.b)
+		p.write(c.values)
+		chunk.size = p.offset - chunk.offset
+		group.chunks = append(group.chunks, chunk)
+		c.values = c.values[:0]
+	}
+	p.rowGroups = append(p.rowGroups, group)
+	p.rows = 0
+}
+
+// footer encodes the FileMetaData of everything written.
+func (p *ParquetWriter) footer() []byte {
+	var t thriftWriter
+	var rows int64
+	for _, g := range p.rowGroups {
+		rows += g.rows
+	}
+	t.beginStruct()
+	t.i32(1, 1)
+	t.list(2, thriftStruct, len(p.columns)+1)
+	t.beginStruct()
+	t.binary(4, "schema")
+	t.i32(5, int32(len(p.columns)))
+	t.endStruct()
+	for _, c := range p.columns {
+		t.beginStruct()
+		t.i32(1, c.kind)
+		t.i32(3, parquetRequired)
+		t.binary(4, c.name)
+		t.i32(6, c.converted)
+		t.structField(10)
+		if c.kind == parquetByteArray {
+			t.structField(1) // STRING
+			t.endStruct()
+		} else {
+			t.structField(8) // TIMESTAMP
+			t.boolean(1, true)
+			t.structField(2)
+			t.structField(2) // MICROS
+			t.endStruct()
+			t.endStruct()
+			t.endStruct()
+		}
+		t.endStruct()
+		t.endStruct()
+	}
+	t.i64(3, rows)
+	t.list(4, thriftStruct, len(p.rowGroups))
+	for _, g := range p.rowGroups {
+		var size int64
+		t.beginStruct()
+		t.list(1, thriftStruct, len(g.chunks))
+		for i, chunk := range g.chunks {
+			c := p.columns[i]
+			size += chunk.size
+			t.beginStruct()
+			t.i64(2, chunk.offset)
+			t.structField(3)
+			t.i32(1, c.kind)
+			t.list(2, thriftI32, 2)
+			t.zigzag(parquetPlain)
+			t.zigzag(parquetRLE)
+			t.list(3, thriftBinary, 1)
+			t.bytes(c.name)
+			t.i32(4, parquetUncompressed)
+			t.i64(5, g.rows)
+			t.i64(6, chunk.size)
+			t.i64(7, chunk.size)
+			t.i64(9, chunk.offset)
+			t.endStruct()
+			t.endStruct()
+		}
+		t.i64(2, size)
+		t.i64(3, g.rows)
+		t.endStruct()
+	}
+	t.binary(6, "github.com/slcjordan/library")
+	t.endStruct()
+	return t.b
+}
+
+// Close writes the last row group and the file footer.
+func (p *ParquetWriter) Close() error {
+	p.flush()
+	if p.offset == 0 {
+		p.write([]byte(parquetMagic))
+	}
+	footer := p.footer()
+	p.write(footer)
+	p.write(appendUint32(nil, uint32(len(footer))))
+	p.write([]byte(parquetMagic))
+	return p.err
+}
+
+// A thriftWriter encodes structs with the Thrift compact protocol, which is
+// what Parquet metadata is written in.
+type thriftWriter struct {
+	b []byte
+	// last holds the last field id of every struct being written.
+	last []int16
+}
+
+func (t *thriftWriter) beginStruct() {
+	t.last = append(t.last, 0)
+}
+
+func (t *thriftWriter) endStruct() {
+	t.b = append(t.b, 0)
+	t.last = t.last[:len(t.last)-1]
+}
+
+func (t *thriftWriter) varint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	t.b = append(t.b, buf[:n]...)
+}
+
+func (t *thriftWriter) zigzag(v int64) {
+	t.varint(uint64(v<<1) ^ uint64(v>>63))
+}
+
+func (t *thriftWriter) bytes(s string) {
+	t.varint(uint64(len(s)))
+	t.b = append(t.b, s...)
+}
+
+func (t *thriftWriter) field(id int16, kind byte) {
+	last := &t.last[len(t.last)-1]
+	delta := id - *last
+	if delta > 0 && delta <= 15 {
+		t.b = append(t.b, byte(delta)<<4|kind)
+	} else {
+		t.b = append(t.b, kind)
+		t.zigzag(int64(id))
+	}
+	*last = id
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.field(id, thriftI32)
+	t.zigzag(int64(v))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.field(id, thriftI64)
+	t.zigzag(v)
+}
+
+func (t *thriftWriter) boolean(id int16, v bool) {
+	if v {
+		t.field(id, thriftTrue)
+	} else {
+		t.field(id, thriftFalse)
+	}
+}
+
+func (t *thriftWriter) binary(id int16, s string) {
+	t.field(id, thriftBinary)
+	t.bytes(s)
+}
+
+// structField starts a struct field; it is finished with endStruct.
+func (t *thriftWriter) structField(id int16) {
+	t.field(id, thriftStruct)
+	t.beginStruct()
+}
+
+// list starts a list field of n elements, which are written right after it.
+// Struct elements start with beginStruct.
+func (t *thriftWriter) list(id int16, kind byte, n int) {
+	t.field(id, thriftList)
+	if n < 15 {
+		t.b = append(t.b, byte(n)<<4|kind)
+		return
+	}
+	t.b = append(t.b, 0xf0|kind)
+	t.varint(uint64(n))
+}
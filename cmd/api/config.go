@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/config"
+)
+
+const configUsage = "usage: api config print"
+
+// runConfig runs the config subcommand with the arguments after "config".
+// The config is printed even if it is invalid, followed by its problems as
+// comments, and the command then fails.
+func runConfig(args []string) {
+	if len(args) != 1 || args[0] != "print" {
+		panic(&library.Error{
+			Actual: fmt.Errorf("unknown config command %q", args),
+			Desc:   configUsage,
+			Type:   library.BadInput,
+		})
+	}
+	err := config.Print(os.Stdout)
+	if err != nil {
+		panic(&library.Error{
+			Actual: err,
+			Desc:   "while printing the config",
+			Type:   library.Unknown,
+		})
+	}
+	err = config.Validate()
+	if err == nil {
+		return
+	}
+	var problems library.FieldErrors
+	if !errors.As(err, &problems) {
+		panic(err)
+	}
+	fmt.Println("# the config is invalid:")
+	for _, p := range problems {
+		fmt.Printf("#   %s: %s\n", p.Field, p.Message)
+	}
+	os.Exit(1)
+}
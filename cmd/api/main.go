@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"flag"
 	"log"
 	"net/http"
 
 	"github.com/slcjordan/library"
 	"github.com/slcjordan/library/config"
 	_ "github.com/slcjordan/library/config/envvar"
+	_ "github.com/slcjordan/library/config/file"
+	_ "github.com/slcjordan/library/config/flags"
+	_ "github.com/slcjordan/library/config/secrets"
 	_ "github.com/slcjordan/library/log/stdlib"
 	"github.com/slcjordan/library/wire/api"
 )
@@ -32,9 +37,26 @@ func main() {
 		log.Fatalf("the app crashed: %s", err)
 	}()
 
+	flag.Parse()
+	// the subcommands check the parts of the config they need themselves:
+	// migrating needs nothing but the database, and printing the config is
+	// most useful when it is invalid.
+	switch flag.Arg(0) {
+	case "migrate":
+		config.MustParseUnvalidated()
+		runMigrate(flag.Args()[1:])
+		return
+	case "config":
+		config.MustParseUnvalidated()
+		runConfig(flag.Args()[1:])
+		return
+	}
 	config.MustParse()
-	log.Printf("listening at %s", config.HTTP.ListenAddress)
-	err := http.ListenAndServe(config.HTTP.ListenAddress, api.Wire())
+	go api.WireReload(context.Background())
+	go api.WireWorkers(context.Background())
+	address := config.Current().HTTP.ListenAddress
+	log.Printf("listening at %s", address)
+	err := http.ListenAndServe(address, api.Wire())
 	if err != nil {
 		panic(&library.Error{
 			Actual: err,
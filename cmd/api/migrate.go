@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/config"
+	"github.com/slcjordan/library/db"
+	"github.com/slcjordan/library/db/migrate"
+)
+
+const migrateUsage = "usage: api migrate up | down | to VERSION | status"
+
+func badMigrateArgs(err error) error {
+	return &library.Error{
+		Actual: err,
+		Desc:   migrateUsage,
+		Type:   library.BadInput,
+	}
+}
+
+// runMigrate runs the migrate subcommand with the arguments after "migrate".
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		panic(badMigrateArgs(fmt.Errorf("missing a migrate command")))
+	}
+	command, args := args[0], args[1:]
+	var version int64
+	switch command {
+	case "up", "down", "status":
+		if len(args) != 0 {
+			panic(badMigrateArgs(fmt.Errorf("%s takes no arguments", command)))
+		}
+	case "to":
+		if len(args) != 1 {
+			panic(badMigrateArgs(fmt.Errorf("to takes a version")))
+		}
+		var err error
+		version, err = strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			panic(badMigrateArgs(err))
+		}
+	default:
+		panic(badMigrateArgs(fmt.Errorf("unknown migrate command %q", command)))
+	}
+
+	// the rest of the config isn't validated since migrating doesn't use it.
+	if config.Current().Postgres.ConnectionString == "" {
+		panic(&library.Error{
+			Actual: library.FieldErrors{{Field: "Postgres.ConnectionString", Message: "is required to migrate"}},
+			Desc:   "while validating the config",
+			Type:   library.InvalidSettings,
+		})
+	}
+	ctx := context.Background()
+	pool := db.MustConnect()
+	defer pool.Close()
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		panic(&library.Error{
+			Actual: err,
+			Desc:   "while connecting to migrate",
+			Type:   library.DatabaseError,
+		})
+	}
+	defer conn.Release()
+	migrator := &migrate.Migrator{Conn: conn}
+
+	var ran []migrate.Migration
+	switch command {
+	case "up":
+		ran, err = migrator.Up(ctx)
+	case "down":
+		ran, err = migrator.Down(ctx)
+	case "to":
+		ran, err = migrator.To(ctx, version)
+	case "status":
+		err = printStatus(ctx, migrator)
+	}
+	if err != nil {
+		panic(err)
+	}
+	for _, m := range ran {
+		fmt.Printf("migrated %s\n", m.Name)
+	}
+	if command != "status" && len(ran) == 0 {
+		fmt.Println("no migrations to run")
+	}
+}
+
+func printStatus(ctx context.Context, migrator *migrate.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "APPLIED AT\tMIGRATION")
+	for _, s := range statuses {
+		appliedAt := "pending"
+		if s.Applied {
+			appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%s\t%s\n", appliedAt, s.Migration.Name)
+	}
+	return w.Flush()
+}
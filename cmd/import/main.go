@@ -0,0 +1,74 @@
+// Import creates books from a CSV or NDJSON file, or stdin if no file is
+// given. Rows that aren't imported are printed with the reason.
+//
+//	import [-format csv|ndjson] [-on-conflict skip|overwrite|fail] [file]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/bookio"
+	"github.com/slcjordan/library/config"
+	_ "github.com/slcjordan/library/config/envvar"
+	_ "github.com/slcjordan/library/config/file"
+	_ "github.com/slcjordan/library/config/flags"
+	_ "github.com/slcjordan/library/config/secrets"
+	"github.com/slcjordan/library/db"
+	_ "github.com/slcjordan/library/log/stdlib"
+)
+
+func main() {
+	format := flag.String("format", "", "csv or ndjson; guessed from the file extension if empty")
+	onConflict := flag.String("on-conflict", string(library.SkipExisting), "skip, overwrite or fail for books that already exist")
+	flag.Parse()
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	config.MustParse()
+	var input io.Reader = os.Stdin
+	if flag.NArg() == 1 {
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			log.Fatalf("while opening the import file: %s", err)
+		}
+		defer f.Close()
+		input = f
+		if *format == "" {
+			*format = strings.TrimPrefix(filepath.Ext(f.Name()), ".")
+		}
+	}
+	reader, err := bookio.NewReader(bookio.Format(*format), input)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	conn := db.MustConnect()
+	defer conn.Close()
+	queryer := &db.Queryer{
+		DBTX: conn,
+	}
+	report, err := queryer.ImportBooks(context.Background(), reader, library.ImportMode(*onConflict))
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, e := range report.Errors {
+		fmt.Printf("row %d: %s\n", e.Row, e.Message)
+	}
+	if report.Truncated {
+		fmt.Printf("only the first %d errors are listed\n", len(report.Errors))
+	}
+	fmt.Printf("%d rows: %d imported, %d skipped, %d errors\n", report.Rows, report.Imported, report.Skipped, report.ErrorCount)
+	if report.Aborted {
+		log.Fatal("the import was aborted and nothing was imported")
+	}
+}
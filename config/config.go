@@ -2,13 +2,102 @@ package config
 
 import "time"
 
-var Postgres struct {
-	ConnectTimeout   time.Duration
-	ConnectionString string
+// PostgresSettings are the settings of the postgres storage backend.
+type PostgresSettings struct {
+	ConnectTimeout   time.Duration `default:"3s" min:"1ms"`
+	ConnectionString string        `secret:"password" url:"true"`
+	// PageTokenSecret signs page tokens and change feed event ids. Every
+	// storage backend needs it.
+	PageTokenSecret string `secret:"true" required:"true"`
+	// AutoMigrate applies pending migrations when the api starts.
+	AutoMigrate bool
 }
 
-var HTTP struct {
-	BaseURL       string
-	ListenAddress string
-	MaxListSize   int32
+var Postgres PostgresSettings
+
+// StorageSettings pick where the API keeps its data. Backend is "postgres",
+// the default, "sqlite" or "memory". The sqlite and memory backends only hold
+// the catalog of books and authors. SQLitePath is the database file of the
+// sqlite backend, or ":memory:".
+type StorageSettings struct {
+	Backend    string `default:"postgres" oneof:"postgres sqlite memory"`
+	SQLitePath string
+}
+
+var Storage StorageSettings
+
+// HTTPSettings are the settings of the api server.
+type HTTPSettings struct {
+	BaseURL       string `url:"true"`
+	ListenAddress string `default:"0.0.0.0:5082" required:"true"`
+	MaxListSize   int32  `default:"500" min:"1" reload:"true"`
+	// BulkTimeout replaces the request timeout for bulk imports and exports.
+	// They never time out if it is zero.
+	BulkTimeout time.Duration `default:"10m" min:"0s"`
+	// HeartbeatInterval is how often the change feed sends a comment while
+	// nothing changes, so that proxies keep the stream open.
+	HeartbeatInterval time.Duration `default:"15s" min:"1s" reload:"true"`
+}
+
+var HTTP HTTPSettings
+
+// TrashSettings set how long deleted books can be restored. Every
+// PurgeInterval, books that have been in the trash for longer than Retention
+// are deleted for good. They are never purged if PurgeInterval is zero.
+type TrashSettings struct {
+	Retention     time.Duration `default:"720h" min:"0s"`
+	PurgeInterval time.Duration `default:"1h" min:"0s"`
+}
+
+var Trash TrashSettings
+
+// WebhooksSettings set how changes are delivered to partner systems. Pending
+// deliveries are sent every DeliveryInterval, never if it is zero. A failed
+// delivery is retried after RetryBackoff, doubling with every attempt up to
+// MaxRetryBackoff, and is dead after MaxAttempts attempts. Zero MaxAttempts
+// or MaxRetryBackoff are unlimited. Timeout limits every attempt.
+type WebhooksSettings struct {
+	DeliveryInterval time.Duration `default:"5s" min:"0s"`
+	Timeout          time.Duration `default:"10s" min:"0s"`
+	MaxAttempts      int32         `default:"10" min:"0"`
+	RetryBackoff     time.Duration `default:"30s" min:"0s"`
+	MaxRetryBackoff  time.Duration `default:"6h" min:"0s"`
+}
+
+var Webhooks WebhooksSettings
+
+// CirculationSettings set how long loans and holds last. Expired holds are
+// released every HoldExpiryInterval, never if it is zero.
+type CirculationSettings struct {
+	LoanPeriod         time.Duration `default:"504h" min:"1s" reload:"true"`
+	MaxRenewals        int32         `default:"2" min:"0" reload:"true"`
+	HoldPickupPeriod   time.Duration `default:"168h" min:"1s" reload:"true"`
+	HoldExpiryInterval time.Duration `default:"1m" min:"0s"`
+}
+
+var Circulation CirculationSettings
+
+// A FeeSchedule sets how overdue fines accrue for one class of patrons.
+// Amounts are in cents. A Cap of zero means fines are not capped.
+type FeeSchedule struct {
+	DailyRate   int64
+	GracePeriod time.Duration
+	Cap         int64
+	Holidays    []time.Time
 }
+
+// FinesSettings hold a fee schedule for each patron class. Patrons of a
+// class without a schedule are never fined.
+type FinesSettings struct {
+	Schedules map[string]FeeSchedule `reload:"true"`
+}
+
+var Fines FinesSettings
+
+// LogSettings set what is logged. Level is "info", the default, to log
+// everything or "error" to only log errors of this application.
+type LogSettings struct {
+	Level string `default:"info" oneof:"info error" reload:"true"`
+}
+
+var Log LogSettings
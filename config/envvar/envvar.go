@@ -1,9 +1,11 @@
 package envvar
 
 import (
-	"net/url"
+	"fmt"
 	"os"
+	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/slcjordan/library"
@@ -11,27 +13,54 @@ import (
 )
 
 func init() {
-	config.Register(0, MustParse)
+	config.Register(1, MustParse)
 }
 
-func mustParseInt32(dest *int32, name string) {
+// lookupEnv reads an env-var, or the file named by the env-var with _FILE
+// after its name, the way Docker and Kubernetes secrets are given. A trailing
+// newline in the file is dropped.
+func lookupEnv(name string) (string, bool) {
 	value, ok := os.LookupEnv(name)
+	file, fromFile := os.LookupEnv(name + "_FILE")
+	if !fromFile {
+		return value, ok
+	}
+	if ok {
+		panic(&library.Error{
+			Actual: fmt.Errorf("only one of %s and %s_FILE can be set", name, name),
+			Desc:   "while reading env-var: " + name,
+			Type:   library.InvalidSettings,
+		})
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		panic(&library.Error{
+			Actual: err,
+			Desc:   "while reading env-var file: " + name + "_FILE",
+			Type:   library.InvalidSettings,
+		})
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r"), true
+}
+
+func mustParseInt64(dest *int64, name string) {
+	value, ok := lookupEnv(name)
 	if !ok {
 		return
 	}
-	parsed, err := strconv.ParseInt(value, 10, 32)
+	parsed, err := strconv.ParseInt(value, 10, 64)
 	if err != nil {
 		panic(&library.Error{
 			Actual: err,
-			Desc:   "while parsing int32 from env-var: " + name,
+			Desc:   "while parsing int64 from env-var: " + name,
 			Type:   library.InvalidSettings,
 		})
 	}
-	*dest = int32(parsed)
+	*dest = parsed
 }
 
 func mustParseDuration(dest *time.Duration, name string) {
-	value, ok := os.LookupEnv(name)
+	value, ok := lookupEnv(name)
 	if !ok {
 		return
 	}
@@ -46,36 +75,77 @@ func mustParseDuration(dest *time.Duration, name string) {
 	}
 }
 
-func mustMatchURL(dest *string, name string) {
-	value, ok := os.LookupEnv(name)
+// mustParseDates parses a comma separated list of dates like 2006-01-02.
+func mustParseDates(dest *[]time.Time, name string) {
+	value, ok := lookupEnv(name)
 	if !ok {
 		return
 	}
-	_, err := url.Parse(value)
-	if err != nil {
-		panic(&library.Error{
-			Actual: err,
-			Desc:   "while parsing url from env-var: " + name,
-			Type:   library.InvalidSettings,
-		})
+	var dates []time.Time
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", field)
+		if err != nil {
+			panic(&library.Error{
+				Actual: err,
+				Desc:   "while parsing dates from env-var: " + name,
+				Type:   library.InvalidSettings,
+			})
+		}
+		dates = append(dates, date)
 	}
-	*dest = value
+	*dest = dates
 }
 
-func maybeSetString(dest *string, name string) {
-	value, ok := os.LookupEnv(name)
+// mustParseFeeSchedules reads a comma separated list of patron classes from
+// name. The schedule of each class is read from env-vars with the class name
+// upper cased in them, e.g. LIBRARY_FINES_STANDARD_DAILY_RATE.
+func mustParseFeeSchedules(dest *map[string]config.FeeSchedule, name string) {
+	value, ok := lookupEnv(name)
 	if !ok {
 		return
 	}
-	*dest = value
+	schedules := make(map[string]config.FeeSchedule)
+	for _, class := range strings.Split(value, ",") {
+		class = strings.TrimSpace(class)
+		if class == "" {
+			continue
+		}
+		prefix := "LIBRARY_FINES_" + strings.ToUpper(class) + "_"
+		schedule := (*dest)[class]
+		mustParseInt64(&schedule.DailyRate, prefix+"DAILY_RATE")
+		mustParseDuration(&schedule.GracePeriod, prefix+"GRACE_PERIOD")
+		mustParseInt64(&schedule.Cap, prefix+"CAP")
+		mustParseDates(&schedule.Holidays, prefix+"HOLIDAYS")
+		schedules[class] = schedule
+	}
+	*dest = schedules
+}
+
+// mustParseField sets a config value from its env-var.
+func mustParseField(field config.Field) {
+	value, ok := lookupEnv(field.EnvVar)
+	if !ok {
+		return
+	}
+	parsed, err := field.Parse(value)
+	if err != nil {
+		panic(&library.Error{
+			Actual: err,
+			Desc:   "while parsing env-var: " + field.EnvVar,
+			Type:   library.InvalidSettings,
+		})
+	}
+	reflect.ValueOf(field.Value).Elem().Set(parsed)
 }
 
 // Sets config values from environment variables.
 func MustParse() {
-	mustParseDuration(&config.Postgres.ConnectTimeout, "LIBRARY_PG_CONNECT_TIMEOUT")
-	mustMatchURL(&config.Postgres.ConnectionString, "LIBRARY_PG_CONNECTION_STRING")
-
-	mustMatchURL(&config.HTTP.BaseURL, "LIBRARY_HTTP_BASE_URL")
-	maybeSetString(&config.HTTP.ListenAddress, "LIBRARY_HTTP_LISTEN_ADDRESS")
-	mustParseInt32(&config.HTTP.MaxListSize, "LIBRARY_HTTP_MAX_LIST_SIZE")
+	for _, field := range config.Fields() {
+		mustParseField(field)
+	}
+	mustParseFeeSchedules(&config.Fines.Schedules, "LIBRARY_FINES_CLASSES")
 }
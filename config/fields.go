@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// A Field is a config value and where it is in the config. Fields are
+// declared with tags:
+//
+//	default: the value before the config is parsed
+//	required: "true" if the value can't be empty
+//	oneof: the values a string can be, separated by spaces
+//	min, max: the bounds of a number or duration
+//	secret: "true" if Print redacts the value, or "password" if it only
+//	redacts the password in it
+//	reload: "true" if Reload can change the value while the api runs
+//	url: "true" if a string must parse as a URL
+type Field struct {
+	// Section and Name are the names of the config var and of the field in
+	// it, e.g. HTTP and MaxListSize.
+	Section string
+	Name    string
+	// EnvVar is the env-var that sets the value.
+	EnvVar string
+	// Default is the value before the config is parsed, as it would be
+	// given to Parse.
+	Default string
+	// Value points to the value, e.g. an *int32.
+	Value interface{}
+
+	tag reflect.StructTag
+}
+
+// sections are the config vars and the prefix of their env-vars.
+var sections = []struct {
+	name   string
+	envVar string
+	value  interface{}
+}{
+	{"Postgres", "LIBRARY_PG", &Postgres},
+	{"Storage", "LIBRARY_STORAGE", &Storage},
+	{"HTTP", "LIBRARY_HTTP", &HTTP},
+	{"Trash", "LIBRARY_TRASH", &Trash},
+	{"Webhooks", "LIBRARY_WEBHOOKS", &Webhooks},
+	{"Circulation", "LIBRARY_CIRCULATION", &Circulation},
+	{"Fines", "LIBRARY_FINES", &Fines},
+	{"Log", "LIBRARY_LOG", &Log},
+}
+
+// Fields returns every config value that is a single value, in the order
+// they are declared. The fee schedules of Fines, which are set by patron
+// class, are left out.
+func Fields() []Field {
+	var fields []Field
+	for _, section := range sections {
+		v := reflect.ValueOf(section.value).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			switch field.Kind() {
+			case reflect.Map, reflect.Slice, reflect.Struct:
+				continue
+			}
+			structField := v.Type().Field(i)
+			fields = append(fields, Field{
+				Section: section.name,
+				Name:    structField.Name,
+				EnvVar:  section.envVar + "_" + strings.ToUpper(strings.Join(Words(structField.Name), "_")),
+				Default: structField.Tag.Get("default"),
+				Value:   field.Addr().Interface(),
+				tag:     structField.Tag,
+			})
+		}
+	}
+	return fields
+}
+
+// Path returns the struct path of the field, e.g. HTTP.MaxListSize.
+func (f Field) Path() string {
+	return f.Section + "." + f.Name
+}
+
+// Parse parses s as a value of the type of the field.
+func (f Field) Parse(s string) (reflect.Value, error) {
+	var parsed interface{}
+	var err error
+	switch f.Value.(type) {
+	case *string:
+		if f.tag.Get("url") == "true" {
+			_, err = url.Parse(s)
+		}
+		parsed = s
+	case *int32:
+		var i int64
+		i, err = strconv.ParseInt(s, 10, 32)
+		parsed = int32(i)
+	case *bool:
+		parsed, err = strconv.ParseBool(s)
+	case *time.Duration:
+		parsed, err = time.ParseDuration(s)
+	default:
+		err = fmt.Errorf("unsupported type %T", f.Value)
+	}
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return reflect.ValueOf(parsed), nil
+}
+
+// initialisms are kept as single words by Words.
+var initialisms = []string{"HTTP", "SQLite", "URL"}
+
+// Words splits a name like SQLitePath into lower case words like sqlite and
+// path.
+func Words(name string) []string {
+	var words []string
+	for len(name) > 0 {
+		n := wordLength(name)
+		words = append(words, strings.ToLower(name[:n]))
+		name = name[n:]
+	}
+	return words
+}
+
+// wordLength returns the length of the word at the start of name: an
+// initialism, or a letter and the lower case letters after it.
+func wordLength(name string) int {
+	for _, initialism := range initialisms {
+		if strings.HasPrefix(name, initialism) {
+			return len(initialism)
+		}
+	}
+	n := 1
+	for n < len(name) && !unicode.IsUpper(rune(name[n])) {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	for name, expected := range map[string][]string{
+		"MaxListSize": {"max", "list", "size"},
+		"BaseURL":     {"base", "url"},
+		"SQLitePath":  {"sqlite", "path"},
+		"HTTP":        {"http"},
+		"Retention":   {"retention"},
+	} {
+		words := Words(name)
+		if !reflect.DeepEqual(words, expected) {
+			t.Fatalf("expected %s to be split into %q but got %q", name, expected, words)
+		}
+	}
+}
+
+func TestFields(t *testing.T) {
+	for _, field := range Fields() {
+		if field.Section == "HTTP" && field.Name == "MaxListSize" {
+			if field.EnvVar != "LIBRARY_HTTP_MAX_LIST_SIZE" {
+				t.Fatalf("unexpected env-var %s", field.EnvVar)
+			}
+			*field.Value.(*int32) = 7
+			if HTTP.MaxListSize != 7 {
+				t.Fatalf("expected the field to point to the config value")
+			}
+			return
+		}
+		if field.Section == "Fines" {
+			t.Fatalf("expected the fee schedules to be left out but got %s", field.Name)
+		}
+	}
+	t.Fatalf("expected a field for HTTP.MaxListSize")
+}
+
+func TestParseURL(t *testing.T) {
+	for _, field := range Fields() {
+		if field.Path() != "HTTP.BaseURL" {
+			continue
+		}
+		_, err := field.Parse("/api/v1")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		_, err = field.Parse("http://[::1")
+		if err == nil {
+			t.Fatalf("expected an invalid url to be an error")
+		}
+		return
+	}
+	t.Fatalf("expected a field for HTTP.BaseURL")
+}
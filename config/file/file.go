@@ -0,0 +1,365 @@
+// Package file sets config values from a YAML, TOML or JSON config file. The
+// file is given by the -config flag or the LIBRARY_CONFIG_FILE env-var and
+// its format by its extension. Keys are the snake cased struct paths of the
+// config values, e.g. http.max_list_size, and fee schedules are tables under
+// fines named by patron class, e.g. fines.standard.daily_rate.
+//
+// The file is parsed before env-vars so that they override it, and is
+// watched so that the config is reloaded when it changes.
+package file
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/config"
+)
+
+var path = flag.String("config", "", "a YAML, TOML or JSON config file; overrides LIBRARY_CONFIG_FILE")
+
+func init() {
+	config.Register(0, MustParse)
+	config.RegisterWatcher(Watch)
+}
+
+// A KeyError is why the value of a single key in a config file is invalid.
+type KeyError struct {
+	File    string
+	Line    int
+	Key     string
+	Message string
+}
+
+func (e KeyError) Error() string {
+	return fmt.Sprintf("%s:%d: %s: %s", e.File, e.Line, e.Key, e.Message)
+}
+
+// KeyErrors is the Actual error of an InvalidSettings error about specific
+// keys in a config file.
+type KeyErrors []KeyError
+
+func (k KeyErrors) Error() string {
+	messages := make([]string, 0, len(k))
+	for _, e := range k {
+		messages = append(messages, e.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// A setting is a value read from a config file and the line of its key.
+// Values are strings, int64s, json.Numbers, float64s, bools, times, and
+// slices and maps of them.
+type setting struct {
+	line  int
+	value interface{}
+}
+
+// joinKey appends name to a dotted key.
+func joinKey(key string, name string) string {
+	if key == "" {
+		return name
+	}
+	return key + "." + name
+}
+
+// settings are the settings of a config file. Every key that is looked up is
+// marked used so that the rest can be reported as unknown.
+type settings struct {
+	file   string
+	values map[string]setting
+	used   map[string]bool
+	errors KeyErrors
+}
+
+func (s *settings) lookup(key string) (setting, bool) {
+	value, ok := s.values[key]
+	if ok {
+		s.used[key] = true
+	}
+	return value, ok
+}
+
+func (s *settings) invalid(key string, value setting, message string) {
+	s.errors = append(s.errors, KeyError{
+		File:    s.file,
+		Line:    value.line,
+		Key:     key,
+		Message: message,
+	})
+}
+
+// describe names the kind of a value the same way for every format.
+func describe(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("the string %q", v)
+	case int64, float64, json.Number:
+		return fmt.Sprintf("the number %v", v)
+	case bool:
+		return fmt.Sprintf("%t", v)
+	case time.Time:
+		return "the date " + v.Format("2006-01-02")
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "a table"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func (s *settings) mismatch(key string, value setting, expected string) {
+	s.invalid(key, value, fmt.Sprintf("expected %s but got %s", expected, describe(value.value)))
+}
+
+func toInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case json.Number:
+		parsed, err := v.Int64()
+		return parsed, err == nil
+	}
+	return 0, false
+}
+
+func (s *settings) parseInt64(dest *int64, key string) {
+	value, ok := s.lookup(key)
+	if !ok {
+		return
+	}
+	parsed, ok := toInt64(value.value)
+	if !ok {
+		s.mismatch(key, value, "an integer")
+		return
+	}
+	*dest = parsed
+}
+
+func (s *settings) parseInt32(dest *int32, key string) {
+	value, ok := s.lookup(key)
+	if !ok {
+		return
+	}
+	parsed, ok := toInt64(value.value)
+	if !ok || parsed != int64(int32(parsed)) {
+		s.mismatch(key, value, "a 32 bit integer")
+		return
+	}
+	*dest = int32(parsed)
+}
+
+func (s *settings) parseBool(dest *bool, key string) {
+	value, ok := s.lookup(key)
+	if !ok {
+		return
+	}
+	parsed, ok := value.value.(bool)
+	if !ok {
+		s.mismatch(key, value, "a bool")
+		return
+	}
+	*dest = parsed
+}
+
+// parseDuration reads a duration like 1h30m.
+func (s *settings) parseDuration(dest *time.Duration, key string) {
+	value, ok := s.lookup(key)
+	if !ok {
+		return
+	}
+	str, ok := value.value.(string)
+	if !ok {
+		s.mismatch(key, value, "a duration string")
+		return
+	}
+	parsed, err := time.ParseDuration(str)
+	if err != nil {
+		s.invalid(key, value, err.Error())
+		return
+	}
+	*dest = parsed
+}
+
+// parseDates reads a list of dates, either dates of the file format or
+// strings like 2006-01-02.
+func (s *settings) parseDates(dest *[]time.Time, key string) {
+	value, ok := s.lookup(key)
+	if !ok {
+		return
+	}
+	list, ok := value.value.([]interface{})
+	if !ok {
+		s.mismatch(key, value, "a list of dates")
+		return
+	}
+	dates := make([]time.Time, 0, len(list))
+	for _, item := range list {
+		switch v := item.(type) {
+		case time.Time:
+			dates = append(dates, v)
+		case string:
+			date, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				s.invalid(key, value, err.Error())
+				return
+			}
+			dates = append(dates, date)
+		default:
+			s.mismatch(key, value, "a list of dates")
+			return
+		}
+	}
+	*dest = dates
+}
+
+// parseField reads a config value. Strings and durations are given as
+// strings.
+func (s *settings) parseField(field config.Field, key string) {
+	switch dest := field.Value.(type) {
+	case *int32:
+		s.parseInt32(dest, key)
+		return
+	case *bool:
+		s.parseBool(dest, key)
+		return
+	}
+	value, ok := s.lookup(key)
+	if !ok {
+		return
+	}
+	str, ok := value.value.(string)
+	if !ok {
+		s.mismatch(key, value, "a string")
+		return
+	}
+	parsed, err := field.Parse(str)
+	if err != nil {
+		s.invalid(key, value, err.Error())
+		return
+	}
+	reflect.ValueOf(field.Value).Elem().Set(parsed)
+}
+
+// key returns the key of a config value, e.g. http.max_list_size.
+func key(field config.Field) string {
+	return strings.Join(config.Words(field.Section), "_") + "." + strings.Join(config.Words(field.Name), "_")
+}
+
+// parseFeeSchedules reads a table of fee schedules under key, one for each
+// patron class.
+func (s *settings) parseFeeSchedules(dest *map[string]config.FeeSchedule, key string) {
+	classes := make(map[string]bool)
+	for k := range s.values {
+		if !strings.HasPrefix(k, key+".") {
+			continue
+		}
+		class := strings.SplitN(strings.TrimPrefix(k, key+"."), ".", 2)[0]
+		classes[class] = true
+	}
+	if len(classes) == 0 {
+		return
+	}
+	schedules := make(map[string]config.FeeSchedule)
+	for class := range classes {
+		prefix := joinKey(key, class)
+		schedule := (*dest)[class]
+		s.parseInt64(&schedule.DailyRate, joinKey(prefix, "daily_rate"))
+		s.parseDuration(&schedule.GracePeriod, joinKey(prefix, "grace_period"))
+		s.parseInt64(&schedule.Cap, joinKey(prefix, "cap"))
+		s.parseDates(&schedule.Holidays, joinKey(prefix, "holidays"))
+		schedules[class] = schedule
+	}
+	*dest = schedules
+}
+
+// unknown reports every key that wasn't looked up.
+func (s *settings) unknown() {
+	var keys []string
+	for key := range s.values {
+		if !s.used[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := s.values[keys[i]], s.values[keys[j]]
+		if a.line != b.line {
+			return a.line < b.line
+		}
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		s.invalid(key, s.values[key], "unknown key")
+	}
+}
+
+// read parses a config file by its extension.
+func read(file string) (map[string]setting, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		return parseYAML(data)
+	case ".toml":
+		return parseTOML(data)
+	case ".json":
+		return parseJSON(data)
+	}
+	return nil, fmt.Errorf("unknown config file extension %q", filepath.Ext(file))
+}
+
+// mustParseFile sets config values from a config file.
+func mustParseFile(file string) {
+	values, err := read(file)
+	if err != nil {
+		panic(&library.Error{
+			Actual: err,
+			Desc:   "while reading config file: " + file,
+			Type:   library.InvalidSettings,
+		})
+	}
+	s := &settings{
+		file:   file,
+		values: values,
+		used:   make(map[string]bool),
+	}
+	for _, field := range config.Fields() {
+		s.parseField(field, key(field))
+	}
+	s.parseFeeSchedules(&config.Fines.Schedules, "fines")
+
+	s.unknown()
+	if len(s.errors) > 0 {
+		panic(&library.Error{
+			Actual: s.errors,
+			Desc:   "while parsing config file: " + file,
+			Type:   library.InvalidSettings,
+		})
+	}
+}
+
+// name returns the config file, if one is given.
+func name() string {
+	if *path != "" {
+		return *path
+	}
+	return os.Getenv("LIBRARY_CONFIG_FILE")
+}
+
+// MustParse sets config values from the config file, if one is given.
+func MustParse() {
+	file := name()
+	if file == "" {
+		return
+	}
+	mustParseFile(file)
+}
@@ -0,0 +1,224 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/config"
+)
+
+func writeFile(t *testing.T, name string, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(file, []byte(content), 0o600)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return file
+}
+
+// TestFieldKeys checks that every config field is read from its key.
+func TestFieldKeys(t *testing.T) {
+	content := make(map[string]map[string]interface{})
+	for _, field := range config.Fields() {
+		var value interface{}
+		switch field.Value.(type) {
+		case *string:
+			value = "http://example.com"
+		case *int32:
+			value = 7
+		case *bool:
+			value = true
+		case *time.Duration:
+			value = "7s"
+		default:
+			t.Fatalf("%s: unexpected type %T", key(field), field.Value)
+		}
+		parts := strings.SplitN(key(field), ".", 2)
+		section, name := parts[0], parts[1]
+		if content[section] == nil {
+			content[section] = make(map[string]interface{})
+		}
+		content[section][name] = value
+	}
+	data, err := json.Marshal(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	mustParseFile(writeFile(t, "library.json", string(data)))
+	for _, field := range config.Fields() {
+		if reflect.ValueOf(field.Value).Elem().IsZero() {
+			t.Fatalf("expected %s to be set by %s", field.Path(), key(field))
+		}
+	}
+}
+
+func TestMustParseFile(t *testing.T) {
+	for name, content := range map[string]string{
+		"library.yaml": `
+postgres:
+  connect_timeout: 3s
+  auto_migrate: true
+http:
+  listen_address: 0.0.0.0:5082
+  max_list_size: 500
+fines:
+  standard:
+    daily_rate: 25
+    holidays: [2026-12-25, "2027-01-01"]
+`,
+		"library.toml": `
+[postgres]
+connect_timeout = "3s"
+auto_migrate = true
+
+[http]
+listen_address = "0.0.0.0:5082"
+max_list_size = 500
+
+[fines.standard]
+daily_rate = 25
+holidays = [2026-12-25, "2027-01-01"]
+`,
+		"library.json": `{
+  "postgres": {"connect_timeout": "3s", "auto_migrate": true},
+  "http": {"listen_address": "0.0.0.0:5082", "max_list_size": 500},
+  "fines": {"standard": {"daily_rate": 25, "holidays": ["2026-12-25", "2027-01-01"]}}
+}`,
+	} {
+		config.Postgres.ConnectTimeout = 0
+		config.Postgres.AutoMigrate = false
+		config.HTTP.ListenAddress = ""
+		config.HTTP.MaxListSize = 0
+		config.Fines.Schedules = nil
+		mustParseFile(writeFile(t, name, content))
+
+		if config.Postgres.ConnectTimeout != 3*time.Second || !config.Postgres.AutoMigrate {
+			t.Fatalf("%s: unexpected postgres config %+v", name, config.Postgres)
+		}
+		if config.HTTP.ListenAddress != "0.0.0.0:5082" || config.HTTP.MaxListSize != 500 {
+			t.Fatalf("%s: unexpected http config %+v", name, config.HTTP)
+		}
+		expected := map[string]config.FeeSchedule{
+			"standard": {
+				DailyRate: 25,
+				Holidays: []time.Time{
+					time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC),
+					time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		}
+		if !reflect.DeepEqual(config.Fines.Schedules, expected) {
+			t.Fatalf("%s: expected fee schedules %+v but got %+v", name, expected, config.Fines.Schedules)
+		}
+	}
+}
+
+func TestMustParseFileErrors(t *testing.T) {
+	expected := []struct {
+		line int
+		key  string
+	}{
+		{3, "http.max_list_size"},
+		{4, "http.bulk_timeout"},
+		{5, "http.listen_adress"},
+	}
+	for name, content := range map[string]string{
+		"library.yaml": `
+http:
+  max_list_size: lots
+  bulk_timeout: 10
+  listen_adress: 0.0.0.0:5082
+`,
+		"library.toml": `
+[http]
+max_list_size = "lots"
+bulk_timeout = 10
+listen_adress = "0.0.0.0:5082"
+`,
+		"library.json": `{
+  "http": {
+    "max_list_size": "lots",
+    "bulk_timeout": 10,
+    "listen_adress": "0.0.0.0:5082"
+  }
+}`,
+	} {
+		file := writeFile(t, name, content)
+		var actual KeyErrors
+		func() {
+			defer func() {
+				r := recover()
+				err, ok := r.(error)
+				var liberr *library.Error
+				if !ok || !errors.As(err, &liberr) || liberr.Type != library.InvalidSettings || !errors.As(err, &actual) {
+					t.Fatalf("%s: expected invalid settings but got %v", name, r)
+				}
+			}()
+			mustParseFile(file)
+		}()
+		if len(actual) != len(expected) {
+			t.Fatalf("%s: expected %d errors but got %s", name, len(expected), actual)
+		}
+		for i, e := range expected {
+			if actual[i].File != file || actual[i].Line != e.line || actual[i].Key != e.key {
+				t.Fatalf("%s: expected an error about %s on line %d but got %s", name, e.key, e.line, actual[i])
+			}
+		}
+	}
+}
+
+func TestMustParseFileSyntax(t *testing.T) {
+	for name, content := range map[string]string{
+		"library.yaml": "http:\n  max_list_size: [\n",
+		"library.toml": "[http\n",
+		"library.json": `{"http": }`,
+		"library.ini":  "[http]\n",
+	} {
+		func() {
+			defer func() {
+				r := recover()
+				err, ok := r.(error)
+				var liberr *library.Error
+				if !ok || !errors.As(err, &liberr) || liberr.Type != library.InvalidSettings {
+					t.Fatalf("%s: expected invalid settings but got %v", name, r)
+				}
+			}()
+			mustParseFile(writeFile(t, name, content))
+		}()
+	}
+}
+
+func TestPrintRoundTrip(t *testing.T) {
+	config.Fines.Schedules = map[string]config.FeeSchedule{
+		"standard": {
+			DailyRate:   25,
+			GracePeriod: 72 * time.Hour,
+			Cap:         1000,
+			Holidays:    []time.Time{time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	config.HTTP.MaxListSize = 42
+	config.Webhooks.Timeout = 90 * time.Second
+	var printed bytes.Buffer
+	err := config.Print(&printed)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := config.Fines.Schedules
+	config.Fines.Schedules = nil
+	config.HTTP.MaxListSize = 0
+	config.Webhooks.Timeout = 0
+	mustParseFile(writeFile(t, "library.yaml", printed.String()))
+	if config.HTTP.MaxListSize != 42 || config.Webhooks.Timeout != 90*time.Second || !reflect.DeepEqual(config.Fines.Schedules, expected) {
+		t.Fatalf("expected the printed config to be read back but got:\n%s", printed.String())
+	}
+}
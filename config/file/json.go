@@ -0,0 +1,114 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// lineAt returns the line of an offset in data.
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+func parseJSON(data []byte) (map[string]setting, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	settings := make(map[string]setting)
+	err := readJSON(decoder, data, "", 1, settings)
+	if err == nil {
+		_, err = decoder.Token()
+		if err == nil {
+			err = fmt.Errorf("line %d: unexpected data after the settings", lineAt(data, decoder.InputOffset()))
+		} else if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return nil, fmt.Errorf("line %d: %s", lineAt(data, syntaxErr.Offset), syntaxErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if root, ok := settings[""]; ok {
+		return nil, fmt.Errorf("line %d: expected an object of settings", root.line)
+	}
+	return settings, nil
+}
+
+// readJSON reads the value of key, on line, into settings, flattening
+// objects.
+func readJSON(decoder *json.Decoder, data []byte, key string, line int, settings map[string]setting) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != json.Delim('{') {
+		value, err := jsonValue(decoder, token)
+		if err != nil {
+			return err
+		}
+		if value != nil {
+			settings[key] = setting{line: line, value: value}
+		}
+		return nil
+	}
+	for decoder.More() {
+		name, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		err = readJSON(decoder, data, joinKey(key, name.(string)), lineAt(data, decoder.InputOffset()), settings)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = decoder.Token()
+	return err
+}
+
+// jsonValue reads the rest of a value that starts with token.
+func jsonValue(decoder *json.Decoder, token json.Token) (interface{}, error) {
+	switch token {
+	case json.Delim('['):
+		values := []interface{}{}
+		for decoder.More() {
+			next, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := jsonValue(decoder, next)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		_, err := decoder.Token()
+		return values, err
+	case json.Delim('{'):
+		values := make(map[string]interface{})
+		for decoder.More() {
+			name, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			next, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			values[name.(string)], err = jsonValue(decoder, next)
+			if err != nil {
+				return nil, err
+			}
+		}
+		_, err := decoder.Token()
+		return values, err
+	}
+	return token, nil
+}
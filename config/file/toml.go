@@ -0,0 +1,95 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+)
+
+func parseTOML(data []byte) (map[string]setting, error) {
+	var values map[string]interface{}
+	err := toml.Unmarshal(data, &values)
+	if err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			row, _ := decodeErr.Position()
+			return nil, fmt.Errorf("line %d: %s", row, decodeErr)
+		}
+		return nil, err
+	}
+	settings := make(map[string]setting)
+	readTOML(values, "", tomlLines(data), settings)
+	return settings, nil
+}
+
+// readTOML flattens a table into settings under key.
+func readTOML(table map[string]interface{}, key string, lines map[string]int, settings map[string]setting) {
+	for name, value := range table {
+		if inner, ok := value.(map[string]interface{}); ok {
+			readTOML(inner, joinKey(key, name), lines, settings)
+			continue
+		}
+		settings[joinKey(key, name)] = setting{line: lines[joinKey(key, name)], value: fromTOML(value)}
+	}
+}
+
+// fromTOML makes local dates times, like they are in YAML.
+func fromTOML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case toml.LocalDate:
+		return v.AsTime(time.UTC)
+	case []interface{}:
+		for i := range v {
+			v[i] = fromTOML(v[i])
+		}
+	}
+	return value
+}
+
+// tomlLines returns the line every key is first on, since decoding TOML
+// doesn't keep them.
+func tomlLines(data []byte) map[string]int {
+	lines := make(map[string]int)
+	var p unstable.Parser
+	p.Reset(data)
+	var table string
+	for p.NextExpression() {
+		expr := p.Expression()
+		switch expr.Kind {
+		case unstable.Table, unstable.ArrayTable:
+			table = tomlKey(&p, "", expr.Key(), lines)
+		case unstable.KeyValue:
+			tomlKeyValue(&p, table, expr, lines)
+		}
+	}
+	return lines
+}
+
+// tomlKeyValue records the lines of the key of a key value and of the keys
+// of its value if it is an inline table.
+func tomlKeyValue(p *unstable.Parser, table string, kv *unstable.Node, lines map[string]int) {
+	key := tomlKey(p, table, kv.Key(), lines)
+	if kv.Value().Kind != unstable.InlineTable {
+		return
+	}
+	it := kv.Value().Children()
+	for it.Next() {
+		tomlKeyValue(p, key, it.Node(), lines)
+	}
+}
+
+// tomlKey joins a dotted key to prefix, recording the line of every part.
+func tomlKey(p *unstable.Parser, prefix string, it unstable.Iterator, lines map[string]int) string {
+	key := prefix
+	for it.Next() {
+		part := it.Node()
+		key = joinKey(key, string(part.Data))
+		if _, ok := lines[key]; !ok {
+			lines[key] = p.Shape(part.Raw).Start.Line
+		}
+	}
+	return key
+}
@@ -0,0 +1,49 @@
+package file
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// watchInterval is how often Watch checks the config file.
+var watchInterval = 2 * time.Second
+
+// stamp tells whether a file changed. It is empty if the file can't be
+// read.
+type stamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func stampOf(file string) stamp {
+	info, err := os.Stat(file)
+	if err != nil {
+		return stamp{}
+	}
+	return stamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+// Watch calls changed whenever the config file is written, replaced or
+// removed, until ctx is done. It does nothing if no config file is given.
+func Watch(ctx context.Context, changed func()) {
+	file := name()
+	if file == "" {
+		return
+	}
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	last := stampOf(file)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s := stampOf(file)
+		if s != last {
+			last = s
+			changed()
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package file
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	file := writeFile(t, "library.yaml", "http:\n  max_list_size: 500\n")
+	saved, interval := *path, watchInterval
+	defer func() { *path, watchInterval = saved, interval }()
+	*path, watchInterval = file, time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan struct{}, 1)
+	go Watch(ctx, func() {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	})
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case <-changes:
+		t.Fatalf("expected no change before the file is written")
+	default:
+	}
+	err := os.WriteFile(file, []byte("http:\n  max_list_size: 50\n"), 0o600)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	select {
+	case <-changes:
+	case <-time.After(time.Second):
+		t.Fatalf("expected a change once the file is written")
+	}
+}
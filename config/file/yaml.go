@@ -0,0 +1,69 @@
+package file
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+func parseYAML(data []byte) (map[string]setting, error) {
+	var doc yaml.Node
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, err
+	}
+	settings := make(map[string]setting)
+	if len(doc.Content) == 0 {
+		return settings, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: expected a mapping of settings", root.Line)
+	}
+	err = readYAML(root, "", settings)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// readYAML flattens a mapping into settings under key. Null values are left
+// unset.
+func readYAML(mapping *yaml.Node, key string, settings map[string]setting) error {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		name, node := mapping.Content[i], mapping.Content[i+1]
+		for node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+		if node.Kind == yaml.MappingNode {
+			err := readYAML(node, joinKey(key, name.Value), settings)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		var value interface{}
+		err := node.Decode(&value)
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			continue
+		}
+		settings[joinKey(key, name.Value)] = setting{line: name.Line, value: fromYAML(value)}
+	}
+	return nil
+}
+
+// fromYAML makes integers int64s, like they are in the other formats.
+func fromYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case []interface{}:
+		for i := range v {
+			v[i] = fromYAML(v[i])
+		}
+	}
+	return value
+}
@@ -0,0 +1,115 @@
+// Package flags sets config values from command-line flags. Every config
+// value has a flag named by its struct path, e.g. --http-max-list-size for
+// config.HTTP.MaxListSize. Flags are parsed by the command with flag.Parse
+// and set the config values when the config is parsed, after every other
+// source, so that they override them.
+package flags
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/slcjordan/library/config"
+)
+
+// values are the flags of the config values.
+var values []*value
+
+func init() {
+	for _, field := range config.Fields() {
+		v := &value{field: field}
+		values = append(values, v)
+		flag.Var(v, Name(field), "sets "+field.Path())
+	}
+	flag.Usage = Usage
+	config.Register(2, MustParse)
+}
+
+// Name returns the flag of a config value.
+func Name(field config.Field) string {
+	words := append(config.Words(field.Section), config.Words(field.Name)...)
+	return strings.Join(words, "-")
+}
+
+// value is the flag of a config value. It keeps what it was set to until
+// MustParse so that the other sources can't override it.
+type value struct {
+	field config.Field
+	set   func()
+}
+
+// typeName names the type of value in the usage.
+func (v *value) typeName() string {
+	switch v.field.Value.(type) {
+	case *time.Duration:
+		return "duration"
+	case *bool:
+		return ""
+	}
+	return reflect.TypeOf(v.field.Value).Elem().Name()
+}
+
+func (v *value) String() string {
+	if v == nil || v.field.Value == nil {
+		return ""
+	}
+	return fmt.Sprint(reflect.ValueOf(v.field.Value).Elem().Interface())
+}
+
+func (v *value) Set(s string) error {
+	parsed, err := v.field.Parse(s)
+	if err != nil {
+		return err
+	}
+	v.set = func() { reflect.ValueOf(v.field.Value).Elem().Set(parsed) }
+	return nil
+}
+
+// IsBoolFlag lets bool flags be given without a value.
+func (v *value) IsBoolFlag() bool {
+	_, ok := v.field.Value.(*bool)
+	return ok
+}
+
+// Usage prints the flags of the command, with the default and the env-var
+// of every config value.
+func Usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage of %s:\n", os.Args[0])
+	flag.VisitAll(func(f *flag.Flag) {
+		name, usage := flag.UnquoteUsage(f)
+		v, ok := f.Value.(*value)
+		if ok {
+			name = v.typeName()
+		}
+		fmt.Fprintf(out, "  --%s", f.Name)
+		if name != "" {
+			fmt.Fprintf(out, " %s", name)
+		}
+		fmt.Fprintf(out, "\n    \t%s", strings.ReplaceAll(usage, "\n", "\n    \t"))
+		if !ok {
+			if f.DefValue != "" {
+				fmt.Fprintf(out, " (default %q)", f.DefValue)
+			}
+			fmt.Fprintln(out)
+			return
+		}
+		if v.field.Default != "" {
+			fmt.Fprintf(out, " (default %s)", v.field.Default)
+		}
+		fmt.Fprintf(out, "\n    \tenv %s\n", v.field.EnvVar)
+	})
+}
+
+// MustParse sets the config values of the flags that were given.
+func MustParse() {
+	for _, v := range values {
+		if v.set != nil {
+			v.set()
+		}
+	}
+}
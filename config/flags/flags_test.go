@@ -0,0 +1,60 @@
+package flags
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library/config"
+)
+
+func TestMustParse(t *testing.T) {
+	postgres, storage, http := config.Postgres, config.Storage, config.HTTP
+	defer func() {
+		config.Postgres, config.Storage, config.HTTP = postgres, storage, http
+		for _, v := range values {
+			v.set = nil
+		}
+	}()
+	err := flag.CommandLine.Parse([]string{"--http-max-list-size=7", "--postgres-auto-migrate", "--postgres-connect-timeout", "3s", "--storage-sqlite-path", "library.db"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if config.HTTP.MaxListSize != 0 {
+		t.Fatalf("expected flags not to be set until the config is parsed")
+	}
+	// values from the env-vars
+	config.HTTP.MaxListSize = 500
+	config.HTTP.BulkTimeout = time.Minute
+	MustParse()
+	if config.HTTP.MaxListSize != 7 || !config.Postgres.AutoMigrate || config.Postgres.ConnectTimeout != 3*time.Second ||
+		config.Storage.SQLitePath != "library.db" || config.HTTP.BulkTimeout != time.Minute {
+		t.Fatalf("unexpected config %+v %+v %+v", config.HTTP, config.Postgres, config.Storage)
+	}
+
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	set.SetOutput(&bytes.Buffer{})
+	set.Var(&value{field: config.Field{Value: new(int32)}}, "int", "")
+	err = set.Parse([]string{"--int", "lots"})
+	if err == nil {
+		t.Fatalf("expected an error for an invalid int32")
+	}
+}
+
+func TestUsage(t *testing.T) {
+	var out bytes.Buffer
+	flag.CommandLine.SetOutput(&out)
+	defer flag.CommandLine.SetOutput(nil)
+	Usage()
+	for _, expected := range []string{
+		"  --http-max-list-size int32\n    \tsets HTTP.MaxListSize (default 500)\n    \tenv LIBRARY_HTTP_MAX_LIST_SIZE\n",
+		"  --postgres-auto-migrate\n    \tsets Postgres.AutoMigrate\n    \tenv LIBRARY_PG_AUTO_MIGRATE\n",
+		"  --webhooks-max-retry-backoff duration\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Fatalf("expected the usage to contain %q but got:\n%s", expected, out.String())
+		}
+	}
+}
@@ -1,19 +1,61 @@
 package config
 
+import "github.com/slcjordan/library"
+
 var parsers [3][]func()
 
 // Register a parser. This follows compile-time plugin pattern. f is expected
 // to directly read and write config values and won't be called concurrent to
-// any other caller.
+// any other caller. Parsers of a higher priority run later, so the values
+// they set override those of lower priorities. f is called again whenever
+// the config is reloaded.
 func Register(priority int, f func()) {
 	parsers[priority] = append(parsers[priority], f)
 }
 
-// MustParse the config.
+// MustParse the config and put it in effect. Fields start at their defaults.
+// Once every parser has run, references to secrets are read and the config
+// is validated.
 func MustParse() {
+	mu.Lock()
+	defer mu.Unlock()
+	mustParse()
+	current.Store(take())
+}
+
+// MustParseUnvalidated is MustParse without validating the config, for
+// commands that only need part of it or that report what is wrong with it.
+// Validate returns the problems.
+func MustParseUnvalidated() {
+	mu.Lock()
+	defer mu.Unlock()
+	mustParseUnvalidated()
+	current.Store(take())
+}
+
+// mustParse sets the config vars from every parser and validates them.
+func mustParse() {
+	mustParseUnvalidated()
+	err := Validate()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// mustParseUnvalidated sets the config vars from every parser.
+func mustParseUnvalidated() {
+	setDefaults()
 	for priority := range parsers {
 		for _, f := range parsers[priority] {
 			f()
 		}
 	}
+	errs := resolveSecrets()
+	if len(errs) > 0 {
+		panic(&library.Error{
+			Actual: errs,
+			Desc:   "while reading secrets",
+			Type:   library.InvalidSettings,
+		})
+	}
 }
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// redacted replaces secrets printed by Print.
+const redacted = "xxxxx"
+
+// dsnPassword matches the password of a key=value connection string.
+var dsnPassword = regexp.MustCompile(`(password=)('(\\.|[^'])*'|\S*)`)
+
+// redact hides the secret of a field.
+func (f Field) redact(s string) string {
+	switch f.tag.Get("secret") {
+	case "":
+		return s
+	case "password":
+		u, err := url.Parse(s)
+		if err == nil && u.Scheme != "" {
+			// a URL can carry the password as a parameter instead.
+			q := u.Query()
+			if q.Has("password") {
+				q.Set("password", redacted)
+				u.RawQuery = q.Encode()
+			}
+			return u.Redacted()
+		}
+		return dsnPassword.ReplaceAllString(s, "${1}"+redacted)
+	}
+	if s == "" {
+		return s
+	}
+	return redacted
+}
+
+// key returns the snake cased key of a name in a config file.
+func key(name string) string {
+	return strings.Join(Words(name), "_")
+}
+
+// Print writes the config as YAML, in the form the config file is read in,
+// with secrets redacted.
+func Print(w io.Writer) error {
+	out := bufio.NewWriter(w)
+	for _, section := range sections {
+		if section.name == "Fines" {
+			continue
+		}
+		fmt.Fprintf(out, "%s:\n", key(section.name))
+		for _, f := range Fields() {
+			if f.Section != section.name {
+				continue
+			}
+			value := reflect.ValueOf(f.Value).Elem().Interface()
+			if s, ok := value.(string); ok {
+				value = strconv.Quote(f.redact(s))
+			}
+			fmt.Fprintf(out, "  %s: %v\n", key(f.Name), value)
+		}
+	}
+	printFines(out)
+	return out.Flush()
+}
+
+// printFines writes the fee schedules by patron class.
+func printFines(out io.Writer) {
+	if len(Fines.Schedules) == 0 {
+		fmt.Fprintln(out, "fines: {}")
+		return
+	}
+	fmt.Fprintln(out, "fines:")
+	classes := make([]string, 0, len(Fines.Schedules))
+	for class := range Fines.Schedules {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		schedule := Fines.Schedules[class]
+		holidays := make([]string, 0, len(schedule.Holidays))
+		for _, h := range schedule.Holidays {
+			holidays = append(holidays, h.Format("2006-01-02"))
+		}
+		fmt.Fprintf(out, "  %s:\n", strconv.Quote(class))
+		fmt.Fprintf(out, "    daily_rate: %d\n", schedule.DailyRate)
+		fmt.Fprintf(out, "    grace_period: %s\n", schedule.GracePeriod)
+		fmt.Fprintf(out, "    cap: %d\n", schedule.Cap)
+		fmt.Fprintf(out, "    holidays: [%s]\n", strings.Join(holidays, ", "))
+	}
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/slcjordan/library/log"
+)
+
+// A Snapshot is a copy of the config vars as they were when the config was
+// last parsed. Snapshots are never changed, so they can be read while the
+// config is reloaded.
+type Snapshot struct {
+	Postgres    PostgresSettings
+	Storage     StorageSettings
+	HTTP        HTTPSettings
+	Trash       TrashSettings
+	Webhooks    WebhooksSettings
+	Circulation CirculationSettings
+	Fines       FinesSettings
+	Log         LogSettings
+}
+
+// mu is held while the config vars are written.
+var mu sync.Mutex
+
+// current holds the *Snapshot in effect.
+var current atomic.Value
+
+var observers []func(old *Snapshot, current *Snapshot)
+
+var watchers []func(ctx context.Context, changed func())
+
+// take copies the config vars.
+func take() *Snapshot {
+	return &Snapshot{
+		Postgres:    Postgres,
+		Storage:     Storage,
+		HTTP:        HTTP,
+		Trash:       Trash,
+		Webhooks:    Webhooks,
+		Circulation: Circulation,
+		Fines:       Fines,
+		Log:         Log,
+	}
+}
+
+// restore sets the config vars back to s.
+func (s *Snapshot) restore() {
+	Postgres = s.Postgres
+	Storage = s.Storage
+	HTTP = s.HTTP
+	Trash = s.Trash
+	Webhooks = s.Webhooks
+	Circulation = s.Circulation
+	Fines = s.Fines
+	Log = s.Log
+}
+
+// Current returns the config in effect. The config vars are only written
+// while the config is parsed, so code that runs alongside a reload reads the
+// config from here instead.
+func Current() *Snapshot {
+	s, ok := current.Load().(*Snapshot)
+	if !ok {
+		return &Snapshot{}
+	}
+	return s
+}
+
+// Update sets config values with f and puts them in effect without
+// validating them, e.g. for tests.
+func Update(f func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	f()
+	current.Store(take())
+}
+
+// Observe calls f with the config before and after every reload. f must not
+// reload the config.
+func Observe(f func(old *Snapshot, current *Snapshot)) {
+	mu.Lock()
+	defer mu.Unlock()
+	observers = append(observers, f)
+}
+
+// RegisterWatcher registers a watcher that calls changed whenever a source
+// of the config changes, until ctx is done. This follows the compile-time
+// plugin pattern of Register.
+func RegisterWatcher(f func(ctx context.Context, changed func())) {
+	watchers = append(watchers, f)
+}
+
+// Reload parses the config again and puts it in effect. Only fields tagged
+// reload:"true" change. Any other field that changed keeps its value, and is
+// logged as needing a restart. If the config is invalid nothing changes and
+// the error is returned.
+func Reload(ctx context.Context) error {
+	mu.Lock()
+	defer mu.Unlock()
+	old := Current()
+	err := parse()
+	if err != nil {
+		old.restore()
+		return err
+	}
+	for _, path := range keep(old) {
+		log.Infof(ctx, "config: %s changed but needs a restart, keeping the value in effect", path)
+	}
+	reloaded := take()
+	current.Store(reloaded)
+	for _, f := range observers {
+		f(old, reloaded)
+	}
+	return nil
+}
+
+// parse is mustParse returning the error it panics with.
+func parse() (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		e, ok := r.(error)
+		if !ok {
+			panic(r)
+		}
+		err = e
+	}()
+	mustParse()
+	return nil
+}
+
+// keep sets the fields of the config vars that can't be reloaded back to
+// their values in old, and returns the paths of those that changed.
+func keep(old *Snapshot) []string {
+	var changed []string
+	previous := reflect.ValueOf(old).Elem()
+	for _, section := range sections {
+		v := reflect.ValueOf(section.value).Elem()
+		was := previous.FieldByName(section.name)
+		for i := 0; i < v.NumField(); i++ {
+			structField := v.Type().Field(i)
+			if structField.Tag.Get("reload") == "true" || reflect.DeepEqual(v.Field(i).Interface(), was.Field(i).Interface()) {
+				continue
+			}
+			v.Field(i).Set(was.Field(i))
+			changed = append(changed, section.name+"."+structField.Name)
+		}
+	}
+	return changed
+}
+
+// Watch reloads the config on SIGHUP and whenever a registered watcher sees
+// a change, until ctx is done. A config that can't be reloaded is logged and
+// the one in effect is kept.
+func Watch(ctx context.Context) {
+	changes := make(chan struct{}, 1)
+	changed := func() {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	}
+	for _, f := range watchers {
+		go f(ctx, changed)
+	}
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	defer signal.Stop(hangups)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangups:
+		case <-changes:
+		}
+		err := Reload(ctx)
+		if err != nil {
+			log.Infof(ctx, "config: not reloaded: %s", err)
+			continue
+		}
+		log.Infof(ctx, "config: reloaded")
+	}
+}
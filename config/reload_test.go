@@ -0,0 +1,90 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// withParser replaces the parsers with f until the test ends.
+func withParser(t *testing.T, f func()) {
+	t.Helper()
+	savedParsers, savedObservers := parsers, observers
+	t.Cleanup(func() {
+		parsers, observers = savedParsers, savedObservers
+		setDefaults()
+		current.Store(take())
+	})
+	parsers = [3][]func(){}
+	observers = nil
+	Register(1, f)
+}
+
+func TestReload(t *testing.T) {
+	listSize, address := int32(50), "localhost:5082"
+	withParser(t, func() {
+		Storage.Backend = "memory"
+		Postgres.PageTokenSecret = "hunter2"
+		HTTP.MaxListSize = listSize
+		HTTP.ListenAddress = address
+	})
+	MustParse()
+	var notified []int32
+	Observe(func(old *Snapshot, current *Snapshot) {
+		notified = append(notified, old.HTTP.MaxListSize, current.HTTP.MaxListSize)
+	})
+
+	listSize, address = 100, "localhost:5083"
+	err := Reload(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if Current().HTTP.MaxListSize != 100 {
+		t.Fatalf("expected a reloadable setting to change but got %d", Current().HTTP.MaxListSize)
+	}
+	if Current().HTTP.ListenAddress != "localhost:5082" || HTTP.ListenAddress != "localhost:5082" {
+		t.Fatalf("expected a setting that needs a restart to keep its value but got %q", Current().HTTP.ListenAddress)
+	}
+	if !reflect.DeepEqual(notified, []int32{50, 100}) {
+		t.Fatalf("expected observers to be notified of the change but got %v", notified)
+	}
+
+	listSize = 0
+	err = Reload(context.Background())
+	if err == nil {
+		t.Fatalf("expected an invalid config not to be reloaded")
+	}
+	if Current().HTTP.MaxListSize != 100 || HTTP.MaxListSize != 100 {
+		t.Fatalf("expected the config in effect to be kept but got %d", Current().HTTP.MaxListSize)
+	}
+	if len(notified) != 2 {
+		t.Fatalf("expected observers not to be notified of an invalid config")
+	}
+}
+
+func TestCurrentWhileReloading(t *testing.T) {
+	withParser(t, func() {
+		Storage.Backend = "memory"
+		Postgres.PageTokenSecret = "hunter2"
+	})
+	MustParse()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if Current().HTTP.MaxListSize != 500 {
+				t.Errorf("expected the default list size but got %d", Current().HTTP.MaxListSize)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		err := Reload(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	wg.Wait()
+}
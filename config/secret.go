@@ -0,0 +1,58 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/slcjordan/library"
+)
+
+// A SecretProvider reads secrets from where they are kept, e.g. a file or a
+// secret store. ref is what follows the name of the provider in a reference.
+type SecretProvider interface {
+	Secret(ctx context.Context, ref string) (string, error)
+}
+
+var secretProviders = make(map[string]SecretProvider)
+
+// secretTimeout limits how long it takes to read every secret.
+const secretTimeout = 10 * time.Second
+
+// RegisterSecretProvider lets secret fields reference secrets kept by p as
+// name:ref, e.g. vault:secret/data/library#connection_string. This follows
+// the compile-time plugin pattern of Register.
+func RegisterSecretProvider(name string, p SecretProvider) {
+	secretProviders[name] = p
+}
+
+// resolveSecrets replaces references in secret fields with the secrets they
+// reference. A value without the name of a registered provider before the
+// first colon is kept as it is.
+func resolveSecrets() library.FieldErrors {
+	ctx, cancel := context.WithTimeout(context.Background(), secretTimeout)
+	defer cancel()
+	var errs library.FieldErrors
+	for _, f := range Fields() {
+		value, ok := f.Value.(*string)
+		if !ok || f.tag.Get("secret") == "" {
+			continue
+		}
+		name, ref, found := strings.Cut(*value, ":")
+		provider, registered := secretProviders[name]
+		if !found || !registered {
+			continue
+		}
+		secret, err := provider.Secret(ctx, ref)
+		if err != nil {
+			errs = append(errs, library.FieldError{
+				Field:   f.Path(),
+				Message: fmt.Sprintf("while reading the secret %q: %s", *value, err),
+			})
+			continue
+		}
+		*value = secret
+	}
+	return errs
+}
@@ -0,0 +1,20 @@
+package secrets
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// File reads a secret from a local file, e.g. a Docker or Kubernetes secret.
+// A trailing newline in the file is dropped.
+type File struct{}
+
+// Secret reads the file at path.
+func (File) Secret(ctx context.Context, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r"), nil
+}
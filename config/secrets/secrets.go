@@ -0,0 +1,26 @@
+// Package secrets registers the providers that secret config values can
+// reference instead of holding the secret:
+//
+//	file:/run/secrets/pg_connection_string
+//	vault:secret/data/library#connection_string
+//
+// The Vault provider is set up by VAULT_ADDR, VAULT_TOKEN and
+// VAULT_NAMESPACE, like the Vault CLI.
+package secrets
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/slcjordan/library/config"
+)
+
+func init() {
+	config.RegisterSecretProvider("file", File{})
+	config.RegisterSecretProvider("vault", &Vault{
+		Address:   os.Getenv("VAULT_ADDR"),
+		Token:     os.Getenv("VAULT_TOKEN"),
+		Namespace: os.Getenv("VAULT_NAMESPACE"),
+		Client:    http.DefaultClient,
+	})
+}
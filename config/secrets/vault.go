@@ -0,0 +1,77 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxVaultResponseSize limits how much of a response from Vault is read.
+const maxVaultResponseSize = 1 << 20
+
+// Vault reads a secret from a key-value secrets engine of HashiCorp Vault, or
+// anything that serves its http api.
+type Vault struct {
+	Address   string
+	Token     string
+	Namespace string
+	Client    *http.Client
+}
+
+// vaultSecret is the body of a read.
+type vaultSecret struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []string               `json:"errors"`
+}
+
+// Secret reads the key of a secret given as path#key, e.g.
+// secret/data/library#connection_string. The path is the api path after
+// /v1/, so it includes data/ for version 2 of the key-value engine.
+func (v *Vault) Secret(ctx context.Context, ref string) (string, error) {
+	path, key, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || key == "" {
+		return "", fmt.Errorf("expected a reference like path#key but got %q", ref)
+	}
+	if v.Address == "" {
+		return "", fmt.Errorf("VAULT_ADDR isn't set")
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(v.Address, "/")+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	r.Header.Set("X-Vault-Token", v.Token)
+	if v.Namespace != "" {
+		r.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(r)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var body vaultSecret
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxVaultResponseSize)).Decode(&body)
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault responded %s %s", resp.Status, strings.Join(body.Errors, "; "))
+	}
+	if err != nil {
+		return "", err
+	}
+	data := body.Data
+	// version 2 of the key-value engine nests the secret in another data
+	// object next to its metadata.
+	if inner, ok := data["data"].(map[string]interface{}); ok && data["metadata"] != nil {
+		data = inner
+	}
+	secret, ok := data[key].(string)
+	if !ok {
+		return "", fmt.Errorf("%s has no string %q", path, key)
+	}
+	return secret, nil
+}
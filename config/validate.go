@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/slcjordan/library"
+)
+
+// setDefaults empties the config and sets the defaults of its fields, so a
+// value that is no longer set by any parser goes back to its default.
+func setDefaults() {
+	for _, section := range sections {
+		v := reflect.ValueOf(section.value).Elem()
+		v.Set(reflect.Zero(v.Type()))
+	}
+	for _, f := range Fields() {
+		if f.Default == "" {
+			continue
+		}
+		parsed, err := f.Parse(f.Default)
+		if err != nil {
+			panic(fmt.Errorf("the default of %s: %w", f.Path(), err))
+		}
+		reflect.ValueOf(f.Value).Elem().Set(parsed)
+	}
+}
+
+// check returns why the value of a field doesn't meet the constraints of its
+// tags.
+func (f Field) check() []string {
+	var problems []string
+	value := reflect.ValueOf(f.Value).Elem()
+	if f.tag.Get("required") == "true" && value.IsZero() {
+		problems = append(problems, "is required")
+	}
+	if oneof := f.tag.Get("oneof"); oneof != "" {
+		allowed := strings.Fields(oneof)
+		found := false
+		for _, a := range allowed {
+			found = found || value.String() == a
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("must be one of %s but is %q", strings.Join(allowed, ", "), value.String()))
+		}
+	}
+	if value.Kind() != reflect.Int32 && value.Kind() != reflect.Int64 {
+		return problems
+	}
+	for _, bound := range []string{"min", "max"} {
+		tag := f.tag.Get(bound)
+		if tag == "" {
+			continue
+		}
+		limit, err := f.Parse(tag)
+		if err != nil {
+			panic(fmt.Errorf("the %s of %s: %w", bound, f.Path(), err))
+		}
+		if bound == "min" && value.Int() < limit.Int() {
+			problems = append(problems, fmt.Sprintf("must be at least %v but is %v", limit, value))
+		}
+		if bound == "max" && value.Int() > limit.Int() {
+			problems = append(problems, fmt.Sprintf("must be at most %v but is %v", limit, value))
+		}
+	}
+	return problems
+}
+
+// checkStorage returns the settings the storage backend needs that are
+// missing.
+func checkStorage() library.FieldErrors {
+	var errs library.FieldErrors
+	switch {
+	case Storage.Backend == "postgres" && Postgres.ConnectionString == "":
+		errs = append(errs, library.FieldError{
+			Field:   "Postgres.ConnectionString",
+			Message: "is required by the postgres storage backend",
+		})
+	case Storage.Backend == "sqlite" && Storage.SQLitePath == "":
+		errs = append(errs, library.FieldError{
+			Field:   "Storage.SQLitePath",
+			Message: "is required by the sqlite storage backend",
+		})
+	}
+	return errs
+}
+
+// Validate checks the config against the constraints of its fields and
+// returns every problem in one InvalidSettings error.
+func Validate() error {
+	var errs library.FieldErrors
+	for _, f := range Fields() {
+		for _, problem := range f.check() {
+			errs = append(errs, library.FieldError{
+				Field:   f.Path(),
+				Message: problem,
+			})
+		}
+	}
+	errs = append(errs, checkStorage()...)
+	if len(errs) > 0 {
+		return &library.Error{
+			Actual: errs,
+			Desc:   "while validating the config",
+			Type:   library.InvalidSettings,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library"
+)
+
+func TestSetDefaults(t *testing.T) {
+	HTTP.BaseURL = "/api/v1"
+	Fines.Schedules = map[string]FeeSchedule{"standard": {DailyRate: 25}}
+	setDefaults()
+	if HTTP.MaxListSize != 500 || Storage.Backend != "postgres" || Trash.Retention != 720*time.Hour {
+		t.Fatalf("expected the defaults to be set but got %+v %+v %+v", HTTP, Storage, Trash)
+	}
+	if HTTP.BaseURL != "" || Fines.Schedules != nil {
+		t.Fatalf("expected values without defaults to be emptied")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	setDefaults()
+	Postgres.ConnectionString = "postgres://localhost/library"
+	Postgres.PageTokenSecret = "hunter2"
+	err := Validate()
+	if err != nil {
+		t.Fatalf("expected the defaults to be valid but got %s", err)
+	}
+
+	HTTP.MaxListSize = 0
+	HTTP.ListenAddress = ""
+	Circulation.LoanPeriod = -time.Hour
+	Storage.Backend = "mongo"
+	err = Validate()
+	var liberr *library.Error
+	var fieldErrors library.FieldErrors
+	if !errors.As(err, &liberr) || liberr.Type != library.InvalidSettings || !errors.As(err, &fieldErrors) {
+		t.Fatalf("expected invalid settings but got %v", err)
+	}
+	expected := []library.FieldError{
+		{Field: "Storage.Backend", Message: `must be one of postgres, sqlite, memory but is "mongo"`},
+		{Field: "HTTP.ListenAddress", Message: "is required"},
+		{Field: "HTTP.MaxListSize", Message: "must be at least 1 but is 0"},
+		{Field: "Circulation.LoanPeriod", Message: "must be at least 1s but is -1h0m0s"},
+	}
+	if len(fieldErrors) != len(expected) {
+		t.Fatalf("expected %d problems but got %s", len(expected), fieldErrors)
+	}
+	for i, e := range expected {
+		if fieldErrors[i] != e {
+			t.Fatalf("expected %+v but got %+v", e, fieldErrors[i])
+		}
+	}
+
+	setDefaults()
+	Storage.Backend = "sqlite"
+	Postgres.PageTokenSecret = "hunter2"
+	err = Validate()
+	if !errors.As(err, &fieldErrors) || len(fieldErrors) != 1 || fieldErrors[0].Field != "Storage.SQLitePath" {
+		t.Fatalf("expected the sqlite path to be required but got %v", err)
+	}
+
+	// without a secret anyone could sign page tokens.
+	for _, backend := range []string{"postgres", "sqlite", "memory"} {
+		setDefaults()
+		Storage.Backend = backend
+		Postgres.ConnectionString = "postgres://localhost/library"
+		Storage.SQLitePath = ":memory:"
+		err = Validate()
+		if !errors.As(err, &fieldErrors) || len(fieldErrors) != 1 || fieldErrors[0] != (library.FieldError{Field: "Postgres.PageTokenSecret", Message: "is required"}) {
+			t.Fatalf("%s: expected the page token secret to be required but got %v", backend, err)
+		}
+	}
+}
+
+func TestMustParseUnvalidated(t *testing.T) {
+	defer func(saved [3][]func()) { parsers = saved }(parsers)
+	parsers = [3][]func(){{func() { HTTP.MaxListSize = 0 }}}
+	defer Update(setDefaults)
+
+	MustParseUnvalidated()
+	if Current().HTTP.MaxListSize != 0 {
+		t.Fatalf("expected the invalid config to be in effect but got %+v", Current().HTTP)
+	}
+	if Validate() == nil {
+		t.Fatalf("expected the config to be invalid")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected MustParse to fail")
+		}
+	}()
+	MustParse()
+}
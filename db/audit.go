@@ -0,0 +1,193 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/db/cursor"
+	"github.com/slcjordan/library/db/sqlc"
+)
+
+// auditOrder is the order of audit page tokens, newest record first.
+const auditOrder = "-id"
+
+// auditedBook is a book row as the audit trigger records it. AuthorIDs is
+// nil in records from before authors were recorded.
+type auditedBook struct {
+	ISBN      int64      `json:"isbn"`
+	Title     string     `json:"title"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Version   int64      `json:"version"`
+	DeletedAt *time.Time `json:"deleted_at"`
+	AuthorIDs []int64    `json:"author_ids"`
+}
+
+// fromAudit decodes a book recorded by the audit trigger. It is nil if no
+// book was recorded. Its authors only have ids, since the names they had
+// then aren't recorded.
+func fromAudit(raw pgtype.JSONB) (*library.Book, error) {
+	if raw.Status != pgtype.Present {
+		return nil, nil
+	}
+	var b auditedBook
+	err := json.Unmarshal(raw.Bytes, &b)
+	if err != nil {
+		return nil, err
+	}
+	book := &library.Book{
+		ISBN:      library.ISBN(b.ISBN),
+		Title:     b.Title,
+		CreatedAt: b.CreatedAt,
+		UpdatedAt: b.UpdatedAt,
+		Version:   b.Version,
+	}
+	if b.DeletedAt != nil {
+		book.DeletedAt = *b.DeletedAt
+	}
+	if b.AuthorIDs != nil {
+		book.Authors = make([]library.Author, 0, len(b.AuthorIDs))
+	}
+	for _, id := range b.AuthorIDs {
+		book.Authors = append(book.Authors, library.Author{ID: id})
+	}
+	return book, nil
+}
+
+func toAuditRecord(a sqlc.BookAudit) (library.AuditRecord, error) {
+	record := library.AuditRecord{
+		ID:        a.ID,
+		ISBN:      library.ISBN(a.Isbn),
+		Action:    library.AuditAction(a.Action),
+		Actor:     a.Actor,
+		RequestID: a.RequestID,
+		CreatedAt: a.CreatedAt,
+	}
+	var err error
+	record.Before, err = fromAudit(a.OldBook)
+	if err != nil {
+		return library.AuditRecord{}, err
+	}
+	record.After, err = fromAudit(a.NewBook)
+	if err != nil {
+		return library.AuditRecord{}, err
+	}
+	return record, nil
+}
+
+// toAuditList only sets a next-page token when the page is full; a short
+// page is the last one.
+func toAuditList(records []sqlc.BookAudit, totalSize int32) (library.AuditList, error) {
+	var result library.AuditList
+	for _, a := range records {
+		record, err := toAuditRecord(a)
+		if err != nil {
+			return library.AuditList{}, queryError(err, "while decoding an audit record")
+		}
+		result.Records = append(result.Records, record)
+	}
+	if len(records) > 0 && len(records) == int(totalSize) {
+		token, err := cursor.Encode(cursor.Cursor{
+			Order: auditOrder,
+			ID:    records[len(records)-1].ID,
+		})
+		if err != nil {
+			return library.AuditList{}, err
+		}
+		result.NextPageToken = token
+	}
+	return result, nil
+}
+
+// decodeBeforeToken returns the id that the page of token starts before, or
+// 0 for the first page, for lists in the descending order of their ids.
+func decodeBeforeToken(token string, order string) (int64, error) {
+	before, err := cursor.Decode(token)
+	if err == nil && before != cursor.FirstPage && before.Order != order {
+		err = fmt.Errorf("page token is for order %q", before.Order)
+	}
+	if err != nil {
+		return 0, &library.Error{
+			Type:   library.BadInput,
+			Actual: err,
+			Desc:   "while decoding page token",
+		}
+	}
+	if before == cursor.FirstPage {
+		return 0, nil
+	}
+	return before.ID, nil
+}
+
+// ListBookHistory returns the audit records of a single book, newest first.
+// Purged books keep their history.
+func (q *Queryer) ListBookHistory(ctx context.Context, isbn library.ISBN, PageToken string, TotalSize int32) (library.AuditList, error) {
+	beforeID, err := decodeBeforeToken(PageToken, auditOrder)
+	if err != nil {
+		return library.AuditList{}, err
+	}
+	records, err := sqlc.New(q.DBTX).ListBookAudit(ctx, sqlc.ListBookAuditParams{
+		Isbn:      int64(isbn),
+		BeforeID:  beforeID,
+		TotalSize: TotalSize,
+	})
+	if err != nil {
+		return library.AuditList{}, queryError(err, "while retrieving the history of a book")
+	}
+	return toAuditList(records, TotalSize)
+}
+
+// ListAudit returns the audit records of every book, newest first.
+func (q *Queryer) ListAudit(ctx context.Context, PageToken string, TotalSize int32) (library.AuditList, error) {
+	beforeID, err := decodeBeforeToken(PageToken, auditOrder)
+	if err != nil {
+		return library.AuditList{}, err
+	}
+	records, err := sqlc.New(q.DBTX).ListAudit(ctx, sqlc.ListAuditParams{
+		BeforeID:  beforeID,
+		TotalSize: TotalSize,
+	})
+	if err != nil {
+		return library.AuditList{}, queryError(err, "while retrieving the audit log")
+	}
+	return toAuditList(records, TotalSize)
+}
+
+// GetBookAt reconstructs a single book as it was at a point in time from its
+// audit records. A book that was in the trash then has DeletedAt set. Its
+// authors only have ids, and are nil if they weren't recorded then.
+func (q *Queryer) GetBookAt(ctx context.Context, isbn library.ISBN, at time.Time) (library.Book, error) {
+	record, err := sqlc.New(q.DBTX).GetBookAuditAt(ctx, sqlc.GetBookAuditAtParams{
+		Isbn: int64(isbn),
+		At:   at,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return library.Book{}, &library.Error{
+			Type:   library.NotFound,
+			Actual: fmt.Errorf("no book with isbn %s at %s", isbn, at.Format(time.RFC3339)),
+			Desc:   "while reconstructing a book",
+		}
+	}
+	if err != nil {
+		return library.Book{}, queryError(err, "while reconstructing a book")
+	}
+	book, err := fromAudit(record.NewBook)
+	if err != nil {
+		return library.Book{}, queryError(err, "while decoding an audit record")
+	}
+	if book == nil {
+		return library.Book{}, &library.Error{
+			Type:   library.NotFound,
+			Actual: fmt.Errorf("book %s was purged before %s", isbn, at.Format(time.RFC3339)),
+			Desc:   "while reconstructing a book",
+		}
+	}
+	return *book, nil
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+
+	"github.com/slcjordan/library"
+)
+
+func TestFromAudit(t *testing.T) {
+	// as to_jsonb writes a book row.
+	raw := pgtype.JSONB{
+		Status: pgtype.Present,
+		Bytes:  []byte(`{"isbn": 9780306406157, "title": "Trashed", "version": 3, "created_at": "2026-10-18T09:00:00.123456+00:00", "deleted_at": "2026-10-18T11:30:00+02:00", "updated_at": "2026-10-18T09:30:00+00:00"}`),
+	}
+	book, err := fromAudit(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := library.Book{
+		ISBN:      9780306406157,
+		Title:     "Trashed",
+		Version:   3,
+		CreatedAt: time.Date(2026, 10, 18, 9, 0, 0, 123456000, time.UTC),
+		UpdatedAt: time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC),
+		DeletedAt: time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC),
+	}
+	if book == nil || book.ISBN != expected.ISBN || book.Title != expected.Title || book.Version != expected.Version ||
+		!book.CreatedAt.Equal(expected.CreatedAt) || !book.UpdatedAt.Equal(expected.UpdatedAt) || !book.DeletedAt.Equal(expected.DeletedAt) {
+		t.Fatalf("expected %+v but got %+v", expected, book)
+	}
+
+	raw.Bytes = []byte(`{"isbn": 9780306406157, "title": "Kept", "version": 1, "created_at": "2026-10-18T09:00:00+00:00", "deleted_at": null, "updated_at": "2026-10-18T09:00:00+00:00"}`)
+	book, err = fromAudit(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if book == nil || !book.DeletedAt.IsZero() || book.Authors != nil {
+		t.Fatalf("expected a book that isn't trashed and has no recorded authors but got %+v", book)
+	}
+
+	for authorIDs, expected := range map[string][]library.Author{
+		`[3, 1]`: {{ID: 3}, {ID: 1}},
+		`[]`:     {},
+		`null`:   nil,
+	} {
+		raw.Bytes = []byte(`{"isbn": 9780306406157, "title": "Credited", "version": 2, "created_at": "2026-10-18T09:00:00+00:00", "deleted_at": null, "updated_at": "2026-10-18T09:00:00+00:00", "author_ids": ` + authorIDs + `}`)
+		book, err = fromAudit(raw)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if book == nil || !reflect.DeepEqual(book.Authors, expected) {
+			t.Fatalf("expected authors %+v for %s but got %+v", expected, authorIDs, book)
+		}
+	}
+
+	book, err = fromAudit(pgtype.JSONB{Status: pgtype.Null})
+	if err != nil || book != nil {
+		t.Fatalf("expected no book but got %+v, %v", book, err)
+	}
+}
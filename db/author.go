@@ -0,0 +1,241 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/db/cursor"
+	"github.com/slcjordan/library/db/sqlc"
+)
+
+// authorOrder is the order of author lists.
+const authorOrder = "name"
+
+// authorBooksOrder is the order of the book lists of an author, which are
+// ordered by title but don't share page tokens with book lists.
+const authorBooksOrder = "author_books"
+
+// ListAuthors returns a list of authors ordered by name.
+func (q *Queryer) ListAuthors(ctx context.Context, PageToken string, TotalSize int32) (library.AuthorList, error) {
+	after, err := cursor.Decode(PageToken)
+	if err == nil && after != cursor.FirstPage && after.Order != authorOrder {
+		err = fmt.Errorf("page token is for order %q", after.Order)
+	}
+	if err != nil {
+		return library.AuthorList{}, &library.Error{
+			Type:   library.BadInput,
+			Actual: err,
+			Desc:   "while decoding page token",
+		}
+	}
+	params := sqlc.ListAuthorsParams{
+		AfterName: after.Key,
+		AfterID:   after.ID,
+		TotalSize: TotalSize,
+	}
+	authors, err := sqlc.New(q.DBTX).ListAuthors(ctx, params)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return library.AuthorList{}, &library.Error{
+				Type:   library.Timeout,
+				Actual: err,
+				Desc:   "while retrieving a list of authors",
+			}
+		}
+		return library.AuthorList{}, &library.Error{
+			Type:   library.DatabaseError,
+			Actual: err,
+			Desc:   "while retrieving a list of authors",
+		}
+	}
+	var result library.AuthorList
+	for _, a := range authors {
+		result.Authors = append(result.Authors, library.Author{
+			ID:   a.ID,
+			Name: a.Name,
+		})
+	}
+	if len(authors) > 0 && len(authors) == int(TotalSize) {
+		last := authors[len(authors)-1]
+		result.NextPageToken, err = cursor.Encode(cursor.Cursor{
+			Order: authorOrder,
+			Key:   last.Name,
+			ID:    last.ID,
+		})
+		if err != nil {
+			return library.AuthorList{}, err
+		}
+	}
+	return result, nil
+}
+
+// ListAuthorBooks returns a list of books by a single author ordered by title.
+func (q *Queryer) ListAuthorBooks(ctx context.Context, authorID int64, PageToken string, TotalSize int32) (library.BookList, error) {
+	after, err := cursor.Decode(PageToken)
+	if err == nil && after != cursor.FirstPage && after.Order != authorBooksOrder {
+		err = fmt.Errorf("page token is for order %q", after.Order)
+	}
+	if err != nil {
+		return library.BookList{}, &library.Error{
+			Type:   library.BadInput,
+			Actual: err,
+			Desc:   "while decoding page token",
+		}
+	}
+
+	// an author without books and a missing author look the same to the
+	// list query.
+	_, err = q.GetAuthor(ctx, authorID)
+	if err != nil {
+		return library.BookList{}, err
+	}
+
+	params := sqlc.ListAuthorBooksParams{
+		AuthorID:   authorID,
+		AfterTitle: after.Key,
+		AfterIsbn:  after.ID,
+		TotalSize:  TotalSize,
+	}
+	books, err := sqlc.New(q.DBTX).ListAuthorBooks(ctx, params)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return library.BookList{}, &library.Error{
+				Type:   library.Timeout,
+				Actual: err,
+				Desc:   "while retrieving a list of books by an author",
+			}
+		}
+		return library.BookList{}, &library.Error{
+			Type:   library.DatabaseError,
+			Actual: err,
+			Desc:   "while retrieving a list of books by an author",
+		}
+	}
+	return toBookList(books, authorBooksOrder, TotalSize)
+}
+
+// CreateAuthor creates a single author and returns it with its new id.
+func (q *Queryer) CreateAuthor(ctx context.Context, author library.Author) (library.Author, error) {
+	created, err := sqlc.New(q.DBTX).CreateAuthor(ctx, author.Name)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return library.Author{}, &library.Error{
+				Type:   library.Timeout,
+				Actual: err,
+				Desc:   "while creating an author",
+			}
+		}
+		return library.Author{}, &library.Error{
+			Type:   library.DatabaseError,
+			Actual: err,
+			Desc:   "while creating an author",
+		}
+	}
+	return library.Author{
+		ID:   created.ID,
+		Name: created.Name,
+	}, nil
+}
+
+// DeleteAuthor deletes a single author. Authors of existing books can't be
+// deleted.
+func (q *Queryer) DeleteAuthor(ctx context.Context, id int64) error {
+	count, err := sqlc.New(q.DBTX).DeleteAuthor(ctx, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			return &library.Error{
+				Type:   library.Conflict,
+				Actual: err,
+				Desc:   "the author still has books",
+			}
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return &library.Error{
+				Type:   library.Timeout,
+				Actual: err,
+				Desc:   "while deleting an author",
+			}
+		}
+		return &library.Error{
+			Type:   library.DatabaseError,
+			Actual: err,
+			Desc:   "while deleting an author",
+		}
+	}
+	if count == 0 {
+		return &library.Error{
+			Type:   library.NotFound,
+			Actual: fmt.Errorf("no author with id %d", id),
+			Desc:   "while deleting an author",
+		}
+	}
+	return nil
+}
+
+// GetAuthor fetches a single author.
+func (q *Queryer) GetAuthor(ctx context.Context, id int64) (library.Author, error) {
+	author, err := sqlc.New(q.DBTX).GetAuthor(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return library.Author{}, &library.Error{
+				Type:   library.NotFound,
+				Actual: fmt.Errorf("no author with id %d", id),
+				Desc:   "while fetching an author",
+			}
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return library.Author{}, &library.Error{
+				Type:   library.Timeout,
+				Actual: err,
+				Desc:   "while fetching an author",
+			}
+		}
+		return library.Author{}, &library.Error{
+			Type:   library.DatabaseError,
+			Actual: err,
+			Desc:   "while fetching an author",
+		}
+	}
+	return library.Author{
+		ID:   author.ID,
+		Name: author.Name,
+	}, nil
+}
+
+// UpdateAuthor updates a single author.
+func (q *Queryer) UpdateAuthor(ctx context.Context, author library.Author) error {
+	params := sqlc.UpdateAuthorParams{
+		ID:   author.ID,
+		Name: author.Name,
+	}
+	count, err := sqlc.New(q.DBTX).UpdateAuthor(ctx, params)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return &library.Error{
+				Type:   library.Timeout,
+				Actual: err,
+				Desc:   "while updating an author",
+			}
+		}
+		return &library.Error{
+			Type:   library.DatabaseError,
+			Actual: err,
+			Desc:   "while updating an author",
+		}
+	}
+	if count == 0 {
+		return &library.Error{
+			Type:   library.NotFound,
+			Actual: fmt.Errorf("no author with id %d", author.ID),
+			Desc:   "while updating an author",
+		}
+	}
+	return nil
+}
@@ -2,12 +2,18 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4"
 
 	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/db/cursor"
 	"github.com/slcjordan/library/db/sqlc"
 	"github.com/slcjordan/library/log"
 )
@@ -16,14 +22,34 @@ type Queryer struct {
 	DBTX DBTX
 }
 
-// ListBooks returns a list of books.
-func (q *Queryer) ListBooks(ctx context.Context, PageToken string, TotalSize int32) (library.BookList, error) {
-	params := sqlc.ListBooksParams{
-		PageToken: PageToken,
-		TotalSize: TotalSize,
+// ListBooks returns a filtered list of books in the order the filter asks
+// for. The page token is opaque and only valid if it came from a previous call
+// with the same order.
+func (q *Queryer) ListBooks(ctx context.Context, filter library.BookFilter, PageToken string, TotalSize int32) (library.BookList, error) {
+	if filter.OrderBy == "" {
+		filter.OrderBy = library.ByTitle
 	}
-	books, err := sqlc.New(q.DBTX).ListBooks(ctx, params)
+	order := string(filter.OrderBy)
+	if filter.Descending {
+		order = "-" + order
+	}
+	after, err := cursor.Decode(PageToken)
+	if err == nil && after != cursor.FirstPage && after.Order != order {
+		err = fmt.Errorf("page token is for order %q but the list is in order %q", after.Order, order)
+	}
+	if err != nil {
+		return library.BookList{}, &library.Error{
+			Type:   library.BadInput,
+			Actual: err,
+			Desc:   "while decoding page token",
+		}
+	}
+	books, err := listBooks(ctx, sqlc.New(q.DBTX), filter, after, TotalSize)
 	if err != nil {
+		var libErr *library.Error
+		if errors.As(err, &libErr) {
+			return library.BookList{}, err
+		}
 		if errors.Is(err, context.DeadlineExceeded) {
 			return library.BookList{}, &library.Error{
 				Type:   library.Timeout,
@@ -37,40 +63,186 @@ func (q *Queryer) ListBooks(ctx context.Context, PageToken string, TotalSize int
 			Desc:   "while retrieving a list of books",
 		}
 	}
-	return toBookList(books), nil
+	return toBookList(books, order, TotalSize)
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
 }
 
-func toBookList(books []sqlc.Book) library.BookList {
+func nullISBN(isbn library.ISBN) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(isbn), Valid: isbn != 0}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// listBooks runs the query for the order of the filter. Every order has its
+// own query so that it can walk its own index.
+func listBooks(ctx context.Context, queries *sqlc.Queries, filter library.BookFilter, after cursor.Cursor, totalSize int32) ([]sqlc.Book, error) {
+	first := after == cursor.FirstPage
+	switch filter.OrderBy {
+	case library.ByTitle:
+		params := sqlc.ListBooksByTitleParams{
+			TitlePrefix:    nullString(filter.TitlePrefix),
+			MinIsbn:        nullISBN(filter.MinISBN),
+			MaxIsbn:        nullISBN(filter.MaxISBN),
+			CreatedAfter:   nullTime(filter.CreatedAfter),
+			CreatedBefore:  nullTime(filter.CreatedBefore),
+			UpdatedAfter:   nullTime(filter.UpdatedAfter),
+			UpdatedBefore:  nullTime(filter.UpdatedBefore),
+			AfterTitle:     sql.NullString{String: after.Key, Valid: !first},
+			AfterIsbn:      after.ID,
+			TotalSize:      totalSize,
+			IncludeDeleted: filter.IncludeDeleted,
+		}
+		if filter.Descending {
+			return queries.ListBooksByTitleDesc(ctx, sqlc.ListBooksByTitleDescParams(params))
+		}
+		return queries.ListBooksByTitle(ctx, params)
+	case library.ByISBN:
+		params := sqlc.ListBooksByIsbnParams{
+			TitlePrefix:    nullString(filter.TitlePrefix),
+			MinIsbn:        nullISBN(filter.MinISBN),
+			MaxIsbn:        nullISBN(filter.MaxISBN),
+			CreatedAfter:   nullTime(filter.CreatedAfter),
+			CreatedBefore:  nullTime(filter.CreatedBefore),
+			UpdatedAfter:   nullTime(filter.UpdatedAfter),
+			UpdatedBefore:  nullTime(filter.UpdatedBefore),
+			AfterIsbn:      sql.NullInt64{Int64: after.ID, Valid: !first},
+			TotalSize:      totalSize,
+			IncludeDeleted: filter.IncludeDeleted,
+		}
+		if filter.Descending {
+			return queries.ListBooksByIsbnDesc(ctx, sqlc.ListBooksByIsbnDescParams(params))
+		}
+		return queries.ListBooksByIsbn(ctx, params)
+	case library.ByCreatedAt:
+		var afterCreatedAt sql.NullTime
+		if !first {
+			parsed, err := time.Parse(time.RFC3339Nano, after.Key)
+			if err != nil {
+				return nil, &library.Error{
+					Type:   library.BadInput,
+					Actual: err,
+					Desc:   "while decoding page token",
+				}
+			}
+			afterCreatedAt = sql.NullTime{Time: parsed, Valid: true}
+		}
+		params := sqlc.ListBooksByCreatedAtParams{
+			TitlePrefix:    nullString(filter.TitlePrefix),
+			MinIsbn:        nullISBN(filter.MinISBN),
+			MaxIsbn:        nullISBN(filter.MaxISBN),
+			CreatedAfter:   nullTime(filter.CreatedAfter),
+			CreatedBefore:  nullTime(filter.CreatedBefore),
+			UpdatedAfter:   nullTime(filter.UpdatedAfter),
+			UpdatedBefore:  nullTime(filter.UpdatedBefore),
+			AfterCreatedAt: afterCreatedAt,
+			AfterIsbn:      after.ID,
+			TotalSize:      totalSize,
+			IncludeDeleted: filter.IncludeDeleted,
+		}
+		if filter.Descending {
+			return queries.ListBooksByCreatedAtDesc(ctx, sqlc.ListBooksByCreatedAtDescParams(params))
+		}
+		return queries.ListBooksByCreatedAt(ctx, params)
+	default:
+		return nil, &library.Error{
+			Type:   library.BadInput,
+			Actual: fmt.Errorf("unknown order %q", filter.OrderBy),
+			Desc:   "while listing books",
+		}
+	}
+}
+
+func toBook(b sqlc.Book) library.Book {
+	return library.Book{
+		ISBN:      library.ISBN(b.Isbn),
+		Title:     b.Title,
+		CreatedAt: b.CreatedAt,
+		UpdatedAt: b.UpdatedAt,
+		Version:   b.Version,
+		DeletedAt: b.DeletedAt.Time,
+	}
+}
+
+// toBookList only sets a next-page token when the page is full; a short page
+// is the last one. The token holds the sort key of order, which is a field
+// optionally prefixed with "-" for descending.
+func toBookList(books []sqlc.Book, order string, totalSize int32) (library.BookList, error) {
 	var result library.BookList
 	for _, b := range books {
-		result.Books = append(result.Books, library.Book{
-			ISBN:  b.Isbn,
-			Title: b.Title,
-		})
-		result.NextPageToken = b.Title // next result is after the last item
+		result.Books = append(result.Books, toBook(b))
 	}
-	return result
+	if len(books) > 0 && len(books) == int(totalSize) {
+		last := books[len(books)-1]
+		next := cursor.Cursor{
+			Order: order,
+			ID:    last.Isbn,
+		}
+		switch library.BookOrder(strings.TrimPrefix(order, "-")) {
+		case library.ByTitle, authorBooksOrder:
+			next.Key = last.Title
+		case library.ByCreatedAt:
+			next.Key = last.CreatedAt.Format(time.RFC3339Nano)
+		}
+		token, err := cursor.Encode(next)
+		if err != nil {
+			return library.BookList{}, err
+		}
+		result.NextPageToken = token
+	}
+	return result, nil
 }
 
-// CreateBook creates a single book.
+// CreateBook creates a single book. Its authors must already exist and are
+// linked in the same statement.
 func (q *Queryer) CreateBook(ctx context.Context, book library.Book) error {
+	_, err := library.NewISBN(int64(book.ISBN))
+	if err != nil {
+		return err
+	}
 	params := sqlc.CreateBookParams{
-		Isbn:  book.ISBN,
-		Title: book.Title,
+		Isbn:      int64(book.ISBN),
+		Title:     book.Title,
+		AuthorIds: make([]int64, 0, len(book.Authors)),
+	}
+	for _, a := range book.Authors {
+		params.AuthorIds = append(params.AuthorIds, a.ID)
 	}
 
-	err := sqlc.New(q.DBTX).CreateBook(ctx, params)
+	err = q.inTx(ctx, func(queries *sqlc.Queries) error {
+		return queries.CreateBook(ctx, params)
+	})
 	if err != nil {
+		var libErr *library.Error
+		if errors.As(err, &libErr) {
+			return err
+		}
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
-			switch pgErr.Code {
-			case pgerrcode.UniqueViolation:
-				log.Infof(ctx, "while creating book: %s", err)
+			switch {
+			case pgErr.Code == pgerrcode.UniqueViolation && pgErr.ConstraintName == "book_author_pkey":
 				return &library.Error{
 					Type:   library.BadInput,
 					Actual: err,
+					Desc:   "an author is listed more than once",
+				}
+			case pgErr.Code == pgerrcode.UniqueViolation:
+				log.Infof(ctx, "while creating book: %s", err)
+				return &library.Error{
+					Type:   library.Conflict,
+					Actual: err,
 					Desc:   "a book with that isbn already exists",
 				}
+			case pgErr.Code == pgerrcode.ForeignKeyViolation:
+				return &library.Error{
+					Type:   library.BadInput,
+					Actual: err,
+					Desc:   "an author does not exist",
+				}
 			}
 		}
 		if errors.Is(err, context.DeadlineExceeded) {
@@ -89,30 +261,271 @@ func (q *Queryer) CreateBook(ctx context.Context, book library.Book) error {
 	return nil
 }
 
-// DeleteBook deletes a single book.
-func (q *Queryer) DeleteBook(ctx context.Context, isbn int64) error {
-	err := sqlc.New(q.DBTX).DeleteBook(ctx, isbn)
-	if err != nil {
-		if errors.Is(err, context.DeadlineExceeded) {
+func authorIDs(authors []library.Author) []int64 {
+	ids := make([]int64, 0, len(authors))
+	for _, a := range authors {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+// sameAuthors reports whether two lists credit the same authors in the same
+// order.
+func sameAuthors(a []library.Author, b []library.Author) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ID != b[i].ID {
+			return false
+		}
+	}
+	return true
+}
+
+// PatchBook changes a book with patch in a transaction, so the patch is
+// applied to the latest version of the book and nothing changes if it fails.
+// If version isn't zero, the book must still be at that version. The book is
+// only moved to its next version if the patch changed it.
+func (q *Queryer) PatchBook(ctx context.Context, isbn library.ISBN, version int64, patch library.BookPatch) (library.Book, error) {
+	var result library.Book
+	err := q.inTx(ctx, func(queries *sqlc.Queries) error {
+		book, err := queries.LockBook(ctx, int64(isbn))
+		if errors.Is(err, pgx.ErrNoRows) {
 			return &library.Error{
-				Type:   library.Timeout,
-				Actual: err,
+				Type:   library.NotFound,
+				Actual: fmt.Errorf("no book with isbn %s", isbn),
+				Desc:   "while patching a book",
+			}
+		}
+		if err != nil {
+			return queryError(err, "while locking a book")
+		}
+		if version != 0 && book.Version != version {
+			return &library.Error{
+				Type:   library.PreconditionFailed,
+				Actual: fmt.Errorf("book %s is at version %d, not %d", isbn, book.Version, version),
+				Desc:   "the book changed since it was read",
+			}
+		}
+		authors, err := queries.ListBookAuthors(ctx, int64(isbn))
+		if err != nil {
+			return queryError(err, "while fetching the authors of a book")
+		}
+		current := toBook(book)
+		for _, a := range authors {
+			current.Authors = append(current.Authors, library.Author{
+				ID:   a.ID,
+				Name: a.Name,
+			})
+		}
+		patched, err := patch.Apply(current)
+		if err != nil {
+			return err
+		}
+		authorsChanged := !sameAuthors(current.Authors, patched.Authors)
+		if patched.Title == current.Title && !authorsChanged {
+			result = current
+			return nil
+		}
+		// the audit trigger records the authors as the book moves to its
+		// next version, so they change first.
+		if authorsChanged {
+			err = queries.DeleteBookAuthors(ctx, int64(isbn))
+			if err != nil {
+				return queryError(err, "while patching the authors of a book")
+			}
+			err = queries.AddBookAuthors(ctx, sqlc.AddBookAuthorsParams{
+				Isbn:      int64(isbn),
+				AuthorIds: authorIDs(patched.Authors),
+			})
+			if err != nil {
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) {
+					switch pgErr.Code {
+					case pgerrcode.UniqueViolation:
+						return &library.Error{
+							Type:   library.BadInput,
+							Actual: library.FieldErrors{{Field: "author_ids", Message: "an author is listed more than once"}},
+							Desc:   "while patching a book",
+						}
+					case pgerrcode.ForeignKeyViolation:
+						return &library.Error{
+							Type:   library.BadInput,
+							Actual: library.FieldErrors{{Field: "author_ids", Message: "an author does not exist"}},
+							Desc:   "while patching a book",
+						}
+					}
+				}
+				return queryError(err, "while patching the authors of a book")
+			}
+		}
+		updated, err := queries.UpdateBook(ctx, sqlc.UpdateBookParams{
+			Isbn:    int64(isbn),
+			Title:   patched.Title,
+			Version: sql.NullInt64{Int64: book.Version, Valid: true},
+		})
+		if err != nil {
+			return queryError(err, "while patching a book")
+		}
+		result = toBook(updated)
+		if !authorsChanged {
+			result.Authors = current.Authors
+			return nil
+		}
+		authors, err = queries.ListBookAuthors(ctx, int64(isbn))
+		if err != nil {
+			return queryError(err, "while fetching the authors of a book")
+		}
+		for _, a := range authors {
+			result.Authors = append(result.Authors, library.Author{
+				ID:   a.ID,
+				Name: a.Name,
+			})
+		}
+		return nil
+	})
+	return result, err
+}
+
+// DeleteBook moves a single book to the trash. It stays there until it is
+// restored or purged.
+func (q *Queryer) DeleteBook(ctx context.Context, isbn library.ISBN) error {
+	return q.inTx(ctx, func(queries *sqlc.Queries) error {
+		count, err := queries.TrashBook(ctx, int64(isbn))
+		if err != nil {
+			return queryError(err, "while deleting a book")
+		}
+		if count > 0 {
+			return nil
+		}
+		_, err = queries.GetBook(ctx, sqlc.GetBookParams{Isbn: int64(isbn)})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &library.Error{
+				Type:   library.NotFound,
+				Actual: fmt.Errorf("no book with isbn %s", isbn),
 				Desc:   "while deleting a book",
 			}
 		}
+		if err != nil {
+			return queryError(err, "while deleting a book")
+		}
 		return &library.Error{
-			Type:   library.DatabaseError,
-			Actual: err,
-			Desc:   "while deleting a book",
+			Type:   library.Conflict,
+			Actual: fmt.Errorf("book %s has copies or active holds", isbn),
+			Desc:   "the book still has copies or active holds",
+		}
+	})
+}
+
+// RestoreBook takes a single book out of the trash and returns it at its new
+// version.
+func (q *Queryer) RestoreBook(ctx context.Context, isbn library.ISBN) (library.Book, error) {
+	var result library.Book
+	err := q.inTx(ctx, func(queries *sqlc.Queries) error {
+		restored, err := queries.RestoreBook(ctx, int64(isbn))
+		if errors.Is(err, pgx.ErrNoRows) {
+			_, err = queries.GetBook(ctx, sqlc.GetBookParams{Isbn: int64(isbn)})
+			if errors.Is(err, pgx.ErrNoRows) {
+				return &library.Error{
+					Type:   library.NotFound,
+					Actual: fmt.Errorf("no book with isbn %s", isbn),
+					Desc:   "while restoring a book",
+				}
+			}
+			if err != nil {
+				return queryError(err, "while restoring a book")
+			}
+			return &library.Error{
+				Type:   library.Conflict,
+				Actual: fmt.Errorf("book %s isn't in the trash", isbn),
+				Desc:   "the book isn't in the trash",
+			}
+		}
+		if err != nil {
+			return queryError(err, "while restoring a book")
+		}
+		authors, err := queries.ListBookAuthors(ctx, int64(isbn))
+		if err != nil {
+			return queryError(err, "while fetching the authors of a book")
+		}
+		result = toBook(sqlc.Book(restored))
+		for _, a := range authors {
+			result.Authors = append(result.Authors, library.Author{
+				ID:   a.ID,
+				Name: a.Name,
+			})
+		}
+		return nil
+	})
+	return result, err
+}
+
+// PurgeBooks permanently deletes the books trashed before deletedBefore and
+// returns how many it deleted.
+func (q *Queryer) PurgeBooks(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var count int64
+	err := q.inTx(ctx, func(queries *sqlc.Queries) error {
+		var err error
+		count, err = queries.PurgeBooks(ctx, nullTime(deletedBefore))
+		if err != nil {
+			return queryError(err, "while purging books")
+		}
+		return nil
+	})
+	return count, err
+}
+
+// purgeActor is the actor of the audit records of purged books.
+const purgeActor = "purger"
+
+// PurgeBooksEvery calls PurgeBooks on every tick of interval until ctx is
+// done, purging the books that have been in the trash for longer than
+// retention.
+func (q *Queryer) PurgeBooksEvery(ctx context.Context, interval time.Duration, retention time.Duration) {
+	ctx = library.WithActor(ctx, purgeActor)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		purged, err := q.PurgeBooks(ctx, time.Now().Add(-retention))
+		if err != nil {
+			log.Errorf(ctx, "while purging books: %s", err)
+		}
+		if purged > 0 {
+			log.Infof(ctx, "purged %d books", purged)
 		}
 	}
-	return nil
 }
 
-// GetBook fetches a single book.
-func (q *Queryer) GetBook(ctx context.Context, isbn int64) (library.Book, error) {
-	book, err := sqlc.New(q.DBTX).GetBook(ctx, isbn)
+// GetBook fetches a single book and its authors. Trashed books aren't found.
+func (q *Queryer) GetBook(ctx context.Context, isbn library.ISBN) (library.Book, error) {
+	return q.getBook(ctx, isbn, false)
+}
+
+// GetBookIncludingDeleted fetches a single book and its authors, even if the
+// book is in the trash.
+func (q *Queryer) GetBookIncludingDeleted(ctx context.Context, isbn library.ISBN) (library.Book, error) {
+	return q.getBook(ctx, isbn, true)
+}
+
+func (q *Queryer) getBook(ctx context.Context, isbn library.ISBN, includeDeleted bool) (library.Book, error) {
+	book, err := sqlc.New(q.DBTX).GetBook(ctx, sqlc.GetBookParams{
+		Isbn:           int64(isbn),
+		IncludeDeleted: includeDeleted,
+	})
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return library.Book{}, &library.Error{
+				Type:   library.NotFound,
+				Actual: fmt.Errorf("no book with isbn %s", isbn),
+				Desc:   "while fetching a book",
+			}
+		}
 		if errors.Is(err, context.DeadlineExceeded) {
 			return library.Book{}, &library.Error{
 				Type:   library.Timeout,
@@ -126,33 +539,67 @@ func (q *Queryer) GetBook(ctx context.Context, isbn int64) (library.Book, error)
 			Desc:   "while fetching a book",
 		}
 	}
-	return library.Book{
-		Title: book.Title,
-		ISBN:  book.Isbn,
-	}, nil
-}
-
-// UpdateBook updates a single book.
-func (q *Queryer) UpdateBook(ctx context.Context, book library.Book) error {
-	params := sqlc.UpdateBookParams{
-		Isbn:  book.ISBN,
-		Title: book.Title,
-	}
-
-	err := sqlc.New(q.DBTX).UpdateBook(ctx, params)
+	authors, err := sqlc.New(q.DBTX).ListBookAuthors(ctx, int64(isbn))
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
-			return &library.Error{
+			return library.Book{}, &library.Error{
 				Type:   library.Timeout,
 				Actual: err,
-				Desc:   "while updating a book",
+				Desc:   "while fetching the authors of a book",
 			}
 		}
-		return &library.Error{
+		return library.Book{}, &library.Error{
 			Type:   library.DatabaseError,
 			Actual: err,
-			Desc:   "while updating a book",
+			Desc:   "while fetching the authors of a book",
 		}
 	}
-	return nil
+	result := toBook(book)
+	for _, a := range authors {
+		result.Authors = append(result.Authors, library.Author{
+			ID:   a.ID,
+			Name: a.Name,
+		})
+	}
+	return result, nil
+}
+
+// UpdateBook updates a single book and returns it at its new version. If
+// book.Version isn't zero, the update fails unless the book is still at that
+// version.
+func (q *Queryer) UpdateBook(ctx context.Context, book library.Book) (library.Book, error) {
+	params := sqlc.UpdateBookParams{
+		Isbn:    int64(book.ISBN),
+		Title:   book.Title,
+		Version: sql.NullInt64{Int64: book.Version, Valid: book.Version != 0},
+	}
+
+	var result library.Book
+	err := q.inTx(ctx, func(queries *sqlc.Queries) error {
+		updated, err := queries.UpdateBook(ctx, params)
+		if errors.Is(err, pgx.ErrNoRows) {
+			current, err := queries.GetBook(ctx, sqlc.GetBookParams{Isbn: int64(book.ISBN)})
+			if errors.Is(err, pgx.ErrNoRows) {
+				return &library.Error{
+					Type:   library.NotFound,
+					Actual: fmt.Errorf("no book with isbn %s", book.ISBN),
+					Desc:   "while updating a book",
+				}
+			}
+			if err != nil {
+				return queryError(err, "while updating a book")
+			}
+			return &library.Error{
+				Type:   library.PreconditionFailed,
+				Actual: fmt.Errorf("book %s is at version %d, not %d", book.ISBN, current.Version, book.Version),
+				Desc:   "the book changed since it was read",
+			}
+		}
+		if err != nil {
+			return queryError(err, "while updating a book")
+		}
+		result = toBook(sqlc.Book(updated))
+		return nil
+	})
+	return result, err
 }
@@ -0,0 +1,183 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/db/cursor"
+	"github.com/slcjordan/library/db/sqlc"
+	"github.com/slcjordan/library/log"
+)
+
+// changesOrder is the order of change feed event ids: the transaction that
+// wrote a record, then the record's id.
+const changesOrder = "txid,id"
+
+// changesChannel is the channel the audit trigger notifies when books change.
+const changesChannel = "book_changes"
+
+func changeEventID(txid int64, id int64) (string, error) {
+	return cursor.Encode(cursor.Cursor{
+		Order: changesOrder,
+		Key:   strconv.FormatInt(txid, 10),
+		ID:    id,
+	})
+}
+
+// decodeChangeEventID returns the (txid, id) position of an event id.
+func decodeChangeEventID(eventID string) (int64, int64, error) {
+	after, err := cursor.Decode(eventID)
+	if err == nil && after.Order != changesOrder {
+		err = fmt.Errorf("event id is for order %q", after.Order)
+	}
+	var txid int64
+	if err == nil {
+		txid, err = strconv.ParseInt(after.Key, 10, 64)
+	}
+	if err != nil {
+		return 0, 0, &library.Error{
+			Type:   library.BadInput,
+			Actual: err,
+			Desc:   "while decoding the last event id",
+		}
+	}
+	return txid, after.ID, nil
+}
+
+// ListBookChanges returns up to TotalSize changes of the change feed after
+// lastEventID. An empty lastEventID starts the feed at the current moment
+// rather than at the start of the audit log. Every change is returned once a
+// transaction older than it can no longer add one in front of it, so a feed
+// resumed from the event id of any change it returned misses nothing.
+func (q *Queryer) ListBookChanges(ctx context.Context, lastEventID string, TotalSize int32) (library.BookChangeList, error) {
+	var afterTxid, afterID int64
+	var err error
+	if lastEventID == "" {
+		// the records of the oldest running transaction and newer ones
+		// aren't visible yet.
+		afterTxid, err = sqlc.New(q.DBTX).GetBookChangesHead(ctx)
+		if err != nil {
+			return library.BookChangeList{}, queryError(err, "while starting the change feed")
+		}
+	} else {
+		afterTxid, afterID, err = decodeChangeEventID(lastEventID)
+		if err != nil {
+			return library.BookChangeList{}, err
+		}
+	}
+	records, err := sqlc.New(q.DBTX).ListBookChanges(ctx, sqlc.ListBookChangesParams{
+		AfterTxid: afterTxid,
+		AfterID:   afterID,
+		TotalSize: TotalSize,
+	})
+	if err != nil {
+		return library.BookChangeList{}, queryError(err, "while retrieving book changes")
+	}
+	var result library.BookChangeList
+	result.LastEventID, err = changeEventID(afterTxid, afterID)
+	if err != nil {
+		return library.BookChangeList{}, err
+	}
+	for _, a := range records {
+		record, err := toAuditRecord(a)
+		if err != nil {
+			return library.BookChangeList{}, queryError(err, "while decoding an audit record")
+		}
+		result.LastEventID, err = changeEventID(a.Txid, a.ID)
+		if err != nil {
+			return library.BookChangeList{}, err
+		}
+		result.Changes = append(result.Changes, library.BookChange{
+			EventID: result.LastEventID,
+			Record:  record,
+		})
+	}
+	return result, nil
+}
+
+// A ChangeListener wakes change feeds when books change. It listens for the
+// notifications of the audit trigger on a single connection and passes them
+// on to every subscriber, so that feeds don't each hold a connection.
+type ChangeListener struct {
+	Pool *pgxpool.Pool
+
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+// SubscribeBookChanges returns a channel that receives a value after books
+// change, and a func that unsubscribes. Changes that happen while a value is
+// waiting to be received are folded into it.
+func (l *ChangeListener) SubscribeBookChanges() (<-chan struct{}, func()) {
+	wake := make(chan struct{}, 1)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.subscribers == nil {
+		l.subscribers = make(map[chan struct{}]struct{})
+	}
+	l.subscribers[wake] = struct{}{}
+	return wake, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.subscribers, wake)
+	}
+}
+
+func (l *ChangeListener) wakeAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for wake := range l.subscribers {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Listen passes notifications on to subscribers until ctx is done. When the
+// connection is lost it reconnects after retryDelay, waking every subscriber
+// so that they catch up on changes made in the meantime.
+func (l *ChangeListener) Listen(ctx context.Context, retryDelay time.Duration) {
+	for {
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Errorf(ctx, "while listening for book changes: %s", err)
+		l.wakeAll()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+func (l *ChangeListener) listen(ctx context.Context) error {
+	pooled, err := l.Pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// a listening connection can't go back to the pool.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+	_, err = conn.Exec(ctx, "LISTEN "+changesChannel)
+	if err != nil {
+		return err
+	}
+	// changes made before the LISTEN took effect didn't notify anyone.
+	l.wakeAll()
+	for {
+		_, err = conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		l.wakeAll()
+	}
+}
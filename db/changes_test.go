@@ -0,0 +1,56 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/slcjordan/library/config"
+	"github.com/slcjordan/library/db/cursor"
+)
+
+func TestChangeEventID(t *testing.T) {
+	saved := config.Postgres.PageTokenSecret
+	defer config.Update(func() { config.Postgres.PageTokenSecret = saved })
+	config.Update(func() { config.Postgres.PageTokenSecret = "hunter2" })
+
+	eventID, err := changeEventID(812, 40)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	txid, id, err := decodeChangeEventID(eventID)
+	if err != nil || txid != 812 || id != 40 {
+		t.Fatalf("expected (812, 40) but got (%d, %d) with error %v", txid, id, err)
+	}
+	pageToken, err := cursor.Encode(cursor.Cursor{Order: auditOrder, ID: 40})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for desc, eventID := range map[string]string{
+		"garbage":    "not-an-event-id",
+		"page token": pageToken,
+	} {
+		_, _, err = decodeChangeEventID(eventID)
+		if err == nil {
+			t.Fatalf("%s: expected an error", desc)
+		}
+	}
+}
+
+func TestSubscribeBookChanges(t *testing.T) {
+	l := &ChangeListener{}
+	wake, unsubscribe := l.SubscribeBookChanges()
+	l.wakeAll()
+	l.wakeAll()
+	<-wake
+	select {
+	case <-wake:
+		t.Fatalf("expected changes to be folded into a single wake up")
+	default:
+	}
+	unsubscribe()
+	l.wakeAll()
+	select {
+	case <-wake:
+		t.Fatalf("expected no wake up after unsubscribing")
+	default:
+	}
+}
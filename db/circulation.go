@@ -0,0 +1,477 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/config"
+	"github.com/slcjordan/library/db/sqlc"
+)
+
+func toCopy(c sqlc.Copy) library.Copy {
+	return library.Copy{
+		Barcode:  c.Barcode,
+		ISBN:     library.ISBN(c.Isbn),
+		Location: c.Location,
+		Status:   library.CopyStatus(c.Status),
+	}
+}
+
+func toPatron(p sqlc.Patron) library.Patron {
+	return library.Patron{
+		ID:             p.ID,
+		Name:           p.Name,
+		BorrowingLimit: p.BorrowingLimit,
+		Class:          p.Class,
+	}
+}
+
+func toLoan(l sqlc.Loan) library.Loan {
+	return library.Loan{
+		ID:           l.ID,
+		Barcode:      l.Barcode,
+		PatronID:     l.PatronID,
+		CheckedOutAt: l.CheckedOutAt,
+		DueAt:        l.DueAt,
+		ReturnedAt:   fromNullTime(l.ReturnedAt),
+		Renewals:     l.Renewals,
+	}
+}
+
+func fromNullTime(t sql.NullTime) time.Time {
+	if !t.Valid {
+		return time.Time{}
+	}
+	return t.Time
+}
+
+// CreateCopy creates a single copy of an existing book.
+func (q *Queryer) CreateCopy(ctx context.Context, copy library.Copy) error {
+	if copy.Status == "" {
+		copy.Status = library.Available
+	}
+	if copy.Status == library.OnLoan || copy.Status == library.OnHoldShelf {
+		return &library.Error{
+			Type:   library.BadInput,
+			Actual: fmt.Errorf("new copies can't be %s", copy.Status),
+			Desc:   "while creating a copy",
+		}
+	}
+	params := sqlc.CreateCopyParams{
+		Barcode:  copy.Barcode,
+		Isbn:     int64(copy.ISBN),
+		Location: copy.Location,
+		Status:   string(copy.Status),
+	}
+	err := sqlc.New(q.DBTX).CreateCopy(ctx, params)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case pgerrcode.UniqueViolation:
+				return &library.Error{
+					Type:   library.Conflict,
+					Actual: err,
+					Desc:   "a copy with that barcode already exists",
+				}
+			case pgerrcode.ForeignKeyViolation:
+				return &library.Error{
+					Type:   library.BadInput,
+					Actual: err,
+					Desc:   "the book does not exist",
+				}
+			case pgerrcode.CheckViolation:
+				return &library.Error{
+					Type:   library.BadInput,
+					Actual: err,
+					Desc:   "unknown copy status",
+				}
+			}
+		}
+		return queryError(err, "while creating a copy")
+	}
+	return nil
+}
+
+// DeleteCopy deletes a single copy. Copies that have been lent can't be
+// deleted.
+func (q *Queryer) DeleteCopy(ctx context.Context, barcode string) error {
+	count, err := sqlc.New(q.DBTX).DeleteCopy(ctx, barcode)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			return &library.Error{
+				Type:   library.Conflict,
+				Actual: err,
+				Desc:   "the copy has a loan history",
+			}
+		}
+		return queryError(err, "while deleting a copy")
+	}
+	if count == 0 {
+		return &library.Error{
+			Type:   library.NotFound,
+			Actual: fmt.Errorf("no copy with barcode %q", barcode),
+			Desc:   "while deleting a copy",
+		}
+	}
+	return nil
+}
+
+// GetCopy fetches a single copy.
+func (q *Queryer) GetCopy(ctx context.Context, barcode string) (library.Copy, error) {
+	copy, err := sqlc.New(q.DBTX).GetCopy(ctx, barcode)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return library.Copy{}, &library.Error{
+				Type:   library.NotFound,
+				Actual: fmt.Errorf("no copy with barcode %q", barcode),
+				Desc:   "while fetching a copy",
+			}
+		}
+		return library.Copy{}, queryError(err, "while fetching a copy")
+	}
+	return toCopy(copy), nil
+}
+
+// UpdateCopy updates the location and status of a single copy. Only
+// circulation moves a copy on or off loan or the hold shelf.
+func (q *Queryer) UpdateCopy(ctx context.Context, copy library.Copy) error {
+	if copy.Status == library.OnLoan || copy.Status == library.OnHoldShelf {
+		return &library.Error{
+			Type:   library.BadInput,
+			Actual: fmt.Errorf("copies can't be set %s directly", copy.Status),
+			Desc:   "while updating a copy",
+		}
+	}
+	return q.inTx(ctx, func(queries *sqlc.Queries) error {
+		current, err := lockCopy(ctx, queries, copy.Barcode)
+		if err != nil {
+			return err
+		}
+		if current.Status == string(library.OnLoan) || current.Status == string(library.OnHoldShelf) {
+			return &library.Error{
+				Type:   library.Conflict,
+				Actual: fmt.Errorf("copy %q is %s", copy.Barcode, current.Status),
+				Desc:   "while updating a copy",
+			}
+		}
+		_, err = queries.UpdateCopy(ctx, sqlc.UpdateCopyParams{
+			Barcode:  copy.Barcode,
+			Location: copy.Location,
+			Status:   string(copy.Status),
+		})
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.CheckViolation {
+				return &library.Error{
+					Type:   library.BadInput,
+					Actual: err,
+					Desc:   "unknown copy status",
+				}
+			}
+			return queryError(err, "while updating a copy")
+		}
+		return nil
+	})
+}
+
+func lockCopy(ctx context.Context, queries *sqlc.Queries, barcode string) (sqlc.Copy, error) {
+	copy, err := queries.LockCopy(ctx, barcode)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.Copy{}, &library.Error{
+				Type:   library.NotFound,
+				Actual: fmt.Errorf("no copy with barcode %q", barcode),
+				Desc:   "while locking a copy",
+			}
+		}
+		return sqlc.Copy{}, queryError(err, "while locking a copy")
+	}
+	return copy, nil
+}
+
+// CreatePatron creates a single patron and returns it with its new id.
+func (q *Queryer) CreatePatron(ctx context.Context, patron library.Patron) (library.Patron, error) {
+	if patron.Class == "" {
+		patron.Class = library.DefaultPatronClass
+	}
+	params := sqlc.CreatePatronParams{
+		Name:           patron.Name,
+		BorrowingLimit: patron.BorrowingLimit,
+		Class:          patron.Class,
+	}
+	created, err := sqlc.New(q.DBTX).CreatePatron(ctx, params)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.CheckViolation {
+			return library.Patron{}, &library.Error{
+				Type:   library.BadInput,
+				Actual: err,
+				Desc:   "borrowing limit can't be negative",
+			}
+		}
+		return library.Patron{}, queryError(err, "while creating a patron")
+	}
+	return toPatron(created), nil
+}
+
+// GetPatron fetches a single patron.
+func (q *Queryer) GetPatron(ctx context.Context, id int64) (library.Patron, error) {
+	patron, err := sqlc.New(q.DBTX).GetPatron(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return library.Patron{}, &library.Error{
+				Type:   library.NotFound,
+				Actual: fmt.Errorf("no patron with id %d", id),
+				Desc:   "while fetching a patron",
+			}
+		}
+		return library.Patron{}, queryError(err, "while fetching a patron")
+	}
+	return toPatron(patron), nil
+}
+
+// UpdatePatron updates a single patron. Lowering the borrowing limit doesn't
+// affect open loans and a new class only applies to fines assessed from now
+// on. A change of class is recorded with the actor of ctx, so the update runs
+// in a transaction.
+func (q *Queryer) UpdatePatron(ctx context.Context, patron library.Patron) error {
+	if patron.Class == "" {
+		patron.Class = library.DefaultPatronClass
+	}
+	params := sqlc.UpdatePatronParams{
+		ID:             patron.ID,
+		Name:           patron.Name,
+		BorrowingLimit: patron.BorrowingLimit,
+		Class:          patron.Class,
+	}
+	return q.inTx(ctx, func(queries *sqlc.Queries) error {
+		count, err := queries.UpdatePatron(ctx, params)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.CheckViolation {
+				return &library.Error{
+					Type:   library.BadInput,
+					Actual: err,
+					Desc:   "borrowing limit can't be negative",
+				}
+			}
+			return queryError(err, "while updating a patron")
+		}
+		if count == 0 {
+			return &library.Error{
+				Type:   library.NotFound,
+				Actual: fmt.Errorf("no patron with id %d", patron.ID),
+				Desc:   "while updating a patron",
+			}
+		}
+		return nil
+	})
+}
+
+// ListPatronLoans returns the open loans of a single patron, soonest due
+// first.
+func (q *Queryer) ListPatronLoans(ctx context.Context, patronID int64) ([]library.Loan, error) {
+	_, err := q.GetPatron(ctx, patronID)
+	if err != nil {
+		return nil, err
+	}
+	loans, err := sqlc.New(q.DBTX).ListPatronLoans(ctx, patronID)
+	if err != nil {
+		return nil, queryError(err, "while retrieving the loans of a patron")
+	}
+	result := make([]library.Loan, 0, len(loans))
+	for _, l := range loans {
+		result = append(result, toLoan(l))
+	}
+	return result, nil
+}
+
+// Checkout lends an available copy to a patron who is under their borrowing
+// limit. A copy on the hold shelf may only be lent to the patron it is set
+// aside for, which fulfills their hold. The patron row is locked first so
+// that concurrent checkouts by the same patron can't both slip under the
+// limit.
+func (q *Queryer) Checkout(ctx context.Context, barcode string, patronID int64) (library.Loan, error) {
+	var result library.Loan
+	err := q.inTx(ctx, func(queries *sqlc.Queries) error {
+		patron, err := queries.LockPatron(ctx, patronID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return &library.Error{
+					Type:   library.NotFound,
+					Actual: fmt.Errorf("no patron with id %d", patronID),
+					Desc:   "while checking out a copy",
+				}
+			}
+			return queryError(err, "while locking a patron")
+		}
+		open, err := queries.CountOpenLoans(ctx, patronID)
+		if err != nil {
+			return queryError(err, "while counting open loans")
+		}
+		if open >= int64(patron.BorrowingLimit) {
+			return &library.Error{
+				Type:   library.Conflict,
+				Actual: fmt.Errorf("patron %d has %d of %d copies checked out", patronID, open, patron.BorrowingLimit),
+				Desc:   "the patron has reached their borrowing limit",
+			}
+		}
+		copy, err := lockCopy(ctx, queries, barcode)
+		if err != nil {
+			return err
+		}
+		switch library.CopyStatus(copy.Status) {
+		case library.Available:
+		case library.OnHoldShelf:
+			hold, err := queries.LockReadyHold(ctx, sql.NullString{String: barcode, Valid: true})
+			if err != nil {
+				return queryError(err, "while locking a ready hold")
+			}
+			if hold.PatronID != patronID {
+				return &library.Error{
+					Type:   library.Conflict,
+					Actual: fmt.Errorf("copy %q is set aside for hold %d", barcode, hold.ID),
+					Desc:   "the copy is on hold for another patron",
+				}
+			}
+			err = queries.SetHoldStatus(ctx, sqlc.SetHoldStatusParams{
+				ID:     hold.ID,
+				Status: string(library.Fulfilled),
+			})
+			if err != nil {
+				return queryError(err, "while fulfilling a hold")
+			}
+		default:
+			return &library.Error{
+				Type:   library.Conflict,
+				Actual: fmt.Errorf("copy %q is %s", barcode, copy.Status),
+				Desc:   "the copy is not available",
+			}
+		}
+		err = queries.SetCopyStatus(ctx, sqlc.SetCopyStatusParams{
+			Barcode: barcode,
+			Status:  string(library.OnLoan),
+		})
+		if err != nil {
+			return queryError(err, "while checking out a copy")
+		}
+		loan, err := queries.CreateLoan(ctx, sqlc.CreateLoanParams{
+			Barcode:  barcode,
+			PatronID: patronID,
+			DueAt:    time.Now().Add(config.Current().Circulation.LoanPeriod),
+		})
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+				return &library.Error{
+					Type:   library.Conflict,
+					Actual: err,
+					Desc:   "the copy is already on loan",
+				}
+			}
+			return queryError(err, "while checking out a copy")
+		}
+		result = toLoan(loan)
+		return nil
+	})
+	return result, err
+}
+
+// lockOpenLoan locks a copy and then its open loan.
+func lockOpenLoan(ctx context.Context, queries *sqlc.Queries, barcode string) (sqlc.Copy, sqlc.Loan, error) {
+	copy, err := lockCopy(ctx, queries, barcode)
+	if err != nil {
+		return sqlc.Copy{}, sqlc.Loan{}, err
+	}
+	loan, err := queries.LockOpenLoan(ctx, barcode)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.Copy{}, sqlc.Loan{}, &library.Error{
+				Type:   library.Conflict,
+				Actual: fmt.Errorf("copy %q is not on loan", barcode),
+				Desc:   "while locking a loan",
+			}
+		}
+		return sqlc.Copy{}, sqlc.Loan{}, queryError(err, "while locking a loan")
+	}
+	return copy, loan, nil
+}
+
+// Return closes the open loan of a copy and fines the patron if it is
+// overdue. The copy is set aside for the next waiting hold on its title or
+// becomes available again.
+func (q *Queryer) Return(ctx context.Context, barcode string) (library.Loan, error) {
+	var result library.Loan
+	err := q.inTx(ctx, func(queries *sqlc.Queries) error {
+		copy, loan, err := lockOpenLoan(ctx, queries, barcode)
+		if err != nil {
+			return err
+		}
+		loan, err = queries.ReturnLoan(ctx, loan.ID)
+		if err != nil {
+			return queryError(err, "while returning a copy")
+		}
+		err = assessFine(ctx, queries, loan)
+		if err != nil {
+			return err
+		}
+		err = assignNextHold(ctx, queries, copy)
+		if err != nil {
+			return err
+		}
+		result = toLoan(loan)
+		return nil
+	})
+	return result, err
+}
+
+// Renew extends the open loan of a copy by another loan period from now.
+// Loans can't be renewed while other patrons are waiting for the title.
+func (q *Queryer) Renew(ctx context.Context, barcode string) (library.Loan, error) {
+	var result library.Loan
+	err := q.inTx(ctx, func(queries *sqlc.Queries) error {
+		copy, loan, err := lockOpenLoan(ctx, queries, barcode)
+		if err != nil {
+			return err
+		}
+		waiting, err := queries.CountWaitingHolds(ctx, copy.Isbn)
+		if err != nil {
+			return queryError(err, "while counting waiting holds")
+		}
+		if waiting > 0 {
+			return &library.Error{
+				Type:   library.Conflict,
+				Actual: fmt.Errorf("%d patrons are waiting for isbn %s", waiting, library.ISBN(copy.Isbn)),
+				Desc:   "the loan can't be renewed while the book is on hold",
+			}
+		}
+		circulation := config.Current().Circulation
+		if loan.Renewals >= circulation.MaxRenewals {
+			return &library.Error{
+				Type:   library.Conflict,
+				Actual: fmt.Errorf("loan %d has been renewed %d times", loan.ID, loan.Renewals),
+				Desc:   "the loan can't be renewed again",
+			}
+		}
+		loan, err = queries.RenewLoan(ctx, sqlc.RenewLoanParams{
+			ID:    loan.ID,
+			DueAt: time.Now().Add(circulation.LoanPeriod),
+		})
+		if err != nil {
+			return queryError(err, "while renewing a loan")
+		}
+		result = toLoan(loan)
+		return nil
+	})
+	return result, err
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/slcjordan/library/config"
 	"github.com/slcjordan/library/db/sqlc"
@@ -10,15 +11,19 @@ import (
 
 //go:generate go run github.com/golang/mock/mockgen -package=db -destination=../test/mocks/db/connect.go -source=connect.go
 
+// A DBTX runs queries and can start a transaction. Both *pgxpool.Pool and
+// pgx.Tx satisfy it.
 type DBTX interface {
 	sqlc.DBTX
+	Begin(context.Context) (pgx.Tx, error)
 }
 
 func MustConnect() *pgxpool.Pool {
-	ctx, cancel := context.WithTimeout(context.Background(), config.Postgres.ConnectTimeout)
+	settings := config.Current().Postgres
+	ctx, cancel := context.WithTimeout(context.Background(), settings.ConnectTimeout)
 	defer cancel()
 
-	pool, err := pgxpool.Connect(ctx, config.Postgres.ConnectionString)
+	pool, err := pgxpool.Connect(ctx, settings.ConnectionString)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,105 @@
+// Package cursor encodes the position in a sorted list as an opaque page
+// token. Tokens are signed so that clients can't forge them.
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/config"
+)
+
+// cursorVersion is the first byte of every page token. Bump it whenever the
+// layout changes so that old tokens get rejected instead of misread.
+const cursorVersion byte = 2
+
+const cursorMACSize = 16
+
+// A Cursor is the sort key of the last row on a page: a non-unique key such as
+// a book title or an author name, with the row's unique id breaking ties.
+// Order names the sort order the key belongs to so that a token from one
+// order can't be used to page through another.
+type Cursor struct {
+	Order string
+	Key   string
+	ID    int64
+}
+
+// FirstPage sorts before every row.
+var FirstPage = Cursor{ID: math.MinInt64}
+
+// errNoSecret is returned instead of signing with an empty key, which would
+// let anyone forge a token.
+var errNoSecret = &library.Error{
+	Type:   library.InvalidSettings,
+	Actual: errors.New("Postgres.PageTokenSecret is empty"),
+	Desc:   "while signing a page token",
+}
+
+func cursorMAC(payload []byte) ([]byte, error) {
+	secret := config.Current().Postgres.PageTokenSecret
+	if secret == "" {
+		return nil, errNoSecret
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return mac.Sum(nil)[:cursorMACSize], nil
+}
+
+// Encode returns an opaque page token: a version byte, the big-endian id, the
+// length of the order, the order and the key, followed by a truncated HMAC of
+// all of it. Both Encode and Decode fail if the page token secret is not set.
+func Encode(c Cursor) (string, error) {
+	payload := make([]byte, 1+8+1, 1+8+1+len(c.Order)+len(c.Key)+cursorMACSize)
+	payload[0] = cursorVersion
+	binary.BigEndian.PutUint64(payload[1:9], uint64(c.ID))
+	payload[9] = byte(len(c.Order))
+	payload = append(payload, c.Order...)
+	payload = append(payload, c.Key...)
+	sum, err := cursorMAC(payload)
+	if err != nil {
+		return "", err
+	}
+	payload = append(payload, sum...)
+	return base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+// Decode parses a token made by Encode. The empty token is the first page.
+func Decode(token string) (Cursor, error) {
+	if token == "" {
+		return FirstPage, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, err
+	}
+	if len(raw) < 1+8+1+cursorMACSize {
+		return Cursor{}, errors.New("page token is too short")
+	}
+	if raw[0] != cursorVersion {
+		return Cursor{}, fmt.Errorf("unsupported page token version %d", raw[0])
+	}
+	payload, sum := raw[:len(raw)-cursorMACSize], raw[len(raw)-cursorMACSize:]
+	expected, err := cursorMAC(payload)
+	if err != nil {
+		return Cursor{}, err
+	}
+	if !hmac.Equal(sum, expected) {
+		return Cursor{}, errors.New("page token has been modified")
+	}
+	order := int(payload[9])
+	if len(payload) < 1+8+1+order {
+		return Cursor{}, errors.New("page token is too short")
+	}
+	return Cursor{
+		ID:    int64(binary.BigEndian.Uint64(payload[1:9])),
+		Order: string(payload[10 : 10+order]),
+		Key:   string(payload[10+order:]),
+	}, nil
+}
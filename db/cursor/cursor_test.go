@@ -0,0 +1,91 @@
+package cursor
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/config"
+)
+
+// withSecret signs tokens with secret until the test ends.
+func withSecret(t *testing.T, secret string) {
+	t.Helper()
+	saved := config.Postgres.PageTokenSecret
+	config.Update(func() { config.Postgres.PageTokenSecret = secret })
+	t.Cleanup(func() {
+		config.Update(func() { config.Postgres.PageTokenSecret = saved })
+	})
+}
+
+// mustEncode encodes c or fails the test.
+func mustEncode(t *testing.T, c Cursor) string {
+	t.Helper()
+	token, err := Encode(c)
+	if err != nil {
+		t.Fatalf("while encoding %+v: %s", c, err)
+	}
+	return token
+}
+
+func TestCursor(t *testing.T) {
+	withSecret(t, "hunter2")
+	for _, c := range []Cursor{
+		{Key: "Collected Poems", ID: 9780571216260},
+		{Key: "", ID: math.MinInt64},
+		{Key: "Untitled ☃", ID: 1},
+		{Order: "-created_at", Key: "2026-10-18T12:00:00Z", ID: 9780306406157},
+	} {
+		token := mustEncode(t, c)
+		actual, err := Decode(token)
+		if err != nil {
+			t.Fatalf("while decoding %q: %s", token, err)
+		}
+		if actual != c {
+			t.Fatalf("expected %+v but got %+v", c, actual)
+		}
+	}
+}
+
+func TestCursorFirstPage(t *testing.T) {
+	actual, err := Decode("")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if actual != FirstPage {
+		t.Fatalf("expected first page but got %+v", actual)
+	}
+}
+
+func TestCursorTampered(t *testing.T) {
+	withSecret(t, "hunter2")
+	token := mustEncode(t, Cursor{Key: "Untitled", ID: 9780000000002})
+	for desc, tampered := range map[string]string{
+		"flipped character": strings.Replace(token, token[3:4], string(token[3]^1), 1),
+		"truncated":         token[:len(token)-4],
+		"not base64":        "!!" + token,
+		"legacy title":      "Untitled",
+	} {
+		_, err := Decode(tampered)
+		if err == nil {
+			t.Fatalf("%s: expected an error for token %q", desc, tampered)
+		}
+	}
+}
+
+func TestCursorNoSecret(t *testing.T) {
+	withSecret(t, "hunter2")
+	token := mustEncode(t, Cursor{Key: "Untitled", ID: 9780000000002})
+	withSecret(t, "")
+	var liberr *library.Error
+	_, err := Encode(Cursor{Key: "Untitled", ID: 9780000000002})
+	if !errors.As(err, &liberr) || liberr.Type != library.InvalidSettings {
+		t.Fatalf("expected invalid settings but got %v", err)
+	}
+	_, err = Decode(token)
+	if !errors.As(err, &liberr) || liberr.Type != library.InvalidSettings {
+		t.Fatalf("expected invalid settings but got %v", err)
+	}
+}
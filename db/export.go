@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/db/sqlc"
+)
+
+// exportBatchSize is how many books are read at a time during an export.
+const exportBatchSize = 1000
+
+// ExportBooks writes every book to writer in isbn order. The books are read
+// in batches from a single snapshot so the export is consistent without
+// holding the catalog in memory. Errors from writer are returned as they are.
+func (q *Queryer) ExportBooks(ctx context.Context, writer library.BookWriter) error {
+	return q.inTxAs(ctx, snapshotTx, func(queries *sqlc.Queries) error {
+		var after sql.NullInt64
+		for {
+			books, err := queries.ListBooksByIsbn(ctx, sqlc.ListBooksByIsbnParams{
+				AfterIsbn: after,
+				TotalSize: exportBatchSize,
+			})
+			if err != nil {
+				return queryError(err, "while exporting books")
+			}
+			for _, b := range books {
+				err = writer.Write(toBook(b))
+				if err != nil {
+					return err
+				}
+			}
+			if len(books) < exportBatchSize {
+				return nil
+			}
+			after = sql.NullInt64{Int64: books[len(books)-1].Isbn, Valid: true}
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/config"
+	"github.com/slcjordan/library/db/sqlc"
+)
+
+func toLedgerEntry(e sqlc.LedgerEntry) library.LedgerEntry {
+	return library.LedgerEntry{
+		ID:        e.ID,
+		PatronID:  e.PatronID,
+		LoanID:    e.LoanID.Int64,
+		Kind:      library.LedgerKind(e.Kind),
+		Amount:    e.Amount,
+		Note:      e.Note,
+		Actor:     e.Actor,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+func toClassChange(c sqlc.PatronClassChange) library.ClassChange {
+	return library.ClassChange{
+		ID:        c.ID,
+		PatronID:  c.PatronID,
+		From:      c.OldClass,
+		To:        c.NewClass,
+		Actor:     c.Actor,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+func isHoliday(holidays []time.Time, day time.Time) bool {
+	year, month, date := day.UTC().Date()
+	for _, h := range holidays {
+		y, m, d := h.UTC().Date()
+		if y == year && m == month && d == date {
+			return true
+		}
+	}
+	return false
+}
+
+// overdueFine returns the fine in cents for a loan due at due and returned at
+// returned, along with the number of days fined. Every started day after the
+// grace period is fined unless it starts on a holiday, up to the cap.
+func overdueFine(schedule config.FeeSchedule, due time.Time, returned time.Time) (int64, int) {
+	if schedule.DailyRate <= 0 {
+		return 0, 0
+	}
+	var fine int64
+	var days int
+	for day := due.Add(schedule.GracePeriod); day.Before(returned); day = day.Add(24 * time.Hour) {
+		if isHoliday(schedule.Holidays, day) {
+			continue
+		}
+		days++
+		fine += schedule.DailyRate
+		if schedule.Cap > 0 && fine >= schedule.Cap {
+			return schedule.Cap, days
+		}
+	}
+	return fine, days
+}
+
+// assessFine charges a returned loan its overdue fine, if any, according to
+// the fee schedule of the patron's class.
+func assessFine(ctx context.Context, queries *sqlc.Queries, loan sqlc.Loan) error {
+	patron, err := queries.GetPatron(ctx, loan.PatronID)
+	if err != nil {
+		return queryError(err, "while fetching a patron")
+	}
+	schedule, ok := config.Current().Fines.Schedules[patron.Class]
+	if !ok {
+		return nil
+	}
+	fine, days := overdueFine(schedule, loan.DueAt, loan.ReturnedAt.Time)
+	if fine == 0 {
+		return nil
+	}
+	_, err = queries.CreateLedgerEntry(ctx, sqlc.CreateLedgerEntryParams{
+		PatronID: loan.PatronID,
+		LoanID:   sql.NullInt64{Int64: loan.ID, Valid: true},
+		Kind:     string(library.Fine),
+		Amount:   fine,
+		Note:     fmt.Sprintf("copy %q returned %d days overdue", loan.Barcode, days),
+	})
+	if err != nil {
+		return queryError(err, "while fining an overdue loan")
+	}
+	return nil
+}
+
+// GetFineAccount returns the balance and ledger of a single patron along with
+// the fines their open loans are accruing and the changes to their class.
+func (q *Queryer) GetFineAccount(ctx context.Context, patronID int64) (library.FineAccount, error) {
+	queries := sqlc.New(q.DBTX)
+	patron, err := queries.GetPatron(ctx, patronID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return library.FineAccount{}, &library.Error{
+				Type:   library.NotFound,
+				Actual: fmt.Errorf("no patron with id %d", patronID),
+				Desc:   "while fetching the fines of a patron",
+			}
+		}
+		return library.FineAccount{}, queryError(err, "while fetching a patron")
+	}
+	entries, err := queries.ListPatronLedger(ctx, patronID)
+	if err != nil {
+		return library.FineAccount{}, queryError(err, "while fetching the ledger of a patron")
+	}
+	result := library.FineAccount{
+		PatronID: patronID,
+		Entries:  make([]library.LedgerEntry, 0, len(entries)),
+	}
+	for _, e := range entries {
+		result.Balance += e.Amount
+		result.Entries = append(result.Entries, toLedgerEntry(e))
+	}
+	changes, err := queries.ListPatronClassChanges(ctx, patronID)
+	if err != nil {
+		return library.FineAccount{}, queryError(err, "while fetching the class changes of a patron")
+	}
+	result.ClassChanges = make([]library.ClassChange, 0, len(changes))
+	for _, c := range changes {
+		result.ClassChanges = append(result.ClassChanges, toClassChange(c))
+	}
+	schedule, ok := config.Current().Fines.Schedules[patron.Class]
+	if !ok {
+		return result, nil
+	}
+	loans, err := queries.ListPatronLoans(ctx, patronID)
+	if err != nil {
+		return library.FineAccount{}, queryError(err, "while retrieving the loans of a patron")
+	}
+	now := time.Now()
+	for _, l := range loans {
+		fine, _ := overdueFine(schedule, l.DueAt, now)
+		result.Accruing += fine
+	}
+	return result, nil
+}
+
+// credit appends a payment or waiver to the ledger of a patron. The patron is
+// locked so that concurrent credits can't take the balance below zero.
+func (q *Queryer) credit(ctx context.Context, patronID int64, kind library.LedgerKind, amount int64, note string) (library.LedgerEntry, error) {
+	if amount <= 0 {
+		return library.LedgerEntry{}, &library.Error{
+			Type:   library.BadInput,
+			Actual: fmt.Errorf("amount %d is not positive", amount),
+			Desc:   fmt.Sprintf("while recording a %s", kind),
+		}
+	}
+	var result library.LedgerEntry
+	err := q.inTx(ctx, func(queries *sqlc.Queries) error {
+		_, err := queries.LockPatron(ctx, patronID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return &library.Error{
+					Type:   library.NotFound,
+					Actual: fmt.Errorf("no patron with id %d", patronID),
+					Desc:   fmt.Sprintf("while recording a %s", kind),
+				}
+			}
+			return queryError(err, "while locking a patron")
+		}
+		balance, err := queries.PatronBalance(ctx, patronID)
+		if err != nil {
+			return queryError(err, "while summing the ledger of a patron")
+		}
+		if amount > balance {
+			return &library.Error{
+				Type:   library.Conflict,
+				Actual: fmt.Errorf("patron %d owes %d but the %s is %d", patronID, balance, kind, amount),
+				Desc:   fmt.Sprintf("the %s is more than the patron owes", kind),
+			}
+		}
+		entry, err := queries.CreateLedgerEntry(ctx, sqlc.CreateLedgerEntryParams{
+			PatronID: patronID,
+			Kind:     string(kind),
+			Amount:   -amount,
+			Note:     note,
+		})
+		if err != nil {
+			return queryError(err, fmt.Sprintf("while recording a %s", kind))
+		}
+		result = toLedgerEntry(entry)
+		return nil
+	})
+	return result, err
+}
+
+// RecordPayment credits a patron with a payment of amount cents.
+func (q *Queryer) RecordPayment(ctx context.Context, patronID int64, amount int64, note string) (library.LedgerEntry, error) {
+	return q.credit(ctx, patronID, library.Payment, amount, note)
+}
+
+// WaiveFines forgives amount cents of what a patron owes. The note should say
+// why.
+func (q *Queryer) WaiveFines(ctx context.Context, patronID int64, amount int64, note string) (library.LedgerEntry, error) {
+	if note == "" {
+		return library.LedgerEntry{}, &library.Error{
+			Type:   library.BadInput,
+			Actual: errors.New("missing note"),
+			Desc:   "a waiver needs a note saying why",
+		}
+	}
+	return q.credit(ctx, patronID, library.Waiver, amount, note)
+}
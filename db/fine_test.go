@@ -0,0 +1,41 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library/config"
+)
+
+func TestOverdueFine(t *testing.T) {
+	due := time.Date(2026, time.December, 20, 17, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	schedule := config.FeeSchedule{
+		DailyRate:   25,
+		GracePeriod: 2 * day,
+		Cap:         200,
+		Holidays: []time.Time{
+			time.Date(2026, time.December, 25, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for desc, c := range map[string]struct {
+		schedule config.FeeSchedule
+		returned time.Time
+		fine     int64
+		days     int
+	}{
+		"early":                {schedule, due.Add(-day), 0, 0},
+		"within grace period":  {schedule, due.Add(2 * day), 0, 0},
+		"started day":          {schedule, due.Add(2*day + time.Minute), 25, 1},
+		"skips holiday":        {schedule, due.Add(6 * day), 75, 3},
+		"capped":               {schedule, due.Add(30 * day), 200, 8},
+		"no rate":              {config.FeeSchedule{}, due.Add(30 * day), 0, 0},
+		"uncapped":             {config.FeeSchedule{DailyRate: 10}, due.Add(30 * day), 300, 30},
+		"uncapped partial day": {config.FeeSchedule{DailyRate: 10}, due.Add(time.Second), 10, 1},
+	} {
+		fine, days := overdueFine(c.schedule, due, c.returned)
+		if fine != c.fine || days != c.days {
+			t.Fatalf("%s: expected %d cents for %d days but got %d cents for %d days", desc, c.fine, c.days, fine, days)
+		}
+	}
+}
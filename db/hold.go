@@ -0,0 +1,305 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/config"
+	"github.com/slcjordan/library/db/sqlc"
+	"github.com/slcjordan/library/log"
+)
+
+func toHold(h sqlc.Hold) library.Hold {
+	return library.Hold{
+		ID:       h.ID,
+		ISBN:     library.ISBN(h.Isbn),
+		PatronID: h.PatronID,
+		PlacedAt: h.PlacedAt,
+		Status:   library.HoldStatus(h.Status),
+		Barcode:  h.Barcode.String,
+		PickupBy: fromNullTime(h.PickupBy),
+	}
+}
+
+// lockNextWaitingHold locks the oldest waiting hold on a title, so that holds
+// are served first come, first served. A hold that was cancelled while it
+// was waited on is passed over by looking again.
+func lockNextWaitingHold(ctx context.Context, queries *sqlc.Queries, isbn int64) (sqlc.Hold, error) {
+	for {
+		hold, err := queries.LockNextWaitingHold(ctx, isbn)
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return hold, err
+		}
+		waiting, err := queries.CountWaitingHolds(ctx, isbn)
+		if err != nil {
+			return sqlc.Hold{}, err
+		}
+		if waiting == 0 {
+			return sqlc.Hold{}, pgx.ErrNoRows
+		}
+	}
+}
+
+// assignNextHold sets a copy aside for the oldest waiting hold on its title or
+// makes it available if nobody is waiting. The copy must be locked.
+func assignNextHold(ctx context.Context, queries *sqlc.Queries, copy sqlc.Copy) error {
+	hold, err := lockNextWaitingHold(ctx, queries, copy.Isbn)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = queries.SetCopyStatus(ctx, sqlc.SetCopyStatusParams{
+			Barcode: copy.Barcode,
+			Status:  string(library.Available),
+		})
+		if err != nil {
+			return queryError(err, "while making a copy available")
+		}
+		return nil
+	}
+	if err != nil {
+		return queryError(err, "while locking the next hold")
+	}
+	err = queries.ReadyHold(ctx, sqlc.ReadyHoldParams{
+		ID:       hold.ID,
+		Barcode:  sql.NullString{String: copy.Barcode, Valid: true},
+		PickupBy: sql.NullTime{Time: time.Now().Add(config.Current().Circulation.HoldPickupPeriod), Valid: true},
+	})
+	if err != nil {
+		return queryError(err, "while setting a copy aside for a hold")
+	}
+	err = queries.SetCopyStatus(ctx, sqlc.SetCopyStatusParams{
+		Barcode: copy.Barcode,
+		Status:  string(library.OnHoldShelf),
+	})
+	if err != nil {
+		return queryError(err, "while setting a copy aside for a hold")
+	}
+	return nil
+}
+
+// PlaceHold puts a patron at the end of the queue for a title. If a copy is
+// available it is set aside for the oldest waiting hold right away.
+func (q *Queryer) PlaceHold(ctx context.Context, isbn library.ISBN, patronID int64) (library.Hold, error) {
+	var result library.Hold
+	err := q.inTx(ctx, func(queries *sqlc.Queries) error {
+		hold, err := queries.CreateHold(ctx, sqlc.CreateHoldParams{
+			Isbn:     int64(isbn),
+			PatronID: patronID,
+		})
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) {
+				switch pgErr.Code {
+				case pgerrcode.UniqueViolation:
+					return &library.Error{
+						Type:   library.Conflict,
+						Actual: err,
+						Desc:   "the patron already has a hold on the book",
+					}
+				case pgerrcode.ForeignKeyViolation:
+					return &library.Error{
+						Type:   library.NotFound,
+						Actual: err,
+						Desc:   "the book or patron does not exist",
+					}
+				}
+			}
+			return queryError(err, "while placing a hold")
+		}
+		copy, err := queries.LockAvailableCopy(ctx, int64(isbn))
+		if errors.Is(err, pgx.ErrNoRows) {
+			result = toHold(hold)
+			return nil
+		}
+		if err != nil {
+			return queryError(err, "while locking an available copy")
+		}
+		err = assignNextHold(ctx, queries, copy)
+		if err != nil {
+			return err
+		}
+		hold, err = queries.GetHold(ctx, hold.ID)
+		if err != nil {
+			return queryError(err, "while placing a hold")
+		}
+		result = toHold(hold)
+		return nil
+	})
+	return result, err
+}
+
+// ListBookHolds returns the active holds for a title in the order they are
+// served.
+func (q *Queryer) ListBookHolds(ctx context.Context, isbn library.ISBN) ([]library.Hold, error) {
+	_, err := sqlc.New(q.DBTX).GetBook(ctx, sqlc.GetBookParams{Isbn: int64(isbn)})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &library.Error{
+				Type:   library.NotFound,
+				Actual: fmt.Errorf("no book with isbn %s", isbn),
+				Desc:   "while retrieving the holds on a book",
+			}
+		}
+		return nil, queryError(err, "while retrieving the holds on a book")
+	}
+	holds, err := sqlc.New(q.DBTX).ListBookHolds(ctx, int64(isbn))
+	if err != nil {
+		return nil, queryError(err, "while retrieving the holds on a book")
+	}
+	result := make([]library.Hold, 0, len(holds))
+	for _, h := range holds {
+		result = append(result, library.Hold{
+			ID:       h.ID,
+			ISBN:     library.ISBN(h.Isbn),
+			PatronID: h.PatronID,
+			PlacedAt: h.PlacedAt,
+			Status:   library.HoldStatus(h.Status),
+			Barcode:  h.Barcode.String,
+			PickupBy: fromNullTime(h.PickupBy),
+			Position: h.Position,
+		})
+	}
+	return result, nil
+}
+
+// CancelHold takes a patron out of the queue. A copy set aside for the hold
+// goes to the next patron.
+func (q *Queryer) CancelHold(ctx context.Context, id int64) error {
+	current, err := sqlc.New(q.DBTX).GetHold(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &library.Error{
+				Type:   library.NotFound,
+				Actual: fmt.Errorf("no hold with id %d", id),
+				Desc:   "while cancelling a hold",
+			}
+		}
+		return queryError(err, "while cancelling a hold")
+	}
+	return q.inTx(ctx, func(queries *sqlc.Queries) error {
+		var copy sqlc.Copy
+		if current.Barcode.Valid {
+			copy, err = lockCopy(ctx, queries, current.Barcode.String)
+			if err != nil {
+				return err
+			}
+		}
+		hold, err := queries.LockHold(ctx, id)
+		if err != nil {
+			return queryError(err, "while locking a hold")
+		}
+		if hold.Status == string(library.Ready) && hold.Barcode.String != copy.Barcode {
+			// the hold was readied after it was read. Its copy is locked
+			// after the hold, against the usual order, so a deadlock
+			// rolls the transaction back and it is retried.
+			copy, err = lockCopy(ctx, queries, hold.Barcode.String)
+			if err != nil {
+				return err
+			}
+			hold, err = queries.LockHold(ctx, id)
+			if err != nil {
+				return queryError(err, "while locking a hold")
+			}
+		}
+		switch library.HoldStatus(hold.Status) {
+		case library.Waiting:
+		case library.Ready:
+			if hold.Barcode.String != copy.Barcode {
+				return &library.Error{
+					Type:   library.Conflict,
+					Actual: fmt.Errorf("hold %d changed while cancelling it", id),
+					Desc:   "while cancelling a hold",
+				}
+			}
+		default:
+			return &library.Error{
+				Type:   library.Conflict,
+				Actual: fmt.Errorf("hold %d is %s", id, hold.Status),
+				Desc:   "the hold is no longer active",
+			}
+		}
+		err = queries.SetHoldStatus(ctx, sqlc.SetHoldStatusParams{
+			ID:     id,
+			Status: string(library.Cancelled),
+		})
+		if err != nil {
+			return queryError(err, "while cancelling a hold")
+		}
+		if hold.Status == string(library.Ready) {
+			return assignNextHold(ctx, queries, copy)
+		}
+		return nil
+	})
+}
+
+// ExpireHolds rolls every copy whose pickup deadline has passed to the next
+// waiting patron. It returns the number of expired holds.
+func (q *Queryer) ExpireHolds(ctx context.Context) (int, error) {
+	var expired int
+	for {
+		barcode, err := sqlc.New(q.DBTX).FindExpiredHold(ctx)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return expired, nil
+		}
+		if err != nil {
+			return expired, queryError(err, "while finding expired holds")
+		}
+		var rolled bool
+		err = q.inTx(ctx, func(queries *sqlc.Queries) error {
+			rolled = false
+			copy, err := lockCopy(ctx, queries, barcode)
+			if err != nil {
+				return err
+			}
+			hold, err := queries.LockExpiredHold(ctx, sql.NullString{String: barcode, Valid: true})
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil // picked up or cancelled in the meantime
+			}
+			if err != nil {
+				return queryError(err, "while locking an expired hold")
+			}
+			err = queries.SetHoldStatus(ctx, sqlc.SetHoldStatusParams{
+				ID:     hold.ID,
+				Status: string(library.Expired),
+			})
+			if err != nil {
+				return queryError(err, "while expiring a hold")
+			}
+			rolled = true
+			return assignNextHold(ctx, queries, copy)
+		})
+		if err != nil {
+			return expired, err
+		}
+		if rolled {
+			expired++
+		}
+	}
+}
+
+// ExpireHoldsEvery calls ExpireHolds on every tick of interval until ctx is
+// done.
+func (q *Queryer) ExpireHoldsEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		expired, err := q.ExpireHolds(ctx)
+		if err != nil {
+			log.Errorf(ctx, "while expiring holds: %s", err)
+		}
+		if expired > 0 {
+			log.Infof(ctx, "expired %d holds", expired)
+		}
+	}
+}
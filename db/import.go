@@ -0,0 +1,178 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/db/sqlc"
+)
+
+// importBatchSize is how many rows are copied into the staging table at a
+// time.
+const importBatchSize = 10000
+
+// errImportAborted rolls back an import with FailOnExisting that found errors.
+var errImportAborted = errors.New("import aborted")
+
+// stageBooks copies every valid row of reader into the staging table. Invalid
+// rows are added to the report.
+func stageBooks(ctx context.Context, queries *sqlc.Queries, reader library.BookReader, report *library.ImportReport) (int64, error) {
+	var staged int64
+	batch := make([]sqlc.StageBookImportParams, 0, importBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		n, err := queries.StageBookImport(ctx, batch)
+		if err != nil {
+			return queryError(err, "while staging books")
+		}
+		staged += n
+		batch = batch[:0]
+		return nil
+	}
+	for {
+		book, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return staged, flush()
+		}
+		report.Rows++
+		var libErr *library.Error
+		if errors.As(err, &libErr) && libErr.Type == library.BadInput {
+			report.AddError(library.ImportError{Row: report.Rows, Message: err.Error()})
+			continue
+		}
+		if err != nil {
+			return staged, &library.Error{
+				Type:   library.BadInput,
+				Actual: err,
+				Desc:   fmt.Sprintf("while reading row %d", report.Rows),
+			}
+		}
+		if book.Title == "" {
+			report.AddError(library.ImportError{Row: report.Rows, Message: "a title is required"})
+			continue
+		}
+		batch = append(batch, sqlc.StageBookImportParams{
+			RowNumber: report.Rows,
+			Isbn:      int64(book.ISBN),
+			Title:     book.Title,
+		})
+		if len(batch) == importBatchSize {
+			err = flush()
+			if err != nil {
+				return staged, err
+			}
+		}
+	}
+}
+
+// mergeBooks merges the staged books into the library and returns how many
+// were created or changed.
+func mergeBooks(ctx context.Context, queries *sqlc.Queries, mode library.ImportMode) (int64, error) {
+	switch mode {
+	case library.SkipExisting:
+		return queries.MergeBookImportSkip(ctx)
+	case library.OverwriteExisting:
+		return queries.MergeBookImportOverwrite(ctx)
+	default:
+		return queries.MergeBookImport(ctx)
+	}
+}
+
+// ImportBooks creates the books read from reader in one transaction. Rows are
+// staged with COPY and merged into the library with a single statement so
+// large catalogs load quickly. When an ISBN is on more than one row, the first
+// row is imported. Overwriting doesn't change books in the trash; their rows
+// are errors instead.
+func (q *Queryer) ImportBooks(ctx context.Context, reader library.BookReader, mode library.ImportMode) (library.ImportReport, error) {
+	switch mode {
+	case library.SkipExisting, library.OverwriteExisting, library.FailOnExisting:
+	default:
+		return library.ImportReport{}, &library.Error{
+			Type:   library.BadInput,
+			Actual: fmt.Errorf("unknown import mode %q", mode),
+			Desc:   "while importing books",
+		}
+	}
+	var report library.ImportReport
+	// reader can only be read once, so the import isn't retried.
+	err := q.inTxAs(ctx, TxOptions{MaxAttempts: 1}, func(queries *sqlc.Queries) error {
+		staged, err := stageBooks(ctx, queries, reader, &report)
+		if err != nil {
+			return err
+		}
+		duplicates, err := queries.ListBookImportDuplicates(ctx)
+		if err != nil {
+			return queryError(err, "while finding duplicate rows")
+		}
+		for _, d := range duplicates {
+			report.AddError(library.ImportError{
+				Row:     d.RowNumber,
+				Message: fmt.Sprintf("isbn %s is already on row %d", library.ISBN(d.Isbn), d.FirstRow),
+			})
+		}
+		if mode == library.FailOnExisting {
+			conflicts, err := queries.ListBookImportConflicts(ctx)
+			if err != nil {
+				return queryError(err, "while finding existing books")
+			}
+			for _, c := range conflicts {
+				report.AddError(library.ImportError{
+					Row:     c.RowNumber,
+					Message: fmt.Sprintf("a book with isbn %s already exists", library.ISBN(c.Isbn)),
+				})
+			}
+		}
+		var trashed []sqlc.ListBookImportTrashedRow
+		if mode == library.OverwriteExisting {
+			trashed, err = queries.ListBookImportTrashed(ctx)
+			if err != nil {
+				return queryError(err, "while finding trashed books")
+			}
+			for _, t := range trashed {
+				report.AddError(library.ImportError{
+					Row:     t.RowNumber,
+					Message: fmt.Sprintf("the book with isbn %s is in the trash", library.ISBN(t.Isbn)),
+				})
+			}
+		}
+		report.Sort()
+		if mode == library.FailOnExisting && report.ErrorCount > 0 {
+			return errImportAborted
+		}
+		merged, err := mergeBooks(ctx, queries, mode)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+				return &library.Error{
+					Type:   library.Conflict,
+					Actual: err,
+					Desc:   "a book was created while importing it",
+				}
+			}
+			return queryError(err, "while merging imported books")
+		}
+		report.Imported = merged
+		report.Skipped = staged - int64(len(duplicates)) - int64(len(trashed)) - merged
+		err = queries.ClearBookImport(ctx)
+		if err != nil {
+			return queryError(err, "while clearing staged books")
+		}
+		return nil
+	})
+	if errors.Is(err, errImportAborted) {
+		report.Aborted = true
+		return report, nil
+	}
+	if err != nil {
+		return library.ImportReport{}, err
+	}
+	return report, nil
+}
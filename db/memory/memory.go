@@ -0,0 +1,547 @@
+// Package memory keeps the catalog of books and authors in memory. It is for
+// running the API locally and in unit tests; everything is lost when the
+// process exits.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/db/cursor"
+)
+
+// A book is stored with the ids of its authors so that renaming an author
+// shows up in every book.
+type book struct {
+	library.Book
+	authorIDs []int64
+}
+
+// A Store holds books and authors behind a single lock. The zero value is
+// not ready to use; call New.
+type Store struct {
+	mu           sync.RWMutex
+	books        map[library.ISBN]book
+	authors      map[int64]string
+	lastAuthorID int64
+}
+
+// New returns an empty store.
+func New() *Store {
+	return &Store{
+		books:   make(map[library.ISBN]book),
+		authors: make(map[int64]string),
+	}
+}
+
+// now is rounded to microseconds like Postgres timestamps so that times can
+// round-trip through page tokens.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+func badPageToken(err error) error {
+	return &library.Error{
+		Type:   library.BadInput,
+		Actual: err,
+		Desc:   "while decoding page token",
+	}
+}
+
+func bookNotFound(isbn library.ISBN, desc string) error {
+	return &library.Error{
+		Type:   library.NotFound,
+		Actual: fmt.Errorf("no book with isbn %s", isbn),
+		Desc:   desc,
+	}
+}
+
+func authorNotFound(id int64, desc string) error {
+	return &library.Error{
+		Type:   library.NotFound,
+		Actual: fmt.Errorf("no author with id %d", id),
+		Desc:   desc,
+	}
+}
+
+// sortKey is the key of b in order, formatted the way page tokens hold it.
+func sortKey(b library.Book, order library.BookOrder) string {
+	switch order {
+	case library.ByTitle:
+		return b.Title
+	case library.ByCreatedAt:
+		return b.CreatedAt.Format(time.RFC3339Nano)
+	}
+	return ""
+}
+
+// less sorts books by order and then isbn.
+func less(a library.Book, b library.Book, order library.BookOrder) bool {
+	switch order {
+	case library.ByTitle:
+		if a.Title != b.Title {
+			return a.Title < b.Title
+		}
+	case library.ByCreatedAt:
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+	}
+	return a.ISBN < b.ISBN
+}
+
+// afterCursor reports whether b comes after the last book of the previous
+// page in order.
+func afterCursor(b library.Book, order library.BookOrder, descending bool, after cursor.Cursor) (bool, error) {
+	if after == cursor.FirstPage {
+		return true, nil
+	}
+	last := library.Book{ISBN: library.ISBN(after.ID), Title: after.Key}
+	if order == library.ByCreatedAt {
+		parsed, err := time.Parse(time.RFC3339Nano, after.Key)
+		if err != nil {
+			return false, badPageToken(err)
+		}
+		last.CreatedAt = parsed
+	}
+	if descending {
+		return less(b, last, order), nil
+	}
+	return less(last, b, order), nil
+}
+
+func matches(b library.Book, filter library.BookFilter) bool {
+	switch {
+	case !strings.HasPrefix(b.Title, filter.TitlePrefix):
+	case filter.MinISBN != 0 && b.ISBN < filter.MinISBN:
+	case filter.MaxISBN != 0 && b.ISBN > filter.MaxISBN:
+	case !filter.CreatedAfter.IsZero() && b.CreatedAt.Before(filter.CreatedAfter):
+	case !filter.CreatedBefore.IsZero() && !b.CreatedAt.Before(filter.CreatedBefore):
+	case !filter.UpdatedAfter.IsZero() && b.UpdatedAt.Before(filter.UpdatedAfter):
+	case !filter.UpdatedBefore.IsZero() && !b.UpdatedAt.Before(filter.UpdatedBefore):
+	default:
+		return true
+	}
+	return false
+}
+
+// page sorts books and returns the page after the cursor, with a next-page
+// token for token if the page is full.
+func page(books []library.Book, order library.BookOrder, descending bool, token string, after cursor.Cursor, totalSize int32) (library.BookList, error) {
+	sort.Slice(books, func(i, j int) bool {
+		if descending {
+			return less(books[j], books[i], order)
+		}
+		return less(books[i], books[j], order)
+	})
+	var result library.BookList
+	for _, b := range books {
+		if len(result.Books) == int(totalSize) {
+			break
+		}
+		ok, err := afterCursor(b, order, descending, after)
+		if err != nil {
+			return library.BookList{}, err
+		}
+		if ok {
+			result.Books = append(result.Books, b)
+		}
+	}
+	if len(result.Books) > 0 && len(result.Books) == int(totalSize) {
+		last := result.Books[len(result.Books)-1]
+		var err error
+		result.NextPageToken, err = cursor.Encode(cursor.Cursor{
+			Order: token,
+			Key:   sortKey(last, order),
+			ID:    int64(last.ISBN),
+		})
+		if err != nil {
+			return library.BookList{}, err
+		}
+	}
+	return result, nil
+}
+
+// ListBooks returns a filtered list of books in the order the filter asks
+// for.
+func (s *Store) ListBooks(ctx context.Context, filter library.BookFilter, PageToken string, TotalSize int32) (library.BookList, error) {
+	if filter.OrderBy == "" {
+		filter.OrderBy = library.ByTitle
+	}
+	switch filter.OrderBy {
+	case library.ByTitle, library.ByISBN, library.ByCreatedAt:
+	default:
+		return library.BookList{}, &library.Error{
+			Type:   library.BadInput,
+			Actual: fmt.Errorf("unknown order %q", filter.OrderBy),
+			Desc:   "while listing books",
+		}
+	}
+	order := string(filter.OrderBy)
+	if filter.Descending {
+		order = "-" + order
+	}
+	after, err := cursor.Decode(PageToken)
+	if err == nil && after != cursor.FirstPage && after.Order != order {
+		err = fmt.Errorf("page token is for order %q but the list is in order %q", after.Order, order)
+	}
+	if err != nil {
+		return library.BookList{}, badPageToken(err)
+	}
+	s.mu.RLock()
+	var books []library.Book
+	for _, b := range s.books {
+		if matches(b.Book, filter) {
+			books = append(books, b.Book)
+		}
+	}
+	s.mu.RUnlock()
+	return page(books, filter.OrderBy, filter.Descending, order, after, TotalSize)
+}
+
+// badAuthors describes what is wrong with the authors of a book, or returns
+// an empty description if every author exists and is listed once. It must be
+// called with the lock held.
+func (s *Store) badAuthors(authors []library.Author) (string, error) {
+	seen := make(map[int64]bool, len(authors))
+	for _, a := range authors {
+		if _, ok := s.authors[a.ID]; !ok {
+			return "an author does not exist", fmt.Errorf("no author with id %d", a.ID)
+		}
+		if seen[a.ID] {
+			return "an author is listed more than once", fmt.Errorf("author %d is listed more than once", a.ID)
+		}
+		seen[a.ID] = true
+	}
+	return "", nil
+}
+
+func authorIDs(authors []library.Author) []int64 {
+	ids := make([]int64, 0, len(authors))
+	for _, a := range authors {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+func sameIDs(a []int64, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// withAuthors returns the book with its authors. It must be called with the
+// lock held.
+func (s *Store) withAuthors(b book) library.Book {
+	result := b.Book
+	result.Authors = nil
+	for _, id := range b.authorIDs {
+		result.Authors = append(result.Authors, library.Author{
+			ID:   id,
+			Name: s.authors[id],
+		})
+	}
+	return result
+}
+
+// CreateBook creates a single book. Its authors must already exist.
+func (s *Store) CreateBook(ctx context.Context, b library.Book) error {
+	_, err := library.NewISBN(int64(b.ISBN))
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.books[b.ISBN]; ok {
+		return &library.Error{
+			Type:   library.Conflict,
+			Actual: fmt.Errorf("book %s already exists", b.ISBN),
+			Desc:   "a book with that isbn already exists",
+		}
+	}
+	desc, err := s.badAuthors(b.Authors)
+	if err != nil {
+		return &library.Error{
+			Type:   library.BadInput,
+			Actual: err,
+			Desc:   desc,
+		}
+	}
+	created := now()
+	s.books[b.ISBN] = book{
+		Book: library.Book{
+			ISBN:      b.ISBN,
+			Title:     b.Title,
+			CreatedAt: created,
+			UpdatedAt: created,
+			Version:   1,
+		},
+		authorIDs: authorIDs(b.Authors),
+	}
+	return nil
+}
+
+// DeleteBook deletes a single book.
+func (s *Store) DeleteBook(ctx context.Context, isbn library.ISBN) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.books[isbn]; !ok {
+		return bookNotFound(isbn, "while deleting a book")
+	}
+	delete(s.books, isbn)
+	return nil
+}
+
+// GetBook fetches a single book and its authors.
+func (s *Store) GetBook(ctx context.Context, isbn library.ISBN) (library.Book, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	b, ok := s.books[isbn]
+	if !ok {
+		return library.Book{}, bookNotFound(isbn, "while fetching a book")
+	}
+	return s.withAuthors(b), nil
+}
+
+// update moves a book to its next version. It must be called with the lock
+// held.
+func (s *Store) update(b book, title string) book {
+	b.Title = title
+	b.UpdatedAt = now()
+	b.Version++
+	s.books[b.ISBN] = b
+	return b
+}
+
+// UpdateBook updates a single book and returns it at its new version. If
+// b.Version isn't zero, the update fails unless the book is still at that
+// version.
+func (s *Store) UpdateBook(ctx context.Context, b library.Book) (library.Book, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.books[b.ISBN]
+	if !ok {
+		return library.Book{}, bookNotFound(b.ISBN, "while updating a book")
+	}
+	if b.Version != 0 && current.Version != b.Version {
+		return library.Book{}, &library.Error{
+			Type:   library.PreconditionFailed,
+			Actual: fmt.Errorf("book %s is at version %d, not %d", b.ISBN, current.Version, b.Version),
+			Desc:   "the book changed since it was read",
+		}
+	}
+	return s.update(current, b.Title).Book, nil
+}
+
+// PatchBook changes a book with patch while holding the lock, so the patch is
+// applied to the latest version of the book. If version isn't zero, the book
+// must still be at that version. The book is only moved to its next version
+// if the patch changed it.
+func (s *Store) PatchBook(ctx context.Context, isbn library.ISBN, version int64, patch library.BookPatch) (library.Book, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.books[isbn]
+	if !ok {
+		return library.Book{}, bookNotFound(isbn, "while patching a book")
+	}
+	if version != 0 && current.Version != version {
+		return library.Book{}, &library.Error{
+			Type:   library.PreconditionFailed,
+			Actual: fmt.Errorf("book %s is at version %d, not %d", isbn, current.Version, version),
+			Desc:   "the book changed since it was read",
+		}
+	}
+	patched, err := patch.Apply(s.withAuthors(current))
+	if err != nil {
+		return library.Book{}, err
+	}
+	ids := authorIDs(patched.Authors)
+	authorsChanged := !sameIDs(ids, current.authorIDs)
+	if patched.Title == current.Title && !authorsChanged {
+		return s.withAuthors(current), nil
+	}
+	if authorsChanged {
+		desc, err := s.badAuthors(patched.Authors)
+		if err != nil {
+			return library.Book{}, &library.Error{
+				Type:   library.BadInput,
+				Actual: library.FieldErrors{{Field: "author_ids", Message: desc}},
+				Desc:   "while patching a book",
+			}
+		}
+		current.authorIDs = ids
+	}
+	return s.withAuthors(s.update(current, patched.Title)), nil
+}
+
+// ExportBooks writes every book to writer in isbn order. The books are copied
+// first so that writer can be slow without holding up other requests.
+func (s *Store) ExportBooks(ctx context.Context, writer library.BookWriter) error {
+	s.mu.RLock()
+	books := make([]library.Book, 0, len(s.books))
+	for _, b := range s.books {
+		books = append(books, b.Book)
+	}
+	s.mu.RUnlock()
+	sort.Slice(books, func(i, j int) bool {
+		return books[i].ISBN < books[j].ISBN
+	})
+	for _, b := range books {
+		err := writer.Write(b)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// authorOrder is the order of author lists.
+const authorOrder = "name"
+
+// authorBooksOrder is the order of the book lists of an author, which are
+// ordered by title but don't share page tokens with book lists.
+const authorBooksOrder = "author_books"
+
+// ListAuthors returns a list of authors ordered by name.
+func (s *Store) ListAuthors(ctx context.Context, PageToken string, TotalSize int32) (library.AuthorList, error) {
+	after, err := cursor.Decode(PageToken)
+	if err == nil && after != cursor.FirstPage && after.Order != authorOrder {
+		err = fmt.Errorf("page token is for order %q", after.Order)
+	}
+	if err != nil {
+		return library.AuthorList{}, badPageToken(err)
+	}
+	s.mu.RLock()
+	var authors []library.Author
+	for id, name := range s.authors {
+		if name > after.Key || (name == after.Key && id > after.ID) {
+			authors = append(authors, library.Author{ID: id, Name: name})
+		}
+	}
+	s.mu.RUnlock()
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].Name != authors[j].Name {
+			return authors[i].Name < authors[j].Name
+		}
+		return authors[i].ID < authors[j].ID
+	})
+	var result library.AuthorList
+	if len(authors) >= int(TotalSize) {
+		authors = authors[:TotalSize]
+		if len(authors) > 0 {
+			last := authors[len(authors)-1]
+			result.NextPageToken, err = cursor.Encode(cursor.Cursor{
+				Order: authorOrder,
+				Key:   last.Name,
+				ID:    last.ID,
+			})
+			if err != nil {
+				return library.AuthorList{}, err
+			}
+		}
+	}
+	result.Authors = authors
+	return result, nil
+}
+
+// ListAuthorBooks returns a list of books by a single author ordered by title.
+func (s *Store) ListAuthorBooks(ctx context.Context, authorID int64, PageToken string, TotalSize int32) (library.BookList, error) {
+	after, err := cursor.Decode(PageToken)
+	if err == nil && after != cursor.FirstPage && after.Order != authorBooksOrder {
+		err = fmt.Errorf("page token is for order %q", after.Order)
+	}
+	if err != nil {
+		return library.BookList{}, badPageToken(err)
+	}
+	s.mu.RLock()
+	if _, ok := s.authors[authorID]; !ok {
+		s.mu.RUnlock()
+		return library.BookList{}, authorNotFound(authorID, "while fetching an author")
+	}
+	var books []library.Book
+	for _, b := range s.books {
+		for _, id := range b.authorIDs {
+			if id == authorID {
+				books = append(books, b.Book)
+				break
+			}
+		}
+	}
+	s.mu.RUnlock()
+	return page(books, library.ByTitle, false, authorBooksOrder, after, TotalSize)
+}
+
+// CreateAuthor creates a single author and returns it with its new id.
+func (s *Store) CreateAuthor(ctx context.Context, author library.Author) (library.Author, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastAuthorID++
+	author.ID = s.lastAuthorID
+	s.authors[author.ID] = author.Name
+	return author, nil
+}
+
+// DeleteAuthor deletes a single author. Authors of existing books can't be
+// deleted.
+func (s *Store) DeleteAuthor(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.authors[id]; !ok {
+		return authorNotFound(id, "while deleting an author")
+	}
+	for _, b := range s.books {
+		for _, authorID := range b.authorIDs {
+			if authorID == id {
+				return &library.Error{
+					Type:   library.Conflict,
+					Actual: fmt.Errorf("author %d wrote book %s", id, b.ISBN),
+					Desc:   "the author still has books",
+				}
+			}
+		}
+	}
+	delete(s.authors, id)
+	return nil
+}
+
+// GetAuthor fetches a single author.
+func (s *Store) GetAuthor(ctx context.Context, id int64) (library.Author, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	name, ok := s.authors[id]
+	if !ok {
+		return library.Author{}, authorNotFound(id, "while fetching an author")
+	}
+	return library.Author{ID: id, Name: name}, nil
+}
+
+// UpdateAuthor updates a single author.
+func (s *Store) UpdateAuthor(ctx context.Context, author library.Author) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.authors[author.ID]; !ok {
+		return authorNotFound(author.ID, "while updating an author")
+	}
+	s.authors[author.ID] = author.Name
+	return nil
+}
@@ -0,0 +1,11 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/slcjordan/library/test/conformance"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, New())
+}
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE author (
+  id BIGSERIAL NOT NULL PRIMARY KEY,
+  name TEXT NOT NULL
+);
+
+CREATE INDEX author_name_id_idx ON author (name, id);
+
+CREATE TABLE book_author (
+  isbn BIGINT NOT NULL REFERENCES book (isbn) ON DELETE CASCADE,
+  author_id BIGINT NOT NULL REFERENCES author (id) ON DELETE RESTRICT,
+  position INTEGER NOT NULL,
+  PRIMARY KEY (isbn, author_id),
+  UNIQUE (isbn, position)
+);
+
+CREATE INDEX book_author_author_id_idx ON book_author (author_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS book_author;
+DROP TABLE IF EXISTS author;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE copy (
+  barcode TEXT NOT NULL PRIMARY KEY,
+  isbn BIGINT NOT NULL REFERENCES book (isbn) ON DELETE RESTRICT,
+  location TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'available' CHECK (status IN ('available', 'on_loan', 'lost'))
+);
+
+CREATE INDEX copy_isbn_idx ON copy (isbn);
+
+CREATE TABLE patron (
+  id BIGSERIAL NOT NULL PRIMARY KEY,
+  name TEXT NOT NULL,
+  borrowing_limit INTEGER NOT NULL CHECK (borrowing_limit >= 0)
+);
+
+CREATE TABLE loan (
+  id BIGSERIAL NOT NULL PRIMARY KEY,
+  barcode TEXT NOT NULL REFERENCES copy (barcode) ON DELETE RESTRICT,
+  patron_id BIGINT NOT NULL REFERENCES patron (id) ON DELETE RESTRICT,
+  checked_out_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  due_at TIMESTAMPTZ NOT NULL,
+  returned_at TIMESTAMPTZ,
+  renewals INTEGER NOT NULL DEFAULT 0
+);
+
+-- a copy can only be on one open loan at a time.
+CREATE UNIQUE INDEX loan_barcode_open_idx ON loan (barcode) WHERE returned_at IS NULL;
+
+CREATE INDEX loan_patron_id_open_idx ON loan (patron_id) WHERE returned_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS loan;
+DROP TABLE IF EXISTS patron;
+DROP TABLE IF EXISTS copy;
+-- +goose StatementEnd
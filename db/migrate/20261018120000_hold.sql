@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE copy DROP CONSTRAINT copy_status_check;
+ALTER TABLE copy ADD CONSTRAINT copy_status_check CHECK (status IN ('available', 'on_loan', 'on_hold_shelf', 'lost'));
+
+CREATE TABLE hold (
+  id BIGSERIAL NOT NULL PRIMARY KEY,
+  isbn BIGINT NOT NULL REFERENCES book (isbn) ON DELETE RESTRICT,
+  patron_id BIGINT NOT NULL REFERENCES patron (id) ON DELETE RESTRICT,
+  placed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  status TEXT NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'ready', 'fulfilled', 'expired', 'cancelled')),
+  barcode TEXT REFERENCES copy (barcode) ON DELETE RESTRICT,
+  pickup_by TIMESTAMPTZ
+);
+
+-- a patron can only wait once for the same title.
+CREATE UNIQUE INDEX hold_patron_id_isbn_active_idx ON hold (patron_id, isbn) WHERE status IN ('waiting', 'ready');
+
+-- a copy on the hold shelf is set aside for exactly one hold.
+CREATE UNIQUE INDEX hold_barcode_ready_idx ON hold (barcode) WHERE status = 'ready';
+
+CREATE INDEX hold_isbn_active_idx ON hold (isbn, id) WHERE status IN ('waiting', 'ready');
+
+CREATE INDEX hold_pickup_by_ready_idx ON hold (pickup_by) WHERE status = 'ready';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS hold;
+
+UPDATE copy SET status = 'available' WHERE status = 'on_hold_shelf';
+ALTER TABLE copy DROP CONSTRAINT copy_status_check;
+ALTER TABLE copy ADD CONSTRAINT copy_status_check CHECK (status IN ('available', 'on_loan', 'lost'));
+-- +goose StatementEnd
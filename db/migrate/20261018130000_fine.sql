@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE patron ADD COLUMN class TEXT NOT NULL DEFAULT 'standard';
+
+CREATE TABLE ledger_entry (
+  id BIGSERIAL NOT NULL PRIMARY KEY,
+  patron_id BIGINT NOT NULL REFERENCES patron (id) ON DELETE RESTRICT,
+  loan_id BIGINT REFERENCES loan (id) ON DELETE RESTRICT,
+  kind TEXT NOT NULL CHECK (kind IN ('fine', 'payment', 'waiver')),
+  -- fines are charged as positive amounts and paid or waived as negative
+  -- amounts so that a patron's balance is the sum of their entries.
+  amount BIGINT NOT NULL CHECK ((kind = 'fine') = (amount > 0) AND amount <> 0),
+  note TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ledger_entry_patron_id_idx ON ledger_entry (patron_id, id);
+
+-- a loan is only ever fined once.
+CREATE UNIQUE INDEX ledger_entry_loan_id_fine_idx ON ledger_entry (loan_id) WHERE kind = 'fine';
+
+-- the ledger is the audit trail of every fine, payment and waiver so its
+-- entries are never changed; mistakes are corrected with new entries.
+CREATE FUNCTION ledger_entry_append_only() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  RAISE EXCEPTION 'ledger entries can not be changed';
+END;
+$$;
+
+CREATE TRIGGER ledger_entry_append_only BEFORE UPDATE OR DELETE ON ledger_entry
+FOR EACH ROW EXECUTE FUNCTION ledger_entry_append_only();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS ledger_entry;
+DROP FUNCTION IF EXISTS ledger_entry_append_only;
+ALTER TABLE patron DROP COLUMN IF EXISTS class;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX book_title_search_idx ON book USING gin (to_tsvector('english', title));
+
+CREATE INDEX book_title_trgm_idx ON book USING gin (title gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS book_title_trgm_idx;
+DROP INDEX IF EXISTS book_title_search_idx;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE book ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE book ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX book_created_at_isbn_idx ON book (created_at, isbn);
+
+CREATE INDEX book_updated_at_idx ON book (updated_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS book_updated_at_idx;
+DROP INDEX IF EXISTS book_created_at_isbn_idx;
+ALTER TABLE book DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE book DROP COLUMN IF EXISTS created_at;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- book_import stages the rows of a bulk import until they are merged into
+-- book. Rows are only ever written and deleted inside the transaction of
+-- their import so concurrent imports never see each other's rows, and the
+-- table isn't logged since nothing in it outlives a transaction.
+CREATE UNLOGGED TABLE book_import (
+  row_number BIGINT NOT NULL,
+  isbn BIGINT NOT NULL,
+  title TEXT NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS book_import;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- version goes up by one on every change to a book so that clients can tell
+-- whether a book changed since they read it.
+ALTER TABLE book ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE book DROP COLUMN IF EXISTS version;
+-- +goose StatementEnd
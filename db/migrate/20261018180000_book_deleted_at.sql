@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- deleted_at is set when a book is moved to the trash. Trashed books are
+-- hidden until they are restored or purged.
+ALTER TABLE book ADD COLUMN deleted_at TIMESTAMPTZ;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX book_deleted_at_idx ON book (deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS book_deleted_at_idx;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE book DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,73 @@
+-- +goose Up
+-- +goose StatementBegin
+-- book_audit records every change to a book for compliance. Rows are written
+-- by a trigger in the transaction of the change, so no write can skip it.
+-- isbn has no foreign key since the history outlives purged books.
+CREATE TABLE book_audit (
+  id BIGSERIAL NOT NULL PRIMARY KEY,
+  isbn BIGINT NOT NULL,
+  action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore', 'purge')),
+  -- the actor and request id are set with set_config for the transaction.
+  actor TEXT NOT NULL DEFAULT '',
+  request_id TEXT NOT NULL DEFAULT '',
+  -- the book row before and after the change. old_book is null when the
+  -- book is created and new_book is null when it is purged.
+  old_book JSONB,
+  new_book JSONB,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX book_audit_isbn_id_idx ON book_audit (isbn, id);
+
+CREATE FUNCTION book_audit_record() RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+  change TEXT;
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    change := 'create';
+  ELSIF TG_OP = 'DELETE' THEN
+    change := 'purge';
+  ELSIF OLD IS NOT DISTINCT FROM NEW THEN
+    RETURN NULL;
+  ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+    change := 'delete';
+  ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+    change := 'restore';
+  ELSE
+    change := 'update';
+  END IF;
+  INSERT INTO book_audit (isbn, action, actor, request_id, old_book, new_book)
+  VALUES (
+    CASE WHEN TG_OP = 'DELETE' THEN OLD.isbn ELSE NEW.isbn END,
+    change,
+    coalesce(current_setting('library.actor', true), ''),
+    coalesce(current_setting('library.request_id', true), ''),
+    CASE WHEN TG_OP = 'INSERT' THEN NULL ELSE to_jsonb(OLD) END,
+    CASE WHEN TG_OP = 'DELETE' THEN NULL ELSE to_jsonb(NEW) END
+  );
+  RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER book_audit_record AFTER INSERT OR UPDATE OR DELETE ON book
+FOR EACH ROW EXECUTE FUNCTION book_audit_record();
+
+CREATE FUNCTION book_audit_append_only() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  RAISE EXCEPTION 'audit records can not be changed';
+END;
+$$;
+
+CREATE TRIGGER book_audit_append_only BEFORE UPDATE OR DELETE ON book_audit
+FOR EACH ROW EXECUTE FUNCTION book_audit_append_only();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS book_audit_record ON book;
+DROP FUNCTION IF EXISTS book_audit_record;
+DROP TABLE IF EXISTS book_audit;
+DROP FUNCTION IF EXISTS book_audit_append_only;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- txid is the transaction that wrote an audit record. Ids are handed out
+-- before commit, so a record can become visible after one with a higher id;
+-- the change feed reads records in (txid, id) order and only once every
+-- older transaction has finished, which makes that order gapless.
+ALTER TABLE book_audit ADD COLUMN txid BIGINT NOT NULL DEFAULT txid_current();
+
+CREATE INDEX book_audit_txid_id_idx ON book_audit (txid, id);
+
+-- book_audit_notify wakes the change feeds once the records of a statement
+-- commit. Notifications are only a hint; feeds read the records themselves.
+CREATE FUNCTION book_audit_notify() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  PERFORM pg_notify('book_changes', '');
+  RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER book_audit_notify AFTER INSERT ON book_audit
+FOR EACH STATEMENT EXECUTE FUNCTION book_audit_notify();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS book_audit_notify ON book_audit;
+DROP FUNCTION IF EXISTS book_audit_notify;
+DROP INDEX IF EXISTS book_audit_txid_id_idx;
+ALTER TABLE book_audit DROP COLUMN IF EXISTS txid;
+-- +goose StatementEnd
@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+-- webhook is a partner system that is sent the changes to books. It is sent
+-- every action if events is empty. secret signs the payloads.
+CREATE TABLE webhook (
+  id BIGSERIAL NOT NULL PRIMARY KEY,
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,
+  events TEXT[] NOT NULL DEFAULT '{}',
+  active BOOLEAN NOT NULL DEFAULT true,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- webhook_delivery is the queue of audit records to send to webhooks and the
+-- log of how sending them went. A delivery is dead once it has failed too
+-- many times and is only retried by hand.
+CREATE TABLE webhook_delivery (
+  id BIGSERIAL NOT NULL PRIMARY KEY,
+  webhook_id BIGINT NOT NULL REFERENCES webhook (id) ON DELETE CASCADE,
+  audit_id BIGINT NOT NULL REFERENCES book_audit (id),
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+  attempts INTEGER NOT NULL DEFAULT 0,
+  -- a pending delivery is sent at next_attempt_at. Claiming a delivery
+  -- pushes it back so that a worker that dies while sending it doesn't lose
+  -- it.
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_attempt_at TIMESTAMPTZ,
+  response_status INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  delivered_at TIMESTAMPTZ
+);
+
+CREATE INDEX webhook_delivery_webhook_id_id_idx ON webhook_delivery (webhook_id, id);
+CREATE INDEX webhook_delivery_next_attempt_at_idx ON webhook_delivery (next_attempt_at) WHERE status = 'pending';
+
+-- webhook_enqueue queues an audit record for every webhook that wants it, in
+-- the transaction of the change.
+CREATE FUNCTION webhook_enqueue() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  INSERT INTO webhook_delivery (webhook_id, audit_id)
+  SELECT id, NEW.id
+  FROM webhook
+  WHERE active
+  AND (cardinality(events) = 0 OR NEW.action = ANY (events));
+  RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER webhook_enqueue AFTER INSERT ON book_audit
+FOR EACH ROW EXECUTE FUNCTION webhook_enqueue();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS webhook_enqueue ON book_audit;
+DROP FUNCTION IF EXISTS webhook_enqueue;
+DROP TABLE IF EXISTS webhook_delivery;
+DROP TABLE IF EXISTS webhook;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- holds that are no longer active are history of the book and are purged
+-- along with it.
+ALTER TABLE hold DROP CONSTRAINT hold_isbn_fkey;
+ALTER TABLE hold ADD CONSTRAINT hold_isbn_fkey FOREIGN KEY (isbn) REFERENCES book (isbn) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE hold DROP CONSTRAINT hold_isbn_fkey;
+ALTER TABLE hold ADD CONSTRAINT hold_isbn_fkey FOREIGN KEY (isbn) REFERENCES book (isbn) ON DELETE RESTRICT;
+-- +goose StatementEnd
@@ -0,0 +1,90 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the audit records of a book also record the ids of its authors in the
+-- order they are credited.
+CREATE OR REPLACE FUNCTION book_audit_record() RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+  change TEXT;
+  old_image JSONB;
+  new_image JSONB;
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    change := 'create';
+  ELSIF TG_OP = 'DELETE' THEN
+    change := 'purge';
+  ELSIF OLD IS NOT DISTINCT FROM NEW THEN
+    RETURN NULL;
+  ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+    change := 'delete';
+  ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+    change := 'restore';
+  ELSE
+    change := 'update';
+  END IF;
+  -- authors are changed before the book is moved to its next version, so
+  -- the authors it had before are those of its last record. Records from
+  -- before authors were recorded have none.
+  IF TG_OP <> 'INSERT' THEN
+    old_image := to_jsonb(OLD) || jsonb_build_object('author_ids', (
+      SELECT book_audit.new_book -> 'author_ids'
+      FROM book_audit
+      WHERE book_audit.isbn = OLD.isbn
+      ORDER BY book_audit.id DESC
+      LIMIT 1
+    ));
+  END IF;
+  IF TG_OP <> 'DELETE' THEN
+    new_image := to_jsonb(NEW) || jsonb_build_object('author_ids', (
+      SELECT coalesce(jsonb_agg(book_author.author_id ORDER BY book_author.position), '[]')
+      FROM book_author
+      WHERE book_author.isbn = NEW.isbn
+    ));
+  END IF;
+  INSERT INTO book_audit (isbn, action, actor, request_id, old_book, new_book)
+  VALUES (
+    CASE WHEN TG_OP = 'DELETE' THEN OLD.isbn ELSE NEW.isbn END,
+    change,
+    coalesce(current_setting('library.actor', true), ''),
+    coalesce(current_setting('library.request_id', true), ''),
+    old_image,
+    new_image
+  );
+  RETURN NULL;
+END;
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION book_audit_record() RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+  change TEXT;
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    change := 'create';
+  ELSIF TG_OP = 'DELETE' THEN
+    change := 'purge';
+  ELSIF OLD IS NOT DISTINCT FROM NEW THEN
+    RETURN NULL;
+  ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+    change := 'delete';
+  ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+    change := 'restore';
+  ELSE
+    change := 'update';
+  END IF;
+  INSERT INTO book_audit (isbn, action, actor, request_id, old_book, new_book)
+  VALUES (
+    CASE WHEN TG_OP = 'DELETE' THEN OLD.isbn ELSE NEW.isbn END,
+    change,
+    coalesce(current_setting('library.actor', true), ''),
+    coalesce(current_setting('library.request_id', true), ''),
+    CASE WHEN TG_OP = 'INSERT' THEN NULL ELSE to_jsonb(OLD) END,
+    CASE WHEN TG_OP = 'DELETE' THEN NULL ELSE to_jsonb(NEW) END
+  );
+  RETURN NULL;
+END;
+$$;
+-- +goose StatementEnd
@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the actor is set with set_config for the transaction, as it is for the
+-- audit records of books.
+ALTER TABLE ledger_entry ADD COLUMN actor TEXT NOT NULL DEFAULT coalesce(current_setting('library.actor', true), '');
+
+-- patron_class_change records every change to the class of a patron, which
+-- picks the fee schedule their fines are assessed with.
+CREATE TABLE patron_class_change (
+  id BIGSERIAL NOT NULL PRIMARY KEY,
+  patron_id BIGINT NOT NULL REFERENCES patron (id) ON DELETE RESTRICT,
+  old_class TEXT NOT NULL,
+  new_class TEXT NOT NULL,
+  actor TEXT NOT NULL DEFAULT coalesce(current_setting('library.actor', true), ''),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX patron_class_change_patron_id_idx ON patron_class_change (patron_id, id);
+
+CREATE FUNCTION patron_class_change_record() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  INSERT INTO patron_class_change (patron_id, old_class, new_class)
+  VALUES (NEW.id, OLD.class, NEW.class);
+  RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER patron_class_change_record AFTER UPDATE OF class ON patron
+FOR EACH ROW WHEN (OLD.class IS DISTINCT FROM NEW.class)
+EXECUTE FUNCTION patron_class_change_record();
+
+CREATE FUNCTION patron_class_change_append_only() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  RAISE EXCEPTION 'class changes can not be changed';
+END;
+$$;
+
+CREATE TRIGGER patron_class_change_append_only BEFORE UPDATE OR DELETE ON patron_class_change
+FOR EACH ROW EXECUTE FUNCTION patron_class_change_append_only();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS patron_class_change_record ON patron;
+DROP FUNCTION IF EXISTS patron_class_change_record;
+DROP TABLE IF EXISTS patron_class_change;
+DROP FUNCTION IF EXISTS patron_class_change_append_only;
+ALTER TABLE ledger_entry DROP COLUMN IF EXISTS actor;
+-- +goose StatementEnd
@@ -0,0 +1,381 @@
+// Package migrate applies the SQL migrations in this directory, which are
+// embedded in the binary. Migrations are written for goose and their history
+// is kept in goose's table, so the goose command line and this package can be
+// used on the same database.
+package migrate
+
+import (
+	"bufio"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/db"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// lockID is the key of the advisory lock held while migrating so that only
+// one process migrates a database at a time. It is "library" in ASCII.
+const lockID = 0x6c6962726172
+
+// A Migration moves the schema to Version and back again. Version is the
+// timestamp at the start of the file name.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      []string
+	Down    []string
+	// NoTransaction is set by a "-- +goose NO TRANSACTION" line for
+	// statements that can't run in a transaction.
+	NoTransaction bool
+}
+
+// A Status is whether a migration has been applied and when.
+type Status struct {
+	Migration Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+func badMigration(name string, format string, a ...any) error {
+	return &library.Error{
+		Type:   library.InvalidSettings,
+		Actual: fmt.Errorf(format, a...),
+		Desc:   "while parsing migration " + name,
+	}
+}
+
+// parse splits a goose SQL file into statements. Statements end with a line
+// ending in a semicolon unless they are between StatementBegin and
+// StatementEnd annotations.
+func parse(name string, data string) (Migration, error) {
+	version, err := strconv.ParseInt(strings.SplitN(name, "_", 2)[0], 10, 64)
+	if err != nil {
+		return Migration{}, badMigration(name, "the file name doesn't start with a version")
+	}
+	m := Migration{Version: version, Name: name}
+	var (
+		section   *[]string
+		statement strings.Builder
+		block     bool
+	)
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "-- +goose ") {
+			switch strings.TrimSpace(strings.TrimPrefix(line, "-- +goose ")) {
+			case "Up":
+				section = &m.Up
+			case "Down":
+				section = &m.Down
+			case "StatementBegin":
+				block = true
+			case "StatementEnd":
+				block = false
+				if section != nil && strings.TrimSpace(statement.String()) != "" {
+					*section = append(*section, statement.String())
+				}
+				statement.Reset()
+			case "NO TRANSACTION":
+				m.NoTransaction = true
+			default:
+				return Migration{}, badMigration(name, "unknown annotation %q", line)
+			}
+			continue
+		}
+		if section == nil {
+			continue
+		}
+		if !block && statement.Len() == 0 && (strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "--")) {
+			continue
+		}
+		statement.WriteString(line)
+		statement.WriteString("\n")
+		if !block && strings.HasSuffix(strings.TrimSpace(line), ";") {
+			*section = append(*section, statement.String())
+			statement.Reset()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Migration{}, badMigration(name, "%s", err)
+	}
+	if block {
+		return Migration{}, badMigration(name, "StatementBegin without StatementEnd")
+	}
+	if strings.TrimSpace(statement.String()) != "" {
+		return Migration{}, badMigration(name, "the last statement doesn't end with a semicolon")
+	}
+	return m, nil
+}
+
+// Migrations returns the embedded migrations from oldest to newest.
+func Migrations() ([]Migration, error) {
+	names, err := files.ReadDir(".")
+	if err != nil {
+		return nil, err
+	}
+	var migrations []Migration
+	for _, entry := range names {
+		data, err := files.ReadFile(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		m, err := parse(path.Base(entry.Name()), string(data))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, badMigration(migrations[i].Name, "version %d is also used by %s", migrations[i].Version, migrations[i-1].Name)
+		}
+	}
+	return migrations, nil
+}
+
+func migrateError(err error, desc string) error {
+	if err == nil {
+		return nil
+	}
+	var libErr *library.Error
+	if errors.As(err, &libErr) {
+		return err
+	}
+	return &library.Error{
+		Type:   library.DatabaseError,
+		Actual: err,
+		Desc:   desc,
+	}
+}
+
+// A Migrator applies migrations over a single connection, which holds the
+// advisory lock while it migrates. A pool can't be used since the lock
+// belongs to the connection that took it.
+type Migrator struct {
+	Conn db.DBTX
+}
+
+// applied returns when each migration was applied. Like goose, the latest row
+// of a version decides whether it is applied.
+func (m *Migrator) applied(ctx context.Context) (map[int64]time.Time, error) {
+	_, err := m.Conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS goose_db_version (
+  id serial PRIMARY KEY,
+  version_id bigint NOT NULL,
+  is_applied boolean NOT NULL,
+  tstamp timestamp DEFAULT now()
+)`)
+	if err != nil {
+		return nil, migrateError(err, "while creating the migration table")
+	}
+	_, err = m.Conn.Exec(ctx, `INSERT INTO goose_db_version (version_id, is_applied)
+SELECT 0, true
+WHERE NOT EXISTS (SELECT 1 FROM goose_db_version)`)
+	if err != nil {
+		return nil, migrateError(err, "while creating the migration table")
+	}
+	rows, err := m.Conn.Query(ctx, `SELECT version_id, is_applied, tstamp FROM goose_db_version ORDER BY id DESC`)
+	if err != nil {
+		return nil, migrateError(err, "while reading the migration history")
+	}
+	defer rows.Close()
+	seen := make(map[int64]bool)
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var (
+			version   int64
+			isApplied bool
+			at        time.Time
+		)
+		err = rows.Scan(&version, &isApplied, &at)
+		if err != nil {
+			return nil, migrateError(err, "while reading the migration history")
+		}
+		if seen[version] {
+			continue
+		}
+		seen[version] = true
+		if isApplied && version != 0 {
+			applied[version] = at
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, migrateError(err, "while reading the migration history")
+	}
+	return applied, nil
+}
+
+// locked calls f while holding the advisory lock, waiting for any other
+// process that is migrating the database.
+func (m *Migrator) locked(ctx context.Context, f func() error) error {
+	_, err := m.Conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, int64(lockID))
+	if err != nil {
+		return migrateError(err, "while locking the database for migration")
+	}
+	defer func() {
+		//nolint:errcheck // the lock is released with the connection anyway.
+		m.Conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, int64(lockID))
+	}()
+	return f()
+}
+
+// run runs the statements of one direction of a migration and records it.
+func (m *Migrator) run(ctx context.Context, migration Migration, up bool) error {
+	statements, record, args := migration.Down, `DELETE FROM goose_db_version WHERE version_id = $1`, []any{migration.Version}
+	if up {
+		statements, record, args = migration.Up, `INSERT INTO goose_db_version (version_id, is_applied) VALUES ($1, $2)`, []any{migration.Version, true}
+	}
+	desc := "while migrating down from " + migration.Name
+	if up {
+		desc = "while migrating up to " + migration.Name
+	}
+	if migration.NoTransaction {
+		for _, s := range statements {
+			_, err := m.Conn.Exec(ctx, s)
+			if err != nil {
+				return migrateError(err, desc)
+			}
+		}
+		_, err := m.Conn.Exec(ctx, record, args...)
+		return migrateError(err, desc)
+	}
+	tx, err := m.Conn.Begin(ctx)
+	if err != nil {
+		return migrateError(err, desc)
+	}
+	//nolint:errcheck // rolling back a committed transaction is a no-op.
+	defer tx.Rollback(ctx)
+	for _, s := range statements {
+		_, err = tx.Exec(ctx, s)
+		if err != nil {
+			return migrateError(err, desc)
+		}
+	}
+	_, err = tx.Exec(ctx, record, args...)
+	if err != nil {
+		return migrateError(err, desc)
+	}
+	return migrateError(tx.Commit(ctx), desc)
+}
+
+// To migrates up or down to version and returns the migrations that ran, in
+// the order they ran. Version 0 undoes every migration. Migrations that were
+// skipped, such as one merged from a branch after newer ones were applied,
+// are applied on the way up.
+func (m *Migrator) To(ctx context.Context, version int64) ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 {
+		found := false
+		for _, migration := range migrations {
+			found = found || migration.Version == version
+		}
+		if !found {
+			return nil, &library.Error{
+				Type:   library.BadInput,
+				Actual: fmt.Errorf("no migration has version %d", version),
+				Desc:   "while migrating",
+			}
+		}
+	}
+	var ran []Migration
+	err = m.locked(ctx, func() error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+		for _, migration := range migrations {
+			if _, ok := applied[migration.Version]; ok || migration.Version > version {
+				continue
+			}
+			err = m.run(ctx, migration, true)
+			if err != nil {
+				return err
+			}
+			ran = append(ran, migration)
+		}
+		for i := len(migrations) - 1; i >= 0; i-- {
+			migration := migrations[i]
+			if _, ok := applied[migration.Version]; !ok || migration.Version <= version {
+				continue
+			}
+			err = m.run(ctx, migration, false)
+			if err != nil {
+				return err
+			}
+			ran = append(ran, migration)
+		}
+		return nil
+	})
+	return ran, err
+}
+
+// Up applies every migration that hasn't been applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	if len(migrations) == 0 {
+		return nil, nil
+	}
+	return m.To(ctx, migrations[len(migrations)-1].Version)
+}
+
+// Down undoes the newest applied migration. It returns no migrations if
+// none are applied.
+func (m *Migrator) Down(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var target, current int64
+	for _, s := range statuses {
+		if !s.Applied {
+			continue
+		}
+		target, current = current, s.Migration.Version
+	}
+	if current == 0 {
+		return nil, nil
+	}
+	return m.To(ctx, target)
+}
+
+// Status returns every migration from oldest to newest with whether it has
+// been applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(migrations))
+	for _, migration := range migrations {
+		at, ok := applied[migration.Version]
+		statuses = append(statuses, Status{
+			Migration: migration,
+			Applied:   ok,
+			AppliedAt: at,
+		})
+	}
+	return statuses, nil
+}
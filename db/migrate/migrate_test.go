@@ -0,0 +1,73 @@
+package migrate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatalf("expected embedded migrations")
+	}
+	for i, m := range migrations {
+		if i > 0 && m.Version <= migrations[i-1].Version {
+			t.Fatalf("expected %s after %s", m.Name, migrations[i-1].Name)
+		}
+		if len(m.Up) == 0 || len(m.Down) == 0 {
+			t.Fatalf("expected %s to migrate up and down", m.Name)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	m, err := parse("20261018090000_example.sql", `-- +goose Up
+-- a comment
+CREATE TABLE example (id int);
+CREATE INDEX example_id
+  ON example (id);
+-- +goose StatementBegin
+CREATE FUNCTION example_id() RETURNS int AS $$
+BEGIN
+  RETURN 1;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE example;
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := Migration{
+		Version: 20261018090000,
+		Name:    "20261018090000_example.sql",
+		Up: []string{
+			"CREATE TABLE example (id int);\n",
+			"CREATE INDEX example_id\n  ON example (id);\n",
+			"CREATE FUNCTION example_id() RETURNS int AS $$\nBEGIN\n  RETURN 1;\nEND;\n$$ LANGUAGE plpgsql;\n",
+		},
+		Down: []string{"DROP TABLE example;\n"},
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Fatalf("expected %#v but got %#v", expected, m)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"example.sql":                "-- +goose Up\nSELECT 1;\n",
+		"20261018090000_begin.sql":   "-- +goose Up\n-- +goose StatementBegin\nSELECT 1;\n",
+		"20261018090000_unended.sql": "-- +goose Up\nSELECT 1\n",
+		"20261018090000_unknown.sql": "-- +goose Sideways\n",
+	} {
+		_, err := parse(name, data)
+		if err == nil {
+			t.Fatalf("expected %s to be invalid", name)
+		}
+	}
+}
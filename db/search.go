@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/db/cursor"
+	"github.com/slcjordan/library/db/sqlc"
+)
+
+// searchOrder is the order of search results: best match first.
+const searchOrder = "-score"
+
+// decodeSearchCursor parses the score of a search cursor, whose key is the
+// score followed by the query so that a token can't be used for another
+// query or list.
+func decodeSearchCursor(after cursor.Cursor, query string) (float64, error) {
+	if after.Order != searchOrder {
+		return 0, fmt.Errorf("page token is for order %q", after.Order)
+	}
+	score, tokenQuery, ok := strings.Cut(after.Key, " ")
+	if !ok || tokenQuery != query {
+		return 0, errors.New("page token is for another query")
+	}
+	return strconv.ParseFloat(score, 64)
+}
+
+// SearchBooks returns the books matching a query, best match first. Queries
+// use web search syntax such as quoted phrases and -excluded words, and
+// tolerate a few typos. The page token is opaque and only valid for the
+// query it came from.
+func (q *Queryer) SearchBooks(ctx context.Context, query string, PageToken string, TotalSize int32) (library.SearchResultList, error) {
+	if strings.TrimSpace(query) == "" {
+		return library.SearchResultList{}, &library.Error{
+			Type:   library.BadInput,
+			Actual: errors.New("empty query"),
+			Desc:   "while searching books",
+		}
+	}
+	after, err := cursor.Decode(PageToken)
+	if err != nil {
+		return library.SearchResultList{}, &library.Error{
+			Type:   library.BadInput,
+			Actual: err,
+			Desc:   "while decoding page token",
+		}
+	}
+
+	// results are ordered by descending score so the first page starts
+	// after an infinite score.
+	afterScore := math.Inf(1)
+	if after != cursor.FirstPage {
+		afterScore, err = decodeSearchCursor(after, query)
+		if err != nil {
+			return library.SearchResultList{}, &library.Error{
+				Type:   library.BadInput,
+				Actual: err,
+				Desc:   "while decoding page token",
+			}
+		}
+	}
+	params := sqlc.SearchBooksParams{
+		Query:      query,
+		AfterScore: afterScore,
+		AfterIsbn:  after.ID,
+		TotalSize:  TotalSize,
+	}
+	rows, err := sqlc.New(q.DBTX).SearchBooks(ctx, params)
+	if err != nil {
+		return library.SearchResultList{}, queryError(err, "while searching books")
+	}
+	var result library.SearchResultList
+	for _, r := range rows {
+		result.Results = append(result.Results, library.SearchResult{
+			Book: library.Book{
+				ISBN:  library.ISBN(r.Isbn),
+				Title: r.Title,
+			},
+			Score:   r.Score,
+			Snippet: r.Snippet,
+		})
+	}
+	if len(rows) > 0 && len(rows) == int(TotalSize) {
+		last := rows[len(rows)-1]
+		result.NextPageToken, err = cursor.Encode(cursor.Cursor{
+			Order: searchOrder,
+			Key:   strconv.FormatFloat(last.Score, 'g', -1, 64) + " " + query,
+			ID:    last.Isbn,
+		})
+		if err != nil {
+			return library.SearchResultList{}, err
+		}
+	}
+	return result, nil
+}
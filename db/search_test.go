@@ -0,0 +1,26 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/db/cursor"
+)
+
+func TestDecodeSearchCursor(t *testing.T) {
+	score, err := decodeSearchCursor(cursor.Cursor{Order: searchOrder, Key: "0.25 moby dick", ID: 9780306406157}, "moby dick")
+	if err != nil || score != 0.25 {
+		t.Fatalf("expected a score of 0.25 but got %v with error %v", score, err)
+	}
+	for desc, after := range map[string]cursor.Cursor{
+		"another query": {Order: searchOrder, Key: "0.25 moby", ID: 9780306406157},
+		"title list":    {Order: string(library.ByTitle), Key: "0.25 moby dick", ID: 9780306406157},
+		"author list":   {Order: authorOrder, Key: "0.25 moby dick", ID: 1},
+		"bad score":     {Order: searchOrder, Key: "high moby dick", ID: 9780306406157},
+	} {
+		_, err = decodeSearchCursor(after, "moby dick")
+		if err == nil {
+			t.Fatalf("%s: expected an error", desc)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+-- AddBookAuthors credits authors on a single book in order. The book must
+-- not have any authors yet.
+-- name: AddBookAuthors :exec
+
+INSERT INTO book_author (isbn, author_id, position)
+SELECT @isbn, a.author_id, a.position
+FROM unnest(@author_ids::bigint[]) WITH ORDINALITY AS a (author_id, position);
@@ -0,0 +1,29 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: add_book_authors.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const addBookAuthors = `-- name: AddBookAuthors :exec
+
+INSERT INTO book_author (isbn, author_id, position)
+SELECT $1, a.author_id, a.position
+FROM unnest($2::bigint[]) WITH ORDINALITY AS a (author_id, position)
+`
+
+type AddBookAuthorsParams struct {
+	Isbn      int64
+	AuthorIds []int64
+}
+
+// AddBookAuthors credits authors on a single book in order. The book must
+// not have any authors yet.
+func (q *Queries) AddBookAuthors(ctx context.Context, arg AddBookAuthorsParams) error {
+	_, err := q.db.Exec(ctx, addBookAuthors, arg.Isbn, arg.AuthorIds)
+	return err
+}
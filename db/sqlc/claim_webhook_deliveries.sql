@@ -0,0 +1,31 @@
+-- ClaimWebhookDeliveries claims the pending deliveries of active webhooks
+-- that are due, oldest first, with what is needed to send them. Claimed
+-- deliveries aren't due again until the lease is over, so other workers skip
+-- them and a worker that dies while sending them doesn't lose them.
+-- name: ClaimWebhookDeliveries :many
+
+WITH claimed AS (
+  UPDATE webhook_delivery
+  SET next_attempt_at = now() + interval '1 second' * @lease_seconds::float8
+  WHERE webhook_delivery.id IN (
+    SELECT due.id
+    FROM webhook_delivery AS due
+    JOIN webhook ON webhook.id = due.webhook_id
+    WHERE due.status = 'pending'
+    AND due.next_attempt_at <= now()
+    AND webhook.active
+    ORDER BY due.next_attempt_at, due.id
+    LIMIT @total_size
+    FOR UPDATE OF due SKIP LOCKED
+  )
+  RETURNING webhook_delivery.id, webhook_delivery.webhook_id, webhook_delivery.audit_id,
+    webhook_delivery.attempts, webhook_delivery.created_at
+)
+SELECT claimed.id, claimed.webhook_id, claimed.attempts, claimed.created_at,
+  webhook.url, webhook.secret,
+  book_audit.id AS audit_id, book_audit.isbn, book_audit.action, book_audit.actor, book_audit.request_id,
+  book_audit.old_book, book_audit.new_book, book_audit.created_at AS audit_created_at
+FROM claimed
+JOIN webhook ON webhook.id = claimed.webhook_id
+JOIN book_audit ON book_audit.id = claimed.audit_id
+ORDER BY claimed.id;
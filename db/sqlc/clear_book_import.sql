@@ -0,0 +1,5 @@
+-- ClearBookImport deletes the staged rows of the current import. Rows of
+-- other imports aren't visible to it.
+-- name: ClearBookImport :exec
+
+DELETE FROM book_import;
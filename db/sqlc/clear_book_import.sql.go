@@ -0,0 +1,22 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: clear_book_import.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const clearBookImport = `-- name: ClearBookImport :exec
+
+DELETE FROM book_import
+`
+
+// ClearBookImport deletes the staged rows of the current import. Rows of
+// other imports aren't visible to it.
+func (q *Queries) ClearBookImport(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, clearBookImport)
+	return err
+}
@@ -0,0 +1,45 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: copyfrom.go
+
+package sqlc
+
+import (
+	"context"
+)
+
+// iteratorForStageBookImport implements pgx.CopyFromSource.
+type iteratorForStageBookImport struct {
+	rows                 []StageBookImportParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForStageBookImport) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForStageBookImport) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].RowNumber,
+		r.rows[0].Isbn,
+		r.rows[0].Title,
+	}, nil
+}
+
+func (r iteratorForStageBookImport) Err() error {
+	return nil
+}
+
+// StageBookImport copies a batch of import rows into the staging table.
+func (q *Queries) StageBookImport(ctx context.Context, arg []StageBookImportParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"book_import"}, []string{"row_number", "isbn", "title"}, &iteratorForStageBookImport{rows: arg})
+}
@@ -0,0 +1,4 @@
+-- CountOpenLoans counts the copies a patron currently has checked out.
+-- name: CountOpenLoans :one
+
+SELECT count(*) FROM loan WHERE patron_id = @patron_id AND returned_at IS NULL;
@@ -0,0 +1,23 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: count_open_loans.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const countOpenLoans = `-- name: CountOpenLoans :one
+
+SELECT count(*) FROM loan WHERE patron_id = $1 AND returned_at IS NULL
+`
+
+// CountOpenLoans counts the copies a patron currently has checked out.
+func (q *Queries) CountOpenLoans(ctx context.Context, patronID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countOpenLoans, patronID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
@@ -0,0 +1,4 @@
+-- CountWaitingHolds counts the patrons waiting for a title.
+-- name: CountWaitingHolds :one
+
+SELECT count(*) FROM hold WHERE isbn = @isbn AND status = 'waiting';
@@ -0,0 +1,23 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: count_waiting_holds.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const countWaitingHolds = `-- name: CountWaitingHolds :one
+
+SELECT count(*) FROM hold WHERE isbn = $1 AND status = 'waiting'
+`
+
+// CountWaitingHolds counts the patrons waiting for a title.
+func (q *Queries) CountWaitingHolds(ctx context.Context, isbn int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countWaitingHolds, isbn)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
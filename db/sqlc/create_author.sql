@@ -0,0 +1,6 @@
+-- CreateAuthor creates a single author.
+-- name: CreateAuthor :one
+
+INSERT INTO author (name)
+VALUES (@name)
+RETURNING id, name;
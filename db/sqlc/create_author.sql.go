@@ -0,0 +1,25 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: create_author.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const createAuthor = `-- name: CreateAuthor :one
+
+INSERT INTO author (name)
+VALUES ($1)
+RETURNING id, name
+`
+
+// CreateAuthor creates a single author.
+func (q *Queries) CreateAuthor(ctx context.Context, name string) (Author, error) {
+	row := q.db.QueryRow(ctx, createAuthor, name)
+	var i Author
+	err := row.Scan(&i.ID, &i.Name)
+	return i, err
+}
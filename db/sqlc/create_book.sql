@@ -1,5 +1,11 @@
--- CreateBook creates a single book.
+-- CreateBook creates a single book along with its ordered list of authors.
 -- name: CreateBook :exec
 
-INSERT INTO book (isbn, title)
-VALUES (@isbn, @title);
+WITH new_book AS (
+  INSERT INTO book (isbn, title)
+  VALUES (@isbn, @title)
+  RETURNING isbn
+)
+INSERT INTO book_author (isbn, author_id, position)
+SELECT new_book.isbn, a.author_id, a.position
+FROM new_book, unnest(@author_ids::bigint[]) WITH ORDINALITY AS a (author_id, position);
@@ -11,17 +11,24 @@ import (
 
 const createBook = `-- name: CreateBook :exec
 
-INSERT INTO book (isbn, title)
-VALUES ($1, $2)
+WITH new_book AS (
+  INSERT INTO book (isbn, title)
+  VALUES ($2, $3)
+  RETURNING isbn
+)
+INSERT INTO book_author (isbn, author_id, position)
+SELECT new_book.isbn, a.author_id, a.position
+FROM new_book, unnest($1::bigint[]) WITH ORDINALITY AS a (author_id, position)
 `
 
 type CreateBookParams struct {
-	Isbn  int64
-	Title string
+	AuthorIds []int64
+	Isbn      int64
+	Title     string
 }
 
-// CreateBook creates a single book.
+// CreateBook creates a single book along with its ordered list of authors.
 func (q *Queries) CreateBook(ctx context.Context, arg CreateBookParams) error {
-	_, err := q.db.Exec(ctx, createBook, arg.Isbn, arg.Title)
+	_, err := q.db.Exec(ctx, createBook, arg.AuthorIds, arg.Isbn, arg.Title)
 	return err
 }
@@ -0,0 +1,5 @@
+-- CreateCopy creates a single physical copy of a book.
+-- name: CreateCopy :exec
+
+INSERT INTO copy (barcode, isbn, location, status)
+VALUES (@barcode, @isbn, @location, @status);
@@ -0,0 +1,34 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: create_copy.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const createCopy = `-- name: CreateCopy :exec
+
+INSERT INTO copy (barcode, isbn, location, status)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateCopyParams struct {
+	Barcode  string
+	Isbn     int64
+	Location string
+	Status   string
+}
+
+// CreateCopy creates a single physical copy of a book.
+func (q *Queries) CreateCopy(ctx context.Context, arg CreateCopyParams) error {
+	_, err := q.db.Exec(ctx, createCopy,
+		arg.Barcode,
+		arg.Isbn,
+		arg.Location,
+		arg.Status,
+	)
+	return err
+}
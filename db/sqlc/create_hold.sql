@@ -0,0 +1,6 @@
+-- CreateHold places a patron at the end of the queue for a title.
+-- name: CreateHold :one
+
+INSERT INTO hold (isbn, patron_id)
+VALUES (@isbn, @patron_id)
+RETURNING *;
@@ -0,0 +1,38 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: create_hold.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const createHold = `-- name: CreateHold :one
+
+INSERT INTO hold (isbn, patron_id)
+VALUES ($1, $2)
+RETURNING id, isbn, patron_id, placed_at, status, barcode, pickup_by
+`
+
+type CreateHoldParams struct {
+	Isbn     int64
+	PatronID int64
+}
+
+// CreateHold places a patron at the end of the queue for a title.
+func (q *Queries) CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error) {
+	row := q.db.QueryRow(ctx, createHold, arg.Isbn, arg.PatronID)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn,
+		&i.PatronID,
+		&i.PlacedAt,
+		&i.Status,
+		&i.Barcode,
+		&i.PickupBy,
+	)
+	return i, err
+}
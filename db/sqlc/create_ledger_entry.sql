@@ -0,0 +1,6 @@
+-- CreateLedgerEntry appends a single fine, payment or waiver to the ledger.
+-- name: CreateLedgerEntry :one
+
+INSERT INTO ledger_entry (patron_id, loan_id, kind, amount, note)
+VALUES (@patron_id, @loan_id, @kind, @amount, @note)
+RETURNING *;
@@ -0,0 +1,49 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: create_ledger_entry.sql
+
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createLedgerEntry = `-- name: CreateLedgerEntry :one
+
+INSERT INTO ledger_entry (patron_id, loan_id, kind, amount, note)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, patron_id, loan_id, kind, amount, note, created_at, actor
+`
+
+type CreateLedgerEntryParams struct {
+	PatronID int64
+	LoanID   sql.NullInt64
+	Kind     string
+	Amount   int64
+	Note     string
+}
+
+// CreateLedgerEntry appends a single fine, payment or waiver to the ledger.
+func (q *Queries) CreateLedgerEntry(ctx context.Context, arg CreateLedgerEntryParams) (LedgerEntry, error) {
+	row := q.db.QueryRow(ctx, createLedgerEntry,
+		arg.PatronID,
+		arg.LoanID,
+		arg.Kind,
+		arg.Amount,
+		arg.Note,
+	)
+	var i LedgerEntry
+	err := row.Scan(
+		&i.ID,
+		&i.PatronID,
+		&i.LoanID,
+		&i.Kind,
+		&i.Amount,
+		&i.Note,
+		&i.CreatedAt,
+		&i.Actor,
+	)
+	return i, err
+}
@@ -0,0 +1,6 @@
+-- CreateLoan lends a single copy to a patron.
+-- name: CreateLoan :one
+
+INSERT INTO loan (barcode, patron_id, due_at)
+VALUES (@barcode, @patron_id, @due_at)
+RETURNING *;
@@ -0,0 +1,40 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: create_loan.sql
+
+package sqlc
+
+import (
+	"context"
+	"time"
+)
+
+const createLoan = `-- name: CreateLoan :one
+
+INSERT INTO loan (barcode, patron_id, due_at)
+VALUES ($1, $2, $3)
+RETURNING id, barcode, patron_id, checked_out_at, due_at, returned_at, renewals
+`
+
+type CreateLoanParams struct {
+	Barcode  string
+	PatronID int64
+	DueAt    time.Time
+}
+
+// CreateLoan lends a single copy to a patron.
+func (q *Queries) CreateLoan(ctx context.Context, arg CreateLoanParams) (Loan, error) {
+	row := q.db.QueryRow(ctx, createLoan, arg.Barcode, arg.PatronID, arg.DueAt)
+	var i Loan
+	err := row.Scan(
+		&i.ID,
+		&i.Barcode,
+		&i.PatronID,
+		&i.CheckedOutAt,
+		&i.DueAt,
+		&i.ReturnedAt,
+		&i.Renewals,
+	)
+	return i, err
+}
@@ -0,0 +1,6 @@
+-- CreatePatron creates a single patron.
+-- name: CreatePatron :one
+
+INSERT INTO patron (name, borrowing_limit, class)
+VALUES (@name, @borrowing_limit, @class)
+RETURNING id, name, borrowing_limit, class;
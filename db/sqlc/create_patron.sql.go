@@ -0,0 +1,36 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: create_patron.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const createPatron = `-- name: CreatePatron :one
+
+INSERT INTO patron (name, borrowing_limit, class)
+VALUES ($1, $2, $3)
+RETURNING id, name, borrowing_limit, class
+`
+
+type CreatePatronParams struct {
+	Name           string
+	BorrowingLimit int32
+	Class          string
+}
+
+// CreatePatron creates a single patron.
+func (q *Queries) CreatePatron(ctx context.Context, arg CreatePatronParams) (Patron, error) {
+	row := q.db.QueryRow(ctx, createPatron, arg.Name, arg.BorrowingLimit, arg.Class)
+	var i Patron
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.BorrowingLimit,
+		&i.Class,
+	)
+	return i, err
+}
@@ -0,0 +1,6 @@
+-- CreateWebhook creates a single webhook.
+-- name: CreateWebhook :one
+
+INSERT INTO webhook (url, secret, events, active)
+VALUES (@url, @secret, @events, @active)
+RETURNING id, url, secret, events, active, created_at;
@@ -0,0 +1,44 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: create_webhook.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const createWebhook = `-- name: CreateWebhook :one
+
+INSERT INTO webhook (url, secret, events, active)
+VALUES ($1, $2, $3, $4)
+RETURNING id, url, secret, events, active, created_at
+`
+
+type CreateWebhookParams struct {
+	Url    string
+	Secret string
+	Events []string
+	Active bool
+}
+
+// CreateWebhook creates a single webhook.
+func (q *Queries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error) {
+	row := q.db.QueryRow(ctx, createWebhook,
+		arg.Url,
+		arg.Secret,
+		arg.Events,
+		arg.Active,
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Secret,
+		&i.Events,
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
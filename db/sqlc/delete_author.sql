@@ -0,0 +1,4 @@
+-- DeleteAuthor deletes a single author.
+-- name: DeleteAuthor :execrows
+
+DELETE FROM author WHERE id = @id;
@@ -0,0 +1,24 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: delete_author.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const deleteAuthor = `-- name: DeleteAuthor :execrows
+
+DELETE FROM author WHERE id = $1
+`
+
+// DeleteAuthor deletes a single author.
+func (q *Queries) DeleteAuthor(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAuthor, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -1,4 +0,0 @@
--- DeleteBook deletes a single book.
--- name: DeleteBook :exec
-
-DELETE FROM book WHERE isbn = @isbn;
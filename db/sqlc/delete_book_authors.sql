@@ -0,0 +1,4 @@
+-- DeleteBookAuthors removes every author from a single book.
+-- name: DeleteBookAuthors :exec
+
+DELETE FROM book_author WHERE isbn = @isbn;
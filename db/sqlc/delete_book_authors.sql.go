@@ -0,0 +1,21 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: delete_book_authors.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const deleteBookAuthors = `-- name: DeleteBookAuthors :exec
+
+DELETE FROM book_author WHERE isbn = $1
+`
+
+// DeleteBookAuthors removes every author from a single book.
+func (q *Queries) DeleteBookAuthors(ctx context.Context, isbn int64) error {
+	_, err := q.db.Exec(ctx, deleteBookAuthors, isbn)
+	return err
+}
@@ -0,0 +1,4 @@
+-- DeleteCopy deletes a single copy.
+-- name: DeleteCopy :execrows
+
+DELETE FROM copy WHERE barcode = @barcode;
@@ -0,0 +1,24 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: delete_copy.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const deleteCopy = `-- name: DeleteCopy :execrows
+
+DELETE FROM copy WHERE barcode = $1
+`
+
+// DeleteCopy deletes a single copy.
+func (q *Queries) DeleteCopy(ctx context.Context, barcode string) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteCopy, barcode)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,4 @@
+-- DeleteWebhook deletes a single webhook and its deliveries.
+-- name: DeleteWebhook :execrows
+
+DELETE FROM webhook WHERE id = @id;
@@ -0,0 +1,24 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: delete_webhook.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const deleteWebhook = `-- name: DeleteWebhook :execrows
+
+DELETE FROM webhook WHERE id = $1
+`
+
+// DeleteWebhook deletes a single webhook and its deliveries.
+func (q *Queries) DeleteWebhook(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteWebhook, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
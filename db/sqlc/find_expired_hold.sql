@@ -0,0 +1,8 @@
+-- FindExpiredHold returns the copy of a ready hold that wasn't picked up in
+-- time. It doesn't lock anything: the copy has to be locked before the hold.
+-- name: FindExpiredHold :one
+
+SELECT barcode::text FROM hold
+WHERE status = 'ready' AND pickup_by < now()
+ORDER BY pickup_by
+LIMIT 1;
@@ -0,0 +1,27 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: find_expired_hold.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const findExpiredHold = `-- name: FindExpiredHold :one
+
+SELECT barcode::text FROM hold
+WHERE status = 'ready' AND pickup_by < now()
+ORDER BY pickup_by
+LIMIT 1
+`
+
+// FindExpiredHold returns the copy of a ready hold that wasn't picked up in
+// time. It doesn't lock anything: the copy has to be locked before the hold.
+func (q *Queries) FindExpiredHold(ctx context.Context) (string, error) {
+	row := q.db.QueryRow(ctx, findExpiredHold)
+	var barcode string
+	err := row.Scan(&barcode)
+	return barcode, err
+}
@@ -0,0 +1,4 @@
+-- GetAuthor fetches a single author.
+-- name: GetAuthor :one
+
+SELECT id, name FROM author WHERE id = @id;
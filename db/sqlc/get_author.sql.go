@@ -0,0 +1,23 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: get_author.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const getAuthor = `-- name: GetAuthor :one
+
+SELECT id, name FROM author WHERE id = $1
+`
+
+// GetAuthor fetches a single author.
+func (q *Queries) GetAuthor(ctx context.Context, id int64) (Author, error) {
+	row := q.db.QueryRow(ctx, getAuthor, id)
+	var i Author
+	err := row.Scan(&i.ID, &i.Name)
+	return i, err
+}
@@ -1,4 +1,7 @@
--- GetBook fetches a single book.
+-- GetBook fetches a single book. A trashed book is only fetched if
+-- include_deleted is true.
 -- name: GetBook :one
 
-SELECT title, isbn FROM book WHERE isbn = @isbn;
+SELECT isbn, title, created_at, updated_at, version, deleted_at FROM book
+WHERE isbn = @isbn
+AND (@include_deleted::boolean OR deleted_at IS NULL);
@@ -11,18 +11,28 @@ import (
 
 const getBook = `-- name: GetBook :one
 
-SELECT title, isbn FROM book WHERE isbn = $1
+SELECT isbn, title, created_at, updated_at, version, deleted_at FROM book
+WHERE isbn = $1
+AND ($2::boolean OR deleted_at IS NULL)
 `
 
-type GetBookRow struct {
-	Title string
-	Isbn  int64
+type GetBookParams struct {
+	Isbn           int64
+	IncludeDeleted bool
 }
 
-// GetBook fetches a single book.
-func (q *Queries) GetBook(ctx context.Context, isbn int64) (GetBookRow, error) {
-	row := q.db.QueryRow(ctx, getBook, isbn)
-	var i GetBookRow
-	err := row.Scan(&i.Title, &i.Isbn)
+// GetBook fetches a single book. A trashed book is only fetched if
+// include_deleted is true.
+func (q *Queries) GetBook(ctx context.Context, arg GetBookParams) (Book, error) {
+	row := q.db.QueryRow(ctx, getBook, arg.Isbn, arg.IncludeDeleted)
+	var i Book
+	err := row.Scan(
+		&i.Isbn,
+		&i.Title,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+	)
 	return i, err
 }
@@ -0,0 +1,10 @@
+-- GetBookAuditAt returns the last audit record of a single book made at or
+-- before a point in time. Its new_book is the book as it was then.
+-- name: GetBookAuditAt :one
+
+SELECT id, isbn, action, actor, request_id, old_book, new_book, created_at, txid
+FROM book_audit
+WHERE isbn = @isbn
+AND created_at <= @at
+ORDER BY id DESC
+LIMIT 1;
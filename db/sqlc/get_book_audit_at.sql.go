@@ -0,0 +1,45 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: get_book_audit_at.sql
+
+package sqlc
+
+import (
+	"context"
+	"time"
+)
+
+const getBookAuditAt = `-- name: GetBookAuditAt :one
+
+SELECT id, isbn, action, actor, request_id, old_book, new_book, created_at, txid
+FROM book_audit
+WHERE isbn = $1
+AND created_at <= $2
+ORDER BY id DESC
+LIMIT 1
+`
+
+type GetBookAuditAtParams struct {
+	Isbn int64
+	At   time.Time
+}
+
+// GetBookAuditAt returns the last audit record of a single book made at or
+// before a point in time. Its new_book is the book as it was then.
+func (q *Queries) GetBookAuditAt(ctx context.Context, arg GetBookAuditAtParams) (BookAudit, error) {
+	row := q.db.QueryRow(ctx, getBookAuditAt, arg.Isbn, arg.At)
+	var i BookAudit
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn,
+		&i.Action,
+		&i.Actor,
+		&i.RequestID,
+		&i.OldBook,
+		&i.NewBook,
+		&i.CreatedAt,
+		&i.Txid,
+	)
+	return i, err
+}
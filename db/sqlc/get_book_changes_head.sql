@@ -0,0 +1,6 @@
+-- GetBookChangesHead returns the oldest transaction that is still running.
+-- Every audit record of an older transaction is already visible, so a change
+-- feed that starts now starts just before it.
+-- name: GetBookChangesHead :one
+
+SELECT txid_snapshot_xmin(txid_current_snapshot())::bigint AS xmin;
@@ -0,0 +1,25 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: get_book_changes_head.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const getBookChangesHead = `-- name: GetBookChangesHead :one
+
+SELECT txid_snapshot_xmin(txid_current_snapshot())::bigint AS xmin
+`
+
+// GetBookChangesHead returns the oldest transaction that is still running.
+// Every audit record of an older transaction is already visible, so a change
+// feed that starts now starts just before it.
+func (q *Queries) GetBookChangesHead(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, getBookChangesHead)
+	var xmin int64
+	err := row.Scan(&xmin)
+	return xmin, err
+}
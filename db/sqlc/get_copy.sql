@@ -0,0 +1,4 @@
+-- GetCopy fetches a single copy.
+-- name: GetCopy :one
+
+SELECT barcode, isbn, location, status FROM copy WHERE barcode = @barcode;
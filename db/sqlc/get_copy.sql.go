@@ -0,0 +1,28 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: get_copy.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const getCopy = `-- name: GetCopy :one
+
+SELECT barcode, isbn, location, status FROM copy WHERE barcode = $1
+`
+
+// GetCopy fetches a single copy.
+func (q *Queries) GetCopy(ctx context.Context, barcode string) (Copy, error) {
+	row := q.db.QueryRow(ctx, getCopy, barcode)
+	var i Copy
+	err := row.Scan(
+		&i.Barcode,
+		&i.Isbn,
+		&i.Location,
+		&i.Status,
+	)
+	return i, err
+}
@@ -0,0 +1,4 @@
+-- GetHold fetches a single hold.
+-- name: GetHold :one
+
+SELECT * FROM hold WHERE id = @id;
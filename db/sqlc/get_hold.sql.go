@@ -0,0 +1,31 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: get_hold.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const getHold = `-- name: GetHold :one
+
+SELECT id, isbn, patron_id, placed_at, status, barcode, pickup_by FROM hold WHERE id = $1
+`
+
+// GetHold fetches a single hold.
+func (q *Queries) GetHold(ctx context.Context, id int64) (Hold, error) {
+	row := q.db.QueryRow(ctx, getHold, id)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn,
+		&i.PatronID,
+		&i.PlacedAt,
+		&i.Status,
+		&i.Barcode,
+		&i.PickupBy,
+	)
+	return i, err
+}
@@ -0,0 +1,4 @@
+-- GetPatron fetches a single patron.
+-- name: GetPatron :one
+
+SELECT id, name, borrowing_limit, class FROM patron WHERE id = @id;
@@ -0,0 +1,28 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: get_patron.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const getPatron = `-- name: GetPatron :one
+
+SELECT id, name, borrowing_limit, class FROM patron WHERE id = $1
+`
+
+// GetPatron fetches a single patron.
+func (q *Queries) GetPatron(ctx context.Context, id int64) (Patron, error) {
+	row := q.db.QueryRow(ctx, getPatron, id)
+	var i Patron
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.BorrowingLimit,
+		&i.Class,
+	)
+	return i, err
+}
@@ -0,0 +1,4 @@
+-- GetWebhook fetches a single webhook.
+-- name: GetWebhook :one
+
+SELECT id, url, secret, events, active, created_at FROM webhook WHERE id = @id;
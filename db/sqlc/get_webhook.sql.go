@@ -0,0 +1,30 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: get_webhook.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const getWebhook = `-- name: GetWebhook :one
+
+SELECT id, url, secret, events, active, created_at FROM webhook WHERE id = $1
+`
+
+// GetWebhook fetches a single webhook.
+func (q *Queries) GetWebhook(ctx context.Context, id int64) (Webhook, error) {
+	row := q.db.QueryRow(ctx, getWebhook, id)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Secret,
+		&i.Events,
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,10 @@
+-- ListAudit returns the audit records of every book, newest first. A page
+-- starts just before the previous page's last record; a zero id is the first
+-- page.
+-- name: ListAudit :many
+
+SELECT id, isbn, action, actor, request_id, old_book, new_book, created_at, txid
+FROM book_audit
+WHERE (@before_id::bigint = 0 OR id < @before_id)
+ORDER BY id DESC
+LIMIT @total_size;
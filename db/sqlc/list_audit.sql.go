@@ -0,0 +1,57 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: list_audit.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const listAudit = `-- name: ListAudit :many
+
+SELECT id, isbn, action, actor, request_id, old_book, new_book, created_at, txid
+FROM book_audit
+WHERE ($1::bigint = 0 OR id < $1)
+ORDER BY id DESC
+LIMIT $2
+`
+
+type ListAuditParams struct {
+	BeforeID  int64
+	TotalSize int32
+}
+
+// ListAudit returns the audit records of every book, newest first. A page
+// starts just before the previous page's last record; a zero id is the first
+// page.
+func (q *Queries) ListAudit(ctx context.Context, arg ListAuditParams) ([]BookAudit, error) {
+	rows, err := q.db.Query(ctx, listAudit, arg.BeforeID, arg.TotalSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BookAudit
+	for rows.Next() {
+		var i BookAudit
+		if err := rows.Scan(
+			&i.ID,
+			&i.Isbn,
+			&i.Action,
+			&i.Actor,
+			&i.RequestID,
+			&i.OldBook,
+			&i.NewBook,
+			&i.CreatedAt,
+			&i.Txid,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,12 @@
+-- ListAuthorBooks returns a list of books by a single author ordered by title
+-- and then isbn. Trashed books are left out.
+-- name: ListAuthorBooks :many
+
+SELECT book.isbn, book.title, book.created_at, book.updated_at, book.version, book.deleted_at
+FROM book
+JOIN book_author ON book_author.isbn = book.isbn
+WHERE book_author.author_id = @author_id
+AND book.deleted_at IS NULL
+AND (book.title, book.isbn) > (@after_title::text, @after_isbn::bigint)
+ORDER BY book.title, book.isbn
+LIMIT @total_size;
@@ -0,0 +1,63 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: list_author_books.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const listAuthorBooks = `-- name: ListAuthorBooks :many
+
+SELECT book.isbn, book.title, book.created_at, book.updated_at, book.version, book.deleted_at
+FROM book
+JOIN book_author ON book_author.isbn = book.isbn
+WHERE book_author.author_id = $1
+AND book.deleted_at IS NULL
+AND (book.title, book.isbn) > ($2::text, $3::bigint)
+ORDER BY book.title, book.isbn
+LIMIT $4
+`
+
+type ListAuthorBooksParams struct {
+	AuthorID   int64
+	AfterTitle string
+	AfterIsbn  int64
+	TotalSize  int32
+}
+
+// ListAuthorBooks returns a list of books by a single author ordered by title
+// and then isbn. Trashed books are left out.
+func (q *Queries) ListAuthorBooks(ctx context.Context, arg ListAuthorBooksParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listAuthorBooks,
+		arg.AuthorID,
+		arg.AfterTitle,
+		arg.AfterIsbn,
+		arg.TotalSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.Isbn,
+			&i.Title,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
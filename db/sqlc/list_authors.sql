@@ -0,0 +1,8 @@
+-- ListAuthors returns a list of authors ordered by name and then id.
+-- name: ListAuthors :many
+
+SELECT id, name
+FROM author
+WHERE (name, id) > (@after_name::text, @after_id::bigint)
+ORDER BY name, id
+LIMIT @total_size;
@@ -0,0 +1,46 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: list_authors.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const listAuthors = `-- name: ListAuthors :many
+
+SELECT id, name
+FROM author
+WHERE (name, id) > ($1::text, $2::bigint)
+ORDER BY name, id
+LIMIT $3
+`
+
+type ListAuthorsParams struct {
+	AfterName string
+	AfterID   int64
+	TotalSize int32
+}
+
+// ListAuthors returns a list of authors ordered by name and then id.
+func (q *Queries) ListAuthors(ctx context.Context, arg ListAuthorsParams) ([]Author, error) {
+	rows, err := q.db.Query(ctx, listAuthors, arg.AfterName, arg.AfterID, arg.TotalSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
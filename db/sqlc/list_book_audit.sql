@@ -0,0 +1,11 @@
+-- ListBookAudit returns the audit records of a single book, newest first. A
+-- page starts just before the previous page's last record; a zero id is the
+-- first page.
+-- name: ListBookAudit :many
+
+SELECT id, isbn, action, actor, request_id, old_book, new_book, created_at, txid
+FROM book_audit
+WHERE isbn = @isbn
+AND (@before_id::bigint = 0 OR id < @before_id)
+ORDER BY id DESC
+LIMIT @total_size;
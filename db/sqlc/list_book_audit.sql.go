@@ -0,0 +1,59 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: list_book_audit.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const listBookAudit = `-- name: ListBookAudit :many
+
+SELECT id, isbn, action, actor, request_id, old_book, new_book, created_at, txid
+FROM book_audit
+WHERE isbn = $1
+AND ($2::bigint = 0 OR id < $2)
+ORDER BY id DESC
+LIMIT $3
+`
+
+type ListBookAuditParams struct {
+	Isbn      int64
+	BeforeID  int64
+	TotalSize int32
+}
+
+// ListBookAudit returns the audit records of a single book, newest first. A
+// page starts just before the previous page's last record; a zero id is the
+// first page.
+func (q *Queries) ListBookAudit(ctx context.Context, arg ListBookAuditParams) ([]BookAudit, error) {
+	rows, err := q.db.Query(ctx, listBookAudit, arg.Isbn, arg.BeforeID, arg.TotalSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BookAudit
+	for rows.Next() {
+		var i BookAudit
+		if err := rows.Scan(
+			&i.ID,
+			&i.Isbn,
+			&i.Action,
+			&i.Actor,
+			&i.RequestID,
+			&i.OldBook,
+			&i.NewBook,
+			&i.CreatedAt,
+			&i.Txid,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,8 @@
+-- ListBookAuthors returns the authors of a single book in order.
+-- name: ListBookAuthors :many
+
+SELECT author.id, author.name
+FROM author
+JOIN book_author ON book_author.author_id = author.id
+WHERE book_author.isbn = @isbn
+ORDER BY book_author.position;
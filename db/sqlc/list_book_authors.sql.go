@@ -0,0 +1,40 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: list_book_authors.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const listBookAuthors = `-- name: ListBookAuthors :many
+
+SELECT author.id, author.name
+FROM author
+JOIN book_author ON book_author.author_id = author.id
+WHERE book_author.isbn = $1
+ORDER BY book_author.position
+`
+
+// ListBookAuthors returns the authors of a single book in order.
+func (q *Queries) ListBookAuthors(ctx context.Context, isbn int64) ([]Author, error) {
+	rows, err := q.db.Query(ctx, listBookAuthors, isbn)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,12 @@
+-- ListBookChanges returns the audit records of every book in the order of the
+-- change feed, starting after a (txid, id) position. Records of transactions
+-- that are newer than the oldest one still running are held back, since an
+-- older record could still commit in front of them.
+-- name: ListBookChanges :many
+
+SELECT id, isbn, action, actor, request_id, old_book, new_book, created_at, txid
+FROM book_audit
+WHERE (txid, id) > (@after_txid::bigint, @after_id::bigint)
+AND txid < txid_snapshot_xmin(txid_current_snapshot())
+ORDER BY txid, id
+LIMIT @total_size;
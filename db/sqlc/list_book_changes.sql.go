@@ -0,0 +1,60 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: list_book_changes.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const listBookChanges = `-- name: ListBookChanges :many
+
+SELECT id, isbn, action, actor, request_id, old_book, new_book, created_at, txid
+FROM book_audit
+WHERE (txid, id) > ($1::bigint, $2::bigint)
+AND txid < txid_snapshot_xmin(txid_current_snapshot())
+ORDER BY txid, id
+LIMIT $3
+`
+
+type ListBookChangesParams struct {
+	AfterTxid int64
+	AfterID   int64
+	TotalSize int32
+}
+
+// ListBookChanges returns the audit records of every book in the order of the
+// change feed, starting after a (txid, id) position. Records of transactions
+// that are newer than the oldest one still running are held back, since an
+// older record could still commit in front of them.
+func (q *Queries) ListBookChanges(ctx context.Context, arg ListBookChangesParams) ([]BookAudit, error) {
+	rows, err := q.db.Query(ctx, listBookChanges, arg.AfterTxid, arg.AfterID, arg.TotalSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BookAudit
+	for rows.Next() {
+		var i BookAudit
+		if err := rows.Scan(
+			&i.ID,
+			&i.Isbn,
+			&i.Action,
+			&i.Actor,
+			&i.RequestID,
+			&i.OldBook,
+			&i.NewBook,
+			&i.CreatedAt,
+			&i.Txid,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,10 @@
+-- ListBookHolds returns the active holds for a title in the order they are
+-- served. Ready holds come first with position 0, followed by waiting holds
+-- numbered from 1.
+-- name: ListBookHolds :many
+
+SELECT id, isbn, patron_id, placed_at, status, barcode, pickup_by,
+  (CASE WHEN status = 'waiting' THEN row_number() OVER (PARTITION BY status ORDER BY id) ELSE 0 END)::integer AS position
+FROM hold
+WHERE isbn = @isbn AND status IN ('waiting', 'ready')
+ORDER BY status = 'waiting', id;
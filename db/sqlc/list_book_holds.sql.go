@@ -0,0 +1,64 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: list_book_holds.sql
+
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const listBookHolds = `-- name: ListBookHolds :many
+
+SELECT id, isbn, patron_id, placed_at, status, barcode, pickup_by,
+  (CASE WHEN status = 'waiting' THEN row_number() OVER (PARTITION BY status ORDER BY id) ELSE 0 END)::integer AS position
+FROM hold
+WHERE isbn = $1 AND status IN ('waiting', 'ready')
+ORDER BY status = 'waiting', id
+`
+
+type ListBookHoldsRow struct {
+	ID       int64
+	Isbn     int64
+	PatronID int64
+	PlacedAt time.Time
+	Status   string
+	Barcode  sql.NullString
+	PickupBy sql.NullTime
+	Position int32
+}
+
+// ListBookHolds returns the active holds for a title in the order they are
+// served. Ready holds come first with position 0, followed by waiting holds
+// numbered from 1.
+func (q *Queries) ListBookHolds(ctx context.Context, isbn int64) ([]ListBookHoldsRow, error) {
+	rows, err := q.db.Query(ctx, listBookHolds, isbn)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBookHoldsRow
+	for rows.Next() {
+		var i ListBookHoldsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Isbn,
+			&i.PatronID,
+			&i.PlacedAt,
+			&i.Status,
+			&i.Barcode,
+			&i.PickupBy,
+			&i.Position,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,9 @@
+-- ListBookImportConflicts returns the first staged row of every isbn that is
+-- already in the library.
+-- name: ListBookImportConflicts :many
+
+SELECT min(book_import.row_number)::bigint AS row_number, book_import.isbn
+FROM book_import
+JOIN book ON book.isbn = book_import.isbn
+GROUP BY book_import.isbn
+ORDER BY row_number;
@@ -0,0 +1,46 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: list_book_import_conflicts.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const listBookImportConflicts = `-- name: ListBookImportConflicts :many
+
+SELECT min(book_import.row_number)::bigint AS row_number, book_import.isbn
+FROM book_import
+JOIN book ON book.isbn = book_import.isbn
+GROUP BY book_import.isbn
+ORDER BY row_number
+`
+
+type ListBookImportConflictsRow struct {
+	RowNumber int64
+	Isbn      int64
+}
+
+// ListBookImportConflicts returns the first staged row of every isbn that is
+// already in the library.
+func (q *Queries) ListBookImportConflicts(ctx context.Context) ([]ListBookImportConflictsRow, error) {
+	rows, err := q.db.Query(ctx, listBookImportConflicts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBookImportConflictsRow
+	for rows.Next() {
+		var i ListBookImportConflictsRow
+		if err := rows.Scan(&i.RowNumber, &i.Isbn); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,10 @@
+-- ListBookImportDuplicates returns the staged rows whose isbn already came up
+-- on an earlier row of the same import, along with that earlier row.
+-- name: ListBookImportDuplicates :many
+
+SELECT staged.row_number, staged.isbn, staged.first_row::bigint AS first_row
+FROM (
+  SELECT row_number, isbn, min(row_number) OVER (PARTITION BY isbn) AS first_row FROM book_import
+) AS staged
+WHERE staged.row_number <> staged.first_row
+ORDER BY staged.row_number;
@@ -0,0 +1,48 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: list_book_import_duplicates.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const listBookImportDuplicates = `-- name: ListBookImportDuplicates :many
+
+SELECT staged.row_number, staged.isbn, staged.first_row::bigint AS first_row
+FROM (
+  SELECT row_number, isbn, min(row_number) OVER (PARTITION BY isbn) AS first_row FROM book_import
+) AS staged
+WHERE staged.row_number <> staged.first_row
+ORDER BY staged.row_number
+`
+
+type ListBookImportDuplicatesRow struct {
+	RowNumber int64
+	Isbn      int64
+	FirstRow  int64
+}
+
+// ListBookImportDuplicates returns the staged rows whose isbn already came up
+// on an earlier row of the same import, along with that earlier row.
+func (q *Queries) ListBookImportDuplicates(ctx context.Context) ([]ListBookImportDuplicatesRow, error) {
+	rows, err := q.db.Query(ctx, listBookImportDuplicates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBookImportDuplicatesRow
+	for rows.Next() {
+		var i ListBookImportDuplicatesRow
+		if err := rows.Scan(&i.RowNumber, &i.Isbn, &i.FirstRow); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,10 @@
+-- ListBookImportTrashed returns the first staged row of every isbn whose book
+-- is in the trash.
+-- name: ListBookImportTrashed :many
+
+SELECT min(book_import.row_number)::bigint AS row_number, book_import.isbn
+FROM book_import
+JOIN book ON book.isbn = book_import.isbn
+WHERE book.deleted_at IS NOT NULL
+GROUP BY book_import.isbn
+ORDER BY row_number;
@@ -0,0 +1,19 @@
+-- ListBooksByIsbnDesc returns a filtered list of books ordered by isbn descending. A page
+-- starts just after the previous page's last book; a null cursor is the first
+-- page. Filters that are null don't apply.
+-- Trashed books are only listed if include_deleted is true.
+-- name: ListBooksByIsbnDesc :many
+
+SELECT isbn, title, created_at, updated_at, version, deleted_at
+FROM book
+WHERE (sqlc.narg('title_prefix')::text IS NULL OR starts_with(title, sqlc.narg('title_prefix')))
+AND (sqlc.narg('min_isbn')::bigint IS NULL OR isbn >= sqlc.narg('min_isbn'))
+AND (sqlc.narg('max_isbn')::bigint IS NULL OR isbn <= sqlc.narg('max_isbn'))
+AND (sqlc.narg('created_after')::timestamptz IS NULL OR created_at >= sqlc.narg('created_after'))
+AND (sqlc.narg('created_before')::timestamptz IS NULL OR created_at < sqlc.narg('created_before'))
+AND (sqlc.narg('updated_after')::timestamptz IS NULL OR updated_at >= sqlc.narg('updated_after'))
+AND (sqlc.narg('updated_before')::timestamptz IS NULL OR updated_at < sqlc.narg('updated_before'))
+AND (sqlc.narg('after_isbn')::bigint IS NULL OR isbn < sqlc.narg('after_isbn'))
+AND (@include_deleted::boolean OR deleted_at IS NULL)
+ORDER BY isbn DESC
+LIMIT @total_size;
@@ -0,0 +1,85 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: list_books_by_title.sql
+
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+)
+
+const listBooksByTitle = `-- name: ListBooksByTitle :many
+
+SELECT isbn, title, created_at, updated_at, version, deleted_at
+FROM book
+WHERE ($1::text IS NULL OR starts_with(title, $1))
+AND ($2::bigint IS NULL OR isbn >= $2)
+AND ($3::bigint IS NULL OR isbn <= $3)
+AND ($4::timestamptz IS NULL OR created_at >= $4)
+AND ($5::timestamptz IS NULL OR created_at < $5)
+AND ($6::timestamptz IS NULL OR updated_at >= $6)
+AND ($7::timestamptz IS NULL OR updated_at < $7)
+AND ($8::text IS NULL OR (title, isbn) > ($8, $9::bigint))
+AND ($10::boolean OR deleted_at IS NULL)
+ORDER BY title, isbn
+LIMIT $11
+`
+
+type ListBooksByTitleParams struct {
+	TitlePrefix    sql.NullString
+	MinIsbn        sql.NullInt64
+	MaxIsbn        sql.NullInt64
+	CreatedAfter   sql.NullTime
+	CreatedBefore  sql.NullTime
+	UpdatedAfter   sql.NullTime
+	UpdatedBefore  sql.NullTime
+	AfterTitle     sql.NullString
+	AfterIsbn      int64
+	IncludeDeleted bool
+	TotalSize      int32
+}
+
+// ListBooksByTitle returns a filtered list of books ordered by title and then
+// isbn. A page starts just after the previous page's last book; a null cursor
+// is the first page. Filters that are null don't apply.
+// Trashed books are only listed if include_deleted is true.
+func (q *Queries) ListBooksByTitle(ctx context.Context, arg ListBooksByTitleParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listBooksByTitle,
+		arg.TitlePrefix,
+		arg.MinIsbn,
+		arg.MaxIsbn,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedAfter,
+		arg.UpdatedBefore,
+		arg.AfterTitle,
+		arg.AfterIsbn,
+		arg.IncludeDeleted,
+		arg.TotalSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.Isbn,
+			&i.Title,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
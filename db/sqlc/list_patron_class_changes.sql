@@ -0,0 +1,5 @@
+-- ListPatronClassChanges returns every change to the class of a single
+-- patron, oldest first.
+-- name: ListPatronClassChanges :many
+
+SELECT * FROM patron_class_change WHERE patron_id = @patron_id ORDER BY id;
@@ -0,0 +1,5 @@
+-- ListPatronLedger returns every ledger entry of a single patron, oldest
+-- first.
+-- name: ListPatronLedger :many
+
+SELECT * FROM ledger_entry WHERE patron_id = @patron_id ORDER BY id;
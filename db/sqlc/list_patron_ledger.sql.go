@@ -0,0 +1,46 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: list_patron_ledger.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const listPatronLedger = `-- name: ListPatronLedger :many
+
+SELECT id, patron_id, loan_id, kind, amount, note, created_at, actor FROM ledger_entry WHERE patron_id = $1 ORDER BY id
+`
+
+// ListPatronLedger returns every ledger entry of a single patron, oldest
+// first.
+func (q *Queries) ListPatronLedger(ctx context.Context, patronID int64) ([]LedgerEntry, error) {
+	rows, err := q.db.Query(ctx, listPatronLedger, patronID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LedgerEntry
+	for rows.Next() {
+		var i LedgerEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.PatronID,
+			&i.LoanID,
+			&i.Kind,
+			&i.Amount,
+			&i.Note,
+			&i.CreatedAt,
+			&i.Actor,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,5 @@
+-- ListPatronLoans returns the open loans of a single patron ordered by due
+-- date.
+-- name: ListPatronLoans :many
+
+SELECT * FROM loan WHERE patron_id = @patron_id AND returned_at IS NULL ORDER BY due_at, id;
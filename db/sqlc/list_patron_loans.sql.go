@@ -0,0 +1,45 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: list_patron_loans.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const listPatronLoans = `-- name: ListPatronLoans :many
+
+SELECT id, barcode, patron_id, checked_out_at, due_at, returned_at, renewals FROM loan WHERE patron_id = $1 AND returned_at IS NULL ORDER BY due_at, id
+`
+
+// ListPatronLoans returns the open loans of a single patron ordered by due
+// date.
+func (q *Queries) ListPatronLoans(ctx context.Context, patronID int64) ([]Loan, error) {
+	rows, err := q.db.Query(ctx, listPatronLoans, patronID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Loan
+	for rows.Next() {
+		var i Loan
+		if err := rows.Scan(
+			&i.ID,
+			&i.Barcode,
+			&i.PatronID,
+			&i.CheckedOutAt,
+			&i.DueAt,
+			&i.ReturnedAt,
+			&i.Renewals,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,16 @@
+-- ListWebhookDeliveries returns the deliveries of a single webhook, newest
+-- first, optionally only those with a status. A page starts just before the
+-- previous page's last delivery; a zero id is the first page.
+-- name: ListWebhookDeliveries :many
+
+SELECT webhook_delivery.id, webhook_delivery.webhook_id, webhook_delivery.audit_id, book_audit.action,
+  webhook_delivery.status, webhook_delivery.attempts, webhook_delivery.next_attempt_at,
+  webhook_delivery.last_attempt_at, webhook_delivery.response_status, webhook_delivery.last_error,
+  webhook_delivery.created_at, webhook_delivery.delivered_at
+FROM webhook_delivery
+JOIN book_audit ON book_audit.id = webhook_delivery.audit_id
+WHERE webhook_delivery.webhook_id = @webhook_id
+AND (@status::text = '' OR webhook_delivery.status = @status)
+AND (@before_id::bigint = 0 OR webhook_delivery.id < @before_id)
+ORDER BY webhook_delivery.id DESC
+LIMIT @total_size;
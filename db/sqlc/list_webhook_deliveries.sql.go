@@ -0,0 +1,90 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: list_webhook_deliveries.sql
+
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const listWebhookDeliveries = `-- name: ListWebhookDeliveries :many
+
+SELECT webhook_delivery.id, webhook_delivery.webhook_id, webhook_delivery.audit_id, book_audit.action,
+  webhook_delivery.status, webhook_delivery.attempts, webhook_delivery.next_attempt_at,
+  webhook_delivery.last_attempt_at, webhook_delivery.response_status, webhook_delivery.last_error,
+  webhook_delivery.created_at, webhook_delivery.delivered_at
+FROM webhook_delivery
+JOIN book_audit ON book_audit.id = webhook_delivery.audit_id
+WHERE webhook_delivery.webhook_id = $1
+AND ($2::text = '' OR webhook_delivery.status = $2)
+AND ($3::bigint = 0 OR webhook_delivery.id < $3)
+ORDER BY webhook_delivery.id DESC
+LIMIT $4
+`
+
+type ListWebhookDeliveriesParams struct {
+	WebhookID int64
+	Status    string
+	BeforeID  int64
+	TotalSize int32
+}
+
+type ListWebhookDeliveriesRow struct {
+	ID             int64
+	WebhookID      int64
+	AuditID        int64
+	Action         string
+	Status         string
+	Attempts       int32
+	NextAttemptAt  time.Time
+	LastAttemptAt  sql.NullTime
+	ResponseStatus int32
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    sql.NullTime
+}
+
+// ListWebhookDeliveries returns the deliveries of a single webhook, newest
+// first, optionally only those with a status. A page starts just before the
+// previous page's last delivery; a zero id is the first page.
+func (q *Queries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]ListWebhookDeliveriesRow, error) {
+	rows, err := q.db.Query(ctx, listWebhookDeliveries,
+		arg.WebhookID,
+		arg.Status,
+		arg.BeforeID,
+		arg.TotalSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListWebhookDeliveriesRow
+	for rows.Next() {
+		var i ListWebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.AuditID,
+			&i.Action,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastAttemptAt,
+			&i.ResponseStatus,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
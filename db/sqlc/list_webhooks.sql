@@ -0,0 +1,4 @@
+-- ListWebhooks returns every webhook, oldest first.
+-- name: ListWebhooks :many
+
+SELECT id, url, secret, events, active, created_at FROM webhook ORDER BY id;
@@ -0,0 +1,8 @@
+-- LockAvailableCopy locks any available copy of a title. Copies locked by a
+-- concurrent transaction are skipped.
+-- name: LockAvailableCopy :one
+
+SELECT barcode, isbn, location, status FROM copy
+WHERE isbn = @isbn AND status = 'available'
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
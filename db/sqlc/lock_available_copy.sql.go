@@ -0,0 +1,32 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: lock_available_copy.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const lockAvailableCopy = `-- name: LockAvailableCopy :one
+
+SELECT barcode, isbn, location, status FROM copy
+WHERE isbn = $1 AND status = 'available'
+LIMIT 1
+FOR UPDATE SKIP LOCKED
+`
+
+// LockAvailableCopy locks any available copy of a title. Copies locked by a
+// concurrent transaction are skipped.
+func (q *Queries) LockAvailableCopy(ctx context.Context, isbn int64) (Copy, error) {
+	row := q.db.QueryRow(ctx, lockAvailableCopy, isbn)
+	var i Copy
+	err := row.Scan(
+		&i.Barcode,
+		&i.Isbn,
+		&i.Location,
+		&i.Status,
+	)
+	return i, err
+}
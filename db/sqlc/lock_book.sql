@@ -0,0 +1,7 @@
+-- LockBook fetches a single book that isn't trashed and locks it until the
+-- end of the transaction.
+-- name: LockBook :one
+
+SELECT isbn, title, created_at, updated_at, version, deleted_at FROM book
+WHERE isbn = @isbn AND deleted_at IS NULL
+FOR UPDATE;
@@ -0,0 +1,33 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: lock_book.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const lockBook = `-- name: LockBook :one
+
+SELECT isbn, title, created_at, updated_at, version, deleted_at FROM book
+WHERE isbn = $1 AND deleted_at IS NULL
+FOR UPDATE
+`
+
+// LockBook fetches a single book that isn't trashed and locks it until the
+// end of the transaction.
+func (q *Queries) LockBook(ctx context.Context, isbn int64) (Book, error) {
+	row := q.db.QueryRow(ctx, lockBook, isbn)
+	var i Book
+	err := row.Scan(
+		&i.Isbn,
+		&i.Title,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,5 @@
+-- LockCopy fetches a single copy and locks it until the end of the
+-- transaction.
+-- name: LockCopy :one
+
+SELECT barcode, isbn, location, status FROM copy WHERE barcode = @barcode FOR UPDATE;
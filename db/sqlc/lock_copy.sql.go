@@ -0,0 +1,29 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: lock_copy.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const lockCopy = `-- name: LockCopy :one
+
+SELECT barcode, isbn, location, status FROM copy WHERE barcode = $1 FOR UPDATE
+`
+
+// LockCopy fetches a single copy and locks it until the end of the
+// transaction.
+func (q *Queries) LockCopy(ctx context.Context, barcode string) (Copy, error) {
+	row := q.db.QueryRow(ctx, lockCopy, barcode)
+	var i Copy
+	err := row.Scan(
+		&i.Barcode,
+		&i.Isbn,
+		&i.Location,
+		&i.Status,
+	)
+	return i, err
+}
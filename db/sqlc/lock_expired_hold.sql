@@ -0,0 +1,7 @@
+-- LockExpiredHold locks the hold a copy is set aside for if it wasn't picked
+-- up in time.
+-- name: LockExpiredHold :one
+
+SELECT * FROM hold
+WHERE barcode = @barcode AND status = 'ready' AND pickup_by < now()
+FOR UPDATE;
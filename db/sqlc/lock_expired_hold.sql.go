@@ -0,0 +1,35 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: lock_expired_hold.sql
+
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+)
+
+const lockExpiredHold = `-- name: LockExpiredHold :one
+
+SELECT id, isbn, patron_id, placed_at, status, barcode, pickup_by FROM hold
+WHERE barcode = $1 AND status = 'ready' AND pickup_by < now()
+FOR UPDATE
+`
+
+// LockExpiredHold locks the hold a copy is set aside for if it wasn't picked
+// up in time.
+func (q *Queries) LockExpiredHold(ctx context.Context, barcode sql.NullString) (Hold, error) {
+	row := q.db.QueryRow(ctx, lockExpiredHold, barcode)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn,
+		&i.PatronID,
+		&i.PlacedAt,
+		&i.Status,
+		&i.Barcode,
+		&i.PickupBy,
+	)
+	return i, err
+}
@@ -0,0 +1,5 @@
+-- LockHold fetches a single hold and locks it until the end of the
+-- transaction.
+-- name: LockHold :one
+
+SELECT * FROM hold WHERE id = @id FOR UPDATE;
@@ -0,0 +1,32 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: lock_hold.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const lockHold = `-- name: LockHold :one
+
+SELECT id, isbn, patron_id, placed_at, status, barcode, pickup_by FROM hold WHERE id = $1 FOR UPDATE
+`
+
+// LockHold fetches a single hold and locks it until the end of the
+// transaction.
+func (q *Queries) LockHold(ctx context.Context, id int64) (Hold, error) {
+	row := q.db.QueryRow(ctx, lockHold, id)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn,
+		&i.PatronID,
+		&i.PlacedAt,
+		&i.Status,
+		&i.Barcode,
+		&i.PickupBy,
+	)
+	return i, err
+}
@@ -0,0 +1,10 @@
+-- LockNextWaitingHold locks the oldest waiting hold for a title, waiting for a
+-- concurrent transaction that has it locked. If that transaction takes it out
+-- of the queue no row is returned, even if other holds are waiting.
+-- name: LockNextWaitingHold :one
+
+SELECT * FROM hold
+WHERE isbn = @isbn AND status = 'waiting'
+ORDER BY id
+LIMIT 1
+FOR UPDATE;
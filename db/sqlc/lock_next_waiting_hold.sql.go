@@ -0,0 +1,37 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: lock_next_waiting_hold.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const lockNextWaitingHold = `-- name: LockNextWaitingHold :one
+
+SELECT id, isbn, patron_id, placed_at, status, barcode, pickup_by FROM hold
+WHERE isbn = $1 AND status = 'waiting'
+ORDER BY id
+LIMIT 1
+FOR UPDATE
+`
+
+// LockNextWaitingHold locks the oldest waiting hold for a title, waiting for a
+// concurrent transaction that has it locked. If that transaction takes it out
+// of the queue no row is returned, even if other holds are waiting.
+func (q *Queries) LockNextWaitingHold(ctx context.Context, isbn int64) (Hold, error) {
+	row := q.db.QueryRow(ctx, lockNextWaitingHold, isbn)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn,
+		&i.PatronID,
+		&i.PlacedAt,
+		&i.Status,
+		&i.Barcode,
+		&i.PickupBy,
+	)
+	return i, err
+}
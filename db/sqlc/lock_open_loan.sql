@@ -0,0 +1,5 @@
+-- LockOpenLoan fetches the open loan of a copy and locks it until the end of
+-- the transaction.
+-- name: LockOpenLoan :one
+
+SELECT * FROM loan WHERE barcode = @barcode AND returned_at IS NULL FOR UPDATE;
@@ -0,0 +1,32 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: lock_open_loan.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const lockOpenLoan = `-- name: LockOpenLoan :one
+
+SELECT id, barcode, patron_id, checked_out_at, due_at, returned_at, renewals FROM loan WHERE barcode = $1 AND returned_at IS NULL FOR UPDATE
+`
+
+// LockOpenLoan fetches the open loan of a copy and locks it until the end of
+// the transaction.
+func (q *Queries) LockOpenLoan(ctx context.Context, barcode string) (Loan, error) {
+	row := q.db.QueryRow(ctx, lockOpenLoan, barcode)
+	var i Loan
+	err := row.Scan(
+		&i.ID,
+		&i.Barcode,
+		&i.PatronID,
+		&i.CheckedOutAt,
+		&i.DueAt,
+		&i.ReturnedAt,
+		&i.Renewals,
+	)
+	return i, err
+}
@@ -0,0 +1,5 @@
+-- LockPatron fetches a single patron and locks it until the end of the
+-- transaction, which serializes checkouts for that patron.
+-- name: LockPatron :one
+
+SELECT id, name, borrowing_limit, class FROM patron WHERE id = @id FOR UPDATE;
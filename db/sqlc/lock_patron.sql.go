@@ -0,0 +1,29 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: lock_patron.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const lockPatron = `-- name: LockPatron :one
+
+SELECT id, name, borrowing_limit, class FROM patron WHERE id = $1 FOR UPDATE
+`
+
+// LockPatron fetches a single patron and locks it until the end of the
+// transaction, which serializes checkouts for that patron.
+func (q *Queries) LockPatron(ctx context.Context, id int64) (Patron, error) {
+	row := q.db.QueryRow(ctx, lockPatron, id)
+	var i Patron
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.BorrowingLimit,
+		&i.Class,
+	)
+	return i, err
+}
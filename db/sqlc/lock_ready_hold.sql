@@ -0,0 +1,4 @@
+-- LockReadyHold locks the hold a copy on the hold shelf is set aside for.
+-- name: LockReadyHold :one
+
+SELECT * FROM hold WHERE barcode = @barcode AND status = 'ready' FOR UPDATE;
@@ -0,0 +1,32 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: lock_ready_hold.sql
+
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+)
+
+const lockReadyHold = `-- name: LockReadyHold :one
+
+SELECT id, isbn, patron_id, placed_at, status, barcode, pickup_by FROM hold WHERE barcode = $1 AND status = 'ready' FOR UPDATE
+`
+
+// LockReadyHold locks the hold a copy on the hold shelf is set aside for.
+func (q *Queries) LockReadyHold(ctx context.Context, barcode sql.NullString) (Hold, error) {
+	row := q.db.QueryRow(ctx, lockReadyHold, barcode)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn,
+		&i.PatronID,
+		&i.PlacedAt,
+		&i.Status,
+		&i.Barcode,
+		&i.PickupBy,
+	)
+	return i, err
+}
@@ -0,0 +1,6 @@
+-- MergeBookImport creates a book from the first staged row of every isbn.
+-- It fails if any of them already exists.
+-- name: MergeBookImport :execrows
+
+INSERT INTO book (isbn, title)
+SELECT DISTINCT ON (isbn) isbn, title FROM book_import ORDER BY isbn, row_number;
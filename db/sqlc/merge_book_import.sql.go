@@ -0,0 +1,26 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: merge_book_import.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const mergeBookImport = `-- name: MergeBookImport :execrows
+
+INSERT INTO book (isbn, title)
+SELECT DISTINCT ON (isbn) isbn, title FROM book_import ORDER BY isbn, row_number
+`
+
+// MergeBookImport creates a book from the first staged row of every isbn.
+// It fails if any of them already exists.
+func (q *Queries) MergeBookImport(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, mergeBookImport)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
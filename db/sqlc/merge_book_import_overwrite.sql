@@ -0,0 +1,8 @@
+-- MergeBookImportOverwrite creates or retitles a book from the first staged
+-- row of every isbn. Books in the trash are left alone.
+-- name: MergeBookImportOverwrite :execrows
+
+INSERT INTO book (isbn, title)
+SELECT DISTINCT ON (isbn) isbn, title FROM book_import ORDER BY isbn, row_number
+ON CONFLICT (isbn) DO UPDATE SET title = EXCLUDED.title, updated_at = now(), version = book.version + 1
+WHERE book.deleted_at IS NULL;
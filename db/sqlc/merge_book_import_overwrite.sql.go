@@ -0,0 +1,28 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: merge_book_import_overwrite.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const mergeBookImportOverwrite = `-- name: MergeBookImportOverwrite :execrows
+
+INSERT INTO book (isbn, title)
+SELECT DISTINCT ON (isbn) isbn, title FROM book_import ORDER BY isbn, row_number
+ON CONFLICT (isbn) DO UPDATE SET title = EXCLUDED.title, updated_at = now(), version = book.version + 1
+WHERE book.deleted_at IS NULL
+`
+
+// MergeBookImportOverwrite creates or retitles a book from the first staged
+// row of every isbn. Books in the trash are left alone.
+func (q *Queries) MergeBookImportOverwrite(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, mergeBookImportOverwrite)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
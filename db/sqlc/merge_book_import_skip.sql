@@ -0,0 +1,7 @@
+-- MergeBookImportSkip creates a book from the first staged row of every isbn
+-- that isn't in the library yet.
+-- name: MergeBookImportSkip :execrows
+
+INSERT INTO book (isbn, title)
+SELECT DISTINCT ON (isbn) isbn, title FROM book_import ORDER BY isbn, row_number
+ON CONFLICT (isbn) DO NOTHING;
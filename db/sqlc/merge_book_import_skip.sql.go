@@ -0,0 +1,27 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: merge_book_import_skip.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const mergeBookImportSkip = `-- name: MergeBookImportSkip :execrows
+
+INSERT INTO book (isbn, title)
+SELECT DISTINCT ON (isbn) isbn, title FROM book_import ORDER BY isbn, row_number
+ON CONFLICT (isbn) DO NOTHING
+`
+
+// MergeBookImportSkip creates a book from the first staged row of every isbn
+// that isn't in the library yet.
+func (q *Queries) MergeBookImportSkip(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, mergeBookImportSkip)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -6,11 +6,54 @@ package sqlc
 
 import (
 	"database/sql"
+	"time"
+
+	"github.com/jackc/pgtype"
 )
 
+type Author struct {
+	ID   int64
+	Name string
+}
+
 type Book struct {
-	Isbn  int64
-	Title string
+	Isbn      int64
+	Title     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   int64
+	DeletedAt sql.NullTime
+}
+
+type BookAudit struct {
+	ID        int64
+	Isbn      int64
+	Action    string
+	Actor     string
+	RequestID string
+	OldBook   pgtype.JSONB
+	NewBook   pgtype.JSONB
+	CreatedAt time.Time
+	Txid      int64
+}
+
+type BookAuthor struct {
+	Isbn     int64
+	AuthorID int64
+	Position int32
+}
+
+type BookImport struct {
+	RowNumber int64
+	Isbn      int64
+	Title     string
+}
+
+type Copy struct {
+	Barcode  string
+	Isbn     int64
+	Location string
+	Status   string
 }
 
 type GooseDbVersion struct {
@@ -19,3 +62,73 @@ type GooseDbVersion struct {
 	IsApplied bool
 	Tstamp    sql.NullTime
 }
+
+type Hold struct {
+	ID       int64
+	Isbn     int64
+	PatronID int64
+	PlacedAt time.Time
+	Status   string
+	Barcode  sql.NullString
+	PickupBy sql.NullTime
+}
+
+type LedgerEntry struct {
+	ID        int64
+	PatronID  int64
+	LoanID    sql.NullInt64
+	Kind      string
+	Amount    int64
+	Note      string
+	CreatedAt time.Time
+	Actor     string
+}
+
+type Loan struct {
+	ID           int64
+	Barcode      string
+	PatronID     int64
+	CheckedOutAt time.Time
+	DueAt        time.Time
+	ReturnedAt   sql.NullTime
+	Renewals     int32
+}
+
+type Patron struct {
+	ID             int64
+	Name           string
+	BorrowingLimit int32
+	Class          string
+}
+
+type PatronClassChange struct {
+	ID        int64
+	PatronID  int64
+	OldClass  string
+	NewClass  string
+	Actor     string
+	CreatedAt time.Time
+}
+
+type Webhook struct {
+	ID        int64
+	Url       string
+	Secret    string
+	Events    []string
+	Active    bool
+	CreatedAt time.Time
+}
+
+type WebhookDelivery struct {
+	ID             int64
+	WebhookID      int64
+	AuditID        int64
+	Status         string
+	Attempts       int32
+	NextAttemptAt  time.Time
+	LastAttemptAt  sql.NullTime
+	ResponseStatus int32
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    sql.NullTime
+}
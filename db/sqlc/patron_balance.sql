@@ -0,0 +1,4 @@
+-- PatronBalance sums the ledger entries of a single patron.
+-- name: PatronBalance :one
+
+SELECT COALESCE(SUM(amount), 0)::bigint AS balance FROM ledger_entry WHERE patron_id = @patron_id;
@@ -0,0 +1,23 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: patron_balance.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const patronBalance = `-- name: PatronBalance :one
+
+SELECT COALESCE(SUM(amount), 0)::bigint AS balance FROM ledger_entry WHERE patron_id = $1
+`
+
+// PatronBalance sums the ledger entries of a single patron.
+func (q *Queries) PatronBalance(ctx context.Context, patronID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, patronBalance, patronID)
+	var balance int64
+	err := row.Scan(&balance)
+	return balance, err
+}
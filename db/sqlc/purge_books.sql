@@ -0,0 +1,9 @@
+-- PurgeBooks permanently deletes the books trashed before deleted_before.
+-- Books that gained copies or active holds while trashed are kept. The
+-- history of their holds is deleted with them.
+-- name: PurgeBooks :execrows
+
+DELETE FROM book
+WHERE deleted_at < @deleted_before
+AND NOT EXISTS (SELECT 1 FROM copy WHERE copy.isbn = book.isbn)
+AND NOT EXISTS (SELECT 1 FROM hold WHERE hold.isbn = book.isbn AND hold.status IN ('waiting', 'ready'));
@@ -0,0 +1,4 @@
+-- ReadyHold sets a copy aside for a hold until the pickup deadline.
+-- name: ReadyHold :exec
+
+UPDATE hold SET status = 'ready', barcode = @barcode, pickup_by = @pickup_by WHERE id = @id;
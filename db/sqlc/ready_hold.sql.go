@@ -0,0 +1,28 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: ready_hold.sql
+
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+)
+
+const readyHold = `-- name: ReadyHold :exec
+
+UPDATE hold SET status = 'ready', barcode = $1, pickup_by = $2 WHERE id = $3
+`
+
+type ReadyHoldParams struct {
+	Barcode  sql.NullString
+	PickupBy sql.NullTime
+	ID       int64
+}
+
+// ReadyHold sets a copy aside for a hold until the pickup deadline.
+func (q *Queries) ReadyHold(ctx context.Context, arg ReadyHoldParams) error {
+	_, err := q.db.Exec(ctx, readyHold, arg.Barcode, arg.PickupBy, arg.ID)
+	return err
+}
@@ -0,0 +1,13 @@
+-- RecordWebhookAttempt records how sending a delivery went. A pending
+-- delivery is tried again after the backoff.
+-- name: RecordWebhookAttempt :exec
+
+UPDATE webhook_delivery
+SET attempts = attempts + 1,
+  last_attempt_at = now(),
+  response_status = @response_status,
+  last_error = @last_error,
+  status = @status,
+  next_attempt_at = now() + interval '1 second' * @backoff_seconds::float8,
+  delivered_at = CASE WHEN @status = 'delivered' THEN now() END
+WHERE id = @id;
@@ -0,0 +1,44 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: record_webhook_attempt.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const recordWebhookAttempt = `-- name: RecordWebhookAttempt :exec
+
+UPDATE webhook_delivery
+SET attempts = attempts + 1,
+  last_attempt_at = now(),
+  response_status = $1,
+  last_error = $2,
+  status = $3,
+  next_attempt_at = now() + interval '1 second' * $4::float8,
+  delivered_at = CASE WHEN $3 = 'delivered' THEN now() END
+WHERE id = $5
+`
+
+type RecordWebhookAttemptParams struct {
+	ResponseStatus int32
+	LastError      string
+	Status         string
+	BackoffSeconds float64
+	ID             int64
+}
+
+// RecordWebhookAttempt records how sending a delivery went. A pending
+// delivery is tried again after the backoff.
+func (q *Queries) RecordWebhookAttempt(ctx context.Context, arg RecordWebhookAttemptParams) error {
+	_, err := q.db.Exec(ctx, recordWebhookAttempt,
+		arg.ResponseStatus,
+		arg.LastError,
+		arg.Status,
+		arg.BackoffSeconds,
+		arg.ID,
+	)
+	return err
+}
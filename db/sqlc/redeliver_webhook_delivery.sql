@@ -0,0 +1,13 @@
+-- RedeliverWebhookDelivery queues a delivery of a single webhook to be sent
+-- again right away with a fresh set of attempts.
+-- name: RedeliverWebhookDelivery :one
+
+UPDATE webhook_delivery AS d
+SET status = 'pending', attempts = 0, next_attempt_at = now(), delivered_at = NULL
+FROM book_audit
+WHERE d.id = @id
+AND d.webhook_id = @webhook_id
+AND book_audit.id = d.audit_id
+RETURNING d.id, d.webhook_id, d.audit_id, book_audit.action,
+  d.status, d.attempts, d.next_attempt_at, d.last_attempt_at, d.response_status, d.last_error,
+  d.created_at, d.delivered_at;
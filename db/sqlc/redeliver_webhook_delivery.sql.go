@@ -0,0 +1,67 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: redeliver_webhook_delivery.sql
+
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const redeliverWebhookDelivery = `-- name: RedeliverWebhookDelivery :one
+
+UPDATE webhook_delivery AS d
+SET status = 'pending', attempts = 0, next_attempt_at = now(), delivered_at = NULL
+FROM book_audit
+WHERE d.id = $1
+AND d.webhook_id = $2
+AND book_audit.id = d.audit_id
+RETURNING d.id, d.webhook_id, d.audit_id, book_audit.action,
+  d.status, d.attempts, d.next_attempt_at, d.last_attempt_at, d.response_status, d.last_error,
+  d.created_at, d.delivered_at
+`
+
+type RedeliverWebhookDeliveryParams struct {
+	ID        int64
+	WebhookID int64
+}
+
+type RedeliverWebhookDeliveryRow struct {
+	ID             int64
+	WebhookID      int64
+	AuditID        int64
+	Action         string
+	Status         string
+	Attempts       int32
+	NextAttemptAt  time.Time
+	LastAttemptAt  sql.NullTime
+	ResponseStatus int32
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    sql.NullTime
+}
+
+// RedeliverWebhookDelivery queues a delivery of a single webhook to be sent
+// again right away with a fresh set of attempts.
+func (q *Queries) RedeliverWebhookDelivery(ctx context.Context, arg RedeliverWebhookDeliveryParams) (RedeliverWebhookDeliveryRow, error) {
+	row := q.db.QueryRow(ctx, redeliverWebhookDelivery, arg.ID, arg.WebhookID)
+	var i RedeliverWebhookDeliveryRow
+	err := row.Scan(
+		&i.ID,
+		&i.WebhookID,
+		&i.AuditID,
+		&i.Action,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LastAttemptAt,
+		&i.ResponseStatus,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.DeliveredAt,
+	)
+	return i, err
+}
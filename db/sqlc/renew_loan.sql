@@ -0,0 +1,4 @@
+-- RenewLoan extends a single loan.
+-- name: RenewLoan :one
+
+UPDATE loan SET due_at = @due_at, renewals = renewals + 1 WHERE id = @id RETURNING *;
@@ -0,0 +1,37 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: renew_loan.sql
+
+package sqlc
+
+import (
+	"context"
+	"time"
+)
+
+const renewLoan = `-- name: RenewLoan :one
+
+UPDATE loan SET due_at = $1, renewals = renewals + 1 WHERE id = $2 RETURNING id, barcode, patron_id, checked_out_at, due_at, returned_at, renewals
+`
+
+type RenewLoanParams struct {
+	DueAt time.Time
+	ID    int64
+}
+
+// RenewLoan extends a single loan.
+func (q *Queries) RenewLoan(ctx context.Context, arg RenewLoanParams) (Loan, error) {
+	row := q.db.QueryRow(ctx, renewLoan, arg.DueAt, arg.ID)
+	var i Loan
+	err := row.Scan(
+		&i.ID,
+		&i.Barcode,
+		&i.PatronID,
+		&i.CheckedOutAt,
+		&i.DueAt,
+		&i.ReturnedAt,
+		&i.Renewals,
+	)
+	return i, err
+}
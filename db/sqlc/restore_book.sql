@@ -0,0 +1,8 @@
+-- RestoreBook takes a single book out of the trash and moves it to its next
+-- version.
+-- name: RestoreBook :one
+
+UPDATE book SET deleted_at = NULL, updated_at = now(), version = version + 1
+WHERE isbn = @isbn
+AND deleted_at IS NOT NULL
+RETURNING isbn, title, created_at, updated_at, version, deleted_at;
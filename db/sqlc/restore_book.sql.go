@@ -0,0 +1,34 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: restore_book.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const restoreBook = `-- name: RestoreBook :one
+
+UPDATE book SET deleted_at = NULL, updated_at = now(), version = version + 1
+WHERE isbn = $1
+AND deleted_at IS NOT NULL
+RETURNING isbn, title, created_at, updated_at, version, deleted_at
+`
+
+// RestoreBook takes a single book out of the trash and moves it to its next
+// version.
+func (q *Queries) RestoreBook(ctx context.Context, isbn int64) (Book, error) {
+	row := q.db.QueryRow(ctx, restoreBook, isbn)
+	var i Book
+	err := row.Scan(
+		&i.Isbn,
+		&i.Title,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
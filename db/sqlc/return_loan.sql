@@ -0,0 +1,4 @@
+-- ReturnLoan closes a single loan.
+-- name: ReturnLoan :one
+
+UPDATE loan SET returned_at = now() WHERE id = @id RETURNING *;
@@ -0,0 +1,31 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: return_loan.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const returnLoan = `-- name: ReturnLoan :one
+
+UPDATE loan SET returned_at = now() WHERE id = $1 RETURNING id, barcode, patron_id, checked_out_at, due_at, returned_at, renewals
+`
+
+// ReturnLoan closes a single loan.
+func (q *Queries) ReturnLoan(ctx context.Context, id int64) (Loan, error) {
+	row := q.db.QueryRow(ctx, returnLoan, id)
+	var i Loan
+	err := row.Scan(
+		&i.ID,
+		&i.Barcode,
+		&i.PatronID,
+		&i.CheckedOutAt,
+		&i.DueAt,
+		&i.ReturnedAt,
+		&i.Renewals,
+	)
+	return i, err
+}
@@ -16,22 +16,313 @@ SET xmloption = content;
 SET client_min_messages = warning;
 SET row_security = off;
 
+--
+-- Name: pg_trgm; Type: EXTENSION; Schema: -; Owner: -
+--
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm WITH SCHEMA public;
+
+
+--
+-- Name: EXTENSION pg_trgm; Type: COMMENT; Schema: -; Owner: 
+--
+
+COMMENT ON EXTENSION pg_trgm IS 'text similarity measurement and index searching based on trigrams';
+
+
+--
+-- Name: book_audit_append_only(); Type: FUNCTION; Schema: public; Owner: libraryuser
+--
+
+CREATE FUNCTION public.book_audit_append_only() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  RAISE EXCEPTION 'audit records can not be changed';
+END;
+$$;
+
+
+ALTER FUNCTION public.book_audit_append_only() OWNER TO libraryuser;
+
+--
+-- Name: book_audit_notify(); Type: FUNCTION; Schema: public; Owner: libraryuser
+--
+
+CREATE FUNCTION public.book_audit_notify() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  PERFORM pg_notify('book_changes', '');
+  RETURN NULL;
+END;
+$$;
+
+
+ALTER FUNCTION public.book_audit_notify() OWNER TO libraryuser;
+
+--
+-- Name: book_audit_record(); Type: FUNCTION; Schema: public; Owner: libraryuser
+--
+
+CREATE FUNCTION public.book_audit_record() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+  change TEXT;
+  old_image JSONB;
+  new_image JSONB;
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    change := 'create';
+  ELSIF TG_OP = 'DELETE' THEN
+    change := 'purge';
+  ELSIF OLD IS NOT DISTINCT FROM NEW THEN
+    RETURN NULL;
+  ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+    change := 'delete';
+  ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+    change := 'restore';
+  ELSE
+    change := 'update';
+  END IF;
+  -- authors are changed before the book is moved to its next version, so
+  -- the authors it had before are those of its last record. Records from
+  -- before authors were recorded have none.
+  IF TG_OP <> 'INSERT' THEN
+    old_image := to_jsonb(OLD) || jsonb_build_object('author_ids', (
+      SELECT book_audit.new_book -> 'author_ids'
+      FROM book_audit
+      WHERE book_audit.isbn = OLD.isbn
+      ORDER BY book_audit.id DESC
+      LIMIT 1
+    ));
+  END IF;
+  IF TG_OP <> 'DELETE' THEN
+    new_image := to_jsonb(NEW) || jsonb_build_object('author_ids', (
+      SELECT coalesce(jsonb_agg(book_author.author_id ORDER BY book_author.position), '[]')
+      FROM book_author
+      WHERE book_author.isbn = NEW.isbn
+    ));
+  END IF;
+  INSERT INTO book_audit (isbn, action, actor, request_id, old_book, new_book)
+  VALUES (
+    CASE WHEN TG_OP = 'DELETE' THEN OLD.isbn ELSE NEW.isbn END,
+    change,
+    coalesce(current_setting('library.actor', true), ''),
+    coalesce(current_setting('library.request_id', true), ''),
+    old_image,
+    new_image
+  );
+  RETURN NULL;
+END;
+$$;
+
+
+ALTER FUNCTION public.book_audit_record() OWNER TO libraryuser;
+
+--
+-- Name: ledger_entry_append_only(); Type: FUNCTION; Schema: public; Owner: libraryuser
+--
+
+CREATE FUNCTION public.ledger_entry_append_only() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  RAISE EXCEPTION 'ledger entries can not be changed';
+END;
+$$;
+
+
+ALTER FUNCTION public.ledger_entry_append_only() OWNER TO libraryuser;
+
+--
+-- Name: patron_class_change_append_only(); Type: FUNCTION; Schema: public; Owner: libraryuser
+--
+
+CREATE FUNCTION public.patron_class_change_append_only() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  RAISE EXCEPTION 'class changes can not be changed';
+END;
+$$;
+
+
+ALTER FUNCTION public.patron_class_change_append_only() OWNER TO libraryuser;
+
+--
+-- Name: patron_class_change_record(); Type: FUNCTION; Schema: public; Owner: libraryuser
+--
+
+CREATE FUNCTION public.patron_class_change_record() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  INSERT INTO patron_class_change (patron_id, old_class, new_class)
+  VALUES (NEW.id, OLD.class, NEW.class);
+  RETURN NULL;
+END;
+$$;
+
+
+ALTER FUNCTION public.patron_class_change_record() OWNER TO libraryuser;
+
+--
+-- Name: webhook_enqueue(); Type: FUNCTION; Schema: public; Owner: libraryuser
+--
+
+CREATE FUNCTION public.webhook_enqueue() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  INSERT INTO webhook_delivery (webhook_id, audit_id)
+  SELECT id, NEW.id
+  FROM webhook
+  WHERE active
+  AND (cardinality(events) = 0 OR NEW.action = ANY (events));
+  RETURN NULL;
+END;
+$$;
+
+
+ALTER FUNCTION public.webhook_enqueue() OWNER TO libraryuser;
+
 SET default_tablespace = '';
 
 SET default_table_access_method = heap;
 
+--
+-- Name: author; Type: TABLE; Schema: public; Owner: libraryuser
+--
+
+CREATE TABLE public.author (
+    id bigint NOT NULL,
+    name text NOT NULL
+);
+
+
+ALTER TABLE public.author OWNER TO libraryuser;
+
+--
+-- Name: author_id_seq; Type: SEQUENCE; Schema: public; Owner: libraryuser
+--
+
+CREATE SEQUENCE public.author_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE public.author_id_seq OWNER TO libraryuser;
+
+--
+-- Name: author_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: libraryuser
+--
+
+ALTER SEQUENCE public.author_id_seq OWNED BY public.author.id;
+
+
 --
 -- Name: book; Type: TABLE; Schema: public; Owner: libraryuser
 --
 
 CREATE TABLE public.book (
     isbn bigint NOT NULL,
-    title text NOT NULL
+    title text NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    version bigint DEFAULT 1 NOT NULL,
+    deleted_at timestamp with time zone
 );
 
 
 ALTER TABLE public.book OWNER TO libraryuser;
 
+--
+-- Name: book_audit; Type: TABLE; Schema: public; Owner: libraryuser
+--
+
+CREATE TABLE public.book_audit (
+    id bigint NOT NULL,
+    isbn bigint NOT NULL,
+    action text NOT NULL,
+    actor text DEFAULT ''::text NOT NULL,
+    request_id text DEFAULT ''::text NOT NULL,
+    old_book jsonb,
+    new_book jsonb,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    txid bigint DEFAULT txid_current() NOT NULL,
+    CONSTRAINT book_audit_action_check CHECK ((action = ANY (ARRAY['create'::text, 'update'::text, 'delete'::text, 'restore'::text, 'purge'::text])))
+);
+
+
+ALTER TABLE public.book_audit OWNER TO libraryuser;
+
+--
+-- Name: book_audit_id_seq; Type: SEQUENCE; Schema: public; Owner: libraryuser
+--
+
+CREATE SEQUENCE public.book_audit_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE public.book_audit_id_seq OWNER TO libraryuser;
+
+--
+-- Name: book_audit_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: libraryuser
+--
+
+ALTER SEQUENCE public.book_audit_id_seq OWNED BY public.book_audit.id;
+
+
+--
+-- Name: book_author; Type: TABLE; Schema: public; Owner: libraryuser
+--
+
+CREATE TABLE public.book_author (
+    isbn bigint NOT NULL,
+    author_id bigint NOT NULL,
+    "position" integer NOT NULL
+);
+
+
+ALTER TABLE public.book_author OWNER TO libraryuser;
+
+--
+-- Name: book_import; Type: TABLE; Schema: public; Owner: libraryuser
+--
+
+CREATE UNLOGGED TABLE public.book_import (
+    row_number bigint NOT NULL,
+    isbn bigint NOT NULL,
+    title text NOT NULL
+);
+
+
+ALTER TABLE public.book_import OWNER TO libraryuser;
+
+--
+-- Name: copy; Type: TABLE; Schema: public; Owner: libraryuser
+--
+
+CREATE TABLE public.copy (
+    barcode text NOT NULL,
+    isbn bigint NOT NULL,
+    location text NOT NULL,
+    status text DEFAULT 'available'::text NOT NULL,
+    CONSTRAINT copy_status_check CHECK ((status = ANY (ARRAY['available'::text, 'on_loan'::text, 'on_hold_shelf'::text, 'lost'::text])))
+);
+
+
+ALTER TABLE public.copy OWNER TO libraryuser;
+
 --
 -- Name: goose_db_version; Type: TABLE; Schema: public; Owner: libraryuser
 --
@@ -69,26 +360,763 @@ ALTER SEQUENCE public.goose_db_version_id_seq OWNED BY public.goose_db_version.i
 
 
 --
--- Name: goose_db_version id; Type: DEFAULT; Schema: public; Owner: libraryuser
+-- Name: hold; Type: TABLE; Schema: public; Owner: libraryuser
 --
 
-ALTER TABLE ONLY public.goose_db_version ALTER COLUMN id SET DEFAULT nextval('public.goose_db_version_id_seq'::regclass);
+CREATE TABLE public.hold (
+    id bigint NOT NULL,
+    isbn bigint NOT NULL,
+    patron_id bigint NOT NULL,
+    placed_at timestamp with time zone DEFAULT now() NOT NULL,
+    status text DEFAULT 'waiting'::text NOT NULL,
+    barcode text,
+    pickup_by timestamp with time zone,
+    CONSTRAINT hold_status_check CHECK ((status = ANY (ARRAY['waiting'::text, 'ready'::text, 'fulfilled'::text, 'expired'::text, 'cancelled'::text])))
+);
+
 
+ALTER TABLE public.hold OWNER TO libraryuser;
 
 --
--- Name: book book_pkey; Type: CONSTRAINT; Schema: public; Owner: libraryuser
+-- Name: hold_id_seq; Type: SEQUENCE; Schema: public; Owner: libraryuser
 --
 
-ALTER TABLE ONLY public.book
-    ADD CONSTRAINT book_pkey PRIMARY KEY (isbn);
+CREATE SEQUENCE public.hold_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
 
 
+ALTER TABLE public.hold_id_seq OWNER TO libraryuser;
+
 --
--- Name: goose_db_version goose_db_version_pkey; Type: CONSTRAINT; Schema: public; Owner: libraryuser
+-- Name: hold_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: libraryuser
 --
 
-ALTER TABLE ONLY public.goose_db_version
-    ADD CONSTRAINT goose_db_version_pkey PRIMARY KEY (id);
+ALTER SEQUENCE public.hold_id_seq OWNED BY public.hold.id;
+
+
+--
+-- Name: ledger_entry; Type: TABLE; Schema: public; Owner: libraryuser
+--
+
+CREATE TABLE public.ledger_entry (
+    id bigint NOT NULL,
+    patron_id bigint NOT NULL,
+    loan_id bigint,
+    kind text NOT NULL,
+    amount bigint NOT NULL,
+    note text DEFAULT ''::text NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    actor text DEFAULT COALESCE(current_setting('library.actor'::text, true), ''::text) NOT NULL,
+    CONSTRAINT ledger_entry_amount_check CHECK ((((kind = 'fine'::text) = (amount > 0)) AND (amount <> 0))),
+    CONSTRAINT ledger_entry_kind_check CHECK ((kind = ANY (ARRAY['fine'::text, 'payment'::text, 'waiver'::text])))
+);
+
+
+ALTER TABLE public.ledger_entry OWNER TO libraryuser;
+
+--
+-- Name: ledger_entry_id_seq; Type: SEQUENCE; Schema: public; Owner: libraryuser
+--
+
+CREATE SEQUENCE public.ledger_entry_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE public.ledger_entry_id_seq OWNER TO libraryuser;
+
+--
+-- Name: ledger_entry_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: libraryuser
+--
+
+ALTER SEQUENCE public.ledger_entry_id_seq OWNED BY public.ledger_entry.id;
+
+
+--
+-- Name: loan; Type: TABLE; Schema: public; Owner: libraryuser
+--
+
+CREATE TABLE public.loan (
+    id bigint NOT NULL,
+    barcode text NOT NULL,
+    patron_id bigint NOT NULL,
+    checked_out_at timestamp with time zone DEFAULT now() NOT NULL,
+    due_at timestamp with time zone NOT NULL,
+    returned_at timestamp with time zone,
+    renewals integer DEFAULT 0 NOT NULL
+);
+
+
+ALTER TABLE public.loan OWNER TO libraryuser;
+
+--
+-- Name: loan_id_seq; Type: SEQUENCE; Schema: public; Owner: libraryuser
+--
+
+CREATE SEQUENCE public.loan_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE public.loan_id_seq OWNER TO libraryuser;
+
+--
+-- Name: loan_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: libraryuser
+--
+
+ALTER SEQUENCE public.loan_id_seq OWNED BY public.loan.id;
+
+
+--
+-- Name: patron; Type: TABLE; Schema: public; Owner: libraryuser
+--
+
+CREATE TABLE public.patron (
+    id bigint NOT NULL,
+    name text NOT NULL,
+    borrowing_limit integer NOT NULL,
+    class text DEFAULT 'standard'::text NOT NULL,
+    CONSTRAINT patron_borrowing_limit_check CHECK ((borrowing_limit >= 0))
+);
+
+
+ALTER TABLE public.patron OWNER TO libraryuser;
+
+--
+-- Name: patron_id_seq; Type: SEQUENCE; Schema: public; Owner: libraryuser
+--
+
+CREATE SEQUENCE public.patron_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE public.patron_id_seq OWNER TO libraryuser;
+
+--
+-- Name: patron_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: libraryuser
+--
+
+ALTER SEQUENCE public.patron_id_seq OWNED BY public.patron.id;
+
+
+--
+-- Name: patron_class_change; Type: TABLE; Schema: public; Owner: libraryuser
+--
+
+CREATE TABLE public.patron_class_change (
+    id bigint NOT NULL,
+    patron_id bigint NOT NULL,
+    old_class text NOT NULL,
+    new_class text NOT NULL,
+    actor text DEFAULT COALESCE(current_setting('library.actor'::text, true), ''::text) NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+ALTER TABLE public.patron_class_change OWNER TO libraryuser;
+
+--
+-- Name: patron_class_change_id_seq; Type: SEQUENCE; Schema: public; Owner: libraryuser
+--
+
+CREATE SEQUENCE public.patron_class_change_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE public.patron_class_change_id_seq OWNER TO libraryuser;
+
+--
+-- Name: patron_class_change_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: libraryuser
+--
+
+ALTER SEQUENCE public.patron_class_change_id_seq OWNED BY public.patron_class_change.id;
+
+
+--
+-- Name: webhook; Type: TABLE; Schema: public; Owner: libraryuser
+--
+
+CREATE TABLE public.webhook (
+    id bigint NOT NULL,
+    url text NOT NULL,
+    secret text NOT NULL,
+    events text[] DEFAULT '{}'::text[] NOT NULL,
+    active boolean DEFAULT true NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+ALTER TABLE public.webhook OWNER TO libraryuser;
+
+--
+-- Name: webhook_delivery; Type: TABLE; Schema: public; Owner: libraryuser
+--
+
+CREATE TABLE public.webhook_delivery (
+    id bigint NOT NULL,
+    webhook_id bigint NOT NULL,
+    audit_id bigint NOT NULL,
+    status text DEFAULT 'pending'::text NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    next_attempt_at timestamp with time zone DEFAULT now() NOT NULL,
+    last_attempt_at timestamp with time zone,
+    response_status integer DEFAULT 0 NOT NULL,
+    last_error text DEFAULT ''::text NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    delivered_at timestamp with time zone,
+    CONSTRAINT webhook_delivery_status_check CHECK ((status = ANY (ARRAY['pending'::text, 'delivered'::text, 'dead'::text])))
+);
+
+
+ALTER TABLE public.webhook_delivery OWNER TO libraryuser;
+
+--
+-- Name: webhook_delivery_id_seq; Type: SEQUENCE; Schema: public; Owner: libraryuser
+--
+
+CREATE SEQUENCE public.webhook_delivery_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE public.webhook_delivery_id_seq OWNER TO libraryuser;
+
+--
+-- Name: webhook_delivery_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: libraryuser
+--
+
+ALTER SEQUENCE public.webhook_delivery_id_seq OWNED BY public.webhook_delivery.id;
+
+
+--
+-- Name: webhook_id_seq; Type: SEQUENCE; Schema: public; Owner: libraryuser
+--
+
+CREATE SEQUENCE public.webhook_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE public.webhook_id_seq OWNER TO libraryuser;
+
+--
+-- Name: webhook_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: libraryuser
+--
+
+ALTER SEQUENCE public.webhook_id_seq OWNED BY public.webhook.id;
+
+
+--
+-- Name: author id; Type: DEFAULT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.author ALTER COLUMN id SET DEFAULT nextval('public.author_id_seq'::regclass);
+
+
+--
+-- Name: book_audit id; Type: DEFAULT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.book_audit ALTER COLUMN id SET DEFAULT nextval('public.book_audit_id_seq'::regclass);
+
+
+--
+-- Name: goose_db_version id; Type: DEFAULT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.goose_db_version ALTER COLUMN id SET DEFAULT nextval('public.goose_db_version_id_seq'::regclass);
+
+
+--
+-- Name: hold id; Type: DEFAULT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.hold ALTER COLUMN id SET DEFAULT nextval('public.hold_id_seq'::regclass);
+
+
+--
+-- Name: ledger_entry id; Type: DEFAULT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.ledger_entry ALTER COLUMN id SET DEFAULT nextval('public.ledger_entry_id_seq'::regclass);
+
+
+--
+-- Name: loan id; Type: DEFAULT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.loan ALTER COLUMN id SET DEFAULT nextval('public.loan_id_seq'::regclass);
+
+
+--
+-- Name: patron id; Type: DEFAULT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.patron ALTER COLUMN id SET DEFAULT nextval('public.patron_id_seq'::regclass);
+
+
+--
+-- Name: patron_class_change id; Type: DEFAULT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.patron_class_change ALTER COLUMN id SET DEFAULT nextval('public.patron_class_change_id_seq'::regclass);
+
+
+--
+-- Name: webhook id; Type: DEFAULT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.webhook ALTER COLUMN id SET DEFAULT nextval('public.webhook_id_seq'::regclass);
+
+
+--
+-- Name: webhook_delivery id; Type: DEFAULT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.webhook_delivery ALTER COLUMN id SET DEFAULT nextval('public.webhook_delivery_id_seq'::regclass);
+
+
+--
+-- Name: author author_pkey; Type: CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.author
+    ADD CONSTRAINT author_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: book_author book_author_isbn_position_key; Type: CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.book_author
+    ADD CONSTRAINT book_author_isbn_position_key UNIQUE (isbn, "position");
+
+
+--
+-- Name: book_author book_author_pkey; Type: CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.book_author
+    ADD CONSTRAINT book_author_pkey PRIMARY KEY (isbn, author_id);
+
+
+--
+-- Name: book_audit book_audit_pkey; Type: CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.book_audit
+    ADD CONSTRAINT book_audit_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: book book_pkey; Type: CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.book
+    ADD CONSTRAINT book_pkey PRIMARY KEY (isbn);
+
+
+--
+-- Name: copy copy_pkey; Type: CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.copy
+    ADD CONSTRAINT copy_pkey PRIMARY KEY (barcode);
+
+
+--
+-- Name: goose_db_version goose_db_version_pkey; Type: CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.goose_db_version
+    ADD CONSTRAINT goose_db_version_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: hold hold_pkey; Type: CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.hold
+    ADD CONSTRAINT hold_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: ledger_entry ledger_entry_pkey; Type: CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.ledger_entry
+    ADD CONSTRAINT ledger_entry_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: loan loan_pkey; Type: CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.loan
+    ADD CONSTRAINT loan_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: patron patron_pkey; Type: CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.patron
+    ADD CONSTRAINT patron_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: patron_class_change patron_class_change_pkey; Type: CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.patron_class_change
+    ADD CONSTRAINT patron_class_change_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: webhook_delivery webhook_delivery_pkey; Type: CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.webhook_delivery
+    ADD CONSTRAINT webhook_delivery_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: webhook webhook_pkey; Type: CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.webhook
+    ADD CONSTRAINT webhook_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: author_name_id_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE INDEX author_name_id_idx ON public.author USING btree (name, id);
+
+
+--
+-- Name: book_audit_isbn_id_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE INDEX book_audit_isbn_id_idx ON public.book_audit USING btree (isbn, id);
+
+
+--
+-- Name: book_audit_txid_id_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE INDEX book_audit_txid_id_idx ON public.book_audit USING btree (txid, id);
+
+
+--
+-- Name: book_author_author_id_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE INDEX book_author_author_id_idx ON public.book_author USING btree (author_id);
+
+
+--
+-- Name: book_created_at_isbn_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE INDEX book_created_at_isbn_idx ON public.book USING btree (created_at, isbn);
+
+
+--
+-- Name: book_deleted_at_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE INDEX book_deleted_at_idx ON public.book USING btree (deleted_at) WHERE (deleted_at IS NOT NULL);
+
+
+--
+-- Name: book_title_isbn_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE INDEX book_title_isbn_idx ON public.book USING btree (title, isbn);
+
+
+--
+-- Name: book_title_search_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE INDEX book_title_search_idx ON public.book USING gin (to_tsvector('english'::regconfig, title));
+
+
+--
+-- Name: book_title_trgm_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE INDEX book_title_trgm_idx ON public.book USING gin (title public.gin_trgm_ops);
+
+
+--
+-- Name: book_updated_at_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE INDEX book_updated_at_idx ON public.book USING btree (updated_at);
+
+
+--
+-- Name: copy_isbn_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE INDEX copy_isbn_idx ON public.copy USING btree (isbn);
+
+
+--
+-- Name: hold_barcode_ready_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE UNIQUE INDEX hold_barcode_ready_idx ON public.hold USING btree (barcode) WHERE (status = 'ready'::text);
+
+
+--
+-- Name: hold_isbn_active_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE INDEX hold_isbn_active_idx ON public.hold USING btree (isbn, id) WHERE (status = ANY (ARRAY['waiting'::text, 'ready'::text]));
+
+
+--
+-- Name: hold_patron_id_isbn_active_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE UNIQUE INDEX hold_patron_id_isbn_active_idx ON public.hold USING btree (patron_id, isbn) WHERE (status = ANY (ARRAY['waiting'::text, 'ready'::text]));
+
+
+--
+-- Name: hold_pickup_by_ready_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE INDEX hold_pickup_by_ready_idx ON public.hold USING btree (pickup_by) WHERE (status = 'ready'::text);
+
+
+--
+-- Name: ledger_entry_loan_id_fine_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE UNIQUE INDEX ledger_entry_loan_id_fine_idx ON public.ledger_entry USING btree (loan_id) WHERE (kind = 'fine'::text);
+
+
+--
+-- Name: ledger_entry_patron_id_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE INDEX ledger_entry_patron_id_idx ON public.ledger_entry USING btree (patron_id, id);
+
+
+--
+-- Name: patron_class_change_patron_id_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE INDEX patron_class_change_patron_id_idx ON public.patron_class_change USING btree (patron_id, id);
+
+
+--
+-- Name: loan_barcode_open_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE UNIQUE INDEX loan_barcode_open_idx ON public.loan USING btree (barcode) WHERE (returned_at IS NULL);
+
+
+--
+-- Name: loan_patron_id_open_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE INDEX loan_patron_id_open_idx ON public.loan USING btree (patron_id) WHERE (returned_at IS NULL);
+
+
+--
+-- Name: webhook_delivery_next_attempt_at_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE INDEX webhook_delivery_next_attempt_at_idx ON public.webhook_delivery USING btree (next_attempt_at) WHERE (status = 'pending'::text);
+
+
+--
+-- Name: webhook_delivery_webhook_id_id_idx; Type: INDEX; Schema: public; Owner: libraryuser
+--
+
+CREATE INDEX webhook_delivery_webhook_id_id_idx ON public.webhook_delivery USING btree (webhook_id, id);
+
+
+--
+-- Name: book_audit book_audit_append_only; Type: TRIGGER; Schema: public; Owner: libraryuser
+--
+
+CREATE TRIGGER book_audit_append_only BEFORE DELETE OR UPDATE ON public.book_audit FOR EACH ROW EXECUTE FUNCTION public.book_audit_append_only();
+
+
+--
+-- Name: book_audit book_audit_notify; Type: TRIGGER; Schema: public; Owner: libraryuser
+--
+
+CREATE TRIGGER book_audit_notify AFTER INSERT ON public.book_audit FOR EACH STATEMENT EXECUTE FUNCTION public.book_audit_notify();
+
+
+--
+-- Name: book book_audit_record; Type: TRIGGER; Schema: public; Owner: libraryuser
+--
+
+CREATE TRIGGER book_audit_record AFTER INSERT OR DELETE OR UPDATE ON public.book FOR EACH ROW EXECUTE FUNCTION public.book_audit_record();
+
+
+--
+-- Name: ledger_entry ledger_entry_append_only; Type: TRIGGER; Schema: public; Owner: libraryuser
+--
+
+CREATE TRIGGER ledger_entry_append_only BEFORE DELETE OR UPDATE ON public.ledger_entry FOR EACH ROW EXECUTE FUNCTION public.ledger_entry_append_only();
+
+
+--
+-- Name: patron_class_change patron_class_change_append_only; Type: TRIGGER; Schema: public; Owner: libraryuser
+--
+
+CREATE TRIGGER patron_class_change_append_only BEFORE DELETE OR UPDATE ON public.patron_class_change FOR EACH ROW EXECUTE FUNCTION public.patron_class_change_append_only();
+
+
+--
+-- Name: patron patron_class_change_record; Type: TRIGGER; Schema: public; Owner: libraryuser
+--
+
+CREATE TRIGGER patron_class_change_record AFTER UPDATE OF class ON public.patron FOR EACH ROW WHEN ((old.class IS DISTINCT FROM new.class)) EXECUTE FUNCTION public.patron_class_change_record();
+
+
+--
+-- Name: book_audit webhook_enqueue; Type: TRIGGER; Schema: public; Owner: libraryuser
+--
+
+CREATE TRIGGER webhook_enqueue AFTER INSERT ON public.book_audit FOR EACH ROW EXECUTE FUNCTION public.webhook_enqueue();
+
+
+--
+-- Name: book_author book_author_author_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.book_author
+    ADD CONSTRAINT book_author_author_id_fkey FOREIGN KEY (author_id) REFERENCES public.author(id) ON DELETE RESTRICT;
+
+
+--
+-- Name: book_author book_author_isbn_fkey; Type: FK CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.book_author
+    ADD CONSTRAINT book_author_isbn_fkey FOREIGN KEY (isbn) REFERENCES public.book(isbn) ON DELETE CASCADE;
+
+
+--
+-- Name: copy copy_isbn_fkey; Type: FK CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.copy
+    ADD CONSTRAINT copy_isbn_fkey FOREIGN KEY (isbn) REFERENCES public.book(isbn) ON DELETE RESTRICT;
+
+
+--
+-- Name: hold hold_barcode_fkey; Type: FK CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.hold
+    ADD CONSTRAINT hold_barcode_fkey FOREIGN KEY (barcode) REFERENCES public.copy(barcode) ON DELETE RESTRICT;
+
+
+--
+-- Name: hold hold_isbn_fkey; Type: FK CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.hold
+    ADD CONSTRAINT hold_isbn_fkey FOREIGN KEY (isbn) REFERENCES public.book(isbn) ON DELETE CASCADE;
+
+
+--
+-- Name: hold hold_patron_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.hold
+    ADD CONSTRAINT hold_patron_id_fkey FOREIGN KEY (patron_id) REFERENCES public.patron(id) ON DELETE RESTRICT;
+
+
+--
+-- Name: ledger_entry ledger_entry_loan_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.ledger_entry
+    ADD CONSTRAINT ledger_entry_loan_id_fkey FOREIGN KEY (loan_id) REFERENCES public.loan(id) ON DELETE RESTRICT;
+
+
+--
+-- Name: ledger_entry ledger_entry_patron_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.ledger_entry
+    ADD CONSTRAINT ledger_entry_patron_id_fkey FOREIGN KEY (patron_id) REFERENCES public.patron(id) ON DELETE RESTRICT;
+
+
+--
+-- Name: loan loan_barcode_fkey; Type: FK CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.loan
+    ADD CONSTRAINT loan_barcode_fkey FOREIGN KEY (barcode) REFERENCES public.copy(barcode) ON DELETE RESTRICT;
+
+
+--
+-- Name: loan loan_patron_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.loan
+    ADD CONSTRAINT loan_patron_id_fkey FOREIGN KEY (patron_id) REFERENCES public.patron(id) ON DELETE RESTRICT;
+
+
+--
+-- Name: patron_class_change patron_class_change_patron_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.patron_class_change
+    ADD CONSTRAINT patron_class_change_patron_id_fkey FOREIGN KEY (patron_id) REFERENCES public.patron(id) ON DELETE RESTRICT;
+
+
+--
+-- Name: webhook_delivery webhook_delivery_audit_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.webhook_delivery
+    ADD CONSTRAINT webhook_delivery_audit_id_fkey FOREIGN KEY (audit_id) REFERENCES public.book_audit(id);
+
+
+--
+-- Name: webhook_delivery webhook_delivery_webhook_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: libraryuser
+--
+
+ALTER TABLE ONLY public.webhook_delivery
+    ADD CONSTRAINT webhook_delivery_webhook_id_fkey FOREIGN KEY (webhook_id) REFERENCES public.webhook(id) ON DELETE CASCADE;
 
 
 --
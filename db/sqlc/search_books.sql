@@ -0,0 +1,21 @@
+-- SearchBooks ranks the books whose title matches a web search style query,
+-- either word for word or within a few typos, by the full-text rank plus the
+-- trigram word similarity. A page starts just after the (score, isbn) pair
+-- of the previous page's last book. Trashed books are left out.
+-- name: SearchBooks :many
+
+SELECT matches.isbn, matches.title, matches.score,
+  ts_headline('english', matches.title, websearch_to_tsquery('english', @query::text))::text AS snippet
+FROM (
+  SELECT isbn, title,
+    (ts_rank(to_tsvector('english', title), websearch_to_tsquery('english', @query::text))
+      + word_similarity(@query::text, title))::float8 AS score
+  FROM book
+  WHERE (to_tsvector('english', title) @@ websearch_to_tsquery('english', @query::text)
+    OR @query::text <% title)
+  AND deleted_at IS NULL
+) AS matches
+WHERE matches.score < @after_score::float8
+  OR (matches.score = @after_score::float8 AND matches.isbn > @after_isbn::bigint)
+ORDER BY matches.score DESC, matches.isbn
+LIMIT @total_size;
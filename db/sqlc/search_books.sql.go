@@ -0,0 +1,77 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: search_books.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const searchBooks = `-- name: SearchBooks :many
+
+SELECT matches.isbn, matches.title, matches.score,
+  ts_headline('english', matches.title, websearch_to_tsquery('english', $1::text))::text AS snippet
+FROM (
+  SELECT isbn, title,
+    (ts_rank(to_tsvector('english', title), websearch_to_tsquery('english', $1::text))
+      + word_similarity($1::text, title))::float8 AS score
+  FROM book
+  WHERE (to_tsvector('english', title) @@ websearch_to_tsquery('english', $1::text)
+    OR $1::text <% title)
+  AND deleted_at IS NULL
+) AS matches
+WHERE matches.score < $2::float8
+  OR (matches.score = $2::float8 AND matches.isbn > $3::bigint)
+ORDER BY matches.score DESC, matches.isbn
+LIMIT $4
+`
+
+type SearchBooksParams struct {
+	Query      string
+	AfterScore float64
+	AfterIsbn  int64
+	TotalSize  int32
+}
+
+type SearchBooksRow struct {
+	Isbn    int64
+	Title   string
+	Score   float64
+	Snippet string
+}
+
+// SearchBooks ranks the books whose title matches a web search style query,
+// either word for word or within a few typos, by the full-text rank plus the
+// trigram word similarity. A page starts just after the (score, isbn) pair
+// of the previous page's last book. Trashed books are left out.
+func (q *Queries) SearchBooks(ctx context.Context, arg SearchBooksParams) ([]SearchBooksRow, error) {
+	rows, err := q.db.Query(ctx, searchBooks,
+		arg.Query,
+		arg.AfterScore,
+		arg.AfterIsbn,
+		arg.TotalSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchBooksRow
+	for rows.Next() {
+		var i SearchBooksRow
+		if err := rows.Scan(
+			&i.Isbn,
+			&i.Title,
+			&i.Score,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
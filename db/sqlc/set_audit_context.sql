@@ -0,0 +1,5 @@
+-- SetAuditContext sets who is making the changes of the current transaction
+-- and in which request, for the audit records written by the book trigger.
+-- name: SetAuditContext :exec
+
+SELECT set_config('library.actor', @actor::text, true), set_config('library.request_id', @request_id::text, true);
@@ -0,0 +1,32 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: set_audit_context.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const setAuditContext = `-- name: SetAuditContext :exec
+
+SELECT set_config('library.actor', $1::text, true), set_config('library.request_id', $2::text, true)
+`
+
+type SetAuditContextParams struct {
+	Actor     string
+	RequestID string
+}
+
+type SetAuditContextRow struct {
+	SetConfig   string
+	SetConfig_2 string
+}
+
+// SetAuditContext sets who is making the changes of the current transaction
+// and in which request, for the audit records written by the book trigger.
+func (q *Queries) SetAuditContext(ctx context.Context, arg SetAuditContextParams) error {
+	_, err := q.db.Exec(ctx, setAuditContext, arg.Actor, arg.RequestID)
+	return err
+}
@@ -0,0 +1,4 @@
+-- SetCopyStatus moves a single copy through circulation.
+-- name: SetCopyStatus :exec
+
+UPDATE copy SET status = @status WHERE barcode = @barcode;
@@ -0,0 +1,26 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: set_copy_status.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const setCopyStatus = `-- name: SetCopyStatus :exec
+
+UPDATE copy SET status = $1 WHERE barcode = $2
+`
+
+type SetCopyStatusParams struct {
+	Status  string
+	Barcode string
+}
+
+// SetCopyStatus moves a single copy through circulation.
+func (q *Queries) SetCopyStatus(ctx context.Context, arg SetCopyStatusParams) error {
+	_, err := q.db.Exec(ctx, setCopyStatus, arg.Status, arg.Barcode)
+	return err
+}
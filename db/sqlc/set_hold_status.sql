@@ -0,0 +1,4 @@
+-- SetHoldStatus closes a single hold.
+-- name: SetHoldStatus :exec
+
+UPDATE hold SET status = @status WHERE id = @id;
@@ -0,0 +1,26 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: set_hold_status.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const setHoldStatus = `-- name: SetHoldStatus :exec
+
+UPDATE hold SET status = $1 WHERE id = $2
+`
+
+type SetHoldStatusParams struct {
+	Status string
+	ID     int64
+}
+
+// SetHoldStatus closes a single hold.
+func (q *Queries) SetHoldStatus(ctx context.Context, arg SetHoldStatusParams) error {
+	_, err := q.db.Exec(ctx, setHoldStatus, arg.Status, arg.ID)
+	return err
+}
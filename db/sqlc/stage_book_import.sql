@@ -0,0 +1,4 @@
+-- StageBookImport copies a batch of import rows into the staging table.
+-- name: StageBookImport :copyfrom
+
+INSERT INTO book_import (row_number, isbn, title) VALUES (@row_number, @isbn, @title);
@@ -0,0 +1,10 @@
+-- TrashBook moves a single book to the trash and moves it to its next
+-- version. Books with copies or active holds can't be trashed since they
+-- could never be purged.
+-- name: TrashBook :execrows
+
+UPDATE book SET deleted_at = now(), updated_at = now(), version = version + 1
+WHERE book.isbn = @isbn
+AND deleted_at IS NULL
+AND NOT EXISTS (SELECT 1 FROM copy WHERE copy.isbn = book.isbn)
+AND NOT EXISTS (SELECT 1 FROM hold WHERE hold.isbn = book.isbn AND hold.status IN ('waiting', 'ready'));
@@ -0,0 +1,4 @@
+-- UpdateAuthor updates a single author.
+-- name: UpdateAuthor :execrows
+
+UPDATE author SET name = @name WHERE id = @id;
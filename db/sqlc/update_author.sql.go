@@ -0,0 +1,29 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: update_author.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const updateAuthor = `-- name: UpdateAuthor :execrows
+
+UPDATE author SET name = $1 WHERE id = $2
+`
+
+type UpdateAuthorParams struct {
+	Name string
+	ID   int64
+}
+
+// UpdateAuthor updates a single author.
+func (q *Queries) UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateAuthor, arg.Name, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -1,4 +1,10 @@
--- UpdateBook updates a single book.
--- name: UpdateBook :exec
+-- UpdateBook updates a single book and moves it to its next version. If a
+-- version is given, the book is only updated if it is still at that version.
+-- Trashed books can't be updated.
+-- name: UpdateBook :one
 
-UPDATE book SET title = @title WHERE isbn = @isbn;
+UPDATE book SET title = @title, updated_at = now(), version = version + 1
+WHERE isbn = @isbn
+AND deleted_at IS NULL
+AND (sqlc.narg('version')::bigint IS NULL OR version = sqlc.narg('version'))
+RETURNING isbn, title, created_at, updated_at, version, deleted_at;
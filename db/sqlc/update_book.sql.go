@@ -7,20 +7,37 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
 )
 
-const updateBook = `-- name: UpdateBook :exec
+const updateBook = `-- name: UpdateBook :one
 
-UPDATE book SET title = $1 WHERE isbn = $2
+UPDATE book SET title = $1, updated_at = now(), version = version + 1
+WHERE isbn = $2
+AND deleted_at IS NULL
+AND ($3::bigint IS NULL OR version = $3)
+RETURNING isbn, title, created_at, updated_at, version, deleted_at
 `
 
 type UpdateBookParams struct {
-	Title string
-	Isbn  int64
+	Title   string
+	Isbn    int64
+	Version sql.NullInt64
 }
 
-// UpdateBook updates a single book.
-func (q *Queries) UpdateBook(ctx context.Context, arg UpdateBookParams) error {
-	_, err := q.db.Exec(ctx, updateBook, arg.Title, arg.Isbn)
-	return err
+// UpdateBook updates a single book and moves it to its next version. If a
+// version is given, the book is only updated if it is still at that version.
+// Trashed books can't be updated.
+func (q *Queries) UpdateBook(ctx context.Context, arg UpdateBookParams) (Book, error) {
+	row := q.db.QueryRow(ctx, updateBook, arg.Title, arg.Isbn, arg.Version)
+	var i Book
+	err := row.Scan(
+		&i.Isbn,
+		&i.Title,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
 }
@@ -0,0 +1,4 @@
+-- UpdateCopy updates a single copy.
+-- name: UpdateCopy :execrows
+
+UPDATE copy SET location = @location, status = @status WHERE barcode = @barcode;
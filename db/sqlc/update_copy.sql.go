@@ -0,0 +1,30 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: update_copy.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const updateCopy = `-- name: UpdateCopy :execrows
+
+UPDATE copy SET location = $1, status = $2 WHERE barcode = $3
+`
+
+type UpdateCopyParams struct {
+	Location string
+	Status   string
+	Barcode  string
+}
+
+// UpdateCopy updates a single copy.
+func (q *Queries) UpdateCopy(ctx context.Context, arg UpdateCopyParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateCopy, arg.Location, arg.Status, arg.Barcode)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,4 @@
+-- UpdatePatron updates a single patron.
+-- name: UpdatePatron :execrows
+
+UPDATE patron SET name = @name, borrowing_limit = @borrowing_limit, class = @class WHERE id = @id;
@@ -0,0 +1,36 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: update_patron.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const updatePatron = `-- name: UpdatePatron :execrows
+
+UPDATE patron SET name = $1, borrowing_limit = $2, class = $3 WHERE id = $4
+`
+
+type UpdatePatronParams struct {
+	Name           string
+	BorrowingLimit int32
+	Class          string
+	ID             int64
+}
+
+// UpdatePatron updates a single patron.
+func (q *Queries) UpdatePatron(ctx context.Context, arg UpdatePatronParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updatePatron,
+		arg.Name,
+		arg.BorrowingLimit,
+		arg.Class,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
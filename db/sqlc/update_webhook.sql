@@ -0,0 +1,11 @@
+-- UpdateWebhook updates a single webhook. An empty secret keeps the current
+-- one.
+-- name: UpdateWebhook :one
+
+UPDATE webhook
+SET url = @url,
+  secret = CASE WHEN @secret::text = '' THEN secret ELSE @secret::text END,
+  events = @events,
+  active = @active
+WHERE id = @id
+RETURNING id, url, secret, events, active, created_at;
@@ -0,0 +1,51 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: update_webhook.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const updateWebhook = `-- name: UpdateWebhook :one
+
+UPDATE webhook
+SET url = $1,
+  secret = CASE WHEN $2::text = '' THEN secret ELSE $2::text END,
+  events = $3,
+  active = $4
+WHERE id = $5
+RETURNING id, url, secret, events, active, created_at
+`
+
+type UpdateWebhookParams struct {
+	Url    string
+	Secret string
+	Events []string
+	Active bool
+	ID     int64
+}
+
+// UpdateWebhook updates a single webhook. An empty secret keeps the current
+// one.
+func (q *Queries) UpdateWebhook(ctx context.Context, arg UpdateWebhookParams) (Webhook, error) {
+	row := q.db.QueryRow(ctx, updateWebhook,
+		arg.Url,
+		arg.Secret,
+		arg.Events,
+		arg.Active,
+		arg.ID,
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Secret,
+		&i.Events,
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,30 @@
+-- The catalog tables of the sqlite backend. They mirror the Postgres tables of
+-- the same names with times stored as microseconds since the Unix epoch.
+
+CREATE TABLE IF NOT EXISTS author (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS author_name_id_idx ON author (name, id);
+
+CREATE TABLE IF NOT EXISTS book (
+    isbn INTEGER PRIMARY KEY,
+    title TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX IF NOT EXISTS book_title_isbn_idx ON book (title, isbn);
+CREATE INDEX IF NOT EXISTS book_created_at_isbn_idx ON book (created_at, isbn);
+CREATE INDEX IF NOT EXISTS book_updated_at_idx ON book (updated_at);
+
+CREATE TABLE IF NOT EXISTS book_author (
+    isbn INTEGER NOT NULL REFERENCES book (isbn) ON DELETE CASCADE,
+    author_id INTEGER NOT NULL REFERENCES author (id) ON DELETE RESTRICT,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (isbn, author_id)
+);
+
+CREATE INDEX IF NOT EXISTS book_author_author_id_idx ON book_author (author_id);
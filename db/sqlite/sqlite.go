@@ -0,0 +1,698 @@
+// Package sqlite keeps the catalog of books and authors in a SQLite database
+// with a pure Go driver, so the API can run without a database server.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/db/cursor"
+)
+
+//go:embed schema.sql
+var schema string
+
+// A Store runs queries against a SQLite database.
+type Store struct {
+	DB *sql.DB
+}
+
+// MustOpen opens the database file at path, or an in-memory database if path
+// is ":memory:", and creates the tables that are missing. SQLite allows one
+// writer at a time, so the store uses a single connection.
+func MustOpen(path string) *Store {
+	db, err := sql.Open("sqlite", path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		panic(err)
+	}
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec(schema)
+	if err != nil {
+		panic(err)
+	}
+	return &Store{DB: db}
+}
+
+// queryError wraps an unexpected error from the database.
+func queryError(err error, desc string) error {
+	var libErr *library.Error
+	if errors.As(err, &libErr) {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &library.Error{
+			Type:   library.Timeout,
+			Actual: err,
+			Desc:   desc,
+		}
+	}
+	return &library.Error{
+		Type:   library.DatabaseError,
+		Actual: err,
+		Desc:   desc,
+	}
+}
+
+// constraint returns the extended result code of a constraint violation, or
+// 0 if err isn't one.
+func constraint(err error) int {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code()&0xff == sqlite3.SQLITE_CONSTRAINT {
+		return sqliteErr.Code()
+	}
+	return 0
+}
+
+// inTx calls f in a transaction. The transaction is committed if f returns
+// nil and rolled back otherwise.
+func (s *Store) inTx(ctx context.Context, f func(*sql.Tx) error) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return queryError(err, "while starting a transaction")
+	}
+	//nolint:errcheck // rolling back a committed transaction is a no-op.
+	defer tx.Rollback()
+
+	err = f(tx)
+	if err != nil {
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return queryError(err, "while committing a transaction")
+	}
+	return nil
+}
+
+// A querier is a *sql.DB or a *sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// now is in microseconds like the times in the database.
+func now() int64 {
+	return time.Now().UnixMicro()
+}
+
+const bookColumns = "isbn, title, created_at, updated_at, version"
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanBook(row scanner) (library.Book, error) {
+	var (
+		b                    library.Book
+		isbn                 int64
+		createdAt, updatedAt int64
+	)
+	err := row.Scan(&isbn, &b.Title, &createdAt, &updatedAt, &b.Version)
+	b.ISBN = library.ISBN(isbn)
+	b.CreatedAt = time.UnixMicro(createdAt).UTC()
+	b.UpdatedAt = time.UnixMicro(updatedAt).UTC()
+	return b, err
+}
+
+func queryBooks(ctx context.Context, q querier, query string, args ...any) ([]library.Book, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var books []library.Book
+	for rows.Next() {
+		b, err := scanBook(rows)
+		if err != nil {
+			return nil, err
+		}
+		books = append(books, b)
+	}
+	return books, rows.Err()
+}
+
+func badPageToken(err error) error {
+	return &library.Error{
+		Type:   library.BadInput,
+		Actual: err,
+		Desc:   "while decoding page token",
+	}
+}
+
+// bookOrders are the columns books are sorted by for each order. The isbn
+// breaks ties.
+var bookOrders = map[library.BookOrder]string{
+	library.ByTitle:     "title",
+	library.ByISBN:      "isbn",
+	library.ByCreatedAt: "created_at",
+}
+
+// afterKey is the sort key of a page token as a query argument.
+func afterKey(order library.BookOrder, after cursor.Cursor) (any, error) {
+	switch {
+	case order == library.ByISBN:
+		return after.ID, nil
+	case order == library.ByCreatedAt && after != cursor.FirstPage:
+		parsed, err := time.Parse(time.RFC3339Nano, after.Key)
+		if err != nil {
+			return nil, badPageToken(err)
+		}
+		return parsed.UnixMicro(), nil
+	}
+	return after.Key, nil
+}
+
+// nextPage sets a next-page token on a full page; a short page is the last
+// one. The token holds the sort key of order, which is a field optionally
+// prefixed with "-" for descending.
+func nextPage(books []library.Book, order string, totalSize int32) (library.BookList, error) {
+	result := library.BookList{Books: books}
+	if len(books) > 0 && len(books) == int(totalSize) {
+		last := books[len(books)-1]
+		next := cursor.Cursor{
+			Order: order,
+			ID:    int64(last.ISBN),
+		}
+		switch library.BookOrder(strings.TrimPrefix(order, "-")) {
+		case library.ByTitle, authorBooksOrder:
+			next.Key = last.Title
+		case library.ByCreatedAt:
+			next.Key = last.CreatedAt.Format(time.RFC3339Nano)
+		}
+		token, err := cursor.Encode(next)
+		if err != nil {
+			return library.BookList{}, err
+		}
+		result.NextPageToken = token
+	}
+	return result, nil
+}
+
+func nullMicros(t time.Time) sql.NullInt64 {
+	return sql.NullInt64{Int64: t.UnixMicro(), Valid: !t.IsZero()}
+}
+
+func nullISBN(isbn library.ISBN) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(isbn), Valid: isbn != 0}
+}
+
+// ListBooks returns a filtered list of books in the order the filter asks
+// for. The page token is opaque and only valid if it came from a previous call
+// with the same order.
+func (s *Store) ListBooks(ctx context.Context, filter library.BookFilter, PageToken string, TotalSize int32) (library.BookList, error) {
+	if filter.OrderBy == "" {
+		filter.OrderBy = library.ByTitle
+	}
+	column, ok := bookOrders[filter.OrderBy]
+	if !ok {
+		return library.BookList{}, &library.Error{
+			Type:   library.BadInput,
+			Actual: fmt.Errorf("unknown order %q", filter.OrderBy),
+			Desc:   "while listing books",
+		}
+	}
+	order := string(filter.OrderBy)
+	if filter.Descending {
+		order = "-" + order
+	}
+	after, err := cursor.Decode(PageToken)
+	if err == nil && after != cursor.FirstPage && after.Order != order {
+		err = fmt.Errorf("page token is for order %q but the list is in order %q", after.Order, order)
+	}
+	if err != nil {
+		return library.BookList{}, badPageToken(err)
+	}
+	key, err := afterKey(filter.OrderBy, after)
+	if err != nil {
+		return library.BookList{}, err
+	}
+
+	compare, direction := ">", "ASC"
+	if filter.Descending {
+		compare, direction = "<", "DESC"
+	}
+	query := `SELECT ` + bookColumns + `
+FROM book
+WHERE (@title_prefix = '' OR substr(title, 1, length(@title_prefix)) = @title_prefix)
+AND (@min_isbn IS NULL OR isbn >= @min_isbn)
+AND (@max_isbn IS NULL OR isbn <= @max_isbn)
+AND (@created_after IS NULL OR created_at >= @created_after)
+AND (@created_before IS NULL OR created_at < @created_before)
+AND (@updated_after IS NULL OR updated_at >= @updated_after)
+AND (@updated_before IS NULL OR updated_at < @updated_before)
+AND (@first OR (` + column + `, isbn) ` + compare + ` (@after_key, @after_isbn))
+ORDER BY ` + column + ` ` + direction + `, isbn ` + direction + `
+LIMIT @total_size`
+	books, err := queryBooks(ctx, s.DB, query,
+		sql.Named("title_prefix", filter.TitlePrefix),
+		sql.Named("min_isbn", nullISBN(filter.MinISBN)),
+		sql.Named("max_isbn", nullISBN(filter.MaxISBN)),
+		sql.Named("created_after", nullMicros(filter.CreatedAfter)),
+		sql.Named("created_before", nullMicros(filter.CreatedBefore)),
+		sql.Named("updated_after", nullMicros(filter.UpdatedAfter)),
+		sql.Named("updated_before", nullMicros(filter.UpdatedBefore)),
+		sql.Named("first", after == cursor.FirstPage),
+		sql.Named("after_key", key),
+		sql.Named("after_isbn", after.ID),
+		sql.Named("total_size", TotalSize),
+	)
+	if err != nil {
+		return library.BookList{}, queryError(err, "while retrieving a list of books")
+	}
+	return nextPage(books, order, TotalSize)
+}
+
+// addAuthors links the authors of a book in the order they are credited.
+func addAuthors(ctx context.Context, tx *sql.Tx, isbn library.ISBN, authors []library.Author) (string, error) {
+	for i, a := range authors {
+		_, err := tx.ExecContext(ctx, `INSERT INTO book_author (isbn, author_id, position) VALUES (?, ?, ?)`, int64(isbn), a.ID, i+1)
+		switch constraint(err) {
+		case 0:
+		case sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return "an author is listed more than once", err
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+			return "an author does not exist", err
+		}
+		if err != nil {
+			return "", queryError(err, "while linking the authors of a book")
+		}
+	}
+	return "", nil
+}
+
+// CreateBook creates a single book. Its authors must already exist and are
+// linked in the same transaction.
+func (s *Store) CreateBook(ctx context.Context, book library.Book) error {
+	_, err := library.NewISBN(int64(book.ISBN))
+	if err != nil {
+		return err
+	}
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		created := now()
+		_, err := tx.ExecContext(ctx, `INSERT INTO book (isbn, title, created_at, updated_at) VALUES (?, ?, ?, ?)`, int64(book.ISBN), book.Title, created, created)
+		if constraint(err) == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY {
+			return &library.Error{
+				Type:   library.Conflict,
+				Actual: err,
+				Desc:   "a book with that isbn already exists",
+			}
+		}
+		if err != nil {
+			return queryError(err, "while creating a book")
+		}
+		desc, err := addAuthors(ctx, tx, book.ISBN, book.Authors)
+		if desc != "" {
+			return &library.Error{
+				Type:   library.BadInput,
+				Actual: err,
+				Desc:   desc,
+			}
+		}
+		return err
+	})
+}
+
+// DeleteBook deletes a single book.
+func (s *Store) DeleteBook(ctx context.Context, isbn library.ISBN) error {
+	result, err := s.DB.ExecContext(ctx, `DELETE FROM book WHERE isbn = ?`, int64(isbn))
+	if err != nil {
+		return queryError(err, "while deleting a book")
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return queryError(err, "while deleting a book")
+	}
+	if count == 0 {
+		return &library.Error{
+			Type:   library.NotFound,
+			Actual: fmt.Errorf("no book with isbn %s", isbn),
+			Desc:   "while deleting a book",
+		}
+	}
+	return nil
+}
+
+func getBook(ctx context.Context, q querier, isbn library.ISBN, desc string) (library.Book, error) {
+	book, err := scanBook(q.QueryRowContext(ctx, `SELECT `+bookColumns+` FROM book WHERE isbn = ?`, int64(isbn)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return library.Book{}, &library.Error{
+			Type:   library.NotFound,
+			Actual: fmt.Errorf("no book with isbn %s", isbn),
+			Desc:   desc,
+		}
+	}
+	if err != nil {
+		return library.Book{}, queryError(err, desc)
+	}
+	return book, nil
+}
+
+func listBookAuthors(ctx context.Context, q querier, isbn library.ISBN) ([]library.Author, error) {
+	rows, err := q.QueryContext(ctx, `SELECT author.id, author.name
+FROM author
+JOIN book_author ON book_author.author_id = author.id
+WHERE book_author.isbn = ?
+ORDER BY book_author.position`, int64(isbn))
+	if err != nil {
+		return nil, queryError(err, "while fetching the authors of a book")
+	}
+	defer rows.Close()
+	var authors []library.Author
+	for rows.Next() {
+		var a library.Author
+		err = rows.Scan(&a.ID, &a.Name)
+		if err != nil {
+			return nil, queryError(err, "while fetching the authors of a book")
+		}
+		authors = append(authors, a)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, queryError(err, "while fetching the authors of a book")
+	}
+	return authors, nil
+}
+
+// GetBook fetches a single book and its authors.
+func (s *Store) GetBook(ctx context.Context, isbn library.ISBN) (library.Book, error) {
+	var result library.Book
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		book, err := getBook(ctx, tx, isbn, "while fetching a book")
+		if err != nil {
+			return err
+		}
+		book.Authors, err = listBookAuthors(ctx, tx, isbn)
+		result = book
+		return err
+	})
+	return result, err
+}
+
+// updateBook moves a book to its next version if it is still at version, or
+// at any version if version is zero.
+func updateBook(ctx context.Context, q querier, isbn library.ISBN, title string, version int64, desc string) (library.Book, error) {
+	book, err := scanBook(q.QueryRowContext(ctx, `UPDATE book SET title = @title, updated_at = @now, version = version + 1
+WHERE isbn = @isbn
+AND (@version = 0 OR version = @version)
+RETURNING `+bookColumns,
+		sql.Named("title", title),
+		sql.Named("now", now()),
+		sql.Named("isbn", int64(isbn)),
+		sql.Named("version", version),
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		current, err := getBook(ctx, q, isbn, desc)
+		if err != nil {
+			return library.Book{}, err
+		}
+		return library.Book{}, &library.Error{
+			Type:   library.PreconditionFailed,
+			Actual: fmt.Errorf("book %s is at version %d, not %d", isbn, current.Version, version),
+			Desc:   "the book changed since it was read",
+		}
+	}
+	if err != nil {
+		return library.Book{}, queryError(err, desc)
+	}
+	return book, nil
+}
+
+// UpdateBook updates a single book and returns it at its new version. If
+// book.Version isn't zero, the update fails unless the book is still at that
+// version.
+func (s *Store) UpdateBook(ctx context.Context, book library.Book) (library.Book, error) {
+	var result library.Book
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		result, err = updateBook(ctx, tx, book.ISBN, book.Title, book.Version, "while updating a book")
+		return err
+	})
+	return result, err
+}
+
+func sameAuthors(a []library.Author, b []library.Author) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ID != b[i].ID {
+			return false
+		}
+	}
+	return true
+}
+
+// PatchBook changes a book with patch in a transaction, so the patch is
+// applied to the latest version of the book and nothing changes if it fails.
+// If version isn't zero, the book must still be at that version. The book is
+// only moved to its next version if the patch changed it.
+func (s *Store) PatchBook(ctx context.Context, isbn library.ISBN, version int64, patch library.BookPatch) (library.Book, error) {
+	var result library.Book
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		current, err := getBook(ctx, tx, isbn, "while patching a book")
+		if err != nil {
+			return err
+		}
+		if version != 0 && current.Version != version {
+			return &library.Error{
+				Type:   library.PreconditionFailed,
+				Actual: fmt.Errorf("book %s is at version %d, not %d", isbn, current.Version, version),
+				Desc:   "the book changed since it was read",
+			}
+		}
+		current.Authors, err = listBookAuthors(ctx, tx, isbn)
+		if err != nil {
+			return err
+		}
+		patched, err := patch.Apply(current)
+		if err != nil {
+			return err
+		}
+		authorsChanged := !sameAuthors(current.Authors, patched.Authors)
+		if patched.Title == current.Title && !authorsChanged {
+			result = current
+			return nil
+		}
+		result, err = updateBook(ctx, tx, isbn, patched.Title, current.Version, "while patching a book")
+		if err != nil {
+			return err
+		}
+		if !authorsChanged {
+			result.Authors = current.Authors
+			return nil
+		}
+		_, err = tx.ExecContext(ctx, `DELETE FROM book_author WHERE isbn = ?`, int64(isbn))
+		if err != nil {
+			return queryError(err, "while patching the authors of a book")
+		}
+		desc, err := addAuthors(ctx, tx, isbn, patched.Authors)
+		if desc != "" {
+			return &library.Error{
+				Type:   library.BadInput,
+				Actual: library.FieldErrors{{Field: "author_ids", Message: desc}},
+				Desc:   "while patching a book",
+			}
+		}
+		if err != nil {
+			return err
+		}
+		result.Authors, err = listBookAuthors(ctx, tx, isbn)
+		return err
+	})
+	return result, err
+}
+
+// exportBatchSize is how many books are read at a time during an export.
+const exportBatchSize = 1000
+
+// ExportBooks writes every book to writer in isbn order. The books are read
+// in batches from a single transaction so the export is consistent without
+// holding the catalog in memory. Errors from writer are returned as they are.
+func (s *Store) ExportBooks(ctx context.Context, writer library.BookWriter) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		var after int64
+		for {
+			books, err := queryBooks(ctx, tx, `SELECT `+bookColumns+` FROM book WHERE isbn > ? ORDER BY isbn LIMIT ?`, after, exportBatchSize)
+			if err != nil {
+				return queryError(err, "while exporting books")
+			}
+			for _, b := range books {
+				err = writer.Write(b)
+				if err != nil {
+					return err
+				}
+			}
+			if len(books) < exportBatchSize {
+				return nil
+			}
+			after = int64(books[len(books)-1].ISBN)
+		}
+	})
+}
+
+// authorOrder is the order of author lists.
+const authorOrder = "name"
+
+// authorBooksOrder is the order of the book lists of an author, which are
+// ordered by title but don't share page tokens with book lists.
+const authorBooksOrder = "author_books"
+
+// ListAuthors returns a list of authors ordered by name.
+func (s *Store) ListAuthors(ctx context.Context, PageToken string, TotalSize int32) (library.AuthorList, error) {
+	after, err := cursor.Decode(PageToken)
+	if err == nil && after != cursor.FirstPage && after.Order != authorOrder {
+		err = fmt.Errorf("page token is for order %q", after.Order)
+	}
+	if err != nil {
+		return library.AuthorList{}, badPageToken(err)
+	}
+	rows, err := s.DB.QueryContext(ctx, `SELECT id, name
+FROM author
+WHERE (name, id) > (?, ?)
+ORDER BY name, id
+LIMIT ?`, after.Key, after.ID, TotalSize)
+	if err != nil {
+		return library.AuthorList{}, queryError(err, "while retrieving a list of authors")
+	}
+	defer rows.Close()
+	var result library.AuthorList
+	for rows.Next() {
+		var a library.Author
+		err = rows.Scan(&a.ID, &a.Name)
+		if err != nil {
+			return library.AuthorList{}, queryError(err, "while retrieving a list of authors")
+		}
+		result.Authors = append(result.Authors, a)
+	}
+	err = rows.Err()
+	if err != nil {
+		return library.AuthorList{}, queryError(err, "while retrieving a list of authors")
+	}
+	if len(result.Authors) > 0 && len(result.Authors) == int(TotalSize) {
+		last := result.Authors[len(result.Authors)-1]
+		result.NextPageToken, err = cursor.Encode(cursor.Cursor{
+			Order: authorOrder,
+			Key:   last.Name,
+			ID:    last.ID,
+		})
+		if err != nil {
+			return library.AuthorList{}, err
+		}
+	}
+	return result, nil
+}
+
+// ListAuthorBooks returns a list of books by a single author ordered by title.
+func (s *Store) ListAuthorBooks(ctx context.Context, authorID int64, PageToken string, TotalSize int32) (library.BookList, error) {
+	after, err := cursor.Decode(PageToken)
+	if err == nil && after != cursor.FirstPage && after.Order != authorBooksOrder {
+		err = fmt.Errorf("page token is for order %q", after.Order)
+	}
+	if err != nil {
+		return library.BookList{}, badPageToken(err)
+	}
+
+	// an author without books and a missing author look the same to the
+	// list query.
+	_, err = s.GetAuthor(ctx, authorID)
+	if err != nil {
+		return library.BookList{}, err
+	}
+
+	books, err := queryBooks(ctx, s.DB, `SELECT book.isbn, book.title, book.created_at, book.updated_at, book.version
+FROM book
+JOIN book_author ON book_author.isbn = book.isbn
+WHERE book_author.author_id = ?
+AND (book.title, book.isbn) > (?, ?)
+ORDER BY book.title, book.isbn
+LIMIT ?`, authorID, after.Key, after.ID, TotalSize)
+	if err != nil {
+		return library.BookList{}, queryError(err, "while retrieving a list of books by an author")
+	}
+	return nextPage(books, authorBooksOrder, TotalSize)
+}
+
+// CreateAuthor creates a single author and returns it with its new id.
+func (s *Store) CreateAuthor(ctx context.Context, author library.Author) (library.Author, error) {
+	err := s.DB.QueryRowContext(ctx, `INSERT INTO author (name) VALUES (?) RETURNING id`, author.Name).Scan(&author.ID)
+	if err != nil {
+		return library.Author{}, queryError(err, "while creating an author")
+	}
+	return author, nil
+}
+
+// DeleteAuthor deletes a single author. Authors of existing books can't be
+// deleted.
+func (s *Store) DeleteAuthor(ctx context.Context, id int64) error {
+	result, err := s.DB.ExecContext(ctx, `DELETE FROM author WHERE id = ?`, id)
+	// ON DELETE RESTRICT is enforced as a trigger.
+	if code := constraint(err); code == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY || code == sqlite3.SQLITE_CONSTRAINT_TRIGGER {
+		return &library.Error{
+			Type:   library.Conflict,
+			Actual: err,
+			Desc:   "the author still has books",
+		}
+	}
+	if err != nil {
+		return queryError(err, "while deleting an author")
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return queryError(err, "while deleting an author")
+	}
+	if count == 0 {
+		return &library.Error{
+			Type:   library.NotFound,
+			Actual: fmt.Errorf("no author with id %d", id),
+			Desc:   "while deleting an author",
+		}
+	}
+	return nil
+}
+
+// GetAuthor fetches a single author.
+func (s *Store) GetAuthor(ctx context.Context, id int64) (library.Author, error) {
+	author := library.Author{ID: id}
+	err := s.DB.QueryRowContext(ctx, `SELECT name FROM author WHERE id = ?`, id).Scan(&author.Name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return library.Author{}, &library.Error{
+			Type:   library.NotFound,
+			Actual: fmt.Errorf("no author with id %d", id),
+			Desc:   "while fetching an author",
+		}
+	}
+	if err != nil {
+		return library.Author{}, queryError(err, "while fetching an author")
+	}
+	return author, nil
+}
+
+// UpdateAuthor updates a single author.
+func (s *Store) UpdateAuthor(ctx context.Context, author library.Author) error {
+	result, err := s.DB.ExecContext(ctx, `UPDATE author SET name = ? WHERE id = ?`, author.Name, author.ID)
+	if err != nil {
+		return queryError(err, "while updating an author")
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return queryError(err, "while updating an author")
+	}
+	if count == 0 {
+		return &library.Error{
+			Type:   library.NotFound,
+			Actual: fmt.Errorf("no author with id %d", author.ID),
+			Desc:   "while updating an author",
+		}
+	}
+	return nil
+}
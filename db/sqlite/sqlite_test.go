@@ -0,0 +1,18 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/slcjordan/library/test/conformance"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, MustOpen(":memory:"))
+}
+
+func TestConformanceFile(t *testing.T) {
+	store := MustOpen(filepath.Join(t.TempDir(), "library.db"))
+	defer store.DB.Close()
+	conformance.Run(t, store)
+}
@@ -0,0 +1,209 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4"
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/db/sqlc"
+)
+
+// queryError wraps an unexpected error from the database.
+func queryError(err error, desc string) error {
+	var libErr *library.Error
+	if errors.As(err, &libErr) {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &library.Error{
+			Type:   library.Timeout,
+			Actual: err,
+			Desc:   desc,
+		}
+	}
+	return &library.Error{
+		Type:   library.DatabaseError,
+		Actual: err,
+		Desc:   desc,
+	}
+}
+
+// defaultMaxAttempts is how many times WithTx runs a transaction that keeps
+// failing with serialization failures or deadlocks, unless told otherwise.
+const defaultMaxAttempts = 5
+
+// The backoff between attempts starts at minRetryDelay and doubles up to
+// maxRetryDelay. Each delay is picked at random up to that limit so that the
+// transactions that collided don't collide again.
+const (
+	minRetryDelay = 10 * time.Millisecond
+	maxRetryDelay = time.Second
+)
+
+// TxOptions are the characteristics of a transaction. The zero value is a
+// read-write transaction at the database's default isolation level, which is
+// retried up to defaultMaxAttempts times.
+type TxOptions struct {
+	IsoLevel   pgx.TxIsoLevel
+	AccessMode pgx.TxAccessMode
+	// MaxAttempts limits how many times the transaction runs. Set it to 1
+	// for work that can't be repeated, such as reading a stream.
+	MaxAttempts int
+}
+
+// statement is the SET TRANSACTION statement for the options, or "" if the
+// defaults are fine.
+func (o TxOptions) statement() string {
+	var modes []string
+	if o.IsoLevel != "" {
+		modes = append(modes, "ISOLATION LEVEL "+strings.ToUpper(string(o.IsoLevel)))
+	}
+	if o.AccessMode != "" {
+		modes = append(modes, strings.ToUpper(string(o.AccessMode)))
+	}
+	if len(modes) == 0 {
+		return ""
+	}
+	return "SET TRANSACTION " + strings.Join(modes, ", ")
+}
+
+// snapshotTx makes a transaction read-only and read from a single snapshot.
+// Work in a snapshot usually streams its results, so it isn't retried.
+var snapshotTx = TxOptions{
+	IsoLevel:    pgx.RepeatableRead,
+	AccessMode:  pgx.ReadOnly,
+	MaxAttempts: 1,
+}
+
+// retryable reports whether a transaction that failed with err may succeed if
+// it is run again.
+func retryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == pgerrcode.SerializationFailure || pgErr.Code == pgerrcode.DeadlockDetected
+}
+
+// WithTx calls f with a Queryer whose queries all run in one transaction, so
+// several operations can be grouped into a unit of work. The transaction is
+// committed if f returns nil and rolled back otherwise. When the transaction
+// fails with a serialization failure or a deadlock, it is rolled back and f is
+// called again after a backoff, so f must not have effects outside the
+// transaction. Calling WithTx on a Queryer that is already in a transaction
+// starts a savepoint, which is never retried because the failure aborts the
+// enclosing transaction too. A savepoint has the isolation level and access
+// mode of the enclosing transaction, so asking for others fails.
+func (q *Queryer) WithTx(ctx context.Context, opts TxOptions, f func(tx *Queryer) error) error {
+	attempts := opts.MaxAttempts
+	if attempts <= 0 {
+		attempts = defaultMaxAttempts
+	}
+	if tx, nested := q.DBTX.(pgx.Tx); nested {
+		attempts = 1
+		err := checkSavepoint(ctx, tx, opts)
+		if err != nil {
+			return err
+		}
+		opts.IsoLevel, opts.AccessMode = "", ""
+	}
+	delay := minRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := q.attemptTx(ctx, opts, f)
+		if err == nil || attempt >= attempts || !retryable(err) {
+			return err
+		}
+		timer := time.NewTimer(time.Duration(rand.Int63n(int64(delay))) + 1)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay *= 2
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
+
+// checkSavepoint returns an error if opts ask for an isolation level or access
+// mode other than that of tx, which a savepoint in tx can't change.
+func checkSavepoint(ctx context.Context, tx pgx.Tx, opts TxOptions) error {
+	if opts.statement() == "" {
+		return nil
+	}
+	var isoLevel, readOnly string
+	err := tx.QueryRow(ctx, "SELECT current_setting('transaction_isolation'), current_setting('transaction_read_only')").Scan(&isoLevel, &readOnly)
+	if err != nil {
+		return queryError(err, "while starting a savepoint")
+	}
+	accessMode := pgx.ReadWrite
+	if readOnly == "on" {
+		accessMode = pgx.ReadOnly
+	}
+	if (opts.IsoLevel != "" && opts.IsoLevel != pgx.TxIsoLevel(isoLevel)) || (opts.AccessMode != "" && opts.AccessMode != accessMode) {
+		return &library.Error{
+			Type:   library.Unknown,
+			Actual: fmt.Errorf("%s is asked for in a %s, %s transaction", strings.TrimPrefix(opts.statement(), "SET TRANSACTION "), isoLevel, accessMode),
+			Desc:   "while starting a savepoint",
+		}
+	}
+	return nil
+}
+
+// attemptTx runs f once in a transaction. The actor and request id of ctx are
+// set for the transaction so that the audit records of its changes name them.
+func (q *Queryer) attemptTx(ctx context.Context, opts TxOptions, f func(tx *Queryer) error) error {
+	tx, err := q.DBTX.Begin(ctx)
+	if err != nil {
+		return queryError(err, "while starting a transaction")
+	}
+	//nolint:errcheck // rolling back a committed transaction is a no-op.
+	defer tx.Rollback(ctx)
+
+	if mode := opts.statement(); mode != "" {
+		_, err = tx.Exec(ctx, mode)
+		if err != nil {
+			return queryError(err, "while starting a transaction")
+		}
+	}
+	actor, requestID := library.Actor(ctx), library.RequestID(ctx)
+	if actor != "" || requestID != "" {
+		err = sqlc.New(tx).SetAuditContext(ctx, sqlc.SetAuditContextParams{
+			Actor:     actor,
+			RequestID: requestID,
+		})
+		if err != nil {
+			return queryError(err, "while starting a transaction")
+		}
+	}
+	err = f(&Queryer{DBTX: tx})
+	if err != nil {
+		return err
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return queryError(err, "while committing a transaction")
+	}
+	return nil
+}
+
+// inTx calls f in a transaction with the default options.
+func (q *Queryer) inTx(ctx context.Context, f func(*sqlc.Queries) error) error {
+	return q.inTxAs(ctx, TxOptions{}, f)
+}
+
+// inTxAs calls f in a transaction with opts.
+func (q *Queryer) inTxAs(ctx context.Context, opts TxOptions, f func(*sqlc.Queries) error) error {
+	return q.WithTx(ctx, opts, func(tx *Queryer) error {
+		return f(sqlc.New(tx.DBTX))
+	})
+}
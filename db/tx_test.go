@@ -0,0 +1,192 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4"
+	"github.com/slcjordan/library"
+	mockdb "github.com/slcjordan/library/test/mocks/db"
+)
+
+// fakeTx records what is done with a transaction. Methods it doesn't override
+// panic through the nil pgx.Tx.
+type fakeTx struct {
+	pgx.Tx
+	statements []string
+	committed  bool
+	commitErr  error
+}
+
+func (f *fakeTx) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	f.statements = append(f.statements, sql)
+	return nil, nil
+}
+
+func (f *fakeTx) Commit(ctx context.Context) error {
+	if f.commitErr != nil {
+		return f.commitErr
+	}
+	f.committed = true
+	return nil
+}
+
+func (f *fakeTx) Rollback(ctx context.Context) error {
+	return nil
+}
+
+func TestWithTx(t *testing.T) {
+	deadlock := &pgconn.PgError{Code: pgerrcode.DeadlockDetected}
+	serialization := &pgconn.PgError{Code: pgerrcode.SerializationFailure}
+	for desc, c := range map[string]struct {
+		opts       TxOptions
+		errs       []error // returned by f on each attempt
+		commitErr  error   // returned by the first commit
+		calls      int
+		committed  bool
+		statements []string
+	}{
+		"commits":                    {TxOptions{}, []error{nil}, nil, 1, true, nil},
+		"retries deadlock":           {TxOptions{}, []error{queryError(deadlock, "while testing"), nil}, nil, 2, true, nil},
+		"retries failed commit":      {TxOptions{}, []error{nil, nil}, serialization, 2, true, nil},
+		"gives up":                   {TxOptions{MaxAttempts: 2}, []error{serialization, serialization}, nil, 2, false, nil},
+		"doesn't retry other errors": {TxOptions{}, []error{errors.New("boom")}, nil, 1, false, nil},
+		"no retries":                 {TxOptions{MaxAttempts: 1}, []error{deadlock}, nil, 1, false, nil},
+		"sets isolation": {
+			TxOptions{IsoLevel: pgx.Serializable, AccessMode: pgx.ReadOnly},
+			[]error{nil}, nil, 1, true,
+			[]string{"SET TRANSACTION ISOLATION LEVEL SERIALIZABLE, READ ONLY"},
+		},
+	} {
+		ctrl := gomock.NewController(t)
+		dbtx := mockdb.NewMockDBTX(ctrl)
+		var txs []*fakeTx
+		dbtx.EXPECT().Begin(gomock.Any()).DoAndReturn(func(context.Context) (pgx.Tx, error) {
+			tx := &fakeTx{}
+			if len(txs) == 0 {
+				tx.commitErr = c.commitErr
+			}
+			txs = append(txs, tx)
+			return tx, nil
+		}).Times(c.calls)
+
+		q := &Queryer{DBTX: dbtx}
+		calls := 0
+		err := q.WithTx(context.Background(), c.opts, func(tx *Queryer) error {
+			calls++
+			if tx.DBTX != txs[len(txs)-1] {
+				t.Fatalf("%s: expected f to be called with the transaction", desc)
+			}
+			return c.errs[calls-1]
+		})
+		if calls != c.calls {
+			t.Fatalf("%s: expected %d calls but got %d", desc, c.calls, calls)
+		}
+		last := txs[len(txs)-1]
+		if (err == nil) != c.committed || last.committed != c.committed {
+			t.Fatalf("%s: expected committed to be %v but got %v with error %v", desc, c.committed, last.committed, err)
+		}
+		if len(last.statements) != len(c.statements) || (len(c.statements) > 0 && last.statements[0] != c.statements[0]) {
+			t.Fatalf("%s: expected statements %q but got %q", desc, c.statements, last.statements)
+		}
+		ctrl.Finish()
+	}
+}
+
+func TestWithTxNested(t *testing.T) {
+	q := &Queryer{DBTX: &nestedTx{fakeTx: &fakeTx{}, savepoint: &fakeTx{}}}
+	calls := 0
+	err := q.WithTx(context.Background(), TxOptions{}, func(tx *Queryer) error {
+		calls++
+		return &pgconn.PgError{Code: pgerrcode.SerializationFailure}
+	})
+	if err == nil || calls != 1 {
+		t.Fatalf("expected a savepoint to fail without retries but got %d calls and %v", calls, err)
+	}
+}
+
+func TestWithTxNestedOptions(t *testing.T) {
+	for desc, c := range map[string]struct {
+		opts TxOptions
+		ok   bool
+	}{
+		"same isolation":      {TxOptions{IsoLevel: pgx.RepeatableRead}, true},
+		"same access mode":    {TxOptions{AccessMode: pgx.ReadOnly}, true},
+		"snapshot":            {snapshotTx, true},
+		"other isolation":     {TxOptions{IsoLevel: pgx.Serializable}, false},
+		"other access mode":   {TxOptions{AccessMode: pgx.ReadWrite}, false},
+		"other of either one": {TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadWrite}, false},
+	} {
+		savepoint := &fakeTx{}
+		// the enclosing transaction is a snapshot.
+		q := &Queryer{DBTX: &nestedTx{fakeTx: &fakeTx{}, savepoint: savepoint, settings: []string{"repeatable read", "on"}}}
+		calls := 0
+		err := q.WithTx(context.Background(), c.opts, func(tx *Queryer) error {
+			calls++
+			return nil
+		})
+		if c.ok && (err != nil || calls != 1 || len(savepoint.statements) != 0) {
+			t.Fatalf("%s: expected the savepoint to run without SET TRANSACTION but got %d calls, statements %q and error %v", desc, calls, savepoint.statements, err)
+		}
+		var liberr *library.Error
+		if !c.ok && (calls != 0 || !errors.As(err, &liberr) || !strings.Contains(err.Error(), "savepoint")) {
+			t.Fatalf("%s: expected an error about the savepoint but got %d calls and %v", desc, calls, err)
+		}
+	}
+}
+
+// nestedTx is a transaction whose Begin starts a savepoint. Its settings are
+// its isolation level and whether it is read only, as Postgres reports them.
+type nestedTx struct {
+	*fakeTx
+	savepoint *fakeTx
+	settings  []string
+}
+
+func (n *nestedTx) Begin(ctx context.Context) (pgx.Tx, error) {
+	return n.savepoint, nil
+}
+
+func (n *nestedTx) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return settingsRow(n.settings)
+}
+
+// settingsRow scans strings.
+type settingsRow []string
+
+func (r settingsRow) Scan(dest ...interface{}) error {
+	for i, d := range dest {
+		*d.(*string) = r[i]
+	}
+	return nil
+}
+
+func TestWithTxAuditContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbtx := mockdb.NewMockDBTX(ctrl)
+	tx := &fakeTx{}
+	dbtx.EXPECT().Begin(gomock.Any()).Return(tx, nil).Times(2)
+	q := &Queryer{DBTX: dbtx}
+	noop := func(*Queryer) error { return nil }
+
+	err := q.WithTx(context.Background(), TxOptions{}, noop)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(tx.statements) != 0 {
+		t.Fatalf("expected no audit context without an actor but got %q", tx.statements)
+	}
+	ctx := library.WithRequestID(library.WithActor(context.Background(), "librarian"), "req-1")
+	err = q.WithTx(ctx, TxOptions{}, noop)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(tx.statements) != 1 || !strings.Contains(tx.statements[0], "set_config") {
+		t.Fatalf("expected the audit context to be set but got %q", tx.statements)
+	}
+}
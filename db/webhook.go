@@ -0,0 +1,273 @@
+package db
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/db/cursor"
+	"github.com/slcjordan/library/db/sqlc"
+)
+
+// deliveryOrder is the order of delivery page tokens, newest delivery first.
+const deliveryOrder = "-delivery_id"
+
+// secretSize is how many random bytes a generated webhook secret has.
+const secretSize = 32
+
+func toWebhook(w sqlc.Webhook) library.Webhook {
+	events := make([]library.AuditAction, 0, len(w.Events))
+	for _, e := range w.Events {
+		events = append(events, library.AuditAction(e))
+	}
+	return library.Webhook{
+		ID:        w.ID,
+		URL:       w.Url,
+		Secret:    w.Secret,
+		Events:    events,
+		Active:    w.Active,
+		CreatedAt: w.CreatedAt,
+	}
+}
+
+func fromEvents(events []library.AuditAction) []string {
+	result := make([]string, 0, len(events))
+	for _, e := range events {
+		result = append(result, string(e))
+	}
+	return result
+}
+
+func toWebhookDelivery(d sqlc.ListWebhookDeliveriesRow) library.WebhookDelivery {
+	return library.WebhookDelivery{
+		ID:             d.ID,
+		WebhookID:      d.WebhookID,
+		AuditID:        d.AuditID,
+		Event:          library.AuditAction(d.Action),
+		Status:         library.DeliveryStatus(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastAttemptAt:  fromNullTime(d.LastAttemptAt),
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    fromNullTime(d.DeliveredAt),
+	}
+}
+
+func webhookNotFound(id int64, desc string) error {
+	return &library.Error{
+		Type:   library.NotFound,
+		Actual: fmt.Errorf("no webhook with id %d", id),
+		Desc:   desc,
+	}
+}
+
+// CreateWebhook creates a single webhook and returns it with its new id. A
+// secret is generated if it has none.
+func (q *Queryer) CreateWebhook(ctx context.Context, webhook library.Webhook) (library.Webhook, error) {
+	if webhook.Secret == "" {
+		secret := make([]byte, secretSize)
+		_, err := rand.Read(secret)
+		if err != nil {
+			return library.Webhook{}, &library.Error{
+				Type:   library.Unknown,
+				Actual: err,
+				Desc:   "while generating a webhook secret",
+			}
+		}
+		webhook.Secret = hex.EncodeToString(secret)
+	}
+	created, err := sqlc.New(q.DBTX).CreateWebhook(ctx, sqlc.CreateWebhookParams{
+		Url:    webhook.URL,
+		Secret: webhook.Secret,
+		Events: fromEvents(webhook.Events),
+		Active: webhook.Active,
+	})
+	if err != nil {
+		return library.Webhook{}, queryError(err, "while creating a webhook")
+	}
+	return toWebhook(created), nil
+}
+
+// GetWebhook fetches a single webhook.
+func (q *Queryer) GetWebhook(ctx context.Context, id int64) (library.Webhook, error) {
+	webhook, err := sqlc.New(q.DBTX).GetWebhook(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return library.Webhook{}, webhookNotFound(id, "while fetching a webhook")
+	}
+	if err != nil {
+		return library.Webhook{}, queryError(err, "while fetching a webhook")
+	}
+	return toWebhook(webhook), nil
+}
+
+// ListWebhooks returns every webhook, oldest first.
+func (q *Queryer) ListWebhooks(ctx context.Context) ([]library.Webhook, error) {
+	webhooks, err := sqlc.New(q.DBTX).ListWebhooks(ctx)
+	if err != nil {
+		return nil, queryError(err, "while retrieving the webhooks")
+	}
+	result := make([]library.Webhook, 0, len(webhooks))
+	for _, w := range webhooks {
+		result = append(result, toWebhook(w))
+	}
+	return result, nil
+}
+
+// UpdateWebhook updates a single webhook and returns it. An empty secret
+// keeps the current one. Changing the events doesn't affect deliveries that
+// are already queued.
+func (q *Queryer) UpdateWebhook(ctx context.Context, webhook library.Webhook) (library.Webhook, error) {
+	updated, err := sqlc.New(q.DBTX).UpdateWebhook(ctx, sqlc.UpdateWebhookParams{
+		ID:     webhook.ID,
+		Url:    webhook.URL,
+		Secret: webhook.Secret,
+		Events: fromEvents(webhook.Events),
+		Active: webhook.Active,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return library.Webhook{}, webhookNotFound(webhook.ID, "while updating a webhook")
+	}
+	if err != nil {
+		return library.Webhook{}, queryError(err, "while updating a webhook")
+	}
+	return toWebhook(updated), nil
+}
+
+// DeleteWebhook deletes a single webhook along with its deliveries.
+func (q *Queryer) DeleteWebhook(ctx context.Context, id int64) error {
+	count, err := sqlc.New(q.DBTX).DeleteWebhook(ctx, id)
+	if err != nil {
+		return queryError(err, "while deleting a webhook")
+	}
+	if count == 0 {
+		return webhookNotFound(id, "while deleting a webhook")
+	}
+	return nil
+}
+
+// ListWebhookDeliveries returns the deliveries of a single webhook, newest
+// first. An empty status lists deliveries of every status.
+func (q *Queryer) ListWebhookDeliveries(ctx context.Context, webhookID int64, status library.DeliveryStatus, PageToken string, TotalSize int32) (library.WebhookDeliveryList, error) {
+	beforeID, err := decodeBeforeToken(PageToken, deliveryOrder)
+	if err != nil {
+		return library.WebhookDeliveryList{}, err
+	}
+	// a webhook without deliveries and a missing webhook look the same to
+	// the list query.
+	_, err = q.GetWebhook(ctx, webhookID)
+	if err != nil {
+		return library.WebhookDeliveryList{}, err
+	}
+	deliveries, err := sqlc.New(q.DBTX).ListWebhookDeliveries(ctx, sqlc.ListWebhookDeliveriesParams{
+		WebhookID: webhookID,
+		Status:    string(status),
+		BeforeID:  beforeID,
+		TotalSize: TotalSize,
+	})
+	if err != nil {
+		return library.WebhookDeliveryList{}, queryError(err, "while retrieving the deliveries of a webhook")
+	}
+	var result library.WebhookDeliveryList
+	for _, d := range deliveries {
+		result.Deliveries = append(result.Deliveries, toWebhookDelivery(d))
+	}
+	if len(deliveries) > 0 && len(deliveries) == int(TotalSize) {
+		result.NextPageToken, err = cursor.Encode(cursor.Cursor{
+			Order: deliveryOrder,
+			ID:    deliveries[len(deliveries)-1].ID,
+		})
+		if err != nil {
+			return library.WebhookDeliveryList{}, err
+		}
+	}
+	return result, nil
+}
+
+// RedeliverWebhookDelivery queues a delivery to be sent again right away,
+// with a fresh set of attempts. Dead deliveries are brought back this way
+// once the partner system is fixed.
+func (q *Queryer) RedeliverWebhookDelivery(ctx context.Context, webhookID int64, id int64) (library.WebhookDelivery, error) {
+	d, err := sqlc.New(q.DBTX).RedeliverWebhookDelivery(ctx, sqlc.RedeliverWebhookDeliveryParams{
+		ID:        id,
+		WebhookID: webhookID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return library.WebhookDelivery{}, &library.Error{
+			Type:   library.NotFound,
+			Actual: fmt.Errorf("webhook %d has no delivery with id %d", webhookID, id),
+			Desc:   "while redelivering a webhook delivery",
+		}
+	}
+	if err != nil {
+		return library.WebhookDelivery{}, queryError(err, "while redelivering a webhook delivery")
+	}
+	return toWebhookDelivery(sqlc.ListWebhookDeliveriesRow(d)), nil
+}
+
+// ClaimWebhookDeliveries claims up to TotalSize deliveries that are due. They
+// aren't due again until lease is over, by when they should have been
+// recorded with RecordWebhookAttempt.
+func (q *Queryer) ClaimWebhookDeliveries(ctx context.Context, lease time.Duration, TotalSize int32) ([]library.ClaimedDelivery, error) {
+	claimed, err := sqlc.New(q.DBTX).ClaimWebhookDeliveries(ctx, sqlc.ClaimWebhookDeliveriesParams{
+		LeaseSeconds: lease.Seconds(),
+		TotalSize:    TotalSize,
+	})
+	if err != nil {
+		return nil, queryError(err, "while claiming webhook deliveries")
+	}
+	result := make([]library.ClaimedDelivery, 0, len(claimed))
+	for _, c := range claimed {
+		record, err := toAuditRecord(sqlc.BookAudit{
+			ID:        c.AuditID,
+			Isbn:      c.Isbn,
+			Action:    c.Action,
+			Actor:     c.Actor,
+			RequestID: c.RequestID,
+			OldBook:   c.OldBook,
+			NewBook:   c.NewBook,
+			CreatedAt: c.AuditCreatedAt,
+		})
+		if err != nil {
+			return nil, queryError(err, "while decoding an audit record")
+		}
+		result = append(result, library.ClaimedDelivery{
+			Delivery: library.WebhookDelivery{
+				ID:        c.ID,
+				WebhookID: c.WebhookID,
+				AuditID:   c.AuditID,
+				Event:     library.AuditAction(c.Action),
+				Status:    library.DeliveryPending,
+				Attempts:  c.Attempts,
+				CreatedAt: c.CreatedAt,
+			},
+			URL:    c.Url,
+			Secret: c.Secret,
+			Record: record,
+		})
+	}
+	return result, nil
+}
+
+// RecordWebhookAttempt records an attempt at sending a delivery. A delivery
+// that is still pending is due again after backoff.
+func (q *Queryer) RecordWebhookAttempt(ctx context.Context, attempt library.WebhookDelivery, backoff time.Duration) error {
+	err := sqlc.New(q.DBTX).RecordWebhookAttempt(ctx, sqlc.RecordWebhookAttemptParams{
+		ID:             attempt.ID,
+		Status:         string(attempt.Status),
+		ResponseStatus: attempt.ResponseStatus,
+		LastError:      attempt.LastError,
+		BackoffSeconds: backoff.Seconds(),
+	})
+	if err != nil {
+		return queryError(err, "while recording a webhook attempt")
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package library
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 //go:generate stringer -type=ErrorType
 type ErrorType int
@@ -12,6 +15,10 @@ const (
 	BadInput
 	InvalidSettings
 	Timeout
+	NotFound
+	Conflict
+	PreconditionFailed
+	NotImplemented
 )
 
 type Error struct {
@@ -27,3 +34,21 @@ func (e *Error) Unwrap() error {
 func (e *Error) Error() string {
 	return fmt.Sprintf("(%s) %s: %s", e.Type, e.Desc, e.Actual)
 }
+
+// A FieldError is why the value of a single field is invalid.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// FieldErrors is the Actual error of a BadInput or InvalidSettings error about
+// specific fields.
+type FieldErrors []FieldError
+
+func (f FieldErrors) Error() string {
+	messages := make([]string, 0, len(f))
+	for _, e := range f {
+		messages = append(messages, e.Field+": "+e.Message)
+	}
+	return strings.Join(messages, "; ")
+}
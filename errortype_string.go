@@ -13,11 +13,15 @@ func _() {
 	_ = x[BadInput-3]
 	_ = x[InvalidSettings-4]
 	_ = x[Timeout-5]
+	_ = x[NotFound-6]
+	_ = x[Conflict-7]
+	_ = x[PreconditionFailed-8]
+	_ = x[NotImplemented-9]
 }
 
-const _ErrorType_name = "UnknownDatabaseErrorBadInputInvalidSettingsTimeout"
+const _ErrorType_name = "UnknownDatabaseErrorBadInputInvalidSettingsTimeoutNotFoundConflictPreconditionFailedNotImplemented"
 
-var _ErrorType_index = [...]uint8{0, 7, 20, 28, 43, 50}
+var _ErrorType_index = [...]uint8{0, 7, 20, 28, 43, 50, 58, 66, 84, 98}
 
 func (i ErrorType) String() string {
 	i -= 1
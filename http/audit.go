@@ -0,0 +1,96 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/slcjordan/library"
+)
+
+// toAuditedBook is a book as the audit log recorded it, with the ids of its
+// authors if they were recorded.
+func toAuditedBook(b library.Book) Book {
+	result := toBook(b)
+	if b.Authors != nil {
+		ids := make([]int64, 0, len(b.Authors))
+		for _, a := range b.Authors {
+			ids = append(ids, a.ID)
+		}
+		result.AuthorIds = &ids
+	}
+	return result
+}
+
+func toAuditRecord(a library.AuditRecord) AuditRecord {
+	result := AuditRecord{
+		Id:        a.ID,
+		Isbn:      a.ISBN.String(),
+		Action:    AuditRecordAction(a.Action),
+		Actor:     a.Actor,
+		RequestId: a.RequestID,
+		CreatedAt: a.CreatedAt,
+	}
+	if a.Before != nil {
+		before := toAuditedBook(*a.Before)
+		result.Before = &before
+	}
+	if a.After != nil {
+		after := toAuditedBook(*a.After)
+		result.After = &after
+	}
+	return result
+}
+
+// MarshalAuditRecord encodes an audit record as the api sends it, for
+// sending it outside of a response.
+func MarshalAuditRecord(a library.AuditRecord) ([]byte, error) {
+	return json.Marshal(toAuditRecord(a))
+}
+
+func toAuditRecordList(auditList library.AuditList) AuditRecordList {
+	result := AuditRecordList{
+		Items:         make([]AuditRecord, 0, len(auditList.Records)),
+		NextPageToken: auditList.NextPageToken,
+	}
+	for _, a := range auditList.Records {
+		result.Items = append(result.Items, toAuditRecord(a))
+	}
+	return result
+}
+
+// ListBookHistory returns the changes to a single book.
+func (s *Server) ListBookHistory(w http.ResponseWriter, r *http.Request, isbn Isbn, params ListBookHistoryParams) {
+	ctx := r.Context()
+	parsed, err := library.ParseISBN(isbn)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	totalSize, err := checkTotalSize(params.TotalSize)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	auditList, err := s.AuditController.ListBookHistory(ctx, parsed, fromPtr(params.PageToken, ""), totalSize)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	s.serialize(ctx, w, toAuditRecordList(auditList))
+}
+
+// ListAudit returns the changes to every book.
+func (s *Server) ListAudit(w http.ResponseWriter, r *http.Request, params ListAuditParams) {
+	ctx := r.Context()
+	totalSize, err := checkTotalSize(params.TotalSize)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	auditList, err := s.AuditController.ListAudit(ctx, fromPtr(params.PageToken, ""), totalSize)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	s.serialize(ctx, w, toAuditRecordList(auditList))
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/slcjordan/library"
+)
+
+// ListAuthors returns a result of authors in the library.
+func (s *Server) ListAuthors(w http.ResponseWriter, r *http.Request, params ListAuthorsParams) {
+	ctx := r.Context()
+	totalSize, err := checkTotalSize(params.TotalSize)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	authorList, err := s.ListAuthorsController.ListAuthors(ctx, fromPtr(params.PageToken, ""), totalSize)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	result := AuthorList{
+		Items:         make([]Author, 0, len(authorList.Authors)),
+		NextPageToken: authorList.NextPageToken,
+	}
+	for _, a := range authorList.Authors {
+		result.Items = append(result.Items, Author{
+			Id:   a.ID,
+			Name: a.Name,
+		})
+	}
+	s.serialize(ctx, w, result)
+}
+
+// ListAuthorBooks returns a result of books by a single author.
+func (s *Server) ListAuthorBooks(w http.ResponseWriter, r *http.Request, id AuthorId, params ListAuthorBooksParams) {
+	ctx := r.Context()
+	totalSize, err := checkTotalSize(params.TotalSize)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	bookList, err := s.ListAuthorsController.ListAuthorBooks(ctx, id, fromPtr(params.PageToken, ""), totalSize)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	s.serialize(ctx, w, toBookList(bookList))
+}
+
+// checkAuthorName rejects an author without a name.
+func checkAuthorName(author AuthorPartial, desc string) error {
+	if strings.TrimSpace(author.Name) == "" {
+		return &library.Error{
+			Type:   library.BadInput,
+			Actual: library.FieldErrors{{Field: "name", Message: "must not be blank"}},
+			Desc:   desc,
+		}
+	}
+	return nil
+}
+
+// CreateAuthor adds a single author to the library.
+func (s *Server) CreateAuthor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var author AuthorPartial
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&author)
+	if err != nil {
+		s.reportError(ctx, w, &library.Error{
+			Type:   library.BadInput,
+			Desc:   "while parsing request body",
+			Actual: err,
+		})
+		return
+	}
+	err = checkAuthorName(author, "while creating an author")
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	created, err := s.AuthorCRUDController.CreateAuthor(ctx, library.Author{
+		Name: author.Name,
+	})
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	s.serialize(ctx, w, Author{
+		Id:   created.ID,
+		Name: created.Name,
+	})
+}
+
+// DeleteAuthor handles deleting an author
+func (s *Server) DeleteAuthor(w http.ResponseWriter, r *http.Request, id AuthorId) {
+	ctx := r.Context()
+	err := s.AuthorCRUDController.DeleteAuthor(ctx, id)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// FetchAuthor handles fetching an author
+func (s *Server) FetchAuthor(w http.ResponseWriter, r *http.Request, id AuthorId) {
+	ctx := r.Context()
+	author, err := s.AuthorCRUDController.GetAuthor(ctx, id)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	s.serialize(ctx, w, Author{
+		Id:   author.ID,
+		Name: author.Name,
+	})
+}
+
+// UpdateAuthor handles updating an author
+func (s *Server) UpdateAuthor(w http.ResponseWriter, r *http.Request, id AuthorId) {
+	ctx := r.Context()
+	var author AuthorPartial
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&author)
+	if err != nil {
+		s.reportError(ctx, w, &library.Error{
+			Type:   library.BadInput,
+			Desc:   "while parsing request body",
+			Actual: err,
+		})
+		return
+	}
+	err = checkAuthorName(author, "while updating an author")
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	err = s.AuthorCRUDController.UpdateAuthor(ctx, library.Author{
+		ID:   id,
+		Name: author.Name,
+	})
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
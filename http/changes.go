@@ -0,0 +1,113 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/config"
+	"github.com/slcjordan/library/log"
+)
+
+// changesBatchSize is how many changes are read at a time while a feed
+// catches up.
+const changesBatchSize = 100
+
+const defaultHeartbeatInterval = 15 * time.Second
+
+// writeChange sends a change as an event named after its action.
+func writeChange(w http.ResponseWriter, change library.BookChange) error {
+	data, err := MarshalAuditRecord(change.Record)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", change.EventID, change.Record.Action, data)
+	return err
+}
+
+// writeEventID tells the client where the feed is without sending a change,
+// so that it can resume from there even if no change has been sent yet.
+func writeEventID(w http.ResponseWriter, eventID string) error {
+	_, err := fmt.Fprintf(w, "id: %s\n\n", eventID)
+	return err
+}
+
+// StreamBookChanges sends changes to books as Server-Sent Events until the
+// client goes away. Changes made while the client was away are sent first if
+// it reconnects with Last-Event-ID.
+func (s *Server) StreamBookChanges(w http.ResponseWriter, r *http.Request, params StreamBookChangesParams) {
+	ctx := r.Context()
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.reportError(ctx, w, errors.New("the response writer can't stream"))
+		return
+	}
+	// subscribing first means no change slips in between reading the
+	// changes and waiting for more.
+	wake, unsubscribe := s.ChangeController.SubscribeBookChanges()
+	defer unsubscribe()
+	changes, err := s.ChangeController.ListBookChanges(ctx, fromPtr(params.LastEventID, ""), changesBatchSize)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// nginx buffers responses unless told otherwise.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	// a client that goes away before the first change otherwise
+	// reconnects without an event id and misses what changed meanwhile.
+	// The first change carries its own.
+	if len(changes.Changes) == 0 {
+		err = writeEventID(w, changes.LastEventID)
+		if err != nil {
+			return
+		}
+	}
+
+	interval := config.Current().HTTP.HeartbeatInterval
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+	heartbeat := time.NewTicker(interval)
+	defer heartbeat.Stop()
+	for {
+		for _, change := range changes.Changes {
+			err = writeChange(w, change)
+			if err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+		if len(changes.Changes) < changesBatchSize {
+			select {
+			case <-ctx.Done():
+				return
+			case <-wake:
+			case <-heartbeat.C:
+				_, err = fmt.Fprint(w, ": heartbeat\n")
+				if err == nil {
+					err = writeEventID(w, changes.LastEventID)
+				}
+				if err != nil {
+					return
+				}
+				flusher.Flush()
+				// changes held back behind a long transaction don't
+				// notify anyone once it ends, so look again.
+			}
+		}
+		changes, err = s.ChangeController.ListBookChanges(ctx, changes.LastEventID, changesBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				// the client picks up where it left off when it
+				// reconnects.
+				log.Errorf(ctx, "while streaming book changes: %s", err)
+			}
+			return
+		}
+	}
+}
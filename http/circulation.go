@@ -0,0 +1,245 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/slcjordan/library"
+)
+
+func toLoan(l library.Loan) Loan {
+	result := Loan{
+		Id:           l.ID,
+		Barcode:      l.Barcode,
+		PatronId:     l.PatronID,
+		CheckedOutAt: l.CheckedOutAt,
+		DueAt:        l.DueAt,
+		Renewals:     l.Renewals,
+	}
+	if !l.ReturnedAt.IsZero() {
+		result.ReturnedAt = &l.ReturnedAt
+	}
+	return result
+}
+
+func toPatron(p library.Patron) Patron {
+	return Patron{
+		Id:             p.ID,
+		Name:           p.Name,
+		BorrowingLimit: p.BorrowingLimit,
+		Class:          p.Class,
+	}
+}
+
+// CreateCopy adds a single copy of a book to the library.
+func (s *Server) CreateCopy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var copy Copy
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&copy)
+	if err != nil {
+		s.reportError(ctx, w, &library.Error{
+			Type:   library.BadInput,
+			Desc:   "while parsing request body",
+			Actual: err,
+		})
+		return
+	}
+	isbn, err := library.ParseISBN(copy.Isbn)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	err = s.CopyCRUDController.CreateCopy(ctx, library.Copy{
+		Barcode:  copy.Barcode,
+		ISBN:     isbn,
+		Location: copy.Location,
+		Status:   library.CopyStatus(fromPtr(copy.Status, Available)),
+	})
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+// DeleteCopy handles deleting a copy
+func (s *Server) DeleteCopy(w http.ResponseWriter, r *http.Request, barcode Barcode) {
+	ctx := r.Context()
+	err := s.CopyCRUDController.DeleteCopy(ctx, barcode)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// FetchCopy handles fetching a copy
+func (s *Server) FetchCopy(w http.ResponseWriter, r *http.Request, barcode Barcode) {
+	ctx := r.Context()
+	copy, err := s.CopyCRUDController.GetCopy(ctx, barcode)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	status := CopyStatus(copy.Status)
+	s.serialize(ctx, w, Copy{
+		Barcode:  copy.Barcode,
+		Isbn:     copy.ISBN.String(),
+		Location: copy.Location,
+		Status:   &status,
+	})
+}
+
+// UpdateCopy handles updating a copy
+func (s *Server) UpdateCopy(w http.ResponseWriter, r *http.Request, barcode Barcode) {
+	ctx := r.Context()
+	var copy CopyPartial
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&copy)
+	if err != nil {
+		s.reportError(ctx, w, &library.Error{
+			Type:   library.BadInput,
+			Desc:   "while parsing request body",
+			Actual: err,
+		})
+		return
+	}
+	err = s.CopyCRUDController.UpdateCopy(ctx, library.Copy{
+		Barcode:  barcode,
+		Location: copy.Location,
+		Status:   library.CopyStatus(copy.Status),
+	})
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// CheckoutCopy lends a copy to a patron.
+func (s *Server) CheckoutCopy(w http.ResponseWriter, r *http.Request, barcode Barcode) {
+	ctx := r.Context()
+	var checkout Checkout
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&checkout)
+	if err != nil {
+		s.reportError(ctx, w, &library.Error{
+			Type:   library.BadInput,
+			Desc:   "while parsing request body",
+			Actual: err,
+		})
+		return
+	}
+	loan, err := s.CirculationController.Checkout(ctx, barcode, checkout.PatronId)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	s.serialize(ctx, w, toLoan(loan))
+}
+
+// ReturnCopy closes the loan of a copy.
+func (s *Server) ReturnCopy(w http.ResponseWriter, r *http.Request, barcode Barcode) {
+	ctx := r.Context()
+	loan, err := s.CirculationController.Return(ctx, barcode)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	s.serialize(ctx, w, toLoan(loan))
+}
+
+// RenewCopy extends the loan of a copy.
+func (s *Server) RenewCopy(w http.ResponseWriter, r *http.Request, barcode Barcode) {
+	ctx := r.Context()
+	loan, err := s.CirculationController.Renew(ctx, barcode)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	s.serialize(ctx, w, toLoan(loan))
+}
+
+// CreatePatron adds a single patron to the library.
+func (s *Server) CreatePatron(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var patron PatronPartial
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&patron)
+	if err != nil {
+		s.reportError(ctx, w, &library.Error{
+			Type:   library.BadInput,
+			Desc:   "while parsing request body",
+			Actual: err,
+		})
+		return
+	}
+	created, err := s.PatronCRUDController.CreatePatron(ctx, library.Patron{
+		Name:           patron.Name,
+		BorrowingLimit: patron.BorrowingLimit,
+		Class:          fromPtr(patron.Class, ""),
+	})
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	s.serialize(ctx, w, toPatron(created))
+}
+
+// FetchPatron handles fetching a patron
+func (s *Server) FetchPatron(w http.ResponseWriter, r *http.Request, id PatronId) {
+	ctx := r.Context()
+	patron, err := s.PatronCRUDController.GetPatron(ctx, id)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	s.serialize(ctx, w, toPatron(patron))
+}
+
+// UpdatePatron handles updating a patron
+func (s *Server) UpdatePatron(w http.ResponseWriter, r *http.Request, id PatronId) {
+	ctx := r.Context()
+	var patron PatronPartial
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&patron)
+	if err != nil {
+		s.reportError(ctx, w, &library.Error{
+			Type:   library.BadInput,
+			Desc:   "while parsing request body",
+			Actual: err,
+		})
+		return
+	}
+	err = s.PatronCRUDController.UpdatePatron(ctx, library.Patron{
+		ID:             id,
+		Name:           patron.Name,
+		BorrowingLimit: patron.BorrowingLimit,
+		Class:          fromPtr(patron.Class, ""),
+	})
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// ListPatronLoans returns the open loans of a patron.
+func (s *Server) ListPatronLoans(w http.ResponseWriter, r *http.Request, id PatronId) {
+	ctx := r.Context()
+	loans, err := s.CirculationController.ListPatronLoans(ctx, id)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	result := LoanList{
+		Items: make([]Loan, 0, len(loans)),
+	}
+	for _, l := range loans {
+		result.Items = append(result.Items, toLoan(l))
+	}
+	s.serialize(ctx, w, result)
+}
@@ -0,0 +1,153 @@
+package http
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/bookio"
+	"github.com/slcjordan/library/log"
+)
+
+// exportFormats maps the media types an export can be downloaded as to file
+// formats. The first one is the default.
+var exportFormats = []struct {
+	mediaType string
+	format    bookio.Format
+}{
+	{"application/x-ndjson", bookio.NDJSON},
+	{"text/csv", bookio.CSV},
+	{"application/vnd.apache.parquet", bookio.Parquet},
+}
+
+// accepts returns the values of a header like Accept or Accept-Encoding that
+// aren't refused with q=0, without their parameters.
+func accepts(header string) []string {
+	var result []string
+	for _, value := range strings.Split(header, ",") {
+		value, params, err := mime.ParseMediaType(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		q, err := strconv.ParseFloat(params["q"], 64)
+		if err == nil && q == 0 {
+			continue
+		}
+		result = append(result, value)
+	}
+	return result
+}
+
+// exportFormat picks the first supported media type in an Accept header.
+func exportFormat(accept string) (string, bookio.Format, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return exportFormats[0].mediaType, exportFormats[0].format, true
+	}
+	for _, mediaType := range accepts(accept) {
+		if mediaType == "*/*" || mediaType == "application/*" {
+			return exportFormats[0].mediaType, exportFormats[0].format, true
+		}
+		for _, f := range exportFormats {
+			if mediaType == f.mediaType || mediaType == strings.Split(f.mediaType, "/")[0]+"/*" {
+				return f.mediaType, f.format, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// exportResponse sends the response headers just before the first byte of an
+// export so that an error before then still gets an error response.
+type exportResponse struct {
+	w       http.ResponseWriter
+	header  http.Header
+	started bool
+}
+
+func (e *exportResponse) Write(p []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		for k, v := range e.header {
+			e.w.Header()[k] = v
+		}
+		e.w.WriteHeader(http.StatusOK)
+	}
+	return e.w.Write(p)
+}
+
+// abort cuts off a response that already started so the client can't mistake
+// it for the whole export.
+func abort(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		panic(http.ErrAbortHandler)
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	conn.Close()
+}
+
+// ExportBooks streams every book in the library.
+func (s *Server) ExportBooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	mediaType, format, ok := exportFormat(r.Header.Get("Accept"))
+	if !ok {
+		w.WriteHeader(http.StatusNotAcceptable)
+		s.serialize(ctx, w, Error{
+			Code:    int(library.BadInput),
+			Message: fmt.Sprintf("Not Acceptable: books can be exported as %s", exportMediaTypes()),
+		})
+		return
+	}
+	resp := &exportResponse{w: w, header: http.Header{}}
+	resp.header.Set("Content-Type", mediaType)
+	resp.header.Set("Vary", "Accept, Accept-Encoding")
+	var out io.Writer = resp
+	var gz *gzip.Writer
+	for _, encoding := range accepts(r.Header.Get("Accept-Encoding")) {
+		if encoding == "gzip" {
+			resp.header.Set("Content-Encoding", "gzip")
+			gz = gzip.NewWriter(resp)
+			out = gz
+			break
+		}
+	}
+	writer, err := bookio.NewWriter(format, out)
+	if err == nil {
+		err = s.ExportController.ExportBooks(ctx, writer)
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err == nil && gz != nil {
+		err = gz.Close()
+	}
+	if err == nil && !resp.started {
+		// an empty export still gets its headers.
+		_, err = resp.Write(nil)
+	}
+	if err == nil {
+		return
+	}
+	if !resp.started {
+		s.reportError(ctx, w, err)
+		return
+	}
+	log.Errorf(ctx, "while exporting books: %s", err)
+	abort(w)
+}
+
+func exportMediaTypes() string {
+	mediaTypes := make([]string, 0, len(exportFormats))
+	for _, f := range exportFormats {
+		mediaTypes = append(mediaTypes, f.mediaType)
+	}
+	return strings.Join(mediaTypes, ", ")
+}
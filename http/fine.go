@@ -0,0 +1,105 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/slcjordan/library"
+)
+
+func toLedgerEntry(e library.LedgerEntry) LedgerEntry {
+	result := LedgerEntry{
+		Id:        e.ID,
+		PatronId:  e.PatronID,
+		Kind:      LedgerKind(e.Kind),
+		Amount:    e.Amount,
+		Note:      e.Note,
+		Actor:     e.Actor,
+		CreatedAt: e.CreatedAt,
+	}
+	if e.LoanID != 0 {
+		result.LoanId = &e.LoanID
+	}
+	return result
+}
+
+func toClassChange(c library.ClassChange) ClassChange {
+	return ClassChange{
+		Id:        c.ID,
+		PatronId:  c.PatronID,
+		From:      c.From,
+		To:        c.To,
+		Actor:     c.Actor,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+// FetchFineAccount returns what a patron owes.
+func (s *Server) FetchFineAccount(w http.ResponseWriter, r *http.Request, id PatronId) {
+	ctx := r.Context()
+	account, err := s.FineController.GetFineAccount(ctx, id)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	result := FineAccount{
+		PatronId:     account.PatronID,
+		Balance:      account.Balance,
+		Accruing:     account.Accruing,
+		Entries:      make([]LedgerEntry, 0, len(account.Entries)),
+		ClassChanges: make([]ClassChange, 0, len(account.ClassChanges)),
+	}
+	for _, e := range account.Entries {
+		result.Entries = append(result.Entries, toLedgerEntry(e))
+	}
+	for _, c := range account.ClassChanges {
+		result.ClassChanges = append(result.ClassChanges, toClassChange(c))
+	}
+	s.serialize(ctx, w, result)
+}
+
+// RecordPayment credits a patron with a payment.
+func (s *Server) RecordPayment(w http.ResponseWriter, r *http.Request, id PatronId) {
+	ctx := r.Context()
+	var payment Payment
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&payment)
+	if err != nil {
+		s.reportError(ctx, w, &library.Error{
+			Type:   library.BadInput,
+			Desc:   "while parsing request body",
+			Actual: err,
+		})
+		return
+	}
+	entry, err := s.FineController.RecordPayment(ctx, id, payment.Amount, fromPtr(payment.Note, ""))
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	s.serialize(ctx, w, toLedgerEntry(entry))
+}
+
+// WaiveFines forgives part of what a patron owes.
+func (s *Server) WaiveFines(w http.ResponseWriter, r *http.Request, id PatronId) {
+	ctx := r.Context()
+	var waiver Waiver
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&waiver)
+	if err != nil {
+		s.reportError(ctx, w, &library.Error{
+			Type:   library.BadInput,
+			Desc:   "while parsing request body",
+			Actual: err,
+		})
+		return
+	}
+	entry, err := s.FineController.WaiveFines(ctx, id, waiver.Amount, waiver.Note)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	s.serialize(ctx, w, toLedgerEntry(entry))
+}
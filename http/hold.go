@@ -0,0 +1,87 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/slcjordan/library"
+)
+
+func toHold(h library.Hold) Hold {
+	result := Hold{
+		Id:       h.ID,
+		Isbn:     h.ISBN.String(),
+		PatronId: h.PatronID,
+		PlacedAt: h.PlacedAt,
+		Status:   HoldStatus(h.Status),
+		Position: h.Position,
+	}
+	if h.Barcode != "" {
+		result.Barcode = &h.Barcode
+	}
+	if !h.PickupBy.IsZero() {
+		result.PickupBy = &h.PickupBy
+	}
+	return result
+}
+
+// PlaceHold queues a patron for a book.
+func (s *Server) PlaceHold(w http.ResponseWriter, r *http.Request, isbn Isbn) {
+	ctx := r.Context()
+	parsed, err := library.ParseISBN(isbn)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	var hold HoldRequest
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&hold)
+	if err != nil {
+		s.reportError(ctx, w, &library.Error{
+			Type:   library.BadInput,
+			Desc:   "while parsing request body",
+			Actual: err,
+		})
+		return
+	}
+	placed, err := s.HoldController.PlaceHold(ctx, parsed, hold.PatronId)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	s.serialize(ctx, w, toHold(placed))
+}
+
+// ListBookHolds returns the hold queue of a book.
+func (s *Server) ListBookHolds(w http.ResponseWriter, r *http.Request, isbn Isbn) {
+	ctx := r.Context()
+	parsed, err := library.ParseISBN(isbn)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	holds, err := s.HoldController.ListBookHolds(ctx, parsed)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	result := HoldList{
+		Items: make([]Hold, 0, len(holds)),
+	}
+	for _, h := range holds {
+		result.Items = append(result.Items, toHold(h))
+	}
+	s.serialize(ctx, w, result)
+}
+
+// CancelHold takes a patron out of a hold queue.
+func (s *Server) CancelHold(w http.ResponseWriter, r *http.Request, id HoldId) {
+	ctx := r.Context()
+	err := s.HoldController.CancelHold(ctx, id)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -6,6 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/slcjordan/library"
 	"github.com/slcjordan/library/config"
@@ -15,14 +18,105 @@ import (
 //go:generate go run github.com/golang/mock/mockgen -package=http -destination=../test/mocks/http/http.go -source=http.go
 
 type ListBooksController interface {
-	ListBooks(ctx context.Context, PageToken string, TotalSize int32) (library.BookList, error)
+	ListBooks(ctx context.Context, filter library.BookFilter, PageToken string, TotalSize int32) (library.BookList, error)
+}
+
+type SearchController interface {
+	SearchBooks(ctx context.Context, query string, PageToken string, TotalSize int32) (library.SearchResultList, error)
 }
 
 type BookCRUDController interface {
 	CreateBook(ctx context.Context, book library.Book) error
-	DeleteBook(ctx context.Context, isbn int64) error
-	GetBook(ctx context.Context, isbn int64) (library.Book, error)
-	UpdateBook(ctx context.Context, book library.Book) error
+	DeleteBook(ctx context.Context, isbn library.ISBN) error
+	GetBook(ctx context.Context, isbn library.ISBN) (library.Book, error)
+	UpdateBook(ctx context.Context, book library.Book) (library.Book, error)
+	PatchBook(ctx context.Context, isbn library.ISBN, version int64, patch library.BookPatch) (library.Book, error)
+}
+
+// A TrashController finds and restores books that were deleted but not yet
+// purged.
+type TrashController interface {
+	GetBookIncludingDeleted(ctx context.Context, isbn library.ISBN) (library.Book, error)
+	RestoreBook(ctx context.Context, isbn library.ISBN) (library.Book, error)
+}
+
+// An AuditController reads the record of changes to books.
+type AuditController interface {
+	ListBookHistory(ctx context.Context, isbn library.ISBN, PageToken string, TotalSize int32) (library.AuditList, error)
+	ListAudit(ctx context.Context, PageToken string, TotalSize int32) (library.AuditList, error)
+	GetBookAt(ctx context.Context, isbn library.ISBN, at time.Time) (library.Book, error)
+}
+
+// A WebhookController manages the partner systems that are sent the changes
+// to books, and the log of what was sent to them.
+type WebhookController interface {
+	CreateWebhook(ctx context.Context, webhook library.Webhook) (library.Webhook, error)
+	GetWebhook(ctx context.Context, id int64) (library.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]library.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook library.Webhook) (library.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	ListWebhookDeliveries(ctx context.Context, webhookID int64, status library.DeliveryStatus, PageToken string, TotalSize int32) (library.WebhookDeliveryList, error)
+	RedeliverWebhookDelivery(ctx context.Context, webhookID int64, id int64) (library.WebhookDelivery, error)
+}
+
+// A ChangeController feeds the changes to books to clients as they happen.
+// Subscribers are woken after books change; the changes themselves are read
+// with ListBookChanges.
+type ChangeController interface {
+	ListBookChanges(ctx context.Context, lastEventID string, TotalSize int32) (library.BookChangeList, error)
+	SubscribeBookChanges() (<-chan struct{}, func())
+}
+
+type ListAuthorsController interface {
+	ListAuthors(ctx context.Context, PageToken string, TotalSize int32) (library.AuthorList, error)
+	ListAuthorBooks(ctx context.Context, authorID int64, PageToken string, TotalSize int32) (library.BookList, error)
+}
+
+type AuthorCRUDController interface {
+	CreateAuthor(ctx context.Context, author library.Author) (library.Author, error)
+	DeleteAuthor(ctx context.Context, id int64) error
+	GetAuthor(ctx context.Context, id int64) (library.Author, error)
+	UpdateAuthor(ctx context.Context, author library.Author) error
+}
+
+type CopyCRUDController interface {
+	CreateCopy(ctx context.Context, copy library.Copy) error
+	DeleteCopy(ctx context.Context, barcode string) error
+	GetCopy(ctx context.Context, barcode string) (library.Copy, error)
+	UpdateCopy(ctx context.Context, copy library.Copy) error
+}
+
+type PatronCRUDController interface {
+	CreatePatron(ctx context.Context, patron library.Patron) (library.Patron, error)
+	GetPatron(ctx context.Context, id int64) (library.Patron, error)
+	UpdatePatron(ctx context.Context, patron library.Patron) error
+}
+
+type CirculationController interface {
+	Checkout(ctx context.Context, barcode string, patronID int64) (library.Loan, error)
+	Return(ctx context.Context, barcode string) (library.Loan, error)
+	Renew(ctx context.Context, barcode string) (library.Loan, error)
+	ListPatronLoans(ctx context.Context, patronID int64) ([]library.Loan, error)
+}
+
+type HoldController interface {
+	PlaceHold(ctx context.Context, isbn library.ISBN, patronID int64) (library.Hold, error)
+	ListBookHolds(ctx context.Context, isbn library.ISBN) ([]library.Hold, error)
+	CancelHold(ctx context.Context, id int64) error
+}
+
+type FineController interface {
+	GetFineAccount(ctx context.Context, patronID int64) (library.FineAccount, error)
+	RecordPayment(ctx context.Context, patronID int64, amount int64, note string) (library.LedgerEntry, error)
+	WaiveFines(ctx context.Context, patronID int64, amount int64, note string) (library.LedgerEntry, error)
+}
+
+type ImportController interface {
+	ImportBooks(ctx context.Context, reader library.BookReader, mode library.ImportMode) (library.ImportReport, error)
+}
+
+type ExportController interface {
+	ExportBooks(ctx context.Context, writer library.BookWriter) error
 }
 
 func fromPtr[V any](input *V, otherwise V) V {
@@ -34,8 +128,22 @@ func fromPtr[V any](input *V, otherwise V) V {
 
 // Server handles incoming requests.
 type Server struct {
-	ListBooksController ListBooksController
-	BookCRUDController  BookCRUDController
+	ListBooksController   ListBooksController
+	BookCRUDController    BookCRUDController
+	TrashController       TrashController
+	AuditController       AuditController
+	ChangeController      ChangeController
+	WebhookController     WebhookController
+	SearchController      SearchController
+	ListAuthorsController ListAuthorsController
+	AuthorCRUDController  AuthorCRUDController
+	CopyCRUDController    CopyCRUDController
+	PatronCRUDController  PatronCRUDController
+	CirculationController CirculationController
+	HoldController        HoldController
+	FineController        FineController
+	ImportController      ImportController
+	ExportController      ExportController
 }
 
 func (s *Server) serialize(ctx context.Context, w http.ResponseWriter, data any) {
@@ -51,10 +159,47 @@ func (s *Server) reportError(ctx context.Context, w http.ResponseWriter, err err
 	if errors.As(err, &libErr) {
 		switch libErr.Type {
 		case library.BadInput:
+			result := Error{
+				Code:    int(libErr.Type),
+				Message: fmt.Sprintf("Bad Input: %s", err),
+			}
+			var fieldErrors library.FieldErrors
+			if errors.As(err, &fieldErrors) {
+				fields := make([]FieldError, 0, len(fieldErrors))
+				for _, f := range fieldErrors {
+					fields = append(fields, FieldError{Field: f.Field, Message: f.Message})
+				}
+				result.Fields = &fields
+			}
 			w.WriteHeader(http.StatusBadRequest)
+			s.serialize(ctx, w, result)
+			return
+		case library.NotFound:
+			w.WriteHeader(http.StatusNotFound)
 			s.serialize(ctx, w, Error{
 				Code:    int(libErr.Type),
-				Message: fmt.Sprintf("Bad Input: %s", err),
+				Message: fmt.Sprintf("Not Found: %s", err),
+			})
+			return
+		case library.Conflict:
+			w.WriteHeader(http.StatusConflict)
+			s.serialize(ctx, w, Error{
+				Code:    int(libErr.Type),
+				Message: fmt.Sprintf("Conflict: %s", err),
+			})
+			return
+		case library.PreconditionFailed:
+			w.WriteHeader(http.StatusPreconditionFailed)
+			s.serialize(ctx, w, Error{
+				Code:    int(libErr.Type),
+				Message: fmt.Sprintf("Precondition Failed: %s", err),
+			})
+			return
+		case library.NotImplemented:
+			w.WriteHeader(http.StatusNotImplemented)
+			s.serialize(ctx, w, Error{
+				Code:    int(libErr.Type),
+				Message: fmt.Sprintf("Not Implemented: %s", err),
 			})
 			return
 		case library.Timeout:
@@ -90,31 +235,156 @@ func (s *Server) UserErrorHandler(w http.ResponseWriter, r *http.Request, err er
 // ListBooks returns a result of books in the library.
 func (s *Server) ListBooks(w http.ResponseWriter, r *http.Request, params ListBooksParams) {
 	ctx := r.Context()
-	totalSize := fromPtr(params.TotalSize, config.HTTP.MaxListSize)
-	if totalSize < 0 || totalSize > config.HTTP.MaxListSize {
-		s.reportError(ctx, w, &library.Error{
-			Type:   library.BadInput,
-			Desc:   "while checking parameter bounds",
-			Actual: fmt.Errorf("total size should be between %d and %d but got %d", 0, config.HTTP.MaxListSize, totalSize),
-		})
+	totalSize, err := checkTotalSize(params.TotalSize)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	filter, err := toBookFilter(params)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	bookList, err := s.ListBooksController.ListBooks(ctx, filter, fromPtr(params.PageToken, ""), totalSize)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	s.serialize(ctx, w, toBookList(bookList))
+}
+
+// SearchBooks returns the books matching a query, best match first.
+func (s *Server) SearchBooks(w http.ResponseWriter, r *http.Request, params SearchBooksParams) {
+	ctx := r.Context()
+	totalSize, err := checkTotalSize(params.TotalSize)
+	if err != nil {
+		s.reportError(ctx, w, err)
 		return
 	}
-	bookList, err := s.ListBooksController.ListBooks(ctx, fromPtr(params.PageToken, ""), totalSize)
+	found, err := s.SearchController.SearchBooks(ctx, params.Q, fromPtr(params.PageToken, ""), totalSize)
 	if err != nil {
 		s.reportError(ctx, w, err)
 		return
 	}
+	result := SearchResultList{
+		Items:         make([]SearchResult, 0, len(found.Results)),
+		NextPageToken: found.NextPageToken,
+	}
+	for _, f := range found.Results {
+		result.Items = append(result.Items, SearchResult{
+			Isbn:    f.Book.ISBN.String(),
+			Title:   f.Book.Title,
+			Score:   f.Score,
+			Snippet: f.Snippet,
+		})
+	}
+	s.serialize(ctx, w, result)
+}
+
+// toBookFilter parses the filter and sort parameters of ListBooks.
+func toBookFilter(params ListBooksParams) (library.BookFilter, error) {
+	filter := library.BookFilter{
+		TitlePrefix:    fromPtr(params.TitlePrefix, ""),
+		CreatedAfter:   fromPtr(params.CreatedAfter, time.Time{}),
+		CreatedBefore:  fromPtr(params.CreatedBefore, time.Time{}),
+		UpdatedAfter:   fromPtr(params.UpdatedAfter, time.Time{}),
+		UpdatedBefore:  fromPtr(params.UpdatedBefore, time.Time{}),
+		OrderBy:        library.BookOrder(fromPtr(params.Sort, ListBooksParamsSortTitle)),
+		IncludeDeleted: fromPtr(params.IncludeDeleted, false),
+	}
+	var err error
+	if params.MinIsbn != nil {
+		filter.MinISBN, err = library.ParseISBN(*params.MinIsbn)
+		if err != nil {
+			return library.BookFilter{}, err
+		}
+	}
+	if params.MaxIsbn != nil {
+		filter.MaxISBN, err = library.ParseISBN(*params.MaxIsbn)
+		if err != nil {
+			return library.BookFilter{}, err
+		}
+	}
+	switch fromPtr(params.Direction, Asc) {
+	case Asc:
+	case Desc:
+		filter.Descending = true
+	default:
+		return library.BookFilter{}, &library.Error{
+			Type:   library.BadInput,
+			Desc:   "while checking parameters",
+			Actual: fmt.Errorf("direction should be asc or desc but got %q", *params.Direction),
+		}
+	}
+	return filter, nil
+}
+
+// checkTotalSize defaults a missing total size to the maximum.
+func checkTotalSize(totalSize *TotalSize) (int32, error) {
+	maxListSize := config.Current().HTTP.MaxListSize
+	result := fromPtr(totalSize, maxListSize)
+	if result < 0 || result > maxListSize {
+		return 0, &library.Error{
+			Type:   library.BadInput,
+			Desc:   "while checking parameter bounds",
+			Actual: fmt.Errorf("total size should be between %d and %d but got %d", 0, maxListSize, result),
+		}
+	}
+	return result, nil
+}
+
+func toBook(b library.Book) Book {
+	result := Book{
+		Isbn:  b.ISBN.String(),
+		Title: b.Title,
+	}
+	if !b.CreatedAt.IsZero() {
+		result.CreatedAt = &b.CreatedAt
+	}
+	if !b.UpdatedAt.IsZero() {
+		result.UpdatedAt = &b.UpdatedAt
+	}
+	if b.Version != 0 {
+		tag := etag(b.Version)
+		result.Etag = &tag
+	}
+	if !b.DeletedAt.IsZero() {
+		result.DeletedAt = &b.DeletedAt
+	}
+	return result
+}
+
+// etag is the entity tag of a book version. Tags are strong so they can be
+// used with If-Match.
+func etag(version int64) string {
+	return fmt.Sprintf("%q", strconv.FormatInt(version, 10))
+}
+
+// etagMatches reports whether an If-Match or If-None-Match header is * or
+// lists tag. Weak tags in the header only match if weak is set, which is how
+// If-None-Match compares them.
+func etagMatches(header string, tag string, weak bool) bool {
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if weak {
+			t = strings.TrimPrefix(t, "W/")
+		}
+		if t == "*" || t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func toBookList(bookList library.BookList) BookList {
 	result := BookList{
 		Items:         make([]Book, 0, len(bookList.Books)),
 		NextPageToken: bookList.NextPageToken,
 	}
 	for _, b := range bookList.Books {
-		result.Items = append(result.Items, Book{
-			Isbn:  b.ISBN,
-			Title: b.Title,
-		})
+		result.Items = append(result.Items, toBook(b))
 	}
-	s.serialize(ctx, w, result)
+	return result
 }
 
 // CreateBook adds a single book to the library.
@@ -131,10 +401,19 @@ func (s *Server) CreateBook(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	err = s.BookCRUDController.CreateBook(ctx, library.Book{
+	isbn, err := library.ParseISBN(book.Isbn)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	created := library.Book{
 		Title: book.Title,
-		ISBN:  book.Isbn,
-	})
+		ISBN:  isbn,
+	}
+	for _, id := range fromPtr(book.AuthorIds, nil) {
+		created.Authors = append(created.Authors, library.Author{ID: id})
+	}
+	err = s.BookCRUDController.CreateBook(ctx, created)
 	if err != nil {
 		s.reportError(ctx, w, err)
 		return
@@ -142,10 +421,15 @@ func (s *Server) CreateBook(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 }
 
-// DeleteBook handles deleting a book
+// DeleteBook handles moving a book to the trash
 func (s *Server) DeleteBook(w http.ResponseWriter, r *http.Request, isbn Isbn) {
 	ctx := r.Context()
-	err := s.BookCRUDController.DeleteBook(ctx, isbn)
+	parsed, err := library.ParseISBN(isbn)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	err = s.BookCRUDController.DeleteBook(ctx, parsed)
 	if err != nil {
 		s.reportError(ctx, w, err)
 		return
@@ -154,28 +438,106 @@ func (s *Server) DeleteBook(w http.ResponseWriter, r *http.Request, isbn Isbn) {
 }
 
 // FetchBook handles fetching a book
-func (s *Server) FetchBook(w http.ResponseWriter, r *http.Request, isbn Isbn) {
+func (s *Server) FetchBook(w http.ResponseWriter, r *http.Request, isbn Isbn, params FetchBookParams) {
 	ctx := r.Context()
-	book, err := s.BookCRUDController.GetBook(ctx, isbn)
+	parsed, err := library.ParseISBN(isbn)
 	if err != nil {
 		s.reportError(ctx, w, err)
 		return
 	}
+	if params.AsOf != nil {
+		// the audit log only records the ids of the authors.
+		book, err := s.AuditController.GetBookAt(ctx, parsed, *params.AsOf)
+		if err != nil {
+			s.reportError(ctx, w, err)
+			return
+		}
+		w.Header().Set("ETag", etag(book.Version))
+		s.serialize(ctx, w, toAuditedBook(book))
+		return
+	}
+	var book library.Book
+	if fromPtr(params.IncludeDeleted, false) {
+		book, err = s.TrashController.GetBookIncludingDeleted(ctx, parsed)
+	} else {
+		book, err = s.BookCRUDController.GetBook(ctx, parsed)
+	}
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	s.serializeBook(ctx, w, book, params.IfNoneMatch)
+}
 
-	result := Book{
-		Isbn:  book.ISBN,
-		Title: book.Title,
+// RestoreBook handles taking a book out of the trash.
+func (s *Server) RestoreBook(w http.ResponseWriter, r *http.Request, isbn Isbn) {
+	ctx := r.Context()
+	parsed, err := library.ParseISBN(isbn)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	book, err := s.TrashController.RestoreBook(ctx, parsed)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	s.serializeBook(ctx, w, book, nil)
+}
+
+// serializeBook writes a single book with its authors and ETag. The response
+// is 304 with no body if ifNoneMatch matches the book.
+func (s *Server) serializeBook(ctx context.Context, w http.ResponseWriter, book library.Book, ifNoneMatch *string) {
+	authors := make([]Author, 0, len(book.Authors))
+	for _, a := range book.Authors {
+		authors = append(authors, Author{
+			Id:   a.ID,
+			Name: a.Name,
+		})
+	}
+	result := toBook(book)
+	result.Authors = &authors
+	w.Header().Set("ETag", etag(book.Version))
+	if ifNoneMatch != nil && etagMatches(*ifNoneMatch, etag(book.Version), true) {
+		w.WriteHeader(http.StatusNotModified)
+		return
 	}
 	s.serialize(ctx, w, result)
-	w.WriteHeader(http.StatusNoContent)
 }
 
-// UpdateBook handles updating a book
-func (s *Server) UpdateBook(w http.ResponseWriter, r *http.Request, isbn Isbn) {
+// matchVersion returns the version of a book named by an If-Match header, or
+// 0 if there is no header. The book must still be at that version when it is
+// written for the write to succeed.
+func (s *Server) matchVersion(ctx context.Context, isbn library.ISBN, ifMatch *string) (int64, error) {
+	if ifMatch == nil {
+		return 0, nil
+	}
+	current, err := s.BookCRUDController.GetBook(ctx, isbn)
+	if err != nil {
+		return 0, err
+	}
+	if !etagMatches(*ifMatch, etag(current.Version), false) {
+		return 0, &library.Error{
+			Type:   library.PreconditionFailed,
+			Actual: fmt.Errorf("book %s is at %s, not %s", isbn, etag(current.Version), *ifMatch),
+			Desc:   "the book changed since it was read",
+		}
+	}
+	return current.Version, nil
+}
+
+// UpdateBook handles updating a book. With an If-Match header the book is only
+// updated if it hasn't changed since the client read it.
+func (s *Server) UpdateBook(w http.ResponseWriter, r *http.Request, isbn Isbn, params UpdateBookParams) {
 	ctx := r.Context()
+	parsed, err := library.ParseISBN(isbn)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
 	var book BookPartial
 	decoder := json.NewDecoder(r.Body)
-	err := decoder.Decode(&book)
+	err = decoder.Decode(&book)
 	if err != nil {
 		s.reportError(ctx, w, &library.Error{
 			Type:   library.BadInput,
@@ -184,13 +546,20 @@ func (s *Server) UpdateBook(w http.ResponseWriter, r *http.Request, isbn Isbn) {
 		})
 		return
 	}
-	err = s.BookCRUDController.UpdateBook(ctx, library.Book{
-		ISBN:  isbn,
+	updated := library.Book{
+		ISBN:  parsed,
 		Title: book.Title,
-	})
+	}
+	updated.Version, err = s.matchVersion(ctx, parsed, params.IfMatch)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	updated, err = s.BookCRUDController.UpdateBook(ctx, updated)
 	if err != nil {
 		s.reportError(ctx, w, err)
 		return
 	}
+	w.Header().Set("ETag", etag(updated.Version))
 	w.WriteHeader(http.StatusOK)
 }
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/bookio"
+)
+
+// importFormats maps the media types an import accepts to file formats.
+var importFormats = map[string]bookio.Format{
+	"text/csv":             bookio.CSV,
+	"application/x-ndjson": bookio.NDJSON,
+}
+
+func toImportReport(r library.ImportReport) ImportReport {
+	result := ImportReport{
+		Rows:       r.Rows,
+		Imported:   r.Imported,
+		Skipped:    r.Skipped,
+		Errors:     make([]ImportError, 0, len(r.Errors)),
+		ErrorCount: r.ErrorCount,
+		Truncated:  r.Truncated,
+	}
+	for _, e := range r.Errors {
+		result.Errors = append(result.Errors, ImportError{
+			Row:     e.Row,
+			Message: e.Message,
+		})
+	}
+	return result
+}
+
+// ImportBooks creates the books in the request body.
+func (s *Server) ImportBooks(w http.ResponseWriter, r *http.Request, params ImportBooksParams) {
+	ctx := r.Context()
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	format, ok := importFormats[mediaType]
+	if err != nil || !ok {
+		s.reportError(ctx, w, &library.Error{
+			Type:   library.BadInput,
+			Actual: fmt.Errorf("unsupported content type %q", r.Header.Get("Content-Type")),
+			Desc:   "while parsing request body",
+		})
+		return
+	}
+	reader, err := bookio.NewReader(format, r.Body)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	mode := library.ImportMode(fromPtr(params.OnConflict, Skip))
+	report, err := s.ImportController.ImportBooks(ctx, reader, mode)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	if report.Aborted {
+		w.WriteHeader(http.StatusConflict)
+	}
+	s.serialize(ctx, w, toImportReport(report))
+}
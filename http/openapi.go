@@ -6,113 +6,2041 @@ package http
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/deepmap/oapi-codegen/pkg/runtime"
 	"github.com/go-chi/chi/v5"
 )
 
+// Defines values for AuditAction.
+const (
+	AuditActionCreate  AuditAction = "create"
+	AuditActionDelete  AuditAction = "delete"
+	AuditActionPurge   AuditAction = "purge"
+	AuditActionRestore AuditAction = "restore"
+	AuditActionUpdate  AuditAction = "update"
+)
+
+// Defines values for AuditRecordAction.
+const (
+	AuditRecordActionCreate  AuditRecordAction = "create"
+	AuditRecordActionDelete  AuditRecordAction = "delete"
+	AuditRecordActionPurge   AuditRecordAction = "purge"
+	AuditRecordActionRestore AuditRecordAction = "restore"
+	AuditRecordActionUpdate  AuditRecordAction = "update"
+)
+
+// Defines values for CopyStatus.
+const (
+	Available   CopyStatus = "available"
+	Lost        CopyStatus = "lost"
+	OnHoldShelf CopyStatus = "on_hold_shelf"
+	OnLoan      CopyStatus = "on_loan"
+)
+
+// Defines values for DeliveryStatus.
+const (
+	Dead      DeliveryStatus = "dead"
+	Delivered DeliveryStatus = "delivered"
+	Pending   DeliveryStatus = "pending"
+)
+
+// Defines values for HoldStatus.
+const (
+	Cancelled HoldStatus = "cancelled"
+	Expired   HoldStatus = "expired"
+	Fulfilled HoldStatus = "fulfilled"
+	Ready     HoldStatus = "ready"
+	Waiting   HoldStatus = "waiting"
+)
+
+// Defines values for LedgerKind.
+const (
+	LedgerKindFine    LedgerKind = "fine"
+	LedgerKindPayment LedgerKind = "payment"
+	LedgerKindWaiver  LedgerKind = "waiver"
+)
+
+// Defines values for PatchOperationOp.
+const (
+	PatchOperationOpAdd     PatchOperationOp = "add"
+	PatchOperationOpCopy    PatchOperationOp = "copy"
+	PatchOperationOpMove    PatchOperationOp = "move"
+	PatchOperationOpRemove  PatchOperationOp = "remove"
+	PatchOperationOpReplace PatchOperationOp = "replace"
+	PatchOperationOpTest    PatchOperationOp = "test"
+)
+
+// Defines values for ListBooksParamsSort.
+const (
+	ListBooksParamsSortCreatedAt ListBooksParamsSort = "created_at"
+	ListBooksParamsSortIsbn      ListBooksParamsSort = "isbn"
+	ListBooksParamsSortTitle     ListBooksParamsSort = "title"
+)
+
+// Defines values for ListBooksParamsDirection.
+const (
+	Asc  ListBooksParamsDirection = "asc"
+	Desc ListBooksParamsDirection = "desc"
+)
+
+// Defines values for ImportBooksParamsOnConflict.
+const (
+	Fail      ImportBooksParamsOnConflict = "fail"
+	Overwrite ImportBooksParamsOnConflict = "overwrite"
+	Skip      ImportBooksParamsOnConflict = "skip"
+)
+
+// AuditAction defines model for AuditAction.
+type AuditAction string
+
+// AuditRecord defines model for AuditRecord.
+type AuditRecord struct {
+	Action AuditRecordAction `json:"action"`
+
+	// Actor the X-Actor header of the request that made the change
+	Actor     string    `json:"actor"`
+	After     *Book     `json:"after,omitempty"`
+	Before    *Book     `json:"before,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Id        int64     `json:"id"`
+
+	// Isbn an ISBN-10 or ISBN-13 with or without hyphens. Responses always have the ISBN-13 in its canonical form, which is only hyphenated in the English language groups 978-0 and 978-1. In every other group it is the 13 digits without hyphens, e.g. 9788804668237.
+	Isbn ISBN `json:"isbn"`
+
+	// RequestId the X-Request-Id of the request that made the change
+	RequestId string `json:"request_id"`
+}
+
+// AuditRecordAction defines model for AuditRecord.Action.
+type AuditRecordAction string
+
+// AuditRecordList defines model for AuditRecordList.
+type AuditRecordList struct {
+	Items         []AuditRecord `json:"items"`
+	NextPageToken string        `json:"next_page_token"`
+}
+
+// Author defines model for Author.
+type Author struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// AuthorList defines model for AuthorList.
+type AuthorList struct {
+	Items         []Author `json:"items"`
+	NextPageToken string   `json:"next_page_token"`
+}
+
+// AuthorPartial defines model for AuthorPartial.
+type AuthorPartial struct {
+	// Name must not be blank
+	Name string `json:"name"`
+}
+
 // Book defines model for Book.
 type Book struct {
-	Isbn  int64  `json:"isbn"`
+	// AuthorIds ids of existing authors in the order they are credited. Only read when creating a book, and returned for books rebuilt from their history.
+	AuthorIds *[]int64 `json:"author_ids,omitempty"`
+
+	// Authors the authors in the order they are credited. Only returned when fetching a single book.
+	Authors   *[]Author  `json:"authors,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+
+	// DeletedAt when the book was moved to the trash, if it is trashed
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+	// Etag the version of the book, the same as the ETag header of fetching it
+	Etag *string `json:"etag,omitempty"`
+
+	// Isbn an ISBN-10 or ISBN-13 with or without hyphens. Responses always have the ISBN-13 in its canonical form, which is only hyphenated in the English language groups 978-0 and 978-1. In every other group it is the 13 digits without hyphens, e.g. 9788804668237.
+	Isbn      ISBN       `json:"isbn"`
+	Title     string     `json:"title"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// BookList defines model for BookList.
+type BookList struct {
+	Items         []Book `json:"items"`
+	NextPageToken string `json:"next_page_token"`
+}
+
+// BookPartial defines model for BookPartial.
+type BookPartial struct {
 	Title string `json:"title"`
 }
 
-// BookList defines model for BookList.
-type BookList struct {
-	Items         []Book `json:"items"`
-	NextPageToken string `json:"next_page_token"`
+// Checkout defines model for Checkout.
+type Checkout struct {
+	PatronId int64 `json:"patron_id"`
+}
+
+// ClassChange defines model for ClassChange.
+type ClassChange struct {
+	// Actor the X-Actor header of the request that changed the class
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+	From      string    `json:"from"`
+	Id        int64     `json:"id"`
+	PatronId  int64     `json:"patron_id"`
+	To        string    `json:"to"`
+}
+
+// Copy defines model for Copy.
+type Copy struct {
+	Barcode string `json:"barcode"`
+
+	// Isbn an ISBN-10 or ISBN-13 with or without hyphens. Responses always have the ISBN-13 in its canonical form, which is only hyphenated in the English language groups 978-0 and 978-1. In every other group it is the 13 digits without hyphens, e.g. 9788804668237.
+	Isbn     ISBN        `json:"isbn"`
+	Location string      `json:"location"`
+	Status   *CopyStatus `json:"status,omitempty"`
+}
+
+// CopyPartial defines model for CopyPartial.
+type CopyPartial struct {
+	Location string     `json:"location"`
+	Status   CopyStatus `json:"status"`
+}
+
+// CopyStatus defines model for CopyStatus.
+type CopyStatus string
+
+// DeliveryStatus pending deliveries are retried with a growing backoff until they are delivered or have failed too many times, when they are dead.
+type DeliveryStatus string
+
+// Error defines model for Error.
+type Error struct {
+	// Code the kind of error: 1 Unknown, 2 DatabaseError, 3 BadInput, 4 InvalidSettings, 5 Timeout, 6 NotFound, 7 Conflict, 8 PreconditionFailed, 9 NotImplemented
+	Code int `json:"code"`
+
+	// Fields the invalid fields of a BadInput error, if known
+	Fields  *[]FieldError `json:"fields,omitempty"`
+	Message string        `json:"message"`
+}
+
+// FieldError defines model for FieldError.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FineAccount defines model for FineAccount.
+type FineAccount struct {
+	// Accruing the cents the open overdue loans of the patron would be fined if they were returned now
+	Accruing int64 `json:"accruing"`
+
+	// Balance the cents the patron owes
+	Balance int64 `json:"balance"`
+
+	// ClassChanges the changes to the class of the patron, which picks the fee schedule of their fines, oldest first
+	ClassChanges []ClassChange `json:"class_changes"`
+
+	// Entries the ledger behind the balance, oldest first
+	Entries  []LedgerEntry `json:"entries"`
+	PatronId int64         `json:"patron_id"`
+}
+
+// Hold defines model for Hold.
+type Hold struct {
+	// Barcode the copy set aside on the hold shelf once the hold is ready
+	Barcode *string `json:"barcode,omitempty"`
+	Id      int64   `json:"id"`
+
+	// Isbn an ISBN-10 or ISBN-13 with or without hyphens. Responses always have the ISBN-13 in its canonical form, which is only hyphenated in the English language groups 978-0 and 978-1. In every other group it is the 13 digits without hyphens, e.g. 9788804668237.
+	Isbn     ISBN  `json:"isbn"`
+	PatronId int64 `json:"patron_id"`
+
+	// PickupBy when a ready hold expires and the copy goes to the next patron
+	PickupBy *time.Time `json:"pickup_by,omitempty"`
+	PlacedAt time.Time  `json:"placed_at"`
+
+	// Position the place in the queue, 1 being served next. Holds that aren't waiting have position 0.
+	Position int32      `json:"position"`
+	Status   HoldStatus `json:"status"`
+}
+
+// HoldList defines model for HoldList.
+type HoldList struct {
+	Items []Hold `json:"items"`
+}
+
+// HoldRequest defines model for HoldRequest.
+type HoldRequest struct {
+	PatronId int64 `json:"patron_id"`
+}
+
+// HoldStatus defines model for HoldStatus.
+type HoldStatus string
+
+// ISBN an ISBN-10 or ISBN-13 with or without hyphens. Responses always have the ISBN-13 in its canonical form, which is only hyphenated in the English language groups 978-0 and 978-1. In every other group it is the 13 digits without hyphens, e.g. 9788804668237.
+type ISBN = string
+
+// ImportError defines model for ImportError.
+type ImportError struct {
+	Message string `json:"message"`
+
+	// Row the row number, counting from 1 after any header
+	Row int64 `json:"row"`
+}
+
+// ImportReport defines model for ImportReport.
+type ImportReport struct {
+	// ErrorCount every error, including those not listed
+	ErrorCount int64 `json:"error_count"`
+
+	// Errors the errors of the first 1000 rows with one
+	Errors []ImportError `json:"errors"`
+
+	// Imported books created, or replaced with on_conflict=overwrite
+	Imported int64 `json:"imported"`
+	Rows     int64 `json:"rows"`
+
+	// Skipped rows for books that already exist
+	Skipped int64 `json:"skipped"`
+
+	// Truncated some errors are not listed
+	Truncated bool `json:"truncated"`
+}
+
+// LedgerEntry defines model for LedgerEntry.
+type LedgerEntry struct {
+	// Actor the X-Actor header of the request that recorded the entry
+	Actor string `json:"actor"`
+
+	// Amount cents added to the balance. Fines are positive and payments and waivers are negative.
+	Amount    int64      `json:"amount"`
+	CreatedAt time.Time  `json:"created_at"`
+	Id        int64      `json:"id"`
+	Kind      LedgerKind `json:"kind"`
+
+	// LoanId the overdue loan of a fine
+	LoanId   *int64 `json:"loan_id,omitempty"`
+	Note     string `json:"note"`
+	PatronId int64  `json:"patron_id"`
+}
+
+// LedgerKind defines model for LedgerKind.
+type LedgerKind string
+
+// Loan defines model for Loan.
+type Loan struct {
+	Barcode      string     `json:"barcode"`
+	CheckedOutAt time.Time  `json:"checked_out_at"`
+	DueAt        time.Time  `json:"due_at"`
+	Id           int64      `json:"id"`
+	PatronId     int64      `json:"patron_id"`
+	Renewals     int32      `json:"renewals"`
+	ReturnedAt   *time.Time `json:"returned_at,omitempty"`
+}
+
+// LoanList defines model for LoanList.
+type LoanList struct {
+	Items []Loan `json:"items"`
+}
+
+// PatchOperation defines model for PatchOperation.
+type PatchOperation struct {
+	From *string          `json:"from,omitempty"`
+	Op   PatchOperationOp `json:"op"`
+
+	// Path a JSON Pointer (RFC 6901)
+	Path  string       `json:"path"`
+	Value *interface{} `json:"value,omitempty"`
+}
+
+// PatchOperationOp defines model for PatchOperation.Op.
+type PatchOperationOp string
+
+// Patron defines model for Patron.
+type Patron struct {
+	BorrowingLimit int32  `json:"borrowing_limit"`
+	Class          string `json:"class"`
+	Id             int64  `json:"id"`
+	Name           string `json:"name"`
+}
+
+// PatronPartial defines model for PatronPartial.
+type PatronPartial struct {
+	// BorrowingLimit the most copies the patron may have checked out at once
+	BorrowingLimit int32 `json:"borrowing_limit"`
+
+	// Class picks the fee schedule for overdue fines. Defaults to standard.
+	Class *string `json:"class,omitempty"`
+	Name  string  `json:"name"`
+}
+
+// Payment defines model for Payment.
+type Payment struct {
+	// Amount the cents paid, at most the balance
+	Amount int64   `json:"amount"`
+	Note   *string `json:"note,omitempty"`
+}
+
+// SearchResult defines model for SearchResult.
+type SearchResult struct {
+	// Isbn an ISBN-10 or ISBN-13 with or without hyphens. Responses always have the ISBN-13 in its canonical form, which is only hyphenated in the English language groups 978-0 and 978-1. In every other group it is the 13 digits without hyphens, e.g. 9788804668237.
+	Isbn ISBN `json:"isbn"`
+
+	// Score how well the book matches, higher is better
+	Score float64 `json:"score"`
+
+	// Snippet the title with the matched words wrapped in <b> tags
+	Snippet string `json:"snippet"`
+	Title   string `json:"title"`
+}
+
+// SearchResultList defines model for SearchResultList.
+type SearchResultList struct {
+	Items         []SearchResult `json:"items"`
+	NextPageToken string         `json:"next_page_token"`
+}
+
+// Waiver defines model for Waiver.
+type Waiver struct {
+	// Amount the cents forgiven, at most the balance
+	Amount int64 `json:"amount"`
+
+	// Note why the fines are waived
+	Note string `json:"note"`
+}
+
+// Webhook defines model for Webhook.
+type Webhook struct {
+	Active    bool          `json:"active"`
+	CreatedAt time.Time     `json:"created_at"`
+	Events    []AuditAction `json:"events"`
+	Id        int64         `json:"id"`
+
+	// Secret only returned when the webhook is created
+	Secret *string `json:"secret,omitempty"`
+	Url    string  `json:"url"`
+}
+
+// WebhookDelivery defines model for WebhookDelivery.
+type WebhookDelivery struct {
+	Attempts int32 `json:"attempts"`
+
+	// AuditId the id of the AuditRecord that is sent
+	AuditId       int64       `json:"audit_id"`
+	CreatedAt     time.Time   `json:"created_at"`
+	DeliveredAt   *time.Time  `json:"delivered_at,omitempty"`
+	Event         AuditAction `json:"event"`
+	Id            int64       `json:"id"`
+	LastAttemptAt *time.Time  `json:"last_attempt_at,omitempty"`
+	LastError     string      `json:"last_error"`
+
+	// NextAttemptAt when a pending delivery is tried next
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+
+	// ResponseStatus the HTTP status of the last attempt, or 0 if it got no response
+	ResponseStatus int32 `json:"response_status"`
+
+	// Status pending deliveries are retried with a growing backoff until they are delivered or have failed too many times, when they are dead.
+	Status    DeliveryStatus `json:"status"`
+	WebhookId int64          `json:"webhook_id"`
+}
+
+// WebhookDeliveryList defines model for WebhookDeliveryList.
+type WebhookDeliveryList struct {
+	Items         []WebhookDelivery `json:"items"`
+	NextPageToken string            `json:"next_page_token"`
+}
+
+// WebhookList defines model for WebhookList.
+type WebhookList struct {
+	Items []Webhook `json:"items"`
+}
+
+// WebhookPartial defines model for WebhookPartial.
+type WebhookPartial struct {
+	// Active inactive webhooks are sent nothing until they are active again. Defaults to true.
+	Active *bool `json:"active,omitempty"`
+
+	// Events the actions to send. Every action is sent if it is empty.
+	Events *[]AuditAction `json:"events,omitempty"`
+
+	// Secret signs the payloads
+	Secret *string `json:"secret,omitempty"`
+
+	// Url an http or https url to POST changes to
+	Url string `json:"url"`
+}
+
+// AuthorId defines model for authorId.
+type AuthorId = int64
+
+// Barcode defines model for barcode.
+type Barcode = string
+
+// HoldId defines model for holdId.
+type HoldId = int64
+
+// IncludeDeleted defines model for includeDeleted.
+type IncludeDeleted = bool
+
+// Isbn an ISBN-10 or ISBN-13 with or without hyphens. Responses always have the ISBN-13 in its canonical form, which is only hyphenated in the English language groups 978-0 and 978-1. In every other group it is the 13 digits without hyphens, e.g. 9788804668237.
+type Isbn = ISBN
+
+// PageToken defines model for pageToken.
+type PageToken = string
+
+// PatronId defines model for patronId.
+type PatronId = int64
+
+// TotalSize defines model for totalSize.
+type TotalSize = int32
+
+// WebhookId defines model for webhookId.
+type WebhookId = int64
+
+// Conflict defines model for Conflict.
+type Conflict = Error
+
+// NotFound defines model for NotFound.
+type NotFound = Error
+
+// PreconditionFailed defines model for PreconditionFailed.
+type PreconditionFailed = Error
+
+// ListAuditParams defines parameters for ListAudit.
+type ListAuditParams struct {
+	// PageToken an opaque pagination token returned as next_page_token by the previous page
+	PageToken *PageToken `form:"page_token,omitempty" json:"page_token,omitempty"`
+
+	// TotalSize a pagination limit
+	TotalSize *TotalSize `form:"total_size,omitempty" json:"total_size,omitempty"`
+}
+
+// ListAuthorsParams defines parameters for ListAuthors.
+type ListAuthorsParams struct {
+	// PageToken an opaque pagination token returned as next_page_token by the previous page
+	PageToken *PageToken `form:"page_token,omitempty" json:"page_token,omitempty"`
+
+	// TotalSize a pagination limit
+	TotalSize *TotalSize `form:"total_size,omitempty" json:"total_size,omitempty"`
+}
+
+// ListAuthorBooksParams defines parameters for ListAuthorBooks.
+type ListAuthorBooksParams struct {
+	// PageToken an opaque pagination token returned as next_page_token by the previous page
+	PageToken *PageToken `form:"page_token,omitempty" json:"page_token,omitempty"`
+
+	// TotalSize a pagination limit
+	TotalSize *TotalSize `form:"total_size,omitempty" json:"total_size,omitempty"`
+}
+
+// ListBooksParams defines parameters for ListBooks.
+type ListBooksParams struct {
+	// PageToken an opaque pagination token returned as next_page_token by the previous page
+	PageToken *PageToken `form:"page_token,omitempty" json:"page_token,omitempty"`
+
+	// TotalSize a pagination limit
+	TotalSize *TotalSize `form:"total_size,omitempty" json:"total_size,omitempty"`
+
+	// TitlePrefix only list books whose title starts with this, case sensitive
+	TitlePrefix *string `form:"title_prefix,omitempty" json:"title_prefix,omitempty"`
+
+	// MinIsbn only list books with this isbn or a later one
+	MinIsbn *ISBN `form:"min_isbn,omitempty" json:"min_isbn,omitempty"`
+
+	// MaxIsbn only list books with this isbn or an earlier one
+	MaxIsbn *ISBN `form:"max_isbn,omitempty" json:"max_isbn,omitempty"`
+
+	// CreatedAfter only list books created at or after this time
+	CreatedAfter *time.Time `form:"created_after,omitempty" json:"created_after,omitempty"`
+
+	// CreatedBefore only list books created before this time
+	CreatedBefore *time.Time `form:"created_before,omitempty" json:"created_before,omitempty"`
+
+	// UpdatedAfter only list books last updated at or after this time
+	UpdatedAfter *time.Time `form:"updated_after,omitempty" json:"updated_after,omitempty"`
+
+	// UpdatedBefore only list books last updated before this time
+	UpdatedBefore *time.Time `form:"updated_before,omitempty" json:"updated_before,omitempty"`
+
+	// Sort the field to list books by, ties broken by isbn. Page tokens are only valid for the sort and direction they came from.
+	Sort      *ListBooksParamsSort      `form:"sort,omitempty" json:"sort,omitempty"`
+	Direction *ListBooksParamsDirection `form:"direction,omitempty" json:"direction,omitempty"`
+
+	// IncludeDeleted also return books that are in the trash
+	IncludeDeleted *IncludeDeleted `form:"include_deleted,omitempty" json:"include_deleted,omitempty"`
+}
+
+// ListBooksParamsSort defines parameters for ListBooks.
+type ListBooksParamsSort string
+
+// ListBooksParamsDirection defines parameters for ListBooks.
+type ListBooksParamsDirection string
+
+// StreamBookChangesParams defines parameters for StreamBookChanges.
+type StreamBookChangesParams struct {
+	// LastEventID the id of the last event received before reconnecting.
+	LastEventID *string `json:"Last-Event-ID,omitempty"`
+}
+
+// SearchBooksParams defines parameters for SearchBooks.
+type SearchBooksParams struct {
+	// Q words to search for. Quoted phrases, "or" and -excluded words are supported and a few typos are tolerated.
+	Q string `form:"q" json:"q"`
+
+	// PageToken an opaque pagination token returned as next_page_token by the previous page
+	PageToken *PageToken `form:"page_token,omitempty" json:"page_token,omitempty"`
+
+	// TotalSize a pagination limit
+	TotalSize *TotalSize `form:"total_size,omitempty" json:"total_size,omitempty"`
+}
+
+// FetchBookParams defines parameters for FetchBook.
+type FetchBookParams struct {
+	// IncludeDeleted also return books that are in the trash
+	IncludeDeleted *IncludeDeleted `form:"include_deleted,omitempty" json:"include_deleted,omitempty"`
+
+	// AsOf return the book as it was at this time, rebuilt from its history. Its authors are given as author_ids, and the book may have been in the trash then.
+	AsOf *time.Time `form:"as_of,omitempty" json:"as_of,omitempty"`
+
+	// IfNoneMatch ETags of copies the client already has. The response is 304 with no body if the book still matches one of them.
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
+}
+
+// PatchBookJSONBody defines parameters for PatchBook.
+type PatchBookJSONBody = []PatchOperation
+
+// PatchBookParams defines parameters for PatchBook.
+type PatchBookParams struct {
+	// IfMatch only patch the book if its ETag is one of these, or if it exists for *.
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
+// UpdateBookParams defines parameters for UpdateBook.
+type UpdateBookParams struct {
+	// IfMatch only update the book if its ETag is one of these, or if it exists for *. Without it the book is updated whatever its version.
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
+// ListBookHistoryParams defines parameters for ListBookHistory.
+type ListBookHistoryParams struct {
+	// PageToken an opaque pagination token returned as next_page_token by the previous page
+	PageToken *PageToken `form:"page_token,omitempty" json:"page_token,omitempty"`
+
+	// TotalSize a pagination limit
+	TotalSize *TotalSize `form:"total_size,omitempty" json:"total_size,omitempty"`
+}
+
+// ImportBooksParams defines parameters for ImportBooks.
+type ImportBooksParams struct {
+	// OnConflict what to do with a book that is already in the library. With fail, nothing is imported if any row is an error or already exists.
+	OnConflict *ImportBooksParamsOnConflict `form:"on_conflict,omitempty" json:"on_conflict,omitempty"`
+}
+
+// ImportBooksParamsOnConflict defines parameters for ImportBooks.
+type ImportBooksParamsOnConflict string
+
+// ListWebhookDeliveriesParams defines parameters for ListWebhookDeliveries.
+type ListWebhookDeliveriesParams struct {
+	// Status only list deliveries with this status
+	Status *DeliveryStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// PageToken an opaque pagination token returned as next_page_token by the previous page
+	PageToken *PageToken `form:"page_token,omitempty" json:"page_token,omitempty"`
+
+	// TotalSize a pagination limit
+	TotalSize *TotalSize `form:"total_size,omitempty" json:"total_size,omitempty"`
+}
+
+// CreateAuthorJSONRequestBody defines body for CreateAuthor for application/json ContentType.
+type CreateAuthorJSONRequestBody = AuthorPartial
+
+// UpdateAuthorJSONRequestBody defines body for UpdateAuthor for application/json ContentType.
+type UpdateAuthorJSONRequestBody = AuthorPartial
+
+// CreateBookJSONRequestBody defines body for CreateBook for application/json ContentType.
+type CreateBookJSONRequestBody = Book
+
+// PatchBookJSONRequestBody defines body for PatchBook for application/json-patch+json ContentType.
+type PatchBookJSONRequestBody = PatchBookJSONBody
+
+// UpdateBookJSONRequestBody defines body for UpdateBook for application/json ContentType.
+type UpdateBookJSONRequestBody = BookPartial
+
+// PlaceHoldJSONRequestBody defines body for PlaceHold for application/json ContentType.
+type PlaceHoldJSONRequestBody = HoldRequest
+
+// CreateCopyJSONRequestBody defines body for CreateCopy for application/json ContentType.
+type CreateCopyJSONRequestBody = Copy
+
+// UpdateCopyJSONRequestBody defines body for UpdateCopy for application/json ContentType.
+type UpdateCopyJSONRequestBody = CopyPartial
+
+// CheckoutCopyJSONRequestBody defines body for CheckoutCopy for application/json ContentType.
+type CheckoutCopyJSONRequestBody = Checkout
+
+// CreatePatronJSONRequestBody defines body for CreatePatron for application/json ContentType.
+type CreatePatronJSONRequestBody = PatronPartial
+
+// UpdatePatronJSONRequestBody defines body for UpdatePatron for application/json ContentType.
+type UpdatePatronJSONRequestBody = PatronPartial
+
+// RecordPaymentJSONRequestBody defines body for RecordPayment for application/json ContentType.
+type RecordPaymentJSONRequestBody = Payment
+
+// WaiveFinesJSONRequestBody defines body for WaiveFines for application/json ContentType.
+type WaiveFinesJSONRequestBody = Waiver
+
+// CreateWebhookJSONRequestBody defines body for CreateWebhook for application/json ContentType.
+type CreateWebhookJSONRequestBody = WebhookPartial
+
+// UpdateWebhookJSONRequestBody defines body for UpdateWebhook for application/json ContentType.
+type UpdateWebhookJSONRequestBody = WebhookPartial
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// List the changes to every book, newest first
+	// (GET /audit)
+	ListAudit(w http.ResponseWriter, r *http.Request, params ListAuditParams)
+	// List authors in the library
+	// (GET /authors)
+	ListAuthors(w http.ResponseWriter, r *http.Request, params ListAuthorsParams)
+	// Create an author.
+	// (POST /authors)
+	CreateAuthor(w http.ResponseWriter, r *http.Request)
+	// Delete a single author. Authors of existing books can't be deleted.
+	// (DELETE /authors/{id})
+	DeleteAuthor(w http.ResponseWriter, r *http.Request, id AuthorId)
+	// Fetch a single author
+	// (GET /authors/{id})
+	FetchAuthor(w http.ResponseWriter, r *http.Request, id AuthorId)
+	// Update an author.
+	// (PUT /authors/{id})
+	UpdateAuthor(w http.ResponseWriter, r *http.Request, id AuthorId)
+	// List books by a single author
+	// (GET /authors/{id}/books)
+	ListAuthorBooks(w http.ResponseWriter, r *http.Request, id AuthorId, params ListAuthorBooksParams)
+	// List books in the library
+	// (GET /books)
+	ListBooks(w http.ResponseWriter, r *http.Request, params ListBooksParams)
+	// Create a book.
+	// (POST /books)
+	CreateBook(w http.ResponseWriter, r *http.Request)
+	// Stream changes to books as Server-Sent Events
+	// (GET /books/changes)
+	StreamBookChanges(w http.ResponseWriter, r *http.Request, params StreamBookChangesParams)
+	// Search the titles in the library, best match first
+	// (GET /books/search)
+	SearchBooks(w http.ResponseWriter, r *http.Request, params SearchBooksParams)
+	// Move a single book to the trash
+	// (DELETE /books/{isbn})
+	DeleteBook(w http.ResponseWriter, r *http.Request, isbn Isbn)
+	// Fetch a single book in the library
+	// (GET /books/{isbn})
+	FetchBook(w http.ResponseWriter, r *http.Request, isbn Isbn, params FetchBookParams)
+	// Change some fields of a book.
+	// (PATCH /books/{isbn})
+	PatchBook(w http.ResponseWriter, r *http.Request, isbn Isbn, params PatchBookParams)
+	// Update a book.
+	// (PUT /books/{isbn})
+	UpdateBook(w http.ResponseWriter, r *http.Request, isbn Isbn, params UpdateBookParams)
+	// List the changes to a single book, newest first
+	// (GET /books/{isbn}/history)
+	ListBookHistory(w http.ResponseWriter, r *http.Request, isbn Isbn, params ListBookHistoryParams)
+	// List the active holds on a book in the order they are served
+	// (GET /books/{isbn}/holds)
+	ListBookHolds(w http.ResponseWriter, r *http.Request, isbn Isbn)
+	// Queue a patron for the next copy of a book.
+	// (POST /books/{isbn}/holds)
+	PlaceHold(w http.ResponseWriter, r *http.Request, isbn Isbn)
+	// Take a single book out of the trash
+	// (POST /books/{isbn}:restore)
+	RestoreBook(w http.ResponseWriter, r *http.Request, isbn Isbn)
+	// Download every book in the library
+	// (GET /books:export)
+	ExportBooks(w http.ResponseWriter, r *http.Request)
+	// Create many books at once from CSV or NDJSON
+	// (POST /books:import)
+	ImportBooks(w http.ResponseWriter, r *http.Request, params ImportBooksParams)
+	// Create a physical copy of a book.
+	// (POST /copies)
+	CreateCopy(w http.ResponseWriter, r *http.Request)
+	// Delete a single copy. Copies that have been lent can't be deleted.
+	// (DELETE /copies/{barcode})
+	DeleteCopy(w http.ResponseWriter, r *http.Request, barcode Barcode)
+	// Fetch a single copy
+	// (GET /copies/{barcode})
+	FetchCopy(w http.ResponseWriter, r *http.Request, barcode Barcode)
+	// Update the location or status of a copy.
+	// (PUT /copies/{barcode})
+	UpdateCopy(w http.ResponseWriter, r *http.Request, barcode Barcode)
+	// Lend an available copy to a patron under their borrowing limit.
+	// (POST /copies/{barcode}/checkout)
+	CheckoutCopy(w http.ResponseWriter, r *http.Request, barcode Barcode)
+	// Extend the loan of a copy.
+	// (POST /copies/{barcode}/renew)
+	RenewCopy(w http.ResponseWriter, r *http.Request, barcode Barcode)
+	// Return a copy on loan.
+	// (POST /copies/{barcode}/return)
+	ReturnCopy(w http.ResponseWriter, r *http.Request, barcode Barcode)
+	// Cancel a hold. A copy set aside for it goes to the next patron.
+	// (DELETE /holds/{id})
+	CancelHold(w http.ResponseWriter, r *http.Request, id HoldId)
+	// Create a patron.
+	// (POST /patrons)
+	CreatePatron(w http.ResponseWriter, r *http.Request)
+	// Fetch a single patron
+	// (GET /patrons/{id})
+	FetchPatron(w http.ResponseWriter, r *http.Request, id PatronId)
+	// Update a patron.
+	// (PUT /patrons/{id})
+	UpdatePatron(w http.ResponseWriter, r *http.Request, id PatronId)
+	// Fetch the balance of a patron and every fine, payment and waiver behind it
+	// (GET /patrons/{id}/fines)
+	FetchFineAccount(w http.ResponseWriter, r *http.Request, id PatronId)
+	// List the open loans of a patron, soonest due first
+	// (GET /patrons/{id}/loans)
+	ListPatronLoans(w http.ResponseWriter, r *http.Request, id PatronId)
+	// Record a payment towards the balance of a patron.
+	// (POST /patrons/{id}/payments)
+	RecordPayment(w http.ResponseWriter, r *http.Request, id PatronId)
+	// Forgive part of the balance of a patron.
+	// (POST /patrons/{id}/waivers)
+	WaiveFines(w http.ResponseWriter, r *http.Request, id PatronId)
+	// List the webhooks that are sent changes to books
+	// (GET /webhooks)
+	ListWebhooks(w http.ResponseWriter, r *http.Request)
+	// Subscribe a partner system to changes to books.
+	// (POST /webhooks)
+	CreateWebhook(w http.ResponseWriter, r *http.Request)
+	// Delete a webhook and its deliveries.
+	// (DELETE /webhooks/{id})
+	DeleteWebhook(w http.ResponseWriter, r *http.Request, id WebhookId)
+	// Fetch a single webhook
+	// (GET /webhooks/{id})
+	FetchWebhook(w http.ResponseWriter, r *http.Request, id WebhookId)
+	// Update a webhook.
+	// (PUT /webhooks/{id})
+	UpdateWebhook(w http.ResponseWriter, r *http.Request, id WebhookId)
+	// List the deliveries of a webhook, newest first
+	// (GET /webhooks/{id}/deliveries)
+	ListWebhookDeliveries(w http.ResponseWriter, r *http.Request, id WebhookId, params ListWebhookDeliveriesParams)
+	// Send a delivery again right away
+	// (POST /webhooks/{id}/deliveries/{delivery_id}:redeliver)
+	RedeliverWebhookDelivery(w http.ResponseWriter, r *http.Request, id WebhookId, deliveryId int64)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// ListAudit operation middleware
+func (siw *ServerInterfaceWrapper) ListAudit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListAuditParams
+
+	// ------------- Optional query parameter "page_token" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_token", r.URL.Query(), &params.PageToken)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page_token", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "total_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "total_size", r.URL.Query(), &params.TotalSize)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "total_size", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListAudit(w, r, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListAuthors operation middleware
+func (siw *ServerInterfaceWrapper) ListAuthors(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListAuthorsParams
+
+	// ------------- Optional query parameter "page_token" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_token", r.URL.Query(), &params.PageToken)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page_token", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "total_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "total_size", r.URL.Query(), &params.TotalSize)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "total_size", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListAuthors(w, r, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreateAuthor operation middleware
+func (siw *ServerInterfaceWrapper) CreateAuthor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateAuthor(w, r)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteAuthor operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAuthor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AuthorId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteAuthor(w, r, id)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// FetchAuthor operation middleware
+func (siw *ServerInterfaceWrapper) FetchAuthor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AuthorId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.FetchAuthor(w, r, id)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UpdateAuthor operation middleware
+func (siw *ServerInterfaceWrapper) UpdateAuthor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AuthorId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateAuthor(w, r, id)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListAuthorBooks operation middleware
+func (siw *ServerInterfaceWrapper) ListAuthorBooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AuthorId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListAuthorBooksParams
+
+	// ------------- Optional query parameter "page_token" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_token", r.URL.Query(), &params.PageToken)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page_token", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "total_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "total_size", r.URL.Query(), &params.TotalSize)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "total_size", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListAuthorBooks(w, r, id, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListBooks operation middleware
+func (siw *ServerInterfaceWrapper) ListBooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListBooksParams
+
+	// ------------- Optional query parameter "page_token" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_token", r.URL.Query(), &params.PageToken)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page_token", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "total_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "total_size", r.URL.Query(), &params.TotalSize)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "total_size", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "title_prefix" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "title_prefix", r.URL.Query(), &params.TitlePrefix)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "title_prefix", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "min_isbn" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "min_isbn", r.URL.Query(), &params.MinIsbn)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "min_isbn", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "max_isbn" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "max_isbn", r.URL.Query(), &params.MaxIsbn)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "max_isbn", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "created_after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_after", r.URL.Query(), &params.CreatedAfter)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "created_after", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "created_before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_before", r.URL.Query(), &params.CreatedBefore)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "created_before", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "updated_after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "updated_after", r.URL.Query(), &params.UpdatedAfter)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "updated_after", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "updated_before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "updated_before", r.URL.Query(), &params.UpdatedBefore)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "updated_before", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "direction" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "direction", r.URL.Query(), &params.Direction)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "direction", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "include_deleted" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include_deleted", r.URL.Query(), &params.IncludeDeleted)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include_deleted", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListBooks(w, r, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreateBook operation middleware
+func (siw *ServerInterfaceWrapper) CreateBook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateBook(w, r)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// StreamBookChanges operation middleware
+func (siw *ServerInterfaceWrapper) StreamBookChanges(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params StreamBookChangesParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Last-Event-ID" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Last-Event-ID")]; found {
+		var LastEventID string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Last-Event-ID", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Last-Event-ID", runtime.ParamLocationHeader, valueList[0], &LastEventID)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Last-Event-ID", Err: err})
+			return
+		}
+
+		params.LastEventID = &LastEventID
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.StreamBookChanges(w, r, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// SearchBooks operation middleware
+func (siw *ServerInterfaceWrapper) SearchBooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SearchBooksParams
+
+	// ------------- Required query parameter "q" -------------
+
+	if paramValue := r.URL.Query().Get("q"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "q"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "q", r.URL.Query(), &params.Q)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "q", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "page_token" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_token", r.URL.Query(), &params.PageToken)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page_token", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "total_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "total_size", r.URL.Query(), &params.TotalSize)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "total_size", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SearchBooks(w, r, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteBook operation middleware
+func (siw *ServerInterfaceWrapper) DeleteBook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "isbn" -------------
+	var isbn Isbn
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "isbn", runtime.ParamLocationPath, chi.URLParam(r, "isbn"), &isbn)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "isbn", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteBook(w, r, isbn)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// FetchBook operation middleware
+func (siw *ServerInterfaceWrapper) FetchBook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "isbn" -------------
+	var isbn Isbn
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "isbn", runtime.ParamLocationPath, chi.URLParam(r, "isbn"), &isbn)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "isbn", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params FetchBookParams
+
+	// ------------- Optional query parameter "include_deleted" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include_deleted", r.URL.Query(), &params.IncludeDeleted)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include_deleted", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "as_of" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "as_of", r.URL.Query(), &params.AsOf)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "as_of", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-None-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-None-Match", runtime.ParamLocationHeader, valueList[0], &IfNoneMatch)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-None-Match", Err: err})
+			return
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.FetchBook(w, r, isbn, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PatchBook operation middleware
+func (siw *ServerInterfaceWrapper) PatchBook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "isbn" -------------
+	var isbn Isbn
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "isbn", runtime.ParamLocationPath, chi.URLParam(r, "isbn"), &isbn)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "isbn", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchBookParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PatchBook(w, r, isbn, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UpdateBook operation middleware
+func (siw *ServerInterfaceWrapper) UpdateBook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "isbn" -------------
+	var isbn Isbn
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "isbn", runtime.ParamLocationPath, chi.URLParam(r, "isbn"), &isbn)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "isbn", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UpdateBookParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateBook(w, r, isbn, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListBookHistory operation middleware
+func (siw *ServerInterfaceWrapper) ListBookHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "isbn" -------------
+	var isbn Isbn
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "isbn", runtime.ParamLocationPath, chi.URLParam(r, "isbn"), &isbn)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "isbn", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListBookHistoryParams
+
+	// ------------- Optional query parameter "page_token" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_token", r.URL.Query(), &params.PageToken)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page_token", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "total_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "total_size", r.URL.Query(), &params.TotalSize)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "total_size", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListBookHistory(w, r, isbn, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListBookHolds operation middleware
+func (siw *ServerInterfaceWrapper) ListBookHolds(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "isbn" -------------
+	var isbn Isbn
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "isbn", runtime.ParamLocationPath, chi.URLParam(r, "isbn"), &isbn)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "isbn", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListBookHolds(w, r, isbn)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PlaceHold operation middleware
+func (siw *ServerInterfaceWrapper) PlaceHold(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "isbn" -------------
+	var isbn Isbn
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "isbn", runtime.ParamLocationPath, chi.URLParam(r, "isbn"), &isbn)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "isbn", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PlaceHold(w, r, isbn)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RestoreBook operation middleware
+func (siw *ServerInterfaceWrapper) RestoreBook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "isbn" -------------
+	var isbn Isbn
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "isbn", runtime.ParamLocationPath, chi.URLParam(r, "isbn"), &isbn)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "isbn", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RestoreBook(w, r, isbn)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ExportBooks operation middleware
+func (siw *ServerInterfaceWrapper) ExportBooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ExportBooks(w, r)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ImportBooks operation middleware
+func (siw *ServerInterfaceWrapper) ImportBooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ImportBooksParams
+
+	// ------------- Optional query parameter "on_conflict" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "on_conflict", r.URL.Query(), &params.OnConflict)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "on_conflict", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ImportBooks(w, r, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreateCopy operation middleware
+func (siw *ServerInterfaceWrapper) CreateCopy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateCopy(w, r)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteCopy operation middleware
+func (siw *ServerInterfaceWrapper) DeleteCopy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "barcode" -------------
+	var barcode Barcode
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "barcode", runtime.ParamLocationPath, chi.URLParam(r, "barcode"), &barcode)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "barcode", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteCopy(w, r, barcode)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// FetchCopy operation middleware
+func (siw *ServerInterfaceWrapper) FetchCopy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "barcode" -------------
+	var barcode Barcode
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "barcode", runtime.ParamLocationPath, chi.URLParam(r, "barcode"), &barcode)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "barcode", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.FetchCopy(w, r, barcode)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UpdateCopy operation middleware
+func (siw *ServerInterfaceWrapper) UpdateCopy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "barcode" -------------
+	var barcode Barcode
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "barcode", runtime.ParamLocationPath, chi.URLParam(r, "barcode"), &barcode)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "barcode", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateCopy(w, r, barcode)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CheckoutCopy operation middleware
+func (siw *ServerInterfaceWrapper) CheckoutCopy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "barcode" -------------
+	var barcode Barcode
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "barcode", runtime.ParamLocationPath, chi.URLParam(r, "barcode"), &barcode)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "barcode", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CheckoutCopy(w, r, barcode)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RenewCopy operation middleware
+func (siw *ServerInterfaceWrapper) RenewCopy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "barcode" -------------
+	var barcode Barcode
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "barcode", runtime.ParamLocationPath, chi.URLParam(r, "barcode"), &barcode)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "barcode", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RenewCopy(w, r, barcode)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ReturnCopy operation middleware
+func (siw *ServerInterfaceWrapper) ReturnCopy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "barcode" -------------
+	var barcode Barcode
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "barcode", runtime.ParamLocationPath, chi.URLParam(r, "barcode"), &barcode)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "barcode", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ReturnCopy(w, r, barcode)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CancelHold operation middleware
+func (siw *ServerInterfaceWrapper) CancelHold(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id HoldId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CancelHold(w, r, id)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreatePatron operation middleware
+func (siw *ServerInterfaceWrapper) CreatePatron(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreatePatron(w, r)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// FetchPatron operation middleware
+func (siw *ServerInterfaceWrapper) FetchPatron(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id PatronId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.FetchPatron(w, r, id)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UpdatePatron operation middleware
+func (siw *ServerInterfaceWrapper) UpdatePatron(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id PatronId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdatePatron(w, r, id)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// FetchFineAccount operation middleware
+func (siw *ServerInterfaceWrapper) FetchFineAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id PatronId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.FetchFineAccount(w, r, id)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// BookPartial defines model for BookPartial.
-type BookPartial struct {
-	Title string `json:"title"`
-}
+// ListPatronLoans operation middleware
+func (siw *ServerInterfaceWrapper) ListPatronLoans(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-// Error defines model for Error.
-type Error struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-}
+	var err error
 
-// Isbn defines model for isbn.
-type Isbn = int64
+	// ------------- Path parameter "id" -------------
+	var id PatronId
 
-// PageToken defines model for pageToken.
-type PageToken = string
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
 
-// TotalSize defines model for totalSize.
-type TotalSize = int32
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListPatronLoans(w, r, id)
+	})
 
-// ListBooksParams defines parameters for ListBooks.
-type ListBooksParams struct {
-	// PageToken a pagination placeholder
-	PageToken *PageToken `form:"page_token,omitempty" json:"page_token,omitempty"`
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-	// TotalSize a pagination limit
-	TotalSize *TotalSize `form:"total_size,omitempty" json:"total_size,omitempty"`
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// CreateBookJSONRequestBody defines body for CreateBook for application/json ContentType.
-type CreateBookJSONRequestBody = Book
+// RecordPayment operation middleware
+func (siw *ServerInterfaceWrapper) RecordPayment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-// UpdateBookJSONRequestBody defines body for UpdateBook for application/json ContentType.
-type UpdateBookJSONRequestBody = BookPartial
+	var err error
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
-	// List books in the library
-	// (GET /books)
-	ListBooks(w http.ResponseWriter, r *http.Request, params ListBooksParams)
-	// Create a book.
-	// (POST /books)
-	CreateBook(w http.ResponseWriter, r *http.Request)
-	// Delete a single book from the library
-	// (DELETE /books/{isbn})
-	DeleteBook(w http.ResponseWriter, r *http.Request, isbn Isbn)
-	// Fetch a single book in the library
-	// (GET /books/{isbn})
-	FetchBook(w http.ResponseWriter, r *http.Request, isbn Isbn)
-	// Update a book.
-	// (PUT /books/{isbn})
-	UpdateBook(w http.ResponseWriter, r *http.Request, isbn Isbn)
-}
+	// ------------- Path parameter "id" -------------
+	var id PatronId
 
-// ServerInterfaceWrapper converts contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler            ServerInterface
-	HandlerMiddlewares []MiddlewareFunc
-	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
-}
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
 
-type MiddlewareFunc func(http.Handler) http.Handler
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RecordPayment(w, r, id)
+	})
 
-// ListBooks operation middleware
-func (siw *ServerInterfaceWrapper) ListBooks(w http.ResponseWriter, r *http.Request) {
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// WaiveFines operation middleware
+func (siw *ServerInterfaceWrapper) WaiveFines(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params ListBooksParams
-
-	// ------------- Optional query parameter "page_token" -------------
+	// ------------- Path parameter "id" -------------
+	var id PatronId
 
-	err = runtime.BindQueryParameter("form", true, false, "page_token", r.URL.Query(), &params.PageToken)
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page_token", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
 		return
 	}
 
-	// ------------- Optional query parameter "total_size" -------------
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.WaiveFines(w, r, id)
+	})
 
-	err = runtime.BindQueryParameter("form", true, false, "total_size", r.URL.Query(), &params.TotalSize)
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListWebhooks operation middleware
+func (siw *ServerInterfaceWrapper) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListWebhooks(w, r)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreateWebhook operation middleware
+func (siw *ServerInterfaceWrapper) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateWebhook(w, r)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteWebhook operation middleware
+func (siw *ServerInterfaceWrapper) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id WebhookId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "total_size", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
 		return
 	}
 
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ListBooks(w, r, params)
+		siw.Handler.DeleteWebhook(w, r, id)
 	})
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -122,12 +2050,23 @@ func (siw *ServerInterfaceWrapper) ListBooks(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// CreateBook operation middleware
-func (siw *ServerInterfaceWrapper) CreateBook(w http.ResponseWriter, r *http.Request) {
+// FetchWebhook operation middleware
+func (siw *ServerInterfaceWrapper) FetchWebhook(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id WebhookId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CreateBook(w, r)
+		siw.Handler.FetchWebhook(w, r, id)
 	})
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -137,23 +2076,23 @@ func (siw *ServerInterfaceWrapper) CreateBook(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// DeleteBook operation middleware
-func (siw *ServerInterfaceWrapper) DeleteBook(w http.ResponseWriter, r *http.Request) {
+// UpdateWebhook operation middleware
+func (siw *ServerInterfaceWrapper) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "isbn" -------------
-	var isbn Isbn
+	// ------------- Path parameter "id" -------------
+	var id WebhookId
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "isbn", runtime.ParamLocationPath, chi.URLParam(r, "isbn"), &isbn)
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "isbn", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
 		return
 	}
 
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteBook(w, r, isbn)
+		siw.Handler.UpdateWebhook(w, r, id)
 	})
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -163,23 +2102,50 @@ func (siw *ServerInterfaceWrapper) DeleteBook(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// FetchBook operation middleware
-func (siw *ServerInterfaceWrapper) FetchBook(w http.ResponseWriter, r *http.Request) {
+// ListWebhookDeliveries operation middleware
+func (siw *ServerInterfaceWrapper) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "isbn" -------------
-	var isbn Isbn
+	// ------------- Path parameter "id" -------------
+	var id WebhookId
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "isbn", runtime.ParamLocationPath, chi.URLParam(r, "isbn"), &isbn)
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "isbn", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListWebhookDeliveriesParams
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "page_token" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_token", r.URL.Query(), &params.PageToken)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page_token", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "total_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "total_size", r.URL.Query(), &params.TotalSize)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "total_size", Err: err})
 		return
 	}
 
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.FetchBook(w, r, isbn)
+		siw.Handler.ListWebhookDeliveries(w, r, id, params)
 	})
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -189,23 +2155,32 @@ func (siw *ServerInterfaceWrapper) FetchBook(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// UpdateBook operation middleware
-func (siw *ServerInterfaceWrapper) UpdateBook(w http.ResponseWriter, r *http.Request) {
+// RedeliverWebhookDelivery operation middleware
+func (siw *ServerInterfaceWrapper) RedeliverWebhookDelivery(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "isbn" -------------
-	var isbn Isbn
+	// ------------- Path parameter "id" -------------
+	var id WebhookId
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "isbn", runtime.ParamLocationPath, chi.URLParam(r, "isbn"), &isbn)
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "isbn", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "delivery_id" -------------
+	var deliveryId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "delivery_id", runtime.ParamLocationPath, chi.URLParam(r, "delivery_id"), &deliveryId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "delivery_id", Err: err})
 		return
 	}
 
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.UpdateBook(w, r, isbn)
+		siw.Handler.RedeliverWebhookDelivery(w, r, id, deliveryId)
 	})
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -328,21 +2303,135 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/audit", wrapper.ListAudit)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/authors", wrapper.ListAuthors)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/authors", wrapper.CreateAuthor)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/authors/{id}", wrapper.DeleteAuthor)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/authors/{id}", wrapper.FetchAuthor)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/authors/{id}", wrapper.UpdateAuthor)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/authors/{id}/books", wrapper.ListAuthorBooks)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/books", wrapper.ListBooks)
 	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/books", wrapper.CreateBook)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/books/changes", wrapper.StreamBookChanges)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/books/search", wrapper.SearchBooks)
+	})
 	r.Group(func(r chi.Router) {
 		r.Delete(options.BaseURL+"/books/{isbn}", wrapper.DeleteBook)
 	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/books/{isbn}", wrapper.FetchBook)
 	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/books/{isbn}", wrapper.PatchBook)
+	})
 	r.Group(func(r chi.Router) {
 		r.Put(options.BaseURL+"/books/{isbn}", wrapper.UpdateBook)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/books/{isbn}/history", wrapper.ListBookHistory)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/books/{isbn}/holds", wrapper.ListBookHolds)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/books/{isbn}/holds", wrapper.PlaceHold)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/books/{isbn}:restore", wrapper.RestoreBook)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/books:export", wrapper.ExportBooks)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/books:import", wrapper.ImportBooks)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/copies", wrapper.CreateCopy)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/copies/{barcode}", wrapper.DeleteCopy)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/copies/{barcode}", wrapper.FetchCopy)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/copies/{barcode}", wrapper.UpdateCopy)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/copies/{barcode}/checkout", wrapper.CheckoutCopy)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/copies/{barcode}/renew", wrapper.RenewCopy)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/copies/{barcode}/return", wrapper.ReturnCopy)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/holds/{id}", wrapper.CancelHold)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/patrons", wrapper.CreatePatron)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/patrons/{id}", wrapper.FetchPatron)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/patrons/{id}", wrapper.UpdatePatron)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/patrons/{id}/fines", wrapper.FetchFineAccount)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/patrons/{id}/loans", wrapper.ListPatronLoans)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/patrons/{id}/payments", wrapper.RecordPayment)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/patrons/{id}/waivers", wrapper.WaiveFines)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/webhooks", wrapper.ListWebhooks)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/webhooks", wrapper.CreateWebhook)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/webhooks/{id}", wrapper.DeleteWebhook)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/webhooks/{id}", wrapper.FetchWebhook)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/webhooks/{id}", wrapper.UpdateWebhook)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/webhooks/{id}/deliveries", wrapper.ListWebhookDeliveries)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/webhooks/{id}/deliveries/{delivery_id}:redeliver", wrapper.RedeliverWebhookDelivery)
+	})
 
 	return r
 }
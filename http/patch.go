@@ -0,0 +1,171 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/jsonpatch"
+)
+
+// patchableBook is a book as a patch sees it. Only title and author_ids can
+// be changed.
+type patchableBook struct {
+	Isbn      string    `json:"isbn"`
+	Title     string    `json:"title"`
+	AuthorIds []int64   `json:"author_ids"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Etag      string    `json:"etag"`
+}
+
+var readOnlyFields = []string{"isbn", "created_at", "updated_at", "etag"}
+
+// A bookPatch applies a JSON Merge Patch or a JSON Patch to a book.
+type bookPatch struct {
+	merge bool
+	patch []byte
+}
+
+func toPatchableBook(b library.Book) patchableBook {
+	result := patchableBook{
+		Isbn:      b.ISBN.String(),
+		Title:     b.Title,
+		AuthorIds: make([]int64, 0, len(b.Authors)),
+		CreatedAt: b.CreatedAt,
+		UpdatedAt: b.UpdatedAt,
+		Etag:      etag(b.Version),
+	}
+	for _, a := range b.Authors {
+		result.AuthorIds = append(result.AuthorIds, a.ID)
+	}
+	return result
+}
+
+// Apply patches the book and checks every field of the result.
+func (p bookPatch) Apply(book library.Book) (library.Book, error) {
+	original, err := json.Marshal(toPatchableBook(book))
+	if err != nil {
+		return library.Book{}, err
+	}
+	var doc []byte
+	if p.merge {
+		doc, err = jsonpatch.MergePatch(original, p.patch)
+	} else {
+		doc, err = jsonpatch.Apply(original, p.patch)
+	}
+	if err != nil {
+		return library.Book{}, err
+	}
+	var before, after map[string]json.RawMessage
+	err = json.Unmarshal(original, &before)
+	if err != nil {
+		return library.Book{}, err
+	}
+	err = json.Unmarshal(doc, &after)
+	if err != nil {
+		return library.Book{}, &library.Error{
+			Type:   library.BadInput,
+			Actual: fmt.Errorf("the patched book is not an object"),
+			Desc:   "while patching a book",
+		}
+	}
+
+	var fieldErrors library.FieldErrors
+	for _, field := range readOnlyFields {
+		if !bytes.Equal(before[field], after[field]) {
+			fieldErrors = append(fieldErrors, library.FieldError{Field: field, Message: "is read-only"})
+		}
+	}
+	for field := range after {
+		if _, ok := before[field]; !ok {
+			fieldErrors = append(fieldErrors, library.FieldError{Field: field, Message: "is not a field of a book"})
+		}
+	}
+	patched := library.Book{
+		ISBN:      book.ISBN,
+		CreatedAt: book.CreatedAt,
+		UpdatedAt: book.UpdatedAt,
+		Version:   book.Version,
+	}
+	title, ok := after["title"]
+	switch {
+	case !ok:
+		fieldErrors = append(fieldErrors, library.FieldError{Field: "title", Message: "is required"})
+	case json.Unmarshal(title, &patched.Title) != nil:
+		fieldErrors = append(fieldErrors, library.FieldError{Field: "title", Message: "must be a string"})
+	case strings.TrimSpace(patched.Title) == "":
+		fieldErrors = append(fieldErrors, library.FieldError{Field: "title", Message: "must not be blank"})
+	}
+	var ids []int64
+	if authorIDs, ok := after["author_ids"]; ok && json.Unmarshal(authorIDs, &ids) != nil {
+		fieldErrors = append(fieldErrors, library.FieldError{Field: "author_ids", Message: "must be an array of author ids"})
+	}
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			fieldErrors = append(fieldErrors, library.FieldError{Field: "author_ids", Message: fmt.Sprintf("author %d is listed more than once", id)})
+		}
+		seen[id] = true
+		patched.Authors = append(patched.Authors, library.Author{ID: id})
+	}
+	if len(fieldErrors) > 0 {
+		return library.Book{}, &library.Error{
+			Type:   library.BadInput,
+			Actual: fieldErrors,
+			Desc:   "while patching a book",
+		}
+	}
+	return patched, nil
+}
+
+// PatchBook changes some fields of a book with a JSON Merge Patch or a JSON
+// Patch.
+func (s *Server) PatchBook(w http.ResponseWriter, r *http.Request, isbn Isbn, params PatchBookParams) {
+	ctx := r.Context()
+	parsed, err := library.ParseISBN(isbn)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	var patch bookPatch
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch {
+	case err == nil && mediaType == "application/merge-patch+json":
+		patch.merge = true
+	case err == nil && mediaType == "application/json-patch+json":
+	default:
+		s.reportError(ctx, w, &library.Error{
+			Type:   library.BadInput,
+			Actual: fmt.Errorf("unsupported content type %q", r.Header.Get("Content-Type")),
+			Desc:   "while parsing request body",
+		})
+		return
+	}
+	patch.patch, err = io.ReadAll(r.Body)
+	if err != nil {
+		s.reportError(ctx, w, &library.Error{
+			Type:   library.BadInput,
+			Desc:   "while parsing request body",
+			Actual: err,
+		})
+		return
+	}
+	version, err := s.matchVersion(ctx, parsed, params.IfMatch)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	book, err := s.BookCRUDController.PatchBook(ctx, parsed, version, patch)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	s.serializeBook(ctx, w, book, nil)
+}
@@ -0,0 +1,229 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/slcjordan/library"
+)
+
+var auditActions = map[library.AuditAction]bool{
+	library.AuditCreate:  true,
+	library.AuditUpdate:  true,
+	library.AuditDelete:  true,
+	library.AuditRestore: true,
+	library.AuditPurge:   true,
+}
+
+var deliveryStatuses = map[library.DeliveryStatus]bool{
+	library.DeliveryPending:   true,
+	library.DeliveryDelivered: true,
+	library.DeliveryDead:      true,
+}
+
+// toWebhook leaves out the secret, which is only returned when a webhook is
+// created.
+func toWebhook(w library.Webhook) Webhook {
+	result := Webhook{
+		Id:        w.ID,
+		Url:       w.URL,
+		Events:    make([]AuditAction, 0, len(w.Events)),
+		Active:    w.Active,
+		CreatedAt: w.CreatedAt,
+	}
+	for _, e := range w.Events {
+		result.Events = append(result.Events, AuditAction(e))
+	}
+	return result
+}
+
+func toWebhookDelivery(d library.WebhookDelivery) WebhookDelivery {
+	result := WebhookDelivery{
+		Id:             d.ID,
+		WebhookId:      d.WebhookID,
+		AuditId:        d.AuditID,
+		Event:          AuditAction(d.Event),
+		Status:         DeliveryStatus(d.Status),
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+	}
+	if d.Status == library.DeliveryPending {
+		result.NextAttemptAt = &d.NextAttemptAt
+	}
+	if !d.LastAttemptAt.IsZero() {
+		result.LastAttemptAt = &d.LastAttemptAt
+	}
+	if !d.DeliveredAt.IsZero() {
+		result.DeliveredAt = &d.DeliveredAt
+	}
+	return result
+}
+
+// fromWebhookPartial reads a webhook from a request body, with FieldErrors
+// for a url that can't be posted to and unknown events.
+func fromWebhookPartial(r *http.Request) (library.Webhook, error) {
+	var partial WebhookPartial
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&partial)
+	if err != nil {
+		return library.Webhook{}, &library.Error{
+			Type:   library.BadInput,
+			Desc:   "while parsing request body",
+			Actual: err,
+		}
+	}
+	webhook := library.Webhook{
+		URL:    partial.Url,
+		Secret: fromPtr(partial.Secret, ""),
+		Active: fromPtr(partial.Active, true),
+	}
+	var fieldErrors library.FieldErrors
+	parsed, err := url.Parse(partial.Url)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		fieldErrors = append(fieldErrors, library.FieldError{
+			Field:   "url",
+			Message: "must be an absolute http or https url",
+		})
+	}
+	for _, e := range fromPtr(partial.Events, nil) {
+		if !auditActions[library.AuditAction(e)] {
+			fieldErrors = append(fieldErrors, library.FieldError{
+				Field:   "events",
+				Message: fmt.Sprintf("unknown event %q", e),
+			})
+			continue
+		}
+		webhook.Events = append(webhook.Events, library.AuditAction(e))
+	}
+	if len(fieldErrors) > 0 {
+		return library.Webhook{}, &library.Error{
+			Type:   library.BadInput,
+			Desc:   "while validating a webhook",
+			Actual: fieldErrors,
+		}
+	}
+	return webhook, nil
+}
+
+// ListWebhooks returns every webhook.
+func (s *Server) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	webhooks, err := s.WebhookController.ListWebhooks(ctx)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	result := WebhookList{
+		Items: make([]Webhook, 0, len(webhooks)),
+	}
+	for _, webhook := range webhooks {
+		result.Items = append(result.Items, toWebhook(webhook))
+	}
+	s.serialize(ctx, w, result)
+}
+
+// CreateWebhook subscribes a partner system to changes to books. The
+// response is the only one with the secret.
+func (s *Server) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	webhook, err := fromWebhookPartial(r)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	created, err := s.WebhookController.CreateWebhook(ctx, webhook)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	result := toWebhook(created)
+	result.Secret = &created.Secret
+	w.WriteHeader(http.StatusCreated)
+	s.serialize(ctx, w, result)
+}
+
+// FetchWebhook handles fetching a webhook.
+func (s *Server) FetchWebhook(w http.ResponseWriter, r *http.Request, id WebhookId) {
+	ctx := r.Context()
+	webhook, err := s.WebhookController.GetWebhook(ctx, id)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	s.serialize(ctx, w, toWebhook(webhook))
+}
+
+// UpdateWebhook handles updating a webhook.
+func (s *Server) UpdateWebhook(w http.ResponseWriter, r *http.Request, id WebhookId) {
+	ctx := r.Context()
+	webhook, err := fromWebhookPartial(r)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	webhook.ID = id
+	updated, err := s.WebhookController.UpdateWebhook(ctx, webhook)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	s.serialize(ctx, w, toWebhook(updated))
+}
+
+// DeleteWebhook handles deleting a webhook.
+func (s *Server) DeleteWebhook(w http.ResponseWriter, r *http.Request, id WebhookId) {
+	ctx := r.Context()
+	err := s.WebhookController.DeleteWebhook(ctx, id)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries returns the delivery log of a webhook.
+func (s *Server) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request, id WebhookId, params ListWebhookDeliveriesParams) {
+	ctx := r.Context()
+	status := library.DeliveryStatus(fromPtr(params.Status, ""))
+	if status != "" && !deliveryStatuses[status] {
+		s.reportError(ctx, w, &library.Error{
+			Type:   library.BadInput,
+			Desc:   "while listing webhook deliveries",
+			Actual: fmt.Errorf("unknown status %q", status),
+		})
+		return
+	}
+	totalSize, err := checkTotalSize(params.TotalSize)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	deliveries, err := s.WebhookController.ListWebhookDeliveries(ctx, id, status, fromPtr(params.PageToken, ""), totalSize)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	result := WebhookDeliveryList{
+		Items:         make([]WebhookDelivery, 0, len(deliveries.Deliveries)),
+		NextPageToken: deliveries.NextPageToken,
+	}
+	for _, d := range deliveries.Deliveries {
+		result.Items = append(result.Items, toWebhookDelivery(d))
+	}
+	s.serialize(ctx, w, result)
+}
+
+// RedeliverWebhookDelivery queues a delivery to be sent again.
+func (s *Server) RedeliverWebhookDelivery(w http.ResponseWriter, r *http.Request, id WebhookId, deliveryId int64) {
+	ctx := r.Context()
+	delivery, err := s.WebhookController.RedeliverWebhookDelivery(ctx, id, deliveryId)
+	if err != nil {
+		s.reportError(ctx, w, err)
+		return
+	}
+	s.serialize(ctx, w, toWebhookDelivery(delivery))
+}
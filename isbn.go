@@ -0,0 +1,215 @@
+package library
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// An ISBN identifies a book. It holds the 13 digits of the ISBN-13 so that
+// ISBN-10s, which may start with zeros, convert back and forth without loss.
+// The zero ISBN is invalid.
+type ISBN int64
+
+// An isbnRange gives the length of the element that a registration group or
+// registrant starts with, based on the next seven digits of an ISBN.
+type isbnRange struct {
+	low    int
+	high   int
+	length int
+}
+
+// isbnGroups are the registration groups of each ISBN-13 prefix.
+var isbnGroups = map[string][]isbnRange{
+	"978": {
+		{0, 5999999, 1},
+		{6000000, 6499999, 3},
+		{6500000, 6599999, 2},
+		{7000000, 7999999, 1},
+		{8000000, 9499999, 2},
+		{9500000, 9899999, 3},
+		{9900000, 9989999, 4},
+		{9990000, 9999999, 5},
+	},
+	"979": {
+		{1000000, 1299999, 2},
+		{8000000, 8999999, 1},
+	},
+}
+
+// isbnRegistrants are the registrant ranges of the English language groups,
+// which most of the collection is published in. ISBNs of other groups aren't
+// hyphenated since the registrant can't be told from the publication.
+var isbnRegistrants = map[string][]isbnRange{
+	"978-0": {
+		{0, 1999999, 2},
+		{2000000, 6999999, 3},
+		{7000000, 8499999, 4},
+		{8500000, 8999999, 5},
+		{9000000, 9499999, 6},
+		{9500000, 9999999, 7},
+	},
+	"978-1": {
+		{0, 999999, 2},
+		{1000000, 3999999, 3},
+		{4000000, 5499999, 4},
+		{5500000, 8697999, 5},
+		{8698000, 9989999, 6},
+		{9990000, 9999999, 7},
+	},
+}
+
+func elementLength(ranges []isbnRange, digits string) int {
+	n, _ := strconv.Atoi((digits + "000000")[:7])
+	for _, r := range ranges {
+		if r.low <= n && n <= r.high {
+			return r.length
+		}
+	}
+	return 0
+}
+
+func isbnError(s string, format string, args ...any) error {
+	return &Error{
+		Type:   BadInput,
+		Actual: fmt.Errorf(format, args...),
+		Desc:   fmt.Sprintf("while parsing isbn %q", s),
+	}
+}
+
+func isbn13CheckDigit(digits string) byte {
+	var sum int
+	for i, d := range digits[:12] {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(d-'0')
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isbn10CheckDigit(digits string) byte {
+	var sum int
+	for i, d := range digits[:9] {
+		sum += (10 - i) * int(d-'0')
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+	return byte('0' + check)
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseISBN parses an ISBN-10 or ISBN-13 with or without hyphens or spaces
+// and verifies its check digit.
+func ParseISBN(s string) (ISBN, error) {
+	digits := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(s))
+	switch len(digits) {
+	case 10:
+		if !isDigits(digits[:9]) {
+			return 0, isbnError(s, "an isbn-10 is 9 digits and a check digit")
+		}
+		if digits[9] != isbn10CheckDigit(digits) {
+			return 0, isbnError(s, "the check digit should be %c", isbn10CheckDigit(digits))
+		}
+		digits = "978" + digits[:9]
+		digits += string(isbn13CheckDigit(digits + "0"))
+	case 13:
+		if !isDigits(digits) {
+			return 0, isbnError(s, "an isbn-13 is 13 digits")
+		}
+		if !strings.HasPrefix(digits, "978") && !strings.HasPrefix(digits, "979") {
+			return 0, isbnError(s, "an isbn-13 starts with 978 or 979")
+		}
+		if digits[12] != isbn13CheckDigit(digits) {
+			return 0, isbnError(s, "the check digit should be %c", isbn13CheckDigit(digits))
+		}
+	default:
+		return 0, isbnError(s, "an isbn has 10 or 13 digits but it has %d", len(digits))
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, isbnError(s, "%s", err)
+	}
+	return ISBN(n), nil
+}
+
+// NewISBN validates the digits of an ISBN-13.
+func NewISBN(n int64) (ISBN, error) {
+	return ParseISBN(fmt.Sprintf("%013d", n))
+}
+
+// String returns the canonical form of the ISBN-13: hyphenated in the groups
+// of isbnRegistrants, 978-0 and 978-1, and its 13 digits in every other group.
+// Numbers that aren't an ISBN-13 are printed as they are.
+func (i ISBN) String() string {
+	digits := fmt.Sprintf("%013d", int64(i))
+	if len(digits) != 13 || (digits[:3] != "978" && digits[:3] != "979") {
+		return strconv.FormatInt(int64(i), 10)
+	}
+	hyphenated, ok := hyphenate(digits[:3], digits[3:12], digits[12:])
+	if !ok {
+		return digits
+	}
+	return hyphenated
+}
+
+// ISBN10 returns the hyphenated ISBN-10, or its 10 digits if its group has no
+// known registrant ranges. Only ISBN-13s starting with 978 have one.
+func (i ISBN) ISBN10() (string, error) {
+	digits := fmt.Sprintf("%013d", int64(i))
+	if len(digits) != 13 || digits[:3] != "978" {
+		return "", &Error{
+			Type:   BadInput,
+			Actual: fmt.Errorf("%s doesn't start with 978", i),
+			Desc:   "while converting to isbn-10",
+		}
+	}
+	check := string(isbn10CheckDigit(digits[3:12]))
+	hyphenated, ok := hyphenate("978", digits[3:12], check)
+	if !ok {
+		return digits[3:12] + check, nil
+	}
+	return hyphenated[4:], nil
+}
+
+// hyphenate splits the nine digits between the prefix and the check digit
+// into the registration group, registrant and publication. It fails unless
+// the ranges of both the group and the registrant are known, rather than
+// split them in the wrong place.
+func hyphenate(prefix string, body string, check string) (string, bool) {
+	group := elementLength(isbnGroups[prefix], body)
+	if group == 0 {
+		return "", false
+	}
+	registrant := elementLength(isbnRegistrants[prefix+"-"+body[:group]], body[group:])
+	if registrant == 0 || group+registrant >= len(body) {
+		return "", false
+	}
+	return strings.Join([]string{prefix, body[:group], body[group : group+registrant], body[group+registrant:], check}, "-"), true
+}
+
+// MarshalText returns the canonical form of the ISBN-13.
+func (i ISBN) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText parses an ISBN-10 or ISBN-13.
+func (i *ISBN) UnmarshalText(text []byte) error {
+	parsed, err := ParseISBN(string(text))
+	if err != nil {
+		return err
+	}
+	*i = parsed
+	return nil
+}
@@ -0,0 +1,79 @@
+package library
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseISBN(t *testing.T) {
+	for input, expected := range map[string]string{
+		"978-0-306-40615-7": "978-0-306-40615-7",
+		"9780306406157":     "978-0-306-40615-7",
+		"0-306-40615-2":     "978-0-306-40615-7",
+		"0306406152":        "978-0-306-40615-7",
+		"0 8044 2957 X":     "978-0-8044-2957-3",
+		"080442957x":        "978-0-8044-2957-3",
+		"978-1-4028-9462-6": "978-1-4028-9462-6",
+		"978-1-86197-876-9": "978-1-86197-876-9",
+		// groups without known registrant ranges aren't hyphenated.
+		"978-88-04-66823-7": "9788804668237",
+		"979-10-90636-07-1": "9791090636071",
+		"9786000000004":     "9786000000004",
+	} {
+		isbn, err := ParseISBN(input)
+		if err != nil {
+			t.Fatalf("while parsing %q: %s", input, err)
+		}
+		if isbn.String() != expected {
+			t.Fatalf("expected %q to be %q but got %q", input, expected, isbn)
+		}
+	}
+}
+
+func TestParseISBNInvalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"978-0-306-40615-8",
+		"0-306-40615-3",
+		"1234567890123",
+		"977-0-306-40615-7",
+		"97803064061X7",
+		"03064061X2",
+		"978030640615",
+	} {
+		_, err := ParseISBN(input)
+		var liberr *Error
+		if !errors.As(err, &liberr) || liberr.Type != BadInput {
+			t.Fatalf("expected bad input for %q but got %v", input, err)
+		}
+	}
+}
+
+func TestISBN10(t *testing.T) {
+	for input, expected := range map[string]string{
+		"978-0-306-40615-7": "0-306-40615-2",
+		"978-0-8044-2957-3": "0-8044-2957-X",
+		"978-1-86197-876-9": "1-86197-876-6",
+		"978-88-04-66823-7": "8804668237",
+	} {
+		isbn, err := ParseISBN(input)
+		if err != nil {
+			t.Fatalf("while parsing %q: %s", input, err)
+		}
+		actual, err := isbn.ISBN10()
+		if err != nil {
+			t.Fatalf("while converting %q: %s", input, err)
+		}
+		if actual != expected {
+			t.Fatalf("expected %q to be %q but got %q", input, expected, actual)
+		}
+	}
+	isbn, err := ParseISBN("979-10-90636-07-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_, err = isbn.ISBN10()
+	if err == nil {
+		t.Fatalf("expected %s to have no isbn-10", isbn)
+	}
+}
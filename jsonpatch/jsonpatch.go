@@ -0,0 +1,409 @@
+// Package jsonpatch changes JSON documents with JSON Merge Patch (RFC 7396)
+// and JSON Patch (RFC 6902).
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/slcjordan/library"
+)
+
+func decode(data []byte, desc string) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	err := decoder.Decode(&value)
+	if err == nil && decoder.More() {
+		err = fmt.Errorf("unexpected data after the JSON value")
+	}
+	if err != nil {
+		return nil, &library.Error{
+			Type:   library.BadInput,
+			Actual: err,
+			Desc:   desc,
+		}
+	}
+	return value, nil
+}
+
+func badPatch(format string, a ...any) error {
+	return &library.Error{
+		Type:   library.BadInput,
+		Actual: fmt.Errorf(format, a...),
+		Desc:   "while applying a patch",
+	}
+}
+
+// MergePatch returns doc changed by a merge patch. Members of patch objects
+// replace those of doc, recursively, and null members are removed.
+func MergePatch(doc []byte, patch []byte) ([]byte, error) {
+	target, err := decode(doc, "while parsing the document")
+	if err != nil {
+		return nil, err
+	}
+	changes, err := decode(patch, "while parsing the patch")
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(merge(target, changes))
+}
+
+func merge(target any, patch any) any {
+	changes, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	result, ok := target.(map[string]any)
+	if !ok {
+		result = map[string]any{}
+	}
+	for k, v := range changes {
+		if v == nil {
+			delete(result, k)
+			continue
+		}
+		result[k] = merge(result[k], v)
+	}
+	return result
+}
+
+// An Operation is one step of a JSON Patch. Value is nil if it was left out,
+// which is different from null.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Apply returns doc changed by a JSON Patch, an array of operations applied in
+// order. If any operation fails, including a test, the whole patch fails.
+func Apply(doc []byte, patch []byte) ([]byte, error) {
+	target, err := decode(doc, "while parsing the document")
+	if err != nil {
+		return nil, err
+	}
+	var ops []Operation
+	err = json.Unmarshal(patch, &ops)
+	if err != nil {
+		return nil, &library.Error{
+			Type:   library.BadInput,
+			Actual: err,
+			Desc:   "while parsing the patch",
+		}
+	}
+	for i, op := range ops {
+		target, err = op.apply(target)
+		if err != nil {
+			var libErr *library.Error
+			if errors.As(err, &libErr) {
+				libErr.Desc = fmt.Sprintf("while applying operation %d (%s)", i, op.Op)
+			}
+			return nil, err
+		}
+	}
+	return json.Marshal(target)
+}
+
+func (op Operation) value() (any, error) {
+	if op.Value == nil {
+		return nil, badPatch("%s needs a value", op.Op)
+	}
+	return decode(op.Value, "while parsing the value")
+}
+
+func (op Operation) apply(doc any) (any, error) {
+	if op.Path == nil {
+		return nil, badPatch("%s needs a path", op.Op)
+	}
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case "add":
+		value, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case "remove":
+		doc, _, err = remove(doc, path)
+		return doc, err
+	case "replace":
+		value, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		return replace(doc, path, value)
+	case "move", "copy":
+		if op.From == nil {
+			return nil, badPatch("%s needs a from", op.Op)
+		}
+		from, err := parsePointer(*op.From)
+		if err != nil {
+			return nil, err
+		}
+		var value any
+		if op.Op == "move" {
+			if strings.HasPrefix(*op.Path, *op.From+"/") {
+				return nil, badPatch("can't move %q into itself", *op.From)
+			}
+			doc, value, err = remove(doc, from)
+		} else {
+			value, err = get(doc, from)
+			if err == nil {
+				value, err = decode(mustMarshal(value), "while copying a value")
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case "test":
+		value, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		actual, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(actual, value) {
+			return nil, badPatch("the value at %q is %s, not %s", *op.Path, mustMarshal(actual), op.Value)
+		}
+		return doc, nil
+	}
+	return nil, badPatch("unknown operation %q", op.Op)
+}
+
+func mustMarshal(value any) []byte {
+	data, err := json.Marshal(value)
+	if err != nil {
+		panic(err) // decoded JSON always encodes
+	}
+	return data
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its unescaped tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, badPatch("the path %q doesn't start with /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// index parses an array index. An index of len(array) is only allowed for
+// appending, as is "-".
+func index(token string, array []any, appending bool) (int, error) {
+	if appending && token == "-" {
+		return len(array), nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, badPatch("%q is not an array index", token)
+	}
+	if i > len(array) || (i == len(array) && !appending) {
+		return 0, badPatch("index %d is out of range", i)
+	}
+	return i, nil
+}
+
+func get(doc any, path []string) (any, error) {
+	for _, token := range path {
+		switch node := doc.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, badPatch("no member %q", token)
+			}
+			doc = value
+		case []any:
+			i, err := index(token, node, false)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, badPatch("can't look up %q in a %s", token, kind(doc))
+		}
+	}
+	return doc, nil
+}
+
+// change calls f on the container holding the last token of path and returns
+// the document with the container f returns in its place.
+func change(doc any, path []string, f func(container any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return f(doc, path[0])
+	}
+	switch node := doc.(type) {
+	case map[string]any:
+		child, ok := node[path[0]]
+		if !ok {
+			return nil, badPatch("no member %q", path[0])
+		}
+		child, err := change(child, path[1:], f)
+		if err != nil {
+			return nil, err
+		}
+		node[path[0]] = child
+		return node, nil
+	case []any:
+		i, err := index(path[0], node, false)
+		if err != nil {
+			return nil, err
+		}
+		node[i], err = change(node[i], path[1:], f)
+		if err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+	return nil, badPatch("can't look up %q in a %s", path[0], kind(doc))
+}
+
+func add(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return change(doc, path, func(container any, token string) (any, error) {
+		switch node := container.(type) {
+		case map[string]any:
+			node[token] = value
+			return node, nil
+		case []any:
+			i, err := index(token, node, true)
+			if err != nil {
+				return nil, err
+			}
+			node = append(node, nil)
+			copy(node[i+1:], node[i:])
+			node[i] = value
+			return node, nil
+		}
+		return nil, badPatch("can't add %q to a %s", token, kind(container))
+	})
+}
+
+func remove(doc any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, badPatch("can't remove the whole document")
+	}
+	var removed any
+	doc, err := change(doc, path, func(container any, token string) (any, error) {
+		switch node := container.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, badPatch("no member %q", token)
+			}
+			removed = value
+			delete(node, token)
+			return node, nil
+		case []any:
+			i, err := index(token, node, false)
+			if err != nil {
+				return nil, err
+			}
+			removed = node[i]
+			return append(node[:i], node[i+1:]...), nil
+		}
+		return nil, badPatch("can't remove %q from a %s", token, kind(container))
+	})
+	return doc, removed, err
+}
+
+func replace(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return change(doc, path, func(container any, token string) (any, error) {
+		switch node := container.(type) {
+		case map[string]any:
+			if _, ok := node[token]; !ok {
+				return nil, badPatch("no member %q", token)
+			}
+			node[token] = value
+			return node, nil
+		case []any:
+			i, err := index(token, node, false)
+			if err != nil {
+				return nil, err
+			}
+			node[i] = value
+			return node, nil
+		}
+		return nil, badPatch("can't replace %q in a %s", token, kind(container))
+	})
+}
+
+func kind(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
+
+// equal compares decoded JSON values. Numbers are equal if they have the same
+// value however they are written.
+func equal(a any, b any) bool {
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if x == y {
+			return true
+		}
+		f, errX := x.Float64()
+		g, errY := y.Float64()
+		return errX == nil && errY == nil && f == g
+	}
+	return a == b
+}
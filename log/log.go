@@ -2,6 +2,7 @@ package log
 
 import (
 	"context"
+	"sync/atomic"
 )
 
 // A Logger accepts a format string and arguments.
@@ -12,6 +13,9 @@ type Logger interface {
 var infos []Logger
 var errors []Logger
 
+// quiet is 1 while info logs are turned off.
+var quiet int32
+
 // RegisterInfo may be called by a logging package's init function.
 func RegisterInfo(l Logger) {
 	infos = append(infos, l)
@@ -22,8 +26,20 @@ func RegisterError(l Logger) {
 	errors = append(errors, l)
 }
 
+// SetInfo turns info logs on or off. They are on until it is called.
+func SetInfo(on bool) {
+	if on {
+		atomic.StoreInt32(&quiet, 0)
+		return
+	}
+	atomic.StoreInt32(&quiet, 1)
+}
+
 // Infof may be used to log error conditions that are the fault of external applications.
 func Infof(ctx context.Context, format string, a ...interface{}) {
+	if atomic.LoadInt32(&quiet) == 1 {
+		return
+	}
 	for _, l := range infos {
 		l.Printf(ctx, format+"\n", a...)
 	}
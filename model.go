@@ -1,9 +1,56 @@
 package library
 
-// A Book is uniquely identified by its ISBN.
+import (
+	"sort"
+	"time"
+)
+
+// A Book is uniquely identified by its ISBN. Authors are in the order they are
+// credited. Version goes up by one every time the book changes; updating a
+// book with a non-zero Version fails if the book has moved past it.
 type Book struct {
-	ISBN  int64
-	Title string
+	ISBN      ISBN
+	Title     string
+	Authors   []Author
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   int64
+	// DeletedAt is when the book was moved to the trash, or zero if it
+	// isn't trashed.
+	DeletedAt time.Time
+}
+
+// A BookPatch changes some fields of a book. Apply returns the changed book
+// or a BadInput error, with FieldErrors for fields that can't be changed.
+type BookPatch interface {
+	Apply(Book) (Book, error)
+}
+
+// A BookOrder is a field that books can be listed by. Ties are broken by
+// ISBN.
+type BookOrder string
+
+const (
+	ByTitle     BookOrder = "title"
+	ByISBN      BookOrder = "isbn"
+	ByCreatedAt BookOrder = "created_at"
+)
+
+// A BookFilter narrows down and orders a list of books. Zero fields don't
+// filter. Time windows include their start and exclude their end. Books are
+// listed by title when OrderBy is empty.
+type BookFilter struct {
+	TitlePrefix   string
+	MinISBN       ISBN
+	MaxISBN       ISBN
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+	OrderBy       BookOrder
+	Descending    bool
+	// IncludeDeleted lists trashed books along with the others.
+	IncludeDeleted bool
 }
 
 // A BookList includes a next-page token for picking up at the next page.
@@ -11,3 +58,323 @@ type BookList struct {
 	Books         []Book
 	NextPageToken string
 }
+
+// A SearchResult is a book matching a search. Higher scores are better
+// matches. The snippet is the title with the matched words wrapped in <b>
+// tags.
+type SearchResult struct {
+	Book    Book
+	Score   float64
+	Snippet string
+}
+
+// A SearchResultList includes a next-page token for picking up at the next
+// page.
+type SearchResultList struct {
+	Results       []SearchResult
+	NextPageToken string
+}
+
+// An Author is uniquely identified by an ID assigned by the library.
+type Author struct {
+	ID   int64
+	Name string
+}
+
+// An AuthorList includes a next-page token for picking up at the next page.
+type AuthorList struct {
+	Authors       []Author
+	NextPageToken string
+}
+
+// A CopyStatus tracks a copy through circulation.
+type CopyStatus string
+
+const (
+	Available   CopyStatus = "available"
+	OnLoan      CopyStatus = "on_loan"
+	OnHoldShelf CopyStatus = "on_hold_shelf"
+	Lost        CopyStatus = "lost"
+)
+
+// A Copy is a single physical item of a book, uniquely identified by the
+// barcode on it.
+type Copy struct {
+	Barcode  string
+	ISBN     ISBN
+	Location string
+	Status   CopyStatus
+}
+
+// DefaultPatronClass is the class of patrons created without one.
+const DefaultPatronClass = "standard"
+
+// A Patron may borrow up to BorrowingLimit copies at a time. Overdue fines
+// follow the fee schedule of the patron's Class.
+type Patron struct {
+	ID             int64
+	Name           string
+	BorrowingLimit int32
+	Class          string
+}
+
+// A Loan lends a copy to a patron. ReturnedAt is zero while the loan is open.
+type Loan struct {
+	ID           int64
+	Barcode      string
+	PatronID     int64
+	CheckedOutAt time.Time
+	DueAt        time.Time
+	ReturnedAt   time.Time
+	Renewals     int32
+}
+
+// A HoldStatus tracks a hold from the queue to the hold shelf.
+type HoldStatus string
+
+const (
+	Waiting   HoldStatus = "waiting"
+	Ready     HoldStatus = "ready"
+	Fulfilled HoldStatus = "fulfilled"
+	Expired   HoldStatus = "expired"
+	Cancelled HoldStatus = "cancelled"
+)
+
+// A Hold queues a patron for the next copy of a title. Holds are served
+// first-come, first-served. A ready hold has a copy set aside on the hold
+// shelf until PickupBy. Position is 1 for the next waiting hold to be served
+// and 0 for holds that aren't waiting.
+type Hold struct {
+	ID       int64
+	ISBN     ISBN
+	PatronID int64
+	PlacedAt time.Time
+	Status   HoldStatus
+	Barcode  string
+	PickupBy time.Time
+	Position int32
+}
+
+// A LedgerKind is the reason for a ledger entry.
+type LedgerKind string
+
+const (
+	Fine    LedgerKind = "fine"
+	Payment LedgerKind = "payment"
+	Waiver  LedgerKind = "waiver"
+)
+
+// A LedgerEntry changes the balance a patron owes by Amount cents. Fines are
+// positive and payments and waivers are negative. Entries are never changed
+// so the ledger is the audit trail of every charge. LoanID is zero for
+// entries that aren't fines. Actor is who made the change that recorded the
+// entry.
+type LedgerEntry struct {
+	ID        int64
+	PatronID  int64
+	LoanID    int64
+	Kind      LedgerKind
+	Amount    int64
+	Note      string
+	Actor     string
+	CreatedAt time.Time
+}
+
+// A ClassChange records a patron moving from one class, and so one fee
+// schedule, to another.
+type ClassChange struct {
+	ID        int64
+	PatronID  int64
+	From      string
+	To        string
+	Actor     string
+	CreatedAt time.Time
+}
+
+// A FineAccount is what a patron owes. Balance is the sum of the ledger and
+// Accruing is what the open overdue loans would be fined if they were
+// returned now. ClassChanges are the changes to the patron's class, which
+// picks the fee schedule of their fines.
+type FineAccount struct {
+	PatronID     int64
+	Balance      int64
+	Accruing     int64
+	Entries      []LedgerEntry
+	ClassChanges []ClassChange
+}
+
+// An ImportMode is what an import does with a book that is already in the
+// library.
+type ImportMode string
+
+const (
+	SkipExisting      ImportMode = "skip"
+	OverwriteExisting ImportMode = "overwrite"
+	FailOnExisting    ImportMode = "fail"
+)
+
+// A BookReader reads the books of an import one row at a time. Read returns
+// io.EOF after the last row. A BadInput error only rejects the current row
+// and reading can go on; any other error ends the import.
+type BookReader interface {
+	Read() (Book, error)
+}
+
+// An ImportError is why a row was not imported. Rows are numbered from 1 and
+// a header does not count.
+type ImportError struct {
+	Row     int64
+	Message string
+}
+
+// An ImportReport sums up an import. Every row is either imported, skipped
+// because the book already exists or has an error. A row whose ISBN came up
+// on an earlier row is an error, and so is a book in the trash when
+// overwriting. If an import with FailOnExisting has any errors or existing
+// books, nothing is imported and Aborted is set. ErrorCount counts every
+// error but Errors only lists those of the first MaxImportErrors rows with
+// one, and Truncated is set when some were left out.
+type ImportReport struct {
+	Rows       int64
+	Imported   int64
+	Skipped    int64
+	Aborted    bool
+	Errors     []ImportError
+	ErrorCount int64
+	Truncated  bool
+}
+
+// MaxImportErrors is how many errors an ImportReport lists.
+const MaxImportErrors = 1000
+
+// AddError adds e to the report. Errors may be added in any order; they are
+// listed by row once Sort is called.
+func (r *ImportReport) AddError(e ImportError) {
+	r.ErrorCount++
+	r.Errors = append(r.Errors, e)
+	// Errors is trimmed once it doubles, so adding stays cheap and the
+	// report never holds more than twice the errors it lists.
+	if len(r.Errors) >= 2*MaxImportErrors {
+		r.Sort()
+	}
+}
+
+// Sort orders the errors by row and drops any past MaxImportErrors.
+func (r *ImportReport) Sort() {
+	sort.SliceStable(r.Errors, func(i, j int) bool {
+		return r.Errors[i].Row < r.Errors[j].Row
+	})
+	if len(r.Errors) > MaxImportErrors {
+		r.Errors = r.Errors[:MaxImportErrors]
+		r.Truncated = true
+	}
+}
+
+// A BookWriter writes the books of an export one at a time. Close finishes
+// the output after the last book but doesn't close what it is written to.
+type BookWriter interface {
+	Write(Book) error
+	Close() error
+}
+
+// An AuditAction is the kind of change an audit record is for.
+type AuditAction string
+
+const (
+	AuditCreate  AuditAction = "create"
+	AuditUpdate  AuditAction = "update"
+	AuditDelete  AuditAction = "delete"
+	AuditRestore AuditAction = "restore"
+	AuditPurge   AuditAction = "purge"
+)
+
+// An AuditRecord is a single change to a book, made by Actor while handling
+// the request RequestID. Before is nil when the book was created and After is
+// nil when it was purged. The books in a record have no authors.
+type AuditRecord struct {
+	ID        int64
+	ISBN      ISBN
+	Action    AuditAction
+	Actor     string
+	RequestID string
+	Before    *Book
+	After     *Book
+	CreatedAt time.Time
+}
+
+// An AuditList includes a next-page token for picking up at the next page.
+type AuditList struct {
+	Records       []AuditRecord
+	NextPageToken string
+}
+
+// A BookChange is an audit record as the change feed sends it. EventID is the
+// position of the feed just after the record, for picking up after it.
+type BookChange struct {
+	EventID string
+	Record  AuditRecord
+}
+
+// A BookChangeList is the next changes of the feed. LastEventID is the
+// position after them, which is where the feed started if there are none.
+type BookChangeList struct {
+	Changes     []BookChange
+	LastEventID string
+}
+
+// A Webhook is a partner system that is sent the changes to books. It is sent
+// the audit records of the actions in Events, or of every action if there
+// are none. Secret signs the payloads. Inactive webhooks aren't sent
+// anything; their deliveries wait until they are active again.
+type Webhook struct {
+	ID        int64
+	URL       string
+	Secret    string
+	Events    []AuditAction
+	Active    bool
+	CreatedAt time.Time
+}
+
+// A DeliveryStatus is where a webhook delivery is in its life. A pending
+// delivery is retried with a growing backoff until it is delivered or has
+// failed too many times, when it is dead and only sent again by hand.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryDead      DeliveryStatus = "dead"
+)
+
+// A WebhookDelivery is the sending of one audit record to one webhook and how
+// it went. ResponseStatus is zero if the last attempt got no response.
+type WebhookDelivery struct {
+	ID             int64
+	WebhookID      int64
+	AuditID        int64
+	Event          AuditAction
+	Status         DeliveryStatus
+	Attempts       int32
+	NextAttemptAt  time.Time
+	LastAttemptAt  time.Time
+	ResponseStatus int32
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    time.Time
+}
+
+// A WebhookDeliveryList includes a next-page token for picking up at the next
+// page.
+type WebhookDeliveryList struct {
+	Deliveries    []WebhookDelivery
+	NextPageToken string
+}
+
+// A ClaimedDelivery is a delivery that a worker has claimed, with the webhook
+// to send it to and the record to send.
+type ClaimedDelivery struct {
+	Delivery WebhookDelivery
+	URL      string
+	Secret   string
+	Record   AuditRecord
+}
@@ -1,6 +1,37 @@
 package library
 
 import (
+	"testing"
+
 	_ "github.com/golang/mock/gomock" // needed for mock generation
 	_ "golang.org/x/tools/imports"    // needed for mock generation
 )
+
+func TestImportReportErrors(t *testing.T) {
+	var report ImportReport
+	// Added out of order, the way an import finds its duplicate rows after
+	// the rows it couldn't read.
+	for row := int64(2 * MaxImportErrors); row > 0; row -= 2 {
+		report.AddError(ImportError{Row: row})
+	}
+	for row := int64(1); row < 2*MaxImportErrors; row += 2 {
+		report.AddError(ImportError{Row: row})
+	}
+	report.Sort()
+	if report.ErrorCount != 2*MaxImportErrors || !report.Truncated || len(report.Errors) != MaxImportErrors {
+		t.Fatalf("expected %d of %d errors listed but got %d of %d", MaxImportErrors, 2*MaxImportErrors, len(report.Errors), report.ErrorCount)
+	}
+	for i, e := range report.Errors {
+		if e.Row != int64(i+1) {
+			t.Fatalf("expected error %d on row %d but got row %d", i, i+1, e.Row)
+		}
+	}
+
+	report = ImportReport{}
+	report.AddError(ImportError{Row: 2})
+	report.AddError(ImportError{Row: 1})
+	report.Sort()
+	if report.ErrorCount != 2 || report.Truncated || report.Errors[0].Row != 1 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+}
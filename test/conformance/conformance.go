@@ -0,0 +1,548 @@
+// Package conformance checks that a storage backend behaves like every other
+// backend. Each backend's tests call Run. Run only looks at the books and
+// authors it creates so it can share a database with other tests.
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/config"
+	"github.com/slcjordan/library/db/cursor"
+	libhttp "github.com/slcjordan/library/http"
+)
+
+// A Backend stores the catalog of books and authors.
+type Backend interface {
+	libhttp.ListBooksController
+	libhttp.BookCRUDController
+	libhttp.ListAuthorsController
+	libhttp.AuthorCRUDController
+	libhttp.ExportController
+}
+
+// Run runs the conformance suite against backend.
+func Run(t *testing.T, backend Backend) {
+	// backends can't sign page tokens without a secret.
+	if config.Current().Postgres.PageTokenSecret == "" {
+		saved := config.Postgres.PageTokenSecret
+		config.Update(func() { config.Postgres.PageTokenSecret = "conformance" })
+		defer config.Update(func() { config.Postgres.PageTokenSecret = saved })
+	}
+	for name, test := range map[string]func(*testing.T, Backend){
+		"BookCRUD":        testBookCRUD,
+		"PatchBook":       testPatchBook,
+		"ListBooks":       testListBooks,
+		"AuthorCRUD":      testAuthorCRUD,
+		"ListAuthors":     testListAuthors,
+		"ListAuthorBooks": testListAuthorBooks,
+		"ExportBooks":     testExportBooks,
+	} {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			test(t, backend)
+		})
+	}
+}
+
+// newISBNs returns n new ISBNs in increasing order. They are made from the
+// clock so that every run gets its own books.
+func newISBNs(t *testing.T, n int) []library.ISBN {
+	t.Helper()
+	start := time.Now().UnixNano() / 1000 % 100000000 * 10
+	var isbns []library.ISBN
+	for i := int64(0); len(isbns) < n; i++ {
+		base := 9790000000000 + (start+i)*10
+		for check := int64(0); check < 10; check++ {
+			isbn, err := library.NewISBN(base + check)
+			if err == nil {
+				isbns = append(isbns, isbn)
+				break
+			}
+		}
+	}
+	return isbns
+}
+
+func errorType(err error) library.ErrorType {
+	var libErr *library.Error
+	if errors.As(err, &libErr) {
+		return libErr.Type
+	}
+	return library.Unknown
+}
+
+func expectError(t *testing.T, err error, expected library.ErrorType, desc string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("%s: expected a %s error but got none", desc, expected)
+	}
+	if actual := errorType(err); actual != expected {
+		t.Fatalf("%s: expected a %s error but got %s: %s", desc, expected, actual, err)
+	}
+}
+
+func must(t *testing.T, err error, desc string) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: %s", desc, err)
+	}
+}
+
+func createAuthors(t *testing.T, backend Backend, names ...string) []library.Author {
+	t.Helper()
+	var authors []library.Author
+	for _, name := range names {
+		author, err := backend.CreateAuthor(context.Background(), library.Author{Name: name})
+		must(t, err, "while creating an author")
+		if author.ID == 0 || author.Name != name {
+			t.Fatalf("unexpected new author %+v", author)
+		}
+		authors = append(authors, author)
+	}
+	return authors
+}
+
+// A purger moves deleted books to a trash and can empty it.
+type purger interface {
+	PurgeBooks(ctx context.Context, deletedBefore time.Time) (int64, error)
+}
+
+// purge empties the trash of backends that have one, so that the authors of
+// deleted books can be deleted too. Books other tests left in the trash are
+// purged as well.
+func purge(t *testing.T, backend Backend) {
+	t.Helper()
+	p, ok := backend.(purger)
+	if !ok {
+		return
+	}
+	_, err := p.PurgeBooks(context.Background(), time.Now())
+	must(t, err, "while purging books")
+}
+
+// cleanup deletes books and then authors when the test ends.
+func cleanup(t *testing.T, backend Backend, isbns []library.ISBN, authors []library.Author) {
+	t.Cleanup(func() {
+		ctx := context.Background()
+		for _, isbn := range isbns {
+			//nolint:errcheck // the test may have deleted it already.
+			backend.DeleteBook(ctx, isbn)
+		}
+		if len(authors) > 0 {
+			purge(t, backend)
+		}
+		for _, a := range authors {
+			//nolint:errcheck // the test may have deleted it already.
+			backend.DeleteAuthor(ctx, a.ID)
+		}
+	})
+}
+
+func testBookCRUD(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	isbn := newISBNs(t, 1)[0]
+	authors := createAuthors(t, backend, "Second Credited", "First Credited")
+	cleanup(t, backend, []library.ISBN{isbn}, authors)
+
+	err := backend.CreateBook(ctx, library.Book{ISBN: isbn, Title: "Conformance", Authors: authors})
+	must(t, err, "while creating a book")
+	err = backend.CreateBook(ctx, library.Book{ISBN: isbn, Title: "Again"})
+	expectError(t, err, library.Conflict, "creating a book twice")
+	err = backend.CreateBook(ctx, library.Book{ISBN: 9780000000001, Title: "Invalid"})
+	expectError(t, err, library.BadInput, "creating a book with an invalid isbn")
+	other := newISBNs(t, 2)[1]
+	err = backend.CreateBook(ctx, library.Book{ISBN: other, Title: "Repeated", Authors: []library.Author{authors[0], authors[0]}})
+	expectError(t, err, library.BadInput, "creating a book with an author listed twice")
+	err = backend.CreateBook(ctx, library.Book{ISBN: other, Title: "Unknown", Authors: []library.Author{{ID: -1}}})
+	expectError(t, err, library.BadInput, "creating a book with a missing author")
+	_, err = backend.GetBook(ctx, other)
+	expectError(t, err, library.NotFound, "fetching a book that failed to be created")
+
+	book, err := backend.GetBook(ctx, isbn)
+	must(t, err, "while fetching a book")
+	if book.ISBN != isbn || book.Title != "Conformance" || book.Version != 1 || book.CreatedAt.IsZero() || !book.UpdatedAt.Equal(book.CreatedAt) {
+		t.Fatalf("unexpected book %+v", book)
+	}
+	if len(book.Authors) != 2 || book.Authors[0] != authors[0] || book.Authors[1] != authors[1] {
+		t.Fatalf("expected authors %+v in the order they were credited but got %+v", authors, book.Authors)
+	}
+
+	updated, err := backend.UpdateBook(ctx, library.Book{ISBN: isbn, Title: "Updated", Version: 1})
+	must(t, err, "while updating a book")
+	if updated.Title != "Updated" || updated.Version != 2 || updated.UpdatedAt.Before(book.UpdatedAt) {
+		t.Fatalf("unexpected updated book %+v", updated)
+	}
+	_, err = backend.UpdateBook(ctx, library.Book{ISBN: isbn, Title: "Stale", Version: 1})
+	expectError(t, err, library.PreconditionFailed, "updating a book at an old version")
+	updated, err = backend.UpdateBook(ctx, library.Book{ISBN: isbn, Title: "Unconditional"})
+	must(t, err, "while updating a book at any version")
+	if updated.Version != 3 {
+		t.Fatalf("expected version 3 but got %+v", updated)
+	}
+	_, err = backend.UpdateBook(ctx, library.Book{ISBN: other, Title: "Missing"})
+	expectError(t, err, library.NotFound, "updating a missing book")
+
+	must(t, backend.DeleteBook(ctx, isbn), "while deleting a book")
+	_, err = backend.GetBook(ctx, isbn)
+	expectError(t, err, library.NotFound, "fetching a deleted book")
+	expectError(t, backend.DeleteBook(ctx, isbn), library.NotFound, "deleting a deleted book")
+}
+
+// patchFunc is a library.BookPatch made from a function.
+type patchFunc func(library.Book) (library.Book, error)
+
+func (f patchFunc) Apply(book library.Book) (library.Book, error) {
+	return f(book)
+}
+
+func testPatchBook(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	isbns := newISBNs(t, 2)
+	authors := createAuthors(t, backend, "Patched In", "Patched Out")
+	cleanup(t, backend, isbns, authors)
+	must(t, backend.CreateBook(ctx, library.Book{ISBN: isbns[0], Title: "Before", Authors: authors[1:]}), "while creating a book")
+
+	patched, err := backend.PatchBook(ctx, isbns[0], 1, patchFunc(func(book library.Book) (library.Book, error) {
+		if book.Title != "Before" || len(book.Authors) != 1 || book.Authors[0] != authors[1] {
+			return library.Book{}, fmt.Errorf("patch applied to %+v", book)
+		}
+		book.Title = "After"
+		book.Authors = authors[:1]
+		return book, nil
+	}))
+	must(t, err, "while patching a book")
+	if patched.Title != "After" || patched.Version != 2 || len(patched.Authors) != 1 || patched.Authors[0] != authors[0] {
+		t.Fatalf("unexpected patched book %+v", patched)
+	}
+
+	unchanged, err := backend.PatchBook(ctx, isbns[0], 0, patchFunc(func(book library.Book) (library.Book, error) {
+		return book, nil
+	}))
+	must(t, err, "while patching a book without changing it")
+	if unchanged.Version != 2 || len(unchanged.Authors) != 1 {
+		t.Fatalf("expected a patch that changes nothing to keep the version but got %+v", unchanged)
+	}
+
+	titleOnly, err := backend.PatchBook(ctx, isbns[0], 0, patchFunc(func(book library.Book) (library.Book, error) {
+		book.Title = "Title Only"
+		return book, nil
+	}))
+	must(t, err, "while patching the title of a book")
+	if titleOnly.Version != 3 || len(titleOnly.Authors) != 1 || titleOnly.Authors[0] != authors[0] {
+		t.Fatalf("expected the authors to be kept but got %+v", titleOnly)
+	}
+
+	_, err = backend.PatchBook(ctx, isbns[0], 1, patchFunc(func(book library.Book) (library.Book, error) {
+		return book, nil
+	}))
+	expectError(t, err, library.PreconditionFailed, "patching a book at an old version")
+	_, err = backend.PatchBook(ctx, isbns[0], 0, patchFunc(func(book library.Book) (library.Book, error) {
+		return library.Book{}, &library.Error{Type: library.BadInput, Actual: errors.New("rejected"), Desc: "while testing"}
+	}))
+	expectError(t, err, library.BadInput, "a patch that fails")
+	_, err = backend.PatchBook(ctx, isbns[0], 0, patchFunc(func(book library.Book) (library.Book, error) {
+		book.Title = "Never"
+		book.Authors = []library.Author{{ID: -1}}
+		return book, nil
+	}))
+	expectError(t, err, library.BadInput, "patching in a missing author")
+	var fieldErrors library.FieldErrors
+	if !errors.As(err, &fieldErrors) || len(fieldErrors) != 1 || fieldErrors[0].Field != "author_ids" {
+		t.Fatalf("expected an author_ids field error but got %v", err)
+	}
+	_, err = backend.PatchBook(ctx, isbns[1], 0, patchFunc(func(book library.Book) (library.Book, error) {
+		return book, nil
+	}))
+	expectError(t, err, library.NotFound, "patching a missing book")
+
+	book, err := backend.GetBook(ctx, isbns[0])
+	must(t, err, "while fetching a patched book")
+	if book.Title != "Title Only" || book.Version != 3 || len(book.Authors) != 1 || book.Authors[0] != authors[0] {
+		t.Fatalf("expected failed patches to change nothing but got %+v", book)
+	}
+}
+
+// listAll pages through a list of books two at a time.
+func listAll(t *testing.T, backend Backend, filter library.BookFilter) []library.ISBN {
+	t.Helper()
+	var isbns []library.ISBN
+	token := ""
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatalf("too many pages listing %+v", filter)
+		}
+		list, err := backend.ListBooks(context.Background(), filter, token, 2)
+		must(t, err, "while listing books")
+		for _, b := range list.Books {
+			isbns = append(isbns, b.ISBN)
+		}
+		if list.NextPageToken == "" {
+			return isbns
+		}
+		token = list.NextPageToken
+	}
+}
+
+func testListBooks(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	isbns := newISBNs(t, 5)
+	cleanup(t, backend, isbns, nil)
+	// created in this order, so newest last.
+	titles := []string{"Listed C", "Listed A", "Listed B", "Listed A", "Unlisted"}
+	for i, isbn := range isbns {
+		must(t, backend.CreateBook(ctx, library.Book{ISBN: isbn, Title: titles[i]}), "while creating a book")
+		// keep creation times apart.
+		time.Sleep(2 * time.Millisecond)
+	}
+	byTitle := []library.ISBN{isbns[1], isbns[3], isbns[2], isbns[0], isbns[4]}
+	all := library.BookFilter{MinISBN: isbns[0], MaxISBN: isbns[4]}
+	reversed := func(isbns []library.ISBN) []library.ISBN {
+		result := make([]library.ISBN, len(isbns))
+		for i, isbn := range isbns {
+			result[len(isbns)-1-i] = isbn
+		}
+		return result
+	}
+	withOrder := func(filter library.BookFilter, order library.BookOrder, descending bool) library.BookFilter {
+		filter.OrderBy = order
+		filter.Descending = descending
+		return filter
+	}
+	for desc, c := range map[string]struct {
+		filter   library.BookFilter
+		expected []library.ISBN
+	}{
+		"default order":      {all, byTitle},
+		"by title":           {withOrder(all, library.ByTitle, false), byTitle},
+		"by title desc":      {withOrder(all, library.ByTitle, true), reversed(byTitle)},
+		"by isbn":            {withOrder(all, library.ByISBN, false), isbns},
+		"by isbn desc":       {withOrder(all, library.ByISBN, true), reversed(isbns)},
+		"by created_at":      {withOrder(all, library.ByCreatedAt, false), isbns},
+		"by created_at desc": {withOrder(all, library.ByCreatedAt, true), reversed(isbns)},
+		"title prefix": {
+			library.BookFilter{MinISBN: isbns[0], MaxISBN: isbns[4], TitlePrefix: "Listed A"},
+			[]library.ISBN{isbns[1], isbns[3]},
+		},
+		"isbn range": {
+			library.BookFilter{MinISBN: isbns[1], MaxISBN: isbns[3], OrderBy: library.ByISBN},
+			isbns[1:4],
+		},
+	} {
+		actual := listAll(t, backend, c.filter)
+		if fmt.Sprint(actual) != fmt.Sprint(c.expected) {
+			t.Fatalf("%s: expected %v but got %v", desc, c.expected, actual)
+		}
+	}
+
+	second, err := backend.GetBook(ctx, isbns[1])
+	must(t, err, "while fetching a book")
+	fourth, err := backend.GetBook(ctx, isbns[3])
+	must(t, err, "while fetching a book")
+	window := library.BookFilter{MinISBN: isbns[0], MaxISBN: isbns[4], CreatedAfter: second.CreatedAt, CreatedBefore: fourth.CreatedAt, OrderBy: library.ByISBN}
+	if actual := listAll(t, backend, window); fmt.Sprint(actual) != fmt.Sprint(isbns[1:3]) {
+		t.Fatalf("created window: expected %v but got %v", isbns[1:3], actual)
+	}
+	_, err = backend.UpdateBook(ctx, library.Book{ISBN: isbns[0], Title: "Listed C"})
+	must(t, err, "while updating a book")
+	updated, err := backend.GetBook(ctx, isbns[0])
+	must(t, err, "while fetching a book")
+	window = library.BookFilter{MinISBN: isbns[0], MaxISBN: isbns[4], UpdatedAfter: updated.UpdatedAt}
+	if actual := listAll(t, backend, window); fmt.Sprint(actual) != fmt.Sprint(isbns[:1]) {
+		t.Fatalf("updated window: expected %v but got %v", isbns[:1], actual)
+	}
+
+	list, err := backend.ListBooks(ctx, withOrder(all, library.ByISBN, false), "", 2)
+	must(t, err, "while listing books")
+	_, err = backend.ListBooks(ctx, withOrder(all, library.ByTitle, false), list.NextPageToken, 2)
+	expectError(t, err, library.BadInput, "a page token for another order")
+	_, err = backend.ListBooks(ctx, all, "not a page token", 2)
+	expectError(t, err, library.BadInput, "a bad page token")
+	_, err = backend.ListBooks(ctx, withOrder(all, "shelf", false), "", 2)
+	expectError(t, err, library.BadInput, "an unknown order")
+}
+
+func testAuthorCRUD(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	isbn := newISBNs(t, 1)[0]
+	authors := createAuthors(t, backend, "Before Rename")
+	cleanup(t, backend, []library.ISBN{isbn}, authors)
+	author := authors[0]
+
+	fetched, err := backend.GetAuthor(ctx, author.ID)
+	must(t, err, "while fetching an author")
+	if fetched != author {
+		t.Fatalf("expected %+v but got %+v", author, fetched)
+	}
+	author.Name = "After Rename"
+	must(t, backend.UpdateAuthor(ctx, author), "while updating an author")
+	must(t, backend.CreateBook(ctx, library.Book{ISBN: isbn, Title: "Renamed", Authors: authors}), "while creating a book")
+	book, err := backend.GetBook(ctx, isbn)
+	must(t, err, "while fetching a book")
+	if len(book.Authors) != 1 || book.Authors[0] != author {
+		t.Fatalf("expected the book to have author %+v but got %+v", author, book.Authors)
+	}
+
+	expectError(t, backend.DeleteAuthor(ctx, author.ID), library.Conflict, "deleting an author with books")
+	must(t, backend.DeleteBook(ctx, isbn), "while deleting a book")
+	purge(t, backend)
+	must(t, backend.DeleteAuthor(ctx, author.ID), "while deleting an author")
+	_, err = backend.GetAuthor(ctx, author.ID)
+	expectError(t, err, library.NotFound, "fetching a deleted author")
+	expectError(t, backend.DeleteAuthor(ctx, author.ID), library.NotFound, "deleting a deleted author")
+	expectError(t, backend.UpdateAuthor(ctx, author), library.NotFound, "updating a deleted author")
+}
+
+func testListAuthors(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	suffix := fmt.Sprint(time.Now().UnixNano())
+	authors := createAuthors(t, backend, "Zz Listed B "+suffix, "Zz Listed A "+suffix, "Zz Listed B "+suffix)
+	cleanup(t, backend, nil, authors)
+	ours := map[int64]bool{}
+	for _, a := range authors {
+		ours[a.ID] = true
+	}
+
+	// other tests may share the backend, so only the order of this test's
+	// authors is checked. Ties in name are broken by id.
+	var listed []library.Author
+	token := ""
+	for {
+		list, err := backend.ListAuthors(ctx, token, 2)
+		must(t, err, "while listing authors")
+		for _, a := range list.Authors {
+			if ours[a.ID] {
+				listed = append(listed, a)
+			}
+		}
+		if list.NextPageToken == "" {
+			break
+		}
+		token = list.NextPageToken
+	}
+	expected := []library.Author{authors[1], authors[0], authors[2]}
+	if fmt.Sprint(listed) != fmt.Sprint(expected) {
+		t.Fatalf("expected %+v but got %+v", expected, listed)
+	}
+	_, err := backend.ListAuthors(ctx, "not a page token", 2)
+	expectError(t, err, library.BadInput, "a bad page token")
+	titleToken, err := cursor.Encode(cursor.Cursor{Order: string(library.ByTitle), Key: "Zz", ID: 1})
+	must(t, err, "while encoding a page token")
+	_, err = backend.ListAuthors(ctx, titleToken, 2)
+	expectError(t, err, library.BadInput, "a page token for another order")
+}
+
+func testListAuthorBooks(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	isbns := newISBNs(t, 4)
+	authors := createAuthors(t, backend, "Prolific", "Coauthor")
+	cleanup(t, backend, isbns, authors)
+	for i, title := range []string{"Third", "First", "Second", "Not Theirs"} {
+		credited := authors
+		if i == 3 {
+			credited = authors[1:]
+		}
+		must(t, backend.CreateBook(ctx, library.Book{ISBN: isbns[i], Title: title, Authors: credited}), "while creating a book")
+	}
+
+	var titles []string
+	token := ""
+	for {
+		list, err := backend.ListAuthorBooks(ctx, authors[0].ID, token, 2)
+		must(t, err, "while listing the books of an author")
+		for _, b := range list.Books {
+			titles = append(titles, b.Title)
+		}
+		if list.NextPageToken == "" {
+			break
+		}
+		token = list.NextPageToken
+	}
+	if fmt.Sprint(titles) != "[First Second Third]" {
+		t.Fatalf("expected the author's books by title but got %v", titles)
+	}
+	_, err := backend.ListAuthorBooks(ctx, -1, "", 2)
+	expectError(t, err, library.NotFound, "listing the books of a missing author")
+	isbnToken, err := backend.ListBooks(ctx, library.BookFilter{MinISBN: isbns[0], MaxISBN: isbns[3], OrderBy: library.ByISBN}, "", 1)
+	must(t, err, "while listing books")
+	_, err = backend.ListAuthorBooks(ctx, authors[0].ID, isbnToken.NextPageToken, 2)
+	expectError(t, err, library.BadInput, "a page token for another order")
+	titleToken, err := backend.ListBooks(ctx, library.BookFilter{MinISBN: isbns[0], MaxISBN: isbns[3], OrderBy: library.ByTitle}, "", 1)
+	must(t, err, "while listing books")
+	_, err = backend.ListAuthorBooks(ctx, authors[0].ID, titleToken.NextPageToken, 2)
+	expectError(t, err, library.BadInput, "a page token of a book list")
+	authorToken, err := backend.ListAuthorBooks(ctx, authors[0].ID, "", 1)
+	must(t, err, "while listing the books of an author")
+	_, err = backend.ListBooks(ctx, library.BookFilter{OrderBy: library.ByTitle}, authorToken.NextPageToken, 2)
+	expectError(t, err, library.BadInput, "a page token of the books of an author")
+}
+
+// bookWriter collects the books of an export.
+type bookWriter struct {
+	books  []library.Book
+	closed bool
+}
+
+func (w *bookWriter) Write(book library.Book) error {
+	w.books = append(w.books, book)
+	return nil
+}
+
+func (w *bookWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func testExportBooks(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	isbns := newISBNs(t, 3)
+	cleanup(t, backend, isbns, nil)
+	for _, i := range []int{2, 0, 1} {
+		must(t, backend.CreateBook(ctx, library.Book{ISBN: isbns[i], Title: fmt.Sprint("Exported ", i)}), "while creating a book")
+	}
+
+	writer := &bookWriter{}
+	must(t, backend.ExportBooks(ctx, writer), "while exporting books")
+	if writer.closed {
+		t.Fatalf("expected the caller to close the writer")
+	}
+	if !sort.SliceIsSorted(writer.books, func(i, j int) bool { return writer.books[i].ISBN < writer.books[j].ISBN }) {
+		t.Fatalf("expected books in isbn order")
+	}
+	var exported []library.Book
+	for _, b := range writer.books {
+		if b.ISBN >= isbns[0] && b.ISBN <= isbns[2] {
+			exported = append(exported, b)
+		}
+	}
+	if len(exported) != 3 {
+		t.Fatalf("expected 3 exported books but got %+v", exported)
+	}
+	for i, b := range exported {
+		if b.ISBN != isbns[i] || b.Title != fmt.Sprint("Exported ", i) || b.Version != 1 || b.CreatedAt.IsZero() {
+			t.Fatalf("unexpected exported book %+v", b)
+		}
+	}
+	failing := fmt.Errorf("disk full")
+	err := backend.ExportBooks(ctx, failingWriter{failing})
+	if !errors.Is(err, failing) {
+		t.Fatalf("expected the writer's error but got %v", err)
+	}
+}
+
+type failingWriter struct {
+	err error
+}
+
+func (w failingWriter) Write(library.Book) error {
+	return w.err
+}
+
+func (w failingWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,109 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library/config"
+	libhttp "github.com/slcjordan/library/http"
+	"github.com/slcjordan/library/wire/api"
+)
+
+func TestAudit(t *testing.T) {
+	config.MustParse()
+	handler := api.Wire()
+
+	isbn := NewISBN(t, time.Now().UnixNano())
+	Send(t, handler, WithHeader(Request(http.MethodPost, "/books", fmt.Sprintf(`{"isbn": %q, "title": "First Title"}`, isbn)), "X-Actor", "cataloguer"), StatusShouldBe(http.StatusCreated))
+	time.Sleep(10 * time.Millisecond)
+	beforeRename := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	Send(t, handler, WithHeader(Request(http.MethodPut, "/books/"+isbn, `{"title": "Second Title"}`), "X-Actor", "editor"), StatusShouldBe(http.StatusOK))
+	Send(t, handler, WithHeader(Request(http.MethodDelete, "/books/"+isbn, ""), "X-Actor", "editor"), StatusShouldBe(http.StatusNoContent))
+
+	var history libhttp.AuditRecordList
+	Send(t, handler, Request(http.MethodGet, "/books/"+isbn+"/history", ""), StatusShouldBe(http.StatusOK), DecodeInto(&history))
+	if len(history.Items) != 3 {
+		t.Fatalf("expected 3 changes but got %+v", history.Items)
+	}
+	for i, expected := range []struct {
+		action libhttp.AuditRecordAction
+		actor  string
+		title  string
+	}{
+		{"delete", "editor", "Second Title"},
+		{"update", "editor", "Second Title"},
+		{"create", "cataloguer", "First Title"},
+	} {
+		record := history.Items[i]
+		if record.Action != expected.action || record.Actor != expected.actor || record.RequestId == "" ||
+			record.After == nil || record.After.Title != expected.title {
+			t.Fatalf("expected a %s by %s to %q but got %+v", expected.action, expected.actor, expected.title, record)
+		}
+	}
+	if history.Items[2].Before != nil || history.Items[1].Before == nil || history.Items[1].Before.Title != "First Title" {
+		t.Fatalf("unexpected before states in %+v", history.Items)
+	}
+
+	var page libhttp.AuditRecordList
+	Send(t, handler, Request(http.MethodGet, "/books/"+isbn+"/history?total_size=2", ""), StatusShouldBe(http.StatusOK), DecodeInto(&page))
+	Send(t, handler, Request(http.MethodGet, "/books/"+isbn+"/history?total_size=2&page_token="+page.NextPageToken, ""), StatusShouldBe(http.StatusOK), DecodeInto(&page))
+	if len(page.Items) != 1 || page.Items[0].Id != history.Items[2].Id {
+		t.Fatalf("expected the second page to have the create but got %+v", page.Items)
+	}
+	var all libhttp.AuditRecordList
+	Send(t, handler, Request(http.MethodGet, "/audit?total_size=3", ""), StatusShouldBe(http.StatusOK), DecodeInto(&all))
+	if len(all.Items) != 3 || all.Items[0].Id < history.Items[0].Id {
+		t.Fatalf("expected the newest records first but got %+v", all.Items)
+	}
+
+	var then libhttp.Book
+	Send(t, handler, Request(http.MethodGet, "/books/"+isbn+"?as_of="+url.QueryEscape(beforeRename.Format(time.RFC3339Nano)), ""), StatusShouldBe(http.StatusOK), DecodeInto(&then))
+	if then.Title != "First Title" || then.DeletedAt != nil {
+		t.Fatalf("expected the book before it was renamed but got %+v", then)
+	}
+	Send(t, handler, Request(http.MethodGet, "/books/"+isbn+"?as_of="+url.QueryEscape(time.Now().Format(time.RFC3339Nano)), ""), StatusShouldBe(http.StatusOK), DecodeInto(&then))
+	if then.Title != "Second Title" || then.DeletedAt == nil {
+		t.Fatalf("expected the trashed book but got %+v", then)
+	}
+	Send(t, handler, Request(http.MethodGet, "/books/"+isbn+"?as_of=2000-01-01T00:00:00Z", ""), StatusShouldBe(http.StatusNotFound))
+}
+
+func TestAuditAuthors(t *testing.T) {
+	config.MustParse()
+	handler := api.Wire()
+
+	var first, second libhttp.Author
+	Send(t, handler, Request(http.MethodPost, "/authors", `{"name": "Audited First"}`), StatusShouldBe(http.StatusCreated), DecodeInto(&first))
+	Send(t, handler, Request(http.MethodPost, "/authors", `{"name": "Audited Second"}`), StatusShouldBe(http.StatusCreated), DecodeInto(&second))
+	isbn := NewISBN(t, time.Now().UnixNano())
+	Send(t, handler, Request(http.MethodPost, "/books", fmt.Sprintf(`{"isbn": %q, "title": "Credited", "author_ids": [%d]}`, isbn, first.Id)), StatusShouldBe(http.StatusCreated))
+	time.Sleep(10 * time.Millisecond)
+	beforeRecredit := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	Send(t, handler, WithHeader(Request(http.MethodPatch, "/books/"+isbn, fmt.Sprintf(`{"author_ids": [%d, %d]}`, second.Id, first.Id)), "Content-Type", "application/merge-patch+json"), StatusShouldBe(http.StatusOK))
+
+	var history libhttp.AuditRecordList
+	Send(t, handler, Request(http.MethodGet, "/books/"+isbn+"/history", ""), StatusShouldBe(http.StatusOK), DecodeInto(&history))
+	if len(history.Items) != 2 {
+		t.Fatalf("expected 2 changes but got %+v", history.Items)
+	}
+	update, create := history.Items[0], history.Items[1]
+	if create.After == nil || !reflect.DeepEqual(create.After.AuthorIds, &[]int64{first.Id}) {
+		t.Fatalf("expected the create to record the first author but got %+v", create.After)
+	}
+	if update.Before == nil || !reflect.DeepEqual(update.Before.AuthorIds, &[]int64{first.Id}) ||
+		update.After == nil || !reflect.DeepEqual(update.After.AuthorIds, &[]int64{second.Id, first.Id}) {
+		t.Fatalf("expected the update to record the authors before and after but got %+v and %+v", update.Before, update.After)
+	}
+
+	var then libhttp.Book
+	Send(t, handler, Request(http.MethodGet, "/books/"+isbn+"?as_of="+url.QueryEscape(beforeRecredit.Format(time.RFC3339Nano)), ""), StatusShouldBe(http.StatusOK), DecodeInto(&then))
+	if !reflect.DeepEqual(then.AuthorIds, &[]int64{first.Id}) {
+		t.Fatalf("expected the book with its first author but got %+v", then)
+	}
+}
@@ -0,0 +1,137 @@
+package integration
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library/config"
+	libhttp "github.com/slcjordan/library/http"
+	"github.com/slcjordan/library/wire/api"
+)
+
+type changeEvent struct {
+	id     string
+	name   string
+	record libhttp.AuditRecord
+}
+
+// openChanges connects to the change feed and returns its events as they
+// arrive, skipping heartbeats.
+func openChanges(t *testing.T, ctx context.Context, url string, lastEventID string) <-chan changeEvent {
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"/api/v1/books/changes", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if lastEventID != "" {
+		r.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected an event stream but got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	events := make(chan changeEvent)
+	go func() {
+		defer resp.Body.Close()
+		defer close(events)
+		var event changeEvent
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				event.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				event.name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				//nolint:errcheck // a bad record fails the checks on it.
+				json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event.record)
+			case line == "" && event.id != "":
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+				event = changeEvent{}
+			}
+		}
+	}()
+	return events
+}
+
+// nextChange returns the next event for isbn.
+func nextChange(t *testing.T, events <-chan changeEvent, isbn string) changeEvent {
+	for event := range events {
+		if event.record.Isbn == isbn {
+			return event
+		}
+	}
+	t.Fatalf("expected a change to %s before the feed ended", isbn)
+	return changeEvent{}
+}
+
+func TestChanges(t *testing.T) {
+	config.MustParse()
+	server := httptest.NewServer(api.Wire())
+	defer server.Close()
+	handler := server.Config.Handler
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	events := openChanges(t, ctx, server.URL, "")
+	isbn := NewISBN(t, time.Now().UnixNano())
+	Send(t, handler, Request(http.MethodPost, "/books", fmt.Sprintf(`{"isbn": %q, "title": "Fresh Off The Press"}`, isbn)), StatusShouldBe(http.StatusCreated))
+	created := nextChange(t, events, isbn)
+	if created.name != "create" || created.record.After == nil || created.record.After.Title != "Fresh Off The Press" {
+		t.Fatalf("expected a create event but got %+v", created)
+	}
+	cancel()
+
+	// changes made while no one is listening are sent on reconnecting.
+	Send(t, handler, Request(http.MethodPut, "/books/"+isbn, `{"title": "Second Edition"}`), StatusShouldBe(http.StatusOK))
+	Send(t, handler, Request(http.MethodDelete, "/books/"+isbn, ""), StatusShouldBe(http.StatusNoContent))
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	events = openChanges(t, ctx, server.URL, created.id)
+	for _, expected := range []string{"update", "delete"} {
+		event := nextChange(t, events, isbn)
+		if event.name != expected || event.record.Action != libhttp.AuditRecordAction(expected) {
+			t.Fatalf("expected a %s event but got %+v", expected, event)
+		}
+	}
+
+	Send(t, handler, WithHeader(Request(http.MethodGet, "/books/changes", ""), "Last-Event-ID", "forged"), StatusShouldBe(http.StatusBadRequest))
+}
+
+func TestChangesReconnectBeforeAnyChange(t *testing.T) {
+	config.MustParse()
+	server := httptest.NewServer(api.Wire())
+	defer server.Close()
+	handler := server.Config.Handler
+
+	// a client that goes away before the first change still has an event id
+	// to resume from.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	first, ok := <-openChanges(t, ctx, server.URL, "")
+	cancel()
+	if !ok || first.id == "" || first.name != "" {
+		t.Fatalf("expected an event id on connecting but got %+v", first)
+	}
+
+	isbn := NewISBN(t, time.Now().UnixNano())
+	Send(t, handler, Request(http.MethodPost, "/books", fmt.Sprintf(`{"isbn": %q, "title": "While Away"}`, isbn)), StatusShouldBe(http.StatusCreated))
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	created := nextChange(t, openChanges(t, ctx, server.URL, first.id), isbn)
+	if created.name != "create" {
+		t.Fatalf("expected a create event but got %+v", created)
+	}
+}
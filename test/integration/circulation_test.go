@@ -0,0 +1,73 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library"
+	"github.com/slcjordan/library/config"
+	libhttp "github.com/slcjordan/library/http"
+	"github.com/slcjordan/library/wire/api"
+)
+
+// NewISBN makes a valid ISBN-13 from the last nine digits of n so that every
+// run can use new books.
+func NewISBN(t *testing.T, n int64) string {
+	t.Helper()
+	base := 9780000000000 + n%1000000000*10
+	for check := int64(0); check < 10; check++ {
+		isbn, err := library.NewISBN(base + check)
+		if err == nil {
+			return isbn.String()
+		}
+	}
+	t.Fatalf("no check digit for %d", base)
+	return ""
+}
+
+func TestCirculation(t *testing.T) {
+	config.MustParse()
+	handler := api.Wire()
+
+	// copies with a loan history can't be deleted so every run uses new ones.
+	run := time.Now().UnixNano()
+	isbn := NewISBN(t, run)
+	first := fmt.Sprintf("first-%d", run)
+	second := fmt.Sprintf("second-%d", run)
+
+	Send(t, handler, Request(http.MethodPost, "/books", fmt.Sprintf(`{"isbn": %q, "title": "Circulation"}`, isbn)), StatusShouldBe(http.StatusCreated))
+	Send(t, handler, Request(http.MethodPost, "/copies", fmt.Sprintf(`{"barcode": %q, "isbn": %q, "location": "main"}`, first, isbn)), StatusShouldBe(http.StatusCreated))
+	Send(t, handler, Request(http.MethodPost, "/copies", fmt.Sprintf(`{"barcode": %q, "isbn": %q, "location": "main"}`, second, isbn)), StatusShouldBe(http.StatusCreated))
+	Send(t, handler, Request(http.MethodPost, "/copies", fmt.Sprintf(`{"barcode": %q, "isbn": %q, "location": "main"}`, first, isbn)), StatusShouldBe(http.StatusConflict))
+
+	var patron libhttp.Patron
+	Send(t, handler, Request(http.MethodPost, "/patrons", `{"name": "Ada", "borrowing_limit": 1}`), StatusShouldBe(http.StatusCreated), DecodeInto(&patron))
+	checkout := fmt.Sprintf(`{"patron_id": %d}`, patron.Id)
+
+	var loan libhttp.Loan
+	Send(t, handler, Request(http.MethodPost, "/copies/"+first+"/checkout", checkout), StatusShouldBe(http.StatusCreated), DecodeInto(&loan))
+	if loan.Barcode != first || loan.PatronId != patron.Id || loan.ReturnedAt != nil {
+		t.Fatalf("unexpected loan %+v", loan)
+	}
+	Send(t, handler, Request(http.MethodPost, "/copies/"+first+"/checkout", checkout), StatusShouldBe(http.StatusConflict))
+	Send(t, handler, Request(http.MethodPost, "/copies/"+second+"/checkout", checkout), StatusShouldBe(http.StatusConflict))
+	Send(t, handler, Request(http.MethodPut, "/copies/"+first, `{"location": "annex", "status": "lost"}`), StatusShouldBe(http.StatusConflict))
+
+	var loans libhttp.LoanList
+	Send(t, handler, Request(http.MethodGet, fmt.Sprintf("/patrons/%d/loans", patron.Id), ""), StatusShouldBe(http.StatusOK), DecodeInto(&loans))
+	if len(loans.Items) != 1 || loans.Items[0].Id != loan.Id {
+		t.Fatalf("expected loan %d to be open but got %+v", loan.Id, loans.Items)
+	}
+
+	Send(t, handler, Request(http.MethodPost, "/copies/"+first+"/return", ""), StatusShouldBe(http.StatusOK), DecodeInto(&loan))
+	if loan.ReturnedAt == nil {
+		t.Fatalf("expected loan %d to be returned", loan.Id)
+	}
+	Send(t, handler, Request(http.MethodPost, "/copies/"+first+"/return", ""), StatusShouldBe(http.StatusConflict))
+	Send(t, handler, Request(http.MethodPost, "/copies/"+first+"/renew", ""), StatusShouldBe(http.StatusConflict))
+	Send(t, handler, Request(http.MethodPost, "/copies/"+second+"/checkout", checkout), StatusShouldBe(http.StatusCreated))
+	Send(t, handler, Request(http.MethodPost, "/copies/nonexisting-"+first+"/checkout", checkout), StatusShouldBe(http.StatusNotFound))
+	Send(t, handler, Request(http.MethodDelete, fmt.Sprintf("/books/%s", isbn), ""), StatusShouldBe(http.StatusConflict))
+}
@@ -0,0 +1,16 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/slcjordan/library/config"
+	"github.com/slcjordan/library/db"
+	"github.com/slcjordan/library/test/conformance"
+)
+
+func TestPostgresConformance(t *testing.T) {
+	config.MustParse()
+	conn := db.MustConnect()
+	defer conn.Close()
+	conformance.Run(t, &db.Queryer{DBTX: conn})
+}
@@ -0,0 +1,54 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library/config"
+	libhttp "github.com/slcjordan/library/http"
+	"github.com/slcjordan/library/wire/api"
+)
+
+func TestBookETag(t *testing.T) {
+	config.MustParse()
+	handler := api.Wire()
+
+	isbn := NewISBN(t, time.Now().UnixNano())
+	Send(t, handler, Request(http.MethodPost, "/books", fmt.Sprintf(`{"isbn": %q, "title": "Versioned"}`, isbn)), StatusShouldBe(http.StatusCreated))
+	defer Send(t, handler, Request(http.MethodDelete, "/books/"+isbn, ""), StatusShouldBe(http.StatusNoContent))
+
+	resp := Send(t, handler, WithHeader(Request(http.MethodGet, "/books/"+isbn, ""), "If-None-Match", `"0"`), StatusShouldBe(http.StatusOK))
+	first := resp.Header().Get("ETag")
+	if first != `"1"` {
+		t.Fatalf("expected a new book to have etag \"1\" but got %q", first)
+	}
+	var list libhttp.BookList
+	Send(t, handler, Request(http.MethodGet, "/books?sort=isbn&min_isbn="+isbn+"&max_isbn="+isbn, ""), StatusShouldBe(http.StatusOK), DecodeInto(&list))
+	if len(list.Items) != 1 || list.Items[0].Etag == nil || *list.Items[0].Etag != first {
+		t.Fatalf("expected the listed book to have etag %s but got %+v", first, list.Items)
+	}
+	resp = Send(t, handler, WithHeader(Request(http.MethodGet, "/books/"+isbn, ""), "If-None-Match", "W/"+first), StatusShouldBe(http.StatusNotModified))
+	if resp.Body.Len() != 0 {
+		t.Fatalf("expected no body with 304 but got %q", resp.Body)
+	}
+
+	resp = Send(t, handler, WithHeader(Request(http.MethodPut, "/books/"+isbn, `{"title": "First Edit"}`), "If-Match", first), StatusShouldBe(http.StatusOK))
+	second := resp.Header().Get("ETag")
+	if second == first {
+		t.Fatalf("expected the etag to change after an update")
+	}
+	// a second librarian still holding the first version.
+	Send(t, handler, WithHeader(Request(http.MethodPut, "/books/"+isbn, `{"title": "Lost Edit"}`), "If-Match", first), StatusShouldBe(http.StatusPreconditionFailed))
+	Send(t, handler, WithHeader(Request(http.MethodPut, "/books/"+isbn, `{"title": "Lost Edit"}`), "If-Match", "W/"+second), StatusShouldBe(http.StatusPreconditionFailed))
+	Send(t, handler, WithHeader(Request(http.MethodPut, "/books/"+isbn, `{"title": "Second Edit"}`), "If-Match", first+", "+second), StatusShouldBe(http.StatusOK))
+	Send(t, handler, WithHeader(Request(http.MethodPut, "/books/"+isbn, `{"title": "Third Edit"}`), "If-Match", "*"), StatusShouldBe(http.StatusOK))
+	Send(t, handler, WithHeader(Request(http.MethodGet, "/books/"+isbn, ""), "If-None-Match", first+", "+second), StatusShouldBe(http.StatusOK))
+
+	var book libhttp.Book
+	Send(t, handler, Request(http.MethodGet, "/books/"+isbn, ""), StatusShouldBe(http.StatusOK), DecodeInto(&book))
+	if book.Title != "Third Edit" || book.Etag == nil || *book.Etag != `"4"` {
+		t.Fatalf("unexpected book %+v", book)
+	}
+}
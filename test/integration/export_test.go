@@ -0,0 +1,75 @@
+package integration
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library/config"
+	"github.com/slcjordan/library/wire/api"
+)
+
+func TestExportBooks(t *testing.T) {
+	config.MustParse()
+	handler := api.Wire()
+
+	run := time.Now().UnixNano()
+	isbns := []string{NewISBN(t, run), NewISBN(t, run+1)}
+	for i, isbn := range isbns {
+		Send(t, handler, Request(http.MethodPost, "/books", fmt.Sprintf(`{"isbn": %q, "title": "Export %d"}`, isbn, i)), StatusShouldBe(http.StatusCreated))
+		defer Send(t, handler, Request(http.MethodDelete, "/books/"+isbn, ""), StatusShouldBe(http.StatusNoContent))
+	}
+
+	resp := Send(t, handler, Request(http.MethodGet, "/books:export", ""), StatusShouldBe(http.StatusOK))
+	if resp.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("expected ndjson by default but got %q", resp.Header().Get("Content-Type"))
+	}
+	var found []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var book struct {
+			ISBN  string `json:"isbn"`
+			Title string `json:"title"`
+		}
+		err := json.Unmarshal(scanner.Bytes(), &book)
+		if err != nil {
+			t.Fatalf("while decoding %q: %s", scanner.Text(), err)
+		}
+		if book.ISBN == isbns[0] || book.ISBN == isbns[1] {
+			found = append(found, book.Title)
+		}
+	}
+	if fmt.Sprint(found) != "[Export 0 Export 1]" {
+		t.Fatalf("expected both books in isbn order but found %v", found)
+	}
+
+	resp = Send(t, handler, WithHeader(WithHeader(Request(http.MethodGet, "/books:export", ""), "Accept", "application/json, text/csv;q=0.9"), "Accept-Encoding", "gzip"), StatusShouldBe(http.StatusOK))
+	if resp.Header().Get("Content-Type") != "text/csv" || resp.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzipped csv but got %v", resp.Header())
+	}
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(body), "isbn,title,created_at,updated_at\n") || !strings.Contains(string(body), isbns[1]+",Export 1,") {
+		t.Fatalf("unexpected csv export %q", body)
+	}
+
+	resp = Send(t, handler, WithHeader(Request(http.MethodGet, "/books:export", ""), "Accept", "application/vnd.apache.parquet"), StatusShouldBe(http.StatusOK))
+	if !bytes.HasPrefix(resp.Body.Bytes(), []byte("PAR1")) || !bytes.HasSuffix(resp.Body.Bytes(), []byte("PAR1")) {
+		t.Fatalf("expected a parquet file")
+	}
+
+	Send(t, handler, WithHeader(Request(http.MethodGet, "/books:export", ""), "Accept", "application/xml"), StatusShouldBe(http.StatusNotAcceptable))
+}
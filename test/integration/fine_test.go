@@ -0,0 +1,84 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library/config"
+	libhttp "github.com/slcjordan/library/http"
+	"github.com/slcjordan/library/wire/api"
+)
+
+func TestFines(t *testing.T) {
+	config.MustParse()
+	handler := api.Wire()
+
+	// loans are made overdue by checking them out with a loan period in the
+	// past.
+	loanPeriod, schedules := config.Circulation.LoanPeriod, config.Fines.Schedules
+	defer config.Update(func() {
+		config.Circulation.LoanPeriod, config.Fines.Schedules = loanPeriod, schedules
+	})
+	config.Update(func() {
+		config.Circulation.LoanPeriod = -3 * 24 * time.Hour
+		config.Fines.Schedules = map[string]config.FeeSchedule{
+			"standard": {DailyRate: 25, GracePeriod: 24 * time.Hour, Cap: 1000},
+		}
+	})
+
+	run := time.Now().UnixNano()
+	isbn := NewISBN(t, run)
+	barcode := fmt.Sprintf("overdue-%d", run)
+	Send(t, handler, Request(http.MethodPost, "/books", fmt.Sprintf(`{"isbn": %q, "title": "Fines"}`, isbn)), StatusShouldBe(http.StatusCreated))
+	Send(t, handler, Request(http.MethodPost, "/copies", fmt.Sprintf(`{"barcode": %q, "isbn": %q, "location": "main"}`, barcode, isbn)), StatusShouldBe(http.StatusCreated))
+
+	var patron libhttp.Patron
+	Send(t, handler, Request(http.MethodPost, "/patrons", `{"name": "Late", "borrowing_limit": 1}`), StatusShouldBe(http.StatusCreated), DecodeInto(&patron))
+	if patron.Class != "standard" {
+		t.Fatalf("expected the standard class but got %q", patron.Class)
+	}
+	fines := fmt.Sprintf("/patrons/%d/fines", patron.Id)
+	Send(t, handler, Request(http.MethodPost, "/copies/"+barcode+"/checkout", fmt.Sprintf(`{"patron_id": %d}`, patron.Id)), StatusShouldBe(http.StatusCreated))
+
+	var account libhttp.FineAccount
+	Send(t, handler, Request(http.MethodGet, fines, ""), StatusShouldBe(http.StatusOK), DecodeInto(&account))
+	if account.Balance != 0 || account.Accruing != 50 {
+		t.Fatalf("expected 50 cents accruing but got %+v", account)
+	}
+
+	Send(t, handler, Request(http.MethodPost, "/copies/"+barcode+"/return", ""), StatusShouldBe(http.StatusOK))
+	Send(t, handler, Request(http.MethodGet, fines, ""), StatusShouldBe(http.StatusOK), DecodeInto(&account))
+	if account.Balance != 50 || account.Accruing != 0 || len(account.Entries) != 1 || account.Entries[0].Kind != libhttp.LedgerKindFine {
+		t.Fatalf("expected a 50 cent fine but got %+v", account)
+	}
+
+	payments := fmt.Sprintf("/patrons/%d/payments", patron.Id)
+	waivers := fmt.Sprintf("/patrons/%d/waivers", patron.Id)
+	Send(t, handler, Request(http.MethodPost, payments, `{"amount": 60}`), StatusShouldBe(http.StatusConflict))
+	Send(t, handler, Request(http.MethodPost, payments, `{"amount": 0}`), StatusShouldBe(http.StatusBadRequest))
+	Send(t, handler, Request(http.MethodPost, waivers, `{"amount": 10, "note": ""}`), StatusShouldBe(http.StatusBadRequest))
+
+	var entry libhttp.LedgerEntry
+	Send(t, handler, Request(http.MethodPost, payments, `{"amount": 30, "note": "cash"}`), StatusShouldBe(http.StatusCreated), DecodeInto(&entry))
+	if entry.Amount != -30 || entry.Kind != libhttp.LedgerKindPayment {
+		t.Fatalf("expected a 30 cent payment but got %+v", entry)
+	}
+	Send(t, handler, WithHeader(Request(http.MethodPost, waivers, `{"amount": 20, "note": "first offence"}`), "X-Actor", "clerk"), StatusShouldBe(http.StatusCreated))
+	Send(t, handler, Request(http.MethodGet, fines, ""), StatusShouldBe(http.StatusOK), DecodeInto(&account))
+	if account.Balance != 0 || len(account.Entries) != 3 {
+		t.Fatalf("expected the balance to be settled but got %+v", account)
+	}
+	if account.Entries[2].Kind != libhttp.LedgerKindWaiver || account.Entries[2].Actor != "clerk" {
+		t.Fatalf("expected the waiver to record who made it but got %+v", account.Entries[2])
+	}
+
+	Send(t, handler, WithHeader(Request(http.MethodPut, fmt.Sprintf("/patrons/%d", patron.Id), `{"name": "Late", "borrowing_limit": 1, "class": "child"}`), "X-Actor", "supervisor"), StatusShouldBe(http.StatusOK))
+	Send(t, handler, Request(http.MethodGet, fines, ""), StatusShouldBe(http.StatusOK), DecodeInto(&account))
+	if len(account.ClassChanges) != 1 || account.ClassChanges[0].From != "standard" || account.ClassChanges[0].To != "child" ||
+		account.ClassChanges[0].Actor != "supervisor" {
+		t.Fatalf("expected the class change to be recorded but got %+v", account.ClassChanges)
+	}
+	Send(t, handler, Request(http.MethodGet, "/patrons/-1/fines", ""), StatusShouldBe(http.StatusNotFound))
+}
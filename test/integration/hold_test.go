@@ -0,0 +1,114 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library/config"
+	libhttp "github.com/slcjordan/library/http"
+	"github.com/slcjordan/library/wire/api"
+)
+
+func TestHolds(t *testing.T) {
+	config.MustParse()
+	handler := api.Wire()
+
+	run := time.Now().UnixNano()
+	isbn := NewISBN(t, run)
+	barcode := fmt.Sprintf("held-%d", run)
+	Send(t, handler, Request(http.MethodPost, "/books", fmt.Sprintf(`{"isbn": %q, "title": "Holds"}`, isbn)), StatusShouldBe(http.StatusCreated))
+	Send(t, handler, Request(http.MethodPost, "/copies", fmt.Sprintf(`{"barcode": %q, "isbn": %q, "location": "main"}`, barcode, isbn)), StatusShouldBe(http.StatusCreated))
+
+	var borrower libhttp.Patron
+	Send(t, handler, Request(http.MethodPost, "/patrons", `{"name": "Borrower", "borrowing_limit": 5}`), StatusShouldBe(http.StatusCreated), DecodeInto(&borrower))
+	Send(t, handler, Request(http.MethodPost, "/copies/"+barcode+"/checkout", fmt.Sprintf(`{"patron_id": %d}`, borrower.Id)), StatusShouldBe(http.StatusCreated))
+
+	// every patron queues up at the same time; the queue must still be a
+	// strict order without gaps.
+	const waiting = 8
+	patrons := make([]libhttp.Patron, waiting)
+	for i := range patrons {
+		Send(t, handler, Request(http.MethodPost, "/patrons", fmt.Sprintf(`{"name": "Patron %d", "borrowing_limit": 5}`, i)), StatusShouldBe(http.StatusCreated), DecodeInto(&patrons[i]))
+	}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	statuses := make(map[int64]int)
+	for _, p := range patrons {
+		wg.Add(1)
+		go func(p libhttp.Patron) {
+			defer wg.Done()
+			r := httptest.NewRequest(http.MethodPost, "http://"+config.HTTP.ListenAddress+"/api/v1/books/"+isbn+"/holds", strings.NewReader(fmt.Sprintf(`{"patron_id": %d}`, p.Id)))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, r)
+			mu.Lock()
+			statuses[p.Id] = resp.Code
+			mu.Unlock()
+		}(p)
+	}
+	wg.Wait()
+	for _, p := range patrons {
+		if statuses[p.Id] != http.StatusCreated {
+			t.Fatalf("expected patron %d to place a hold but got status %d", p.Id, statuses[p.Id])
+		}
+	}
+	Send(t, handler, Request(http.MethodPost, fmt.Sprintf("/books/%s/holds", isbn), fmt.Sprintf(`{"patron_id": %d}`, patrons[0].Id)), StatusShouldBe(http.StatusConflict))
+
+	var holds libhttp.HoldList
+	Send(t, handler, Request(http.MethodGet, fmt.Sprintf("/books/%s/holds", isbn), ""), StatusShouldBe(http.StatusOK), DecodeInto(&holds))
+	if len(holds.Items) != waiting {
+		t.Fatalf("expected %d holds but got %d", waiting, len(holds.Items))
+	}
+	for i, h := range holds.Items {
+		if h.Position != int32(i+1) || h.Status != libhttp.Waiting {
+			t.Fatalf("expected hold %d to be waiting at position %d but got %+v", h.Id, i+1, h)
+		}
+	}
+	first := holds.Items[0]
+
+	// returning the copy sets it aside for the first patron in line.
+	Send(t, handler, Request(http.MethodPost, "/copies/"+barcode+"/return", ""), StatusShouldBe(http.StatusOK))
+	var copy libhttp.Copy
+	Send(t, handler, Request(http.MethodGet, "/copies/"+barcode, ""), StatusShouldBe(http.StatusOK), DecodeInto(&copy))
+	if copy.Status == nil || *copy.Status != libhttp.OnHoldShelf {
+		t.Fatalf("expected the copy to be on the hold shelf but it is %v", copy.Status)
+	}
+	Send(t, handler, Request(http.MethodGet, fmt.Sprintf("/books/%s/holds", isbn), ""), StatusShouldBe(http.StatusOK), DecodeInto(&holds))
+	if holds.Items[0].Id != first.Id || holds.Items[0].Status != libhttp.Ready || holds.Items[1].Position != 1 {
+		t.Fatalf("expected hold %d to be ready but got %+v", first.Id, holds.Items)
+	}
+	Send(t, handler, Request(http.MethodPost, "/copies/"+barcode+"/checkout", fmt.Sprintf(`{"patron_id": %d}`, holds.Items[1].PatronId)), StatusShouldBe(http.StatusConflict))
+
+	// only one of several simultaneous checkouts by the holder wins.
+	var created int
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := httptest.NewRequest(http.MethodPost, "http://"+config.HTTP.ListenAddress+"/api/v1/copies/"+barcode+"/checkout", strings.NewReader(fmt.Sprintf(`{"patron_id": %d}`, first.PatronId)))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, r)
+			if resp.Code == http.StatusCreated {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if created != 1 {
+		t.Fatalf("expected exactly one checkout but got %d", created)
+	}
+
+	// cancelling a waiting hold moves everyone behind it up.
+	Send(t, handler, Request(http.MethodDelete, fmt.Sprintf("/holds/%d", holds.Items[1].Id), ""), StatusShouldBe(http.StatusNoContent))
+	Send(t, handler, Request(http.MethodDelete, fmt.Sprintf("/holds/%d", holds.Items[1].Id), ""), StatusShouldBe(http.StatusConflict))
+	Send(t, handler, Request(http.MethodGet, fmt.Sprintf("/books/%s/holds", isbn), ""), StatusShouldBe(http.StatusOK), DecodeInto(&holds))
+	if len(holds.Items) != waiting-2 || holds.Items[0].Position != 1 {
+		t.Fatalf("expected %d waiting holds but got %+v", waiting-2, holds.Items)
+	}
+}
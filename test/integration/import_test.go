@@ -0,0 +1,109 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library/config"
+	libhttp "github.com/slcjordan/library/http"
+	"github.com/slcjordan/library/wire/api"
+)
+
+func TestImportBooks(t *testing.T) {
+	config.MustParse()
+	handler := api.Wire()
+
+	run := time.Now().UnixNano()
+	existing, first, second := NewISBN(t, run), NewISBN(t, run+1), NewISBN(t, run+2)
+	Send(t, handler, Request(http.MethodPost, "/books", fmt.Sprintf(`{"isbn": %q, "title": "Existing"}`, existing)), StatusShouldBe(http.StatusCreated))
+	defer Send(t, handler, Request(http.MethodDelete, "/books/"+existing, ""), StatusShouldBe(http.StatusNoContent))
+
+	csv := "isbn,title\n" +
+		existing + ",Replaced\n" +
+		first + ",First\n" +
+		"978-0-306-40615-8,Bad Check Digit\n" +
+		first + ",First Again\n" +
+		second + ",\n"
+
+	var report libhttp.ImportReport
+	Send(t, handler, WithHeader(Request(http.MethodPost, "/books:import?on_conflict=fail", csv), "Content-Type", "text/csv"), StatusShouldBe(http.StatusConflict), DecodeInto(&report))
+	if report.Rows != 5 || report.Imported != 0 || len(report.Errors) != 4 || report.ErrorCount != 4 || report.Truncated {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	for i, row := range []int64{1, 3, 4, 5} {
+		if report.Errors[i].Row != row {
+			t.Fatalf("expected error %d on row %d but got %+v", i, row, report.Errors[i])
+		}
+	}
+	Send(t, handler, Request(http.MethodGet, "/books/"+first, ""), StatusShouldBe(http.StatusNotFound))
+
+	Send(t, handler, WithHeader(Request(http.MethodPost, "/books:import?on_conflict=skip", csv), "Content-Type", "text/csv; charset=utf-8"), StatusShouldBe(http.StatusOK), DecodeInto(&report))
+	defer Send(t, handler, Request(http.MethodDelete, "/books/"+first, ""), StatusShouldBe(http.StatusNoContent))
+	if report.Rows != 5 || report.Imported != 1 || report.Skipped != 1 || len(report.Errors) != 3 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	var book libhttp.Book
+	Send(t, handler, Request(http.MethodGet, "/books/"+first, ""), StatusShouldBe(http.StatusOK), DecodeInto(&book))
+	if book.Title != "First" {
+		t.Fatalf("expected the first row of a duplicated isbn to be imported but got %q", book.Title)
+	}
+
+	ndjson := fmt.Sprintf(`{"isbn": %q, "title": "Replaced"}`+"\n"+`{"isbn": %q, "title": "Second"}`+"\n", existing, second)
+	Send(t, handler, WithHeader(Request(http.MethodPost, "/books:import?on_conflict=overwrite", ndjson), "Content-Type", "application/x-ndjson"), StatusShouldBe(http.StatusOK), DecodeInto(&report))
+	defer Send(t, handler, Request(http.MethodDelete, "/books/"+second, ""), StatusShouldBe(http.StatusNoContent))
+	if report.Rows != 2 || report.Imported != 2 || report.Skipped != 0 || len(report.Errors) != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	Send(t, handler, Request(http.MethodGet, "/books/"+existing, ""), StatusShouldBe(http.StatusOK), DecodeInto(&book))
+	if book.Title != "Replaced" {
+		t.Fatalf("expected the existing book to be overwritten but got %q", book.Title)
+	}
+
+	Send(t, handler, WithHeader(Request(http.MethodPost, "/books:import?on_conflict=skip", ndjson), "Content-Type", "application/json"), StatusShouldBe(http.StatusBadRequest))
+	Send(t, handler, WithHeader(Request(http.MethodPost, "/books:import?on_conflict=merge", csv), "Content-Type", "text/csv"), StatusShouldBe(http.StatusBadRequest))
+}
+
+func TestImportTrashedBook(t *testing.T) {
+	config.MustParse()
+	handler := api.Wire()
+
+	isbn := NewISBN(t, time.Now().UnixNano())
+	Send(t, handler, Request(http.MethodPost, "/books", fmt.Sprintf(`{"isbn": %q, "title": "Trashed"}`, isbn)), StatusShouldBe(http.StatusCreated))
+	Send(t, handler, Request(http.MethodDelete, "/books/"+isbn, ""), StatusShouldBe(http.StatusNoContent))
+
+	var report libhttp.ImportReport
+	Send(t, handler, WithHeader(Request(http.MethodPost, "/books:import?on_conflict=overwrite", "isbn,title\n"+isbn+",Replaced\n"), "Content-Type", "text/csv"), StatusShouldBe(http.StatusOK), DecodeInto(&report))
+	if report.Rows != 1 || report.Imported != 0 || report.Skipped != 0 || report.ErrorCount != 1 || len(report.Errors) != 1 || report.Errors[0].Row != 1 {
+		t.Fatalf("expected the trashed book to be an error but got %+v", report)
+	}
+	var book libhttp.Book
+	Send(t, handler, Request(http.MethodGet, "/books/"+isbn+"?include_deleted=true", ""), StatusShouldBe(http.StatusOK), DecodeInto(&book))
+	if book.Title != "Trashed" || book.DeletedAt == nil {
+		t.Fatalf("expected the trashed book to be left alone but got %+v", book)
+	}
+}
+
+func TestImportManyErrors(t *testing.T) {
+	config.MustParse()
+	handler := api.Wire()
+
+	var csv strings.Builder
+	csv.WriteString("isbn,title\n")
+	rows := 1500
+	for i := 0; i < rows; i++ {
+		csv.WriteString(NewISBN(t, int64(i)) + ",\n")
+	}
+	var report libhttp.ImportReport
+	Send(t, handler, WithHeader(Request(http.MethodPost, "/books:import?on_conflict=fail", csv.String()), "Content-Type", "text/csv"), StatusShouldBe(http.StatusConflict), DecodeInto(&report))
+	if report.Rows != int64(rows) || report.ErrorCount != int64(rows) || !report.Truncated || len(report.Errors) != 1000 {
+		t.Fatalf("expected 1000 of %d errors but got %d of %d", rows, len(report.Errors), report.ErrorCount)
+	}
+	for i, e := range report.Errors {
+		if e.Row != int64(i+1) {
+			t.Fatalf("expected error %d on row %d but got %+v", i, i+1, e)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package integration
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -12,6 +13,9 @@ import (
 
 	"github.com/slcjordan/library/config"
 	_ "github.com/slcjordan/library/config/envvar"
+	_ "github.com/slcjordan/library/config/file"
+	_ "github.com/slcjordan/library/config/secrets"
+	"github.com/slcjordan/library/db"
 	_ "github.com/slcjordan/library/log/stdlib"
 	"github.com/slcjordan/library/wire/api"
 	"github.com/slcjordan/oops"
@@ -21,7 +25,7 @@ type Action func(t *testing.T)
 
 func TestIntegration(t *testing.T) {
 	config.MustParse()
-	config.Postgres.ConnectTimeout = 1 * time.Second
+	config.Update(func() { config.Postgres.ConnectTimeout = 1 * time.Second })
 	dbURL, err := url.Parse(config.Postgres.ConnectionString)
 
 	if err != nil {
@@ -51,15 +55,31 @@ func TestIntegration(t *testing.T) {
 		{
 			Desc: "delete nonexisting book",
 			Action: Do(httptest.NewRequest(
-				http.MethodDelete, "http://"+config.HTTP.ListenAddress+"/api/v1/books/1234567890123", nil,
-			), StatusShouldBe(http.StatusNoContent)),
+				http.MethodDelete, "http://"+config.HTTP.ListenAddress+"/api/v1/books/9780306406157", nil,
+			), StatusShouldBe(http.StatusNotFound)),
+		},
+		{
+			Desc: "get nonexisting book",
+			Action: Do(httptest.NewRequest(
+				http.MethodGet, "http://"+config.HTTP.ListenAddress+"/api/v1/books/9780306406157", nil,
+			), StatusShouldBe(http.StatusNotFound)),
+		},
+		{
+			Desc: "update nonexisting book",
+			Action: Do(httptest.NewRequest(
+				http.MethodPut, "http://"+config.HTTP.ListenAddress+"/api/v1/books/9780306406157", strings.NewReader(`
+				{
+					"title": "Clean Code"
+				}
+				`),
+			), StatusShouldBe(http.StatusNotFound)),
 		},
 		{
 			Desc: "create book happy path",
 			Action: Do(httptest.NewRequest(
 				http.MethodPost, "http://"+config.HTTP.ListenAddress+"/api/v1/books", strings.NewReader(`
 				{
-					"isbn": 1234567890123,
+					"isbn": "978-0-306-40615-7",
 					"title": "Domain Driven Design"
 				}
 				`),
@@ -70,43 +90,120 @@ func TestIntegration(t *testing.T) {
 			Action: Do(httptest.NewRequest(
 				http.MethodPost, "http://"+config.HTTP.ListenAddress+"/api/v1/books", strings.NewReader(`
 				{
-					"isbn": 1234567890123,
+					"isbn": "978-0-306-40615-7",
+					"title": "Domain Driven Design"
+				}
+				`),
+			), StatusShouldBe(http.StatusConflict)),
+		},
+		{
+			Desc: "create book with wrong check digit",
+			Action: Do(httptest.NewRequest(
+				http.MethodPost, "http://"+config.HTTP.ListenAddress+"/api/v1/books", strings.NewReader(`
+				{
+					"isbn": "978-0-306-40615-8",
 					"title": "Domain Driven Design"
 				}
 				`),
 			), StatusShouldBe(http.StatusBadRequest)),
 		},
+		{
+			Desc: "get book by isbn-10",
+			Action: Do(httptest.NewRequest(
+				http.MethodGet, "http://"+config.HTTP.ListenAddress+"/api/v1/books/0-306-40615-2", nil,
+			), StatusShouldBe(http.StatusOK)),
+		},
 		{
 			Desc: "list books happy path",
 			Action: Do(httptest.NewRequest(
 				http.MethodGet, "http://"+config.HTTP.ListenAddress+"/api/v1/books", nil,
 			), StatusShouldBe(http.StatusOK)),
 		},
+		{
+			Desc: "list books with tampered page token",
+			Action: Do(httptest.NewRequest(
+				http.MethodGet, "http://"+config.HTTP.ListenAddress+"/api/v1/books?page_token=Domain", nil,
+			), StatusShouldBe(http.StatusBadRequest)),
+		},
 		{
 			Desc: "get book happy path",
 			Action: Do(httptest.NewRequest(
-				http.MethodGet, "http://"+config.HTTP.ListenAddress+"/api/v1/books/1234567890123", nil,
+				http.MethodGet, "http://"+config.HTTP.ListenAddress+"/api/v1/books/9780306406157", nil,
 			), StatusShouldBe(http.StatusOK)),
 		},
 		{
 			Desc: "update book happy path",
 			Action: Do(httptest.NewRequest(
-				http.MethodPut, "http://"+config.HTTP.ListenAddress+"/api/v1/books/1234567890123", strings.NewReader(`
+				http.MethodPut, "http://"+config.HTTP.ListenAddress+"/api/v1/books/9780306406157", strings.NewReader(`
 				{
-					"isbn": 1234567890123,
+					"isbn": "978-0-306-40615-7",
 					"title": "Clean Code"
 				}
 				`),
 			), StatusShouldBe(http.StatusOK)),
 		},
-		/*
+		{
+			Desc: "create author happy path",
+			Action: Do(httptest.NewRequest(
+				http.MethodPost, "http://"+config.HTTP.ListenAddress+"/api/v1/authors", strings.NewReader(`
+				{
+					"name": "Eric Evans"
+				}
+				`),
+			), StatusShouldBe(http.StatusCreated)),
+		},
+		{
+			Desc: "create author with blank name",
+			Action: Do(httptest.NewRequest(
+				http.MethodPost, "http://"+config.HTTP.ListenAddress+"/api/v1/authors", strings.NewReader(`
+				{
+					"name": "  "
+				}
+				`),
+			), StatusShouldBe(http.StatusBadRequest)),
+		},
+		{
+			Desc: "list authors happy path",
+			Action: Do(httptest.NewRequest(
+				http.MethodGet, "http://"+config.HTTP.ListenAddress+"/api/v1/authors", nil,
+			), StatusShouldBe(http.StatusOK)),
+		},
+		{
+			Desc: "get nonexisting author",
+			Action: Do(httptest.NewRequest(
+				http.MethodGet, "http://"+config.HTTP.ListenAddress+"/api/v1/authors/-1", nil,
+			), StatusShouldBe(http.StatusNotFound)),
+		},
+		{
+			Desc: "list books by nonexisting author",
+			Action: Do(httptest.NewRequest(
+				http.MethodGet, "http://"+config.HTTP.ListenAddress+"/api/v1/authors/-1/books", nil,
+			), StatusShouldBe(http.StatusNotFound)),
+		},
+		{
+			Desc: "create book with nonexisting author",
+			Action: Do(httptest.NewRequest(
+				http.MethodPost, "http://"+config.HTTP.ListenAddress+"/api/v1/books", strings.NewReader(`
+				{
+					"isbn": "978-1-4028-9462-6",
+					"title": "Refactoring",
+					"author_ids": [-1]
+				}
+				`),
+			), StatusShouldBe(http.StatusBadRequest)),
+		},
+		{
+			Desc: "book with nonexisting author is not created",
+			Action: Do(httptest.NewRequest(
+				http.MethodGet, "http://"+config.HTTP.ListenAddress+"/api/v1/books/9781402894626", nil,
+			), StatusShouldBe(http.StatusNotFound)),
+		},
 		{
 			Desc: "delete book cleanup",
 			Action: Do(httptest.NewRequest(
-				http.MethodDelete, "http://"+config.HTTP.ListenAddress+"/api/v1/books/1234567890123", nil,
+				http.MethodDelete, "http://"+config.HTTP.ListenAddress+"/api/v1/books/9780306406157", nil,
 			), StatusShouldBe(http.StatusNoContent)),
 		},
-		*/
 	} {
 		func() {
 			ctx, cancel := context.WithCancel(context.Background())
@@ -114,35 +211,75 @@ func TestIntegration(t *testing.T) {
 			proxy := NewTestTCPProxy(dbURL.Host, test.NetworkConditions)
 			curr := *dbURL
 			curr.Host = proxy.Addr
-			config.Postgres.ConnectionString = curr.String()
+			config.Update(func() { config.Postgres.ConnectionString = curr.String() })
 			//nolint:errcheck
 			go proxy.Run(ctx)
 			t.Run(test.Desc, test.Action)
 		}()
 	}
+
+	// the book is only in the trash; purge it so the next run can create it.
+	config.Update(func() { config.Postgres.ConnectionString = dbURL.String() })
+	conn := db.MustConnect()
+	defer conn.Close()
+	_, err = (&db.Queryer{DBTX: conn}).PurgeBooks(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("while purging books: %s", err)
+	}
 }
 
+// A Validator checks the response to a request.
 type Validator func(*httptest.ResponseRecorder) error
 
+// Do sends r to a newly wired api and validates the response.
 func Do(r *http.Request, validators ...Validator) Action {
 	return func(t *testing.T) {
-		handler := api.Wire()
-		resp := httptest.NewRecorder()
-		handler.ServeHTTP(resp, r)
-
-		for _, v := range validators {
-			err := v(resp)
-			if err != nil {
-				t.Fatalf("while validating response: %s", err)
-			}
+		Send(t, api.Wire(), r, validators...)
+	}
+}
+
+// Send sends r to handler, validates the response and returns it.
+func Send(t *testing.T, handler http.Handler, r *http.Request, validators ...Validator) *httptest.ResponseRecorder {
+	t.Helper()
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, r)
+
+	for _, v := range validators {
+		err := v(resp)
+		if err != nil {
+			t.Fatalf("%s %s: while validating response: %s", r.Method, r.URL.RequestURI(), err)
 		}
 	}
+	return resp
+}
+
+// Request makes a request for path under the api.
+func Request(method string, path string, body string) *http.Request {
+	return httptest.NewRequest(method, "http://"+config.HTTP.ListenAddress+"/api/v1"+path, strings.NewReader(body))
+}
+
+// WithHeader sets a header of r and returns r.
+func WithHeader(r *http.Request, name string, value string) *http.Request {
+	r.Header.Set(name, value)
+	return r
 }
 
 func StatusShouldBe(status int) Validator {
 	return func(resp *httptest.ResponseRecorder) error {
 		if resp.Code != status {
-			return fmt.Errorf("expected response status %d but got %d", status, resp.Code)
+			return fmt.Errorf("expected response status %d but got %d: %s", status, resp.Code, resp.Body)
+		}
+		return nil
+	}
+}
+
+// DecodeInto decodes the JSON response body into result. The body can still
+// be read afterwards.
+func DecodeInto(result any) Validator {
+	return func(resp *httptest.ResponseRecorder) error {
+		err := json.Unmarshal(resp.Body.Bytes(), result)
+		if err != nil {
+			return fmt.Errorf("while decoding response: %w", err)
 		}
 		return nil
 	}
@@ -0,0 +1,68 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library/config"
+	libhttp "github.com/slcjordan/library/http"
+	"github.com/slcjordan/library/wire/api"
+)
+
+func TestListBooksFilterAndSort(t *testing.T) {
+	config.MustParse()
+	handler := api.Wire()
+
+	run := time.Now().UnixNano()
+	prefix := fmt.Sprintf("Sorted %d ", run)
+
+	// created in neither title nor isbn order.
+	isbns := []string{NewISBN(t, run+2), NewISBN(t, run), NewISBN(t, run+1)}
+	titles := []string{prefix + "A", prefix + "C", prefix + "B"}
+	for i := range isbns {
+		Send(t, handler, Request(http.MethodPost, "/books", fmt.Sprintf(`{"isbn": %q, "title": %q}`, isbns[i], titles[i])), StatusShouldBe(http.StatusCreated))
+		defer Send(t, handler, Request(http.MethodDelete, "/books/"+isbns[i], ""), StatusShouldBe(http.StatusNoContent))
+	}
+
+	list := func(query string) []string {
+		var result []string
+		token := ""
+		for {
+			var page libhttp.BookList
+			Send(t, handler, Request(http.MethodGet, fmt.Sprintf("/books?total_size=1&title_prefix=%s&%s&page_token=%s", url.QueryEscape(prefix), query, token), ""), StatusShouldBe(http.StatusOK), DecodeInto(&page))
+			for _, b := range page.Items {
+				result = append(result, b.Isbn)
+			}
+			if page.NextPageToken == "" {
+				return result
+			}
+			token = page.NextPageToken
+		}
+	}
+	for query, expected := range map[string][]string{
+		"sort=title":                     {isbns[0], isbns[2], isbns[1]},
+		"sort=title&direction=desc":      {isbns[1], isbns[2], isbns[0]},
+		"sort=isbn":                      {isbns[1], isbns[2], isbns[0]},
+		"sort=isbn&direction=desc":       {isbns[0], isbns[2], isbns[1]},
+		"sort=created_at":                isbns,
+		"sort=created_at&direction=desc": {isbns[2], isbns[1], isbns[0]},
+		"min_isbn=" + isbns[2]:           {isbns[0], isbns[2]},
+		"max_isbn=" + isbns[2]:           {isbns[2], isbns[1]},
+		"created_after=" + url.QueryEscape(time.Now().Add(time.Hour).Format(time.RFC3339)): nil,
+	} {
+		actual := list(query)
+		if fmt.Sprint(actual) != fmt.Sprint(expected) {
+			t.Fatalf("%s: expected %v but got %v", query, expected, actual)
+		}
+	}
+
+	var page libhttp.BookList
+	Send(t, handler, Request(http.MethodGet, "/books?total_size=1&sort=title&title_prefix="+url.QueryEscape(prefix), ""), StatusShouldBe(http.StatusOK), DecodeInto(&page))
+	Send(t, handler, Request(http.MethodGet, "/books?total_size=1&sort=isbn&page_token="+page.NextPageToken, ""), StatusShouldBe(http.StatusBadRequest))
+	Send(t, handler, Request(http.MethodGet, "/books?direction=sideways", ""), StatusShouldBe(http.StatusBadRequest))
+	Send(t, handler, Request(http.MethodGet, "/books?sort=popularity", ""), StatusShouldBe(http.StatusBadRequest))
+	Send(t, handler, Request(http.MethodGet, "/books?min_isbn=123", ""), StatusShouldBe(http.StatusBadRequest))
+}
@@ -0,0 +1,40 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/slcjordan/library/config"
+	"github.com/slcjordan/library/db"
+	"github.com/slcjordan/library/db/migrate"
+)
+
+func TestMigrate(t *testing.T) {
+	config.MustParse()
+	ctx := context.Background()
+	pool := db.MustConnect()
+	defer pool.Close()
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer conn.Release()
+	migrator := &migrate.Migrator{Conn: conn}
+
+	ran, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(ran) != 0 {
+		t.Fatalf("expected the test database to be migrated but ran %d migrations", len(ran))
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, s := range statuses {
+		if !s.Applied {
+			t.Fatalf("expected %s to be applied", s.Migration.Name)
+		}
+	}
+}
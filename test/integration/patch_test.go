@@ -0,0 +1,75 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library/config"
+	libhttp "github.com/slcjordan/library/http"
+	"github.com/slcjordan/library/wire/api"
+)
+
+func TestPatchBook(t *testing.T) {
+	config.MustParse()
+	handler := api.Wire()
+
+	const (
+		merge     = "application/merge-patch+json"
+		jsonPatch = "application/json-patch+json"
+	)
+	var first, second libhttp.Author
+	Send(t, handler, Request(http.MethodPost, "/authors", `{"name": "Patch First"}`), StatusShouldBe(http.StatusCreated), DecodeInto(&first))
+	Send(t, handler, Request(http.MethodPost, "/authors", `{"name": "Patch Second"}`), StatusShouldBe(http.StatusCreated), DecodeInto(&second))
+	isbn := NewISBN(t, time.Now().UnixNano())
+	Send(t, handler, Request(http.MethodPost, "/books", fmt.Sprintf(`{"isbn": %q, "title": "Unpatched"}`, isbn)), StatusShouldBe(http.StatusCreated))
+	defer Send(t, handler, Request(http.MethodDelete, "/books/"+isbn, ""), StatusShouldBe(http.StatusNoContent))
+
+	var book libhttp.Book
+	resp := Send(t, handler, WithHeader(WithHeader(Request(http.MethodPatch, "/books/"+isbn, fmt.Sprintf(`{"title": "Merged", "author_ids": [%d]}`, first.Id)), "Content-Type", merge), "If-Match", `"1"`), StatusShouldBe(http.StatusOK), DecodeInto(&book))
+	if book.Title != "Merged" || book.Authors == nil || len(*book.Authors) != 1 || (*book.Authors)[0].Id != first.Id {
+		t.Fatalf("unexpected book after merge patch %+v", book)
+	}
+	if tag := resp.Header().Get("ETag"); tag != `"2"` {
+		t.Fatalf("expected etag \"2\" after merge patch but got %q", tag)
+	}
+
+	ops := fmt.Sprintf(`[
+		{"op": "test", "path": "/title", "value": "Merged"},
+		{"op": "replace", "path": "/title", "value": "Patched"},
+		{"op": "add", "path": "/author_ids/-", "value": %d}
+	]`, second.Id)
+	Send(t, handler, WithHeader(Request(http.MethodPatch, "/books/"+isbn, ops), "Content-Type", jsonPatch), StatusShouldBe(http.StatusOK), DecodeInto(&book))
+	if book.Title != "Patched" || book.Authors == nil || len(*book.Authors) != 2 {
+		t.Fatalf("unexpected book after json patch %+v", book)
+	}
+
+	// a failed test leaves the book as it was.
+	Send(t, handler, WithHeader(Request(http.MethodPatch, "/books/"+isbn, `[
+		{"op": "replace", "path": "/title", "value": "Never"},
+		{"op": "test", "path": "/title", "value": "Merged"}
+	]`), "Content-Type", jsonPatch), StatusShouldBe(http.StatusBadRequest))
+	// a stale etag.
+	Send(t, handler, WithHeader(WithHeader(Request(http.MethodPatch, "/books/"+isbn, `{"title": "Never"}`), "Content-Type", merge), "If-Match", `"1"`), StatusShouldBe(http.StatusPreconditionFailed))
+
+	var failure libhttp.Error
+	Send(t, handler, WithHeader(Request(http.MethodPatch, "/books/"+isbn, `{"isbn": "9780000000002", "title": "", "shelf": "A1"}`), "Content-Type", merge), StatusShouldBe(http.StatusBadRequest), DecodeInto(&failure))
+	if failure.Fields == nil || len(*failure.Fields) != 3 {
+		t.Fatalf("expected errors for isbn, title and shelf but got %+v", failure)
+	}
+	Send(t, handler, WithHeader(Request(http.MethodPatch, "/books/"+isbn, `{"author_ids": [-1]}`), "Content-Type", merge), StatusShouldBe(http.StatusBadRequest), DecodeInto(&failure))
+	if failure.Fields == nil || (*failure.Fields)[0].Field != "author_ids" {
+		t.Fatalf("expected an error for author_ids but got %+v", failure)
+	}
+
+	Send(t, handler, Request(http.MethodGet, "/books/"+isbn, ""), StatusShouldBe(http.StatusOK), DecodeInto(&book))
+	if book.Title != "Patched" || book.Etag == nil || *book.Etag != `"3"` {
+		t.Fatalf("expected failed patches to change nothing but got %+v", book)
+	}
+	// removing the authors with a merge patch.
+	Send(t, handler, WithHeader(Request(http.MethodPatch, "/books/"+isbn, `{"author_ids": null}`), "Content-Type", merge), StatusShouldBe(http.StatusOK), DecodeInto(&book))
+	if book.Authors == nil || len(*book.Authors) != 0 {
+		t.Fatalf("expected no authors but got %+v", book.Authors)
+	}
+}
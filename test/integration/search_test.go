@@ -0,0 +1,82 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library/config"
+	libhttp "github.com/slcjordan/library/http"
+	"github.com/slcjordan/library/wire/api"
+)
+
+func TestSearchBooks(t *testing.T) {
+	config.MustParse()
+	handler := api.Wire()
+
+	run := time.Now().UnixNano()
+	titles := map[string]string{
+		NewISBN(t, run):   "Harry Potter and the Philosopher's Stone",
+		NewISBN(t, run+1): "Harry Potter and the Chamber of Secrets",
+		NewISBN(t, run+2): "The Hobbit",
+	}
+	for isbn, title := range titles {
+		Send(t, handler, Request(http.MethodPost, "/books", fmt.Sprintf(`{"isbn": %q, "title": %q}`, isbn, title)), StatusShouldBe(http.StatusCreated))
+		defer Send(t, handler, Request(http.MethodDelete, "/books/"+isbn, ""), StatusShouldBe(http.StatusNoContent))
+	}
+
+	search := func(query string) map[string]libhttp.SearchResult {
+		found := make(map[string]libhttp.SearchResult)
+		token := ""
+		lastScore := 0.0
+		for {
+			var page libhttp.SearchResultList
+			path := fmt.Sprintf("/books/search?total_size=1&q=%s&page_token=%s", url.QueryEscape(query), token)
+			Send(t, handler, Request(http.MethodGet, path, ""), StatusShouldBe(http.StatusOK), DecodeInto(&page))
+			for _, item := range page.Items {
+				if _, ok := found[item.Isbn]; ok {
+					t.Fatalf("%q: %s is on more than one page", query, item.Isbn)
+				}
+				if len(found) > 0 && item.Score > lastScore {
+					t.Fatalf("%q: results are not ranked", query)
+				}
+				found[item.Isbn] = item
+				lastScore = item.Score
+			}
+			if page.NextPageToken == "" {
+				return found
+			}
+			token = page.NextPageToken
+		}
+	}
+
+	found := search("harry poter")
+	for isbn, title := range titles {
+		_, ok := found[isbn]
+		if ok != (title != "The Hobbit") {
+			t.Fatalf("expected %q to be found %t for a misspelled query", title, !ok)
+		}
+	}
+	found = search("chamber secrets")
+	for isbn, title := range titles {
+		if title != "Harry Potter and the Chamber of Secrets" {
+			continue
+		}
+		result, ok := found[isbn]
+		if !ok {
+			t.Fatalf("expected %q to be found", title)
+		}
+		if result.Snippet != "Harry Potter and the <b>Chamber</b> of <b>Secrets</b>" {
+			t.Fatalf("unexpected snippet %q", result.Snippet)
+		}
+	}
+
+	Send(t, handler, Request(http.MethodGet, "/books/search?q=", ""), StatusShouldBe(http.StatusBadRequest))
+	var page libhttp.SearchResultList
+	Send(t, handler, Request(http.MethodGet, "/books/search?total_size=1&q=harry", ""), StatusShouldBe(http.StatusOK), DecodeInto(&page))
+	if page.NextPageToken != "" {
+		Send(t, handler, Request(http.MethodGet, "/books/search?total_size=1&q=hobbit&page_token="+page.NextPageToken, ""), StatusShouldBe(http.StatusBadRequest))
+	}
+}
@@ -0,0 +1,90 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library/config"
+	"github.com/slcjordan/library/db"
+	libhttp "github.com/slcjordan/library/http"
+	"github.com/slcjordan/library/wire/api"
+)
+
+func TestTrash(t *testing.T) {
+	config.MustParse()
+	handler := api.Wire()
+
+	isbn := NewISBN(t, time.Now().UnixNano())
+	Send(t, handler, Request(http.MethodPost, "/books", fmt.Sprintf(`{"isbn": %q, "title": "Trashed"}`, isbn)), StatusShouldBe(http.StatusCreated))
+	Send(t, handler, Request(http.MethodPost, "/books/"+isbn+":restore", ""), StatusShouldBe(http.StatusConflict))
+	Send(t, handler, Request(http.MethodDelete, "/books/"+isbn, ""), StatusShouldBe(http.StatusNoContent))
+
+	Send(t, handler, Request(http.MethodGet, "/books/"+isbn, ""), StatusShouldBe(http.StatusNotFound))
+	Send(t, handler, Request(http.MethodPut, "/books/"+isbn, `{"title": "Edited"}`), StatusShouldBe(http.StatusNotFound))
+	Send(t, handler, Request(http.MethodDelete, "/books/"+isbn, ""), StatusShouldBe(http.StatusNotFound))
+	var book libhttp.Book
+	Send(t, handler, Request(http.MethodGet, "/books/"+isbn+"?include_deleted=true", ""), StatusShouldBe(http.StatusOK), DecodeInto(&book))
+	if book.DeletedAt == nil || book.Title != "Trashed" {
+		t.Fatalf("expected a trashed book but got %+v", book)
+	}
+	var list libhttp.BookList
+	Send(t, handler, Request(http.MethodGet, "/books?sort=isbn&min_isbn="+isbn+"&max_isbn="+isbn, ""), StatusShouldBe(http.StatusOK), DecodeInto(&list))
+	if len(list.Items) != 0 {
+		t.Fatalf("expected the trashed book to be hidden but got %+v", list.Items)
+	}
+	Send(t, handler, Request(http.MethodGet, "/books?sort=isbn&include_deleted=true&min_isbn="+isbn+"&max_isbn="+isbn, ""), StatusShouldBe(http.StatusOK), DecodeInto(&list))
+	if len(list.Items) != 1 || list.Items[0].DeletedAt == nil {
+		t.Fatalf("expected the trashed book to be listed but got %+v", list.Items)
+	}
+
+	var restored libhttp.Book
+	Send(t, handler, Request(http.MethodPost, "/books/"+isbn+":restore", ""), StatusShouldBe(http.StatusOK), DecodeInto(&restored))
+	if restored.DeletedAt != nil || restored.Etag == nil || *restored.Etag == *book.Etag {
+		t.Fatalf("expected a restored book at a new version but got %+v", restored)
+	}
+	Send(t, handler, Request(http.MethodGet, "/books/"+isbn, ""), StatusShouldBe(http.StatusOK))
+
+	Send(t, handler, Request(http.MethodDelete, "/books/"+isbn, ""), StatusShouldBe(http.StatusNoContent))
+	conn := db.MustConnect()
+	defer conn.Close()
+	queryer := &db.Queryer{DBTX: conn}
+	_, err := queryer.PurgeBooks(context.Background(), time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	Send(t, handler, Request(http.MethodGet, "/books/"+isbn+"?include_deleted=true", ""), StatusShouldBe(http.StatusOK))
+	_, err = queryer.PurgeBooks(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	Send(t, handler, Request(http.MethodGet, "/books/"+isbn+"?include_deleted=true", ""), StatusShouldBe(http.StatusNotFound))
+	Send(t, handler, Request(http.MethodPost, "/books/"+isbn+":restore", ""), StatusShouldBe(http.StatusNotFound))
+}
+
+func TestTrashHeldBook(t *testing.T) {
+	config.MustParse()
+	handler := api.Wire()
+
+	isbn := NewISBN(t, time.Now().UnixNano())
+	Send(t, handler, Request(http.MethodPost, "/books", fmt.Sprintf(`{"isbn": %q, "title": "Held"}`, isbn)), StatusShouldBe(http.StatusCreated))
+	var patron libhttp.Patron
+	Send(t, handler, Request(http.MethodPost, "/patrons", `{"name": "Holder", "borrowing_limit": 5}`), StatusShouldBe(http.StatusCreated), DecodeInto(&patron))
+	var hold libhttp.Hold
+	Send(t, handler, Request(http.MethodPost, "/books/"+isbn+"/holds", fmt.Sprintf(`{"patron_id": %d}`, patron.Id)), StatusShouldBe(http.StatusCreated), DecodeInto(&hold))
+	Send(t, handler, Request(http.MethodDelete, "/books/"+isbn, ""), StatusShouldBe(http.StatusConflict))
+
+	// a hold that is no longer active is history and doesn't keep the book
+	// out of the trash.
+	Send(t, handler, Request(http.MethodDelete, fmt.Sprintf("/holds/%d", hold.Id), ""), StatusShouldBe(http.StatusNoContent))
+	Send(t, handler, Request(http.MethodDelete, "/books/"+isbn, ""), StatusShouldBe(http.StatusNoContent))
+	conn := db.MustConnect()
+	defer conn.Close()
+	_, err := (&db.Queryer{DBTX: conn}).PurgeBooks(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	Send(t, handler, Request(http.MethodGet, "/books/"+isbn+"?include_deleted=true", ""), StatusShouldBe(http.StatusNotFound))
+}
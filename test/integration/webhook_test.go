@@ -0,0 +1,137 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library/config"
+	"github.com/slcjordan/library/db"
+	libhttp "github.com/slcjordan/library/http"
+	"github.com/slcjordan/library/webhook"
+	"github.com/slcjordan/library/wire/api"
+)
+
+// receiver is a partner system that fails the first failures deliveries.
+type receiver struct {
+	mu       sync.Mutex
+	secret   string
+	failures int
+	records  []libhttp.AuditRecord
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	payload, _ := io.ReadAll(r.Body)
+	if !webhook.Verify(rc.secret, r.Header.Get(webhook.TimestampHeader), payload, r.Header.Get(webhook.SignatureHeader)) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if rc.failures > 0 {
+		rc.failures--
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	var record libhttp.AuditRecord
+	//nolint:errcheck // a bad record fails the checks on it.
+	json.Unmarshal(payload, &record)
+	rc.records = append(rc.records, record)
+}
+
+func TestWebhooks(t *testing.T) {
+	config.MustParse()
+	handler := api.Wire()
+	rc := &receiver{secret: "shared secret", failures: 1}
+	partner := httptest.NewServer(rc)
+	defer partner.Close()
+
+	Send(t, handler, Request(http.MethodPost, "/webhooks", `{"url": "not a url", "events": ["borrow"]}`), StatusShouldBe(http.StatusBadRequest))
+	var hook libhttp.Webhook
+	Send(t, handler, Request(http.MethodPost, "/webhooks", fmt.Sprintf(`{"url": %q, "secret": %q, "events": ["create", "delete"]}`, partner.URL, rc.secret)), StatusShouldBe(http.StatusCreated), DecodeInto(&hook))
+	defer Send(t, handler, Request(http.MethodDelete, fmt.Sprintf("/webhooks/%d", hook.Id), ""), StatusShouldBe(http.StatusNoContent))
+	if hook.Secret == nil || *hook.Secret != rc.secret || !hook.Active {
+		t.Fatalf("expected an active webhook with its secret but got %+v", hook)
+	}
+	var fetched libhttp.Webhook
+	Send(t, handler, Request(http.MethodGet, fmt.Sprintf("/webhooks/%d", hook.Id), ""), StatusShouldBe(http.StatusOK), DecodeInto(&fetched))
+	if fetched.Secret != nil || len(fetched.Events) != 2 {
+		t.Fatalf("expected the webhook without its secret but got %+v", fetched)
+	}
+
+	isbn := NewISBN(t, time.Now().UnixNano())
+	Send(t, handler, Request(http.MethodPost, "/books", fmt.Sprintf(`{"isbn": %q, "title": "Signed And Delivered"}`, isbn)), StatusShouldBe(http.StatusCreated))
+	Send(t, handler, Request(http.MethodPut, "/books/"+isbn, `{"title": "Not Sent"}`), StatusShouldBe(http.StatusOK))
+	Send(t, handler, Request(http.MethodDelete, "/books/"+isbn, ""), StatusShouldBe(http.StatusNoContent))
+
+	conn := db.MustConnect()
+	defer conn.Close()
+	deliverer := &webhook.Deliverer{
+		Store:       &db.Queryer{DBTX: conn},
+		Client:      &http.Client{Timeout: 5 * time.Second},
+		MaxAttempts: 2,
+		BatchSize:   100,
+		Lease:       time.Minute,
+	}
+	ctx := context.Background()
+	// the first attempt at the create fails and is retried right away since
+	// there is no backoff. The update isn't subscribed to.
+	for i := 0; i < 2; i++ {
+		_, err := deliverer.DeliverPending(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if len(rc.records) != 2 || rc.records[0].Action != "delete" || rc.records[1].Action != "create" || rc.records[1].Isbn != isbn {
+		t.Fatalf("expected the delete and the retried create to be received but got %+v", rc.records)
+	}
+	var deliveries libhttp.WebhookDeliveryList
+	Send(t, handler, Request(http.MethodGet, fmt.Sprintf("/webhooks/%d/deliveries", hook.Id), ""), StatusShouldBe(http.StatusOK), DecodeInto(&deliveries))
+	if len(deliveries.Items) != 2 {
+		t.Fatalf("expected 2 deliveries but got %+v", deliveries.Items)
+	}
+	for _, d := range deliveries.Items {
+		attempts := int32(1)
+		if d.Event == "create" {
+			attempts = 2
+		}
+		if d.Status != "delivered" || d.Attempts != attempts || d.ResponseStatus != http.StatusOK || d.DeliveredAt == nil {
+			t.Fatalf("expected a delivered %s after %d attempts but got %+v", d.Event, attempts, d)
+		}
+	}
+
+	// a partner that keeps failing gets a dead delivery, which can be sent
+	// again by hand.
+	rc.failures = 2
+	Send(t, handler, Request(http.MethodPost, "/books/"+isbn+":restore", ""), StatusShouldBe(http.StatusOK))
+	Send(t, handler, Request(http.MethodDelete, "/books/"+isbn, ""), StatusShouldBe(http.StatusNoContent))
+	for i := 0; i < 2; i++ {
+		_, err := deliverer.DeliverPending(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	var dead libhttp.WebhookDeliveryList
+	Send(t, handler, Request(http.MethodGet, fmt.Sprintf("/webhooks/%d/deliveries?status=dead", hook.Id), ""), StatusShouldBe(http.StatusOK), DecodeInto(&dead))
+	if len(dead.Items) != 1 || dead.Items[0].ResponseStatus != http.StatusInternalServerError || dead.Items[0].LastError == "" {
+		t.Fatalf("expected a dead delivery but got %+v", dead.Items)
+	}
+	var redelivered libhttp.WebhookDelivery
+	Send(t, handler, Request(http.MethodPost, fmt.Sprintf("/webhooks/%d/deliveries/%d:redeliver", hook.Id, dead.Items[0].Id), ""), StatusShouldBe(http.StatusOK), DecodeInto(&redelivered))
+	if redelivered.Status != "pending" || redelivered.Attempts != 0 {
+		t.Fatalf("expected the delivery to be pending again but got %+v", redelivered)
+	}
+	_, err := deliverer.DeliverPending(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(rc.records) != 3 || rc.records[2].Action != "delete" {
+		t.Fatalf("expected the redelivered delete to be received but got %+v", rc.records)
+	}
+}
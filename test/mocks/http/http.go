@@ -7,6 +7,7 @@ package http
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	library "github.com/slcjordan/library"
@@ -36,18 +37,56 @@ func (m *MockListBooksController) EXPECT() *MockListBooksControllerMockRecorder
 }
 
 // ListBooks mocks base method.
-func (m *MockListBooksController) ListBooks(ctx context.Context, PageToken string, TotalSize int32) (library.BookList, error) {
+func (m *MockListBooksController) ListBooks(ctx context.Context, filter library.BookFilter, PageToken string, TotalSize int32) (library.BookList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListBooks", ctx, PageToken, TotalSize)
+	ret := m.ctrl.Call(m, "ListBooks", ctx, filter, PageToken, TotalSize)
 	ret0, _ := ret[0].(library.BookList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListBooks indicates an expected call of ListBooks.
-func (mr *MockListBooksControllerMockRecorder) ListBooks(ctx, PageToken, TotalSize interface{}) *gomock.Call {
+func (mr *MockListBooksControllerMockRecorder) ListBooks(ctx, filter, PageToken, TotalSize interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBooks", reflect.TypeOf((*MockListBooksController)(nil).ListBooks), ctx, PageToken, TotalSize)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBooks", reflect.TypeOf((*MockListBooksController)(nil).ListBooks), ctx, filter, PageToken, TotalSize)
+}
+
+// MockSearchController is a mock of SearchController interface.
+type MockSearchController struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchControllerMockRecorder
+}
+
+// MockSearchControllerMockRecorder is the mock recorder for MockSearchController.
+type MockSearchControllerMockRecorder struct {
+	mock *MockSearchController
+}
+
+// NewMockSearchController creates a new mock instance.
+func NewMockSearchController(ctrl *gomock.Controller) *MockSearchController {
+	mock := &MockSearchController{ctrl: ctrl}
+	mock.recorder = &MockSearchControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearchController) EXPECT() *MockSearchControllerMockRecorder {
+	return m.recorder
+}
+
+// SearchBooks mocks base method.
+func (m *MockSearchController) SearchBooks(ctx context.Context, query, PageToken string, TotalSize int32) (library.SearchResultList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchBooks", ctx, query, PageToken, TotalSize)
+	ret0, _ := ret[0].(library.SearchResultList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchBooks indicates an expected call of SearchBooks.
+func (mr *MockSearchControllerMockRecorder) SearchBooks(ctx, query, PageToken, TotalSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBooks", reflect.TypeOf((*MockSearchController)(nil).SearchBooks), ctx, query, PageToken, TotalSize)
 }
 
 // MockBookCRUDController is a mock of BookCRUDController interface.
@@ -88,7 +127,7 @@ func (mr *MockBookCRUDControllerMockRecorder) CreateBook(ctx, book interface{})
 }
 
 // DeleteBook mocks base method.
-func (m *MockBookCRUDController) DeleteBook(ctx context.Context, isbn int64) error {
+func (m *MockBookCRUDController) DeleteBook(ctx context.Context, isbn library.ISBN) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteBook", ctx, isbn)
 	ret0, _ := ret[0].(error)
@@ -102,7 +141,7 @@ func (mr *MockBookCRUDControllerMockRecorder) DeleteBook(ctx, isbn interface{})
 }
 
 // GetBook mocks base method.
-func (m *MockBookCRUDController) GetBook(ctx context.Context, isbn int64) (library.Book, error) {
+func (m *MockBookCRUDController) GetBook(ctx context.Context, isbn library.ISBN) (library.Book, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetBook", ctx, isbn)
 	ret0, _ := ret[0].(library.Book)
@@ -116,12 +155,28 @@ func (mr *MockBookCRUDControllerMockRecorder) GetBook(ctx, isbn interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBook", reflect.TypeOf((*MockBookCRUDController)(nil).GetBook), ctx, isbn)
 }
 
+// PatchBook mocks base method.
+func (m *MockBookCRUDController) PatchBook(ctx context.Context, isbn library.ISBN, version int64, patch library.BookPatch) (library.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchBook", ctx, isbn, version, patch)
+	ret0, _ := ret[0].(library.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchBook indicates an expected call of PatchBook.
+func (mr *MockBookCRUDControllerMockRecorder) PatchBook(ctx, isbn, version, patch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchBook", reflect.TypeOf((*MockBookCRUDController)(nil).PatchBook), ctx, isbn, version, patch)
+}
+
 // UpdateBook mocks base method.
-func (m *MockBookCRUDController) UpdateBook(ctx context.Context, book library.Book) error {
+func (m *MockBookCRUDController) UpdateBook(ctx context.Context, book library.Book) (library.Book, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateBook", ctx, book)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(library.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UpdateBook indicates an expected call of UpdateBook.
@@ -129,3 +184,878 @@ func (mr *MockBookCRUDControllerMockRecorder) UpdateBook(ctx, book interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBook", reflect.TypeOf((*MockBookCRUDController)(nil).UpdateBook), ctx, book)
 }
+
+// MockTrashController is a mock of TrashController interface.
+type MockTrashController struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrashControllerMockRecorder
+}
+
+// MockTrashControllerMockRecorder is the mock recorder for MockTrashController.
+type MockTrashControllerMockRecorder struct {
+	mock *MockTrashController
+}
+
+// NewMockTrashController creates a new mock instance.
+func NewMockTrashController(ctrl *gomock.Controller) *MockTrashController {
+	mock := &MockTrashController{ctrl: ctrl}
+	mock.recorder = &MockTrashControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrashController) EXPECT() *MockTrashControllerMockRecorder {
+	return m.recorder
+}
+
+// GetBookIncludingDeleted mocks base method.
+func (m *MockTrashController) GetBookIncludingDeleted(ctx context.Context, isbn library.ISBN) (library.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookIncludingDeleted", ctx, isbn)
+	ret0, _ := ret[0].(library.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookIncludingDeleted indicates an expected call of GetBookIncludingDeleted.
+func (mr *MockTrashControllerMockRecorder) GetBookIncludingDeleted(ctx, isbn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookIncludingDeleted", reflect.TypeOf((*MockTrashController)(nil).GetBookIncludingDeleted), ctx, isbn)
+}
+
+// RestoreBook mocks base method.
+func (m *MockTrashController) RestoreBook(ctx context.Context, isbn library.ISBN) (library.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBook", ctx, isbn)
+	ret0, _ := ret[0].(library.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreBook indicates an expected call of RestoreBook.
+func (mr *MockTrashControllerMockRecorder) RestoreBook(ctx, isbn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBook", reflect.TypeOf((*MockTrashController)(nil).RestoreBook), ctx, isbn)
+}
+
+// MockAuditController is a mock of AuditController interface.
+type MockAuditController struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditControllerMockRecorder
+}
+
+// MockAuditControllerMockRecorder is the mock recorder for MockAuditController.
+type MockAuditControllerMockRecorder struct {
+	mock *MockAuditController
+}
+
+// NewMockAuditController creates a new mock instance.
+func NewMockAuditController(ctrl *gomock.Controller) *MockAuditController {
+	mock := &MockAuditController{ctrl: ctrl}
+	mock.recorder = &MockAuditControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditController) EXPECT() *MockAuditControllerMockRecorder {
+	return m.recorder
+}
+
+// GetBookAt mocks base method.
+func (m *MockAuditController) GetBookAt(ctx context.Context, isbn library.ISBN, at time.Time) (library.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookAt", ctx, isbn, at)
+	ret0, _ := ret[0].(library.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookAt indicates an expected call of GetBookAt.
+func (mr *MockAuditControllerMockRecorder) GetBookAt(ctx, isbn, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookAt", reflect.TypeOf((*MockAuditController)(nil).GetBookAt), ctx, isbn, at)
+}
+
+// ListAudit mocks base method.
+func (m *MockAuditController) ListAudit(ctx context.Context, PageToken string, TotalSize int32) (library.AuditList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAudit", ctx, PageToken, TotalSize)
+	ret0, _ := ret[0].(library.AuditList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAudit indicates an expected call of ListAudit.
+func (mr *MockAuditControllerMockRecorder) ListAudit(ctx, PageToken, TotalSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAudit", reflect.TypeOf((*MockAuditController)(nil).ListAudit), ctx, PageToken, TotalSize)
+}
+
+// ListBookHistory mocks base method.
+func (m *MockAuditController) ListBookHistory(ctx context.Context, isbn library.ISBN, PageToken string, TotalSize int32) (library.AuditList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBookHistory", ctx, isbn, PageToken, TotalSize)
+	ret0, _ := ret[0].(library.AuditList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBookHistory indicates an expected call of ListBookHistory.
+func (mr *MockAuditControllerMockRecorder) ListBookHistory(ctx, isbn, PageToken, TotalSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBookHistory", reflect.TypeOf((*MockAuditController)(nil).ListBookHistory), ctx, isbn, PageToken, TotalSize)
+}
+
+// MockWebhookController is a mock of WebhookController interface.
+type MockWebhookController struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookControllerMockRecorder
+}
+
+// MockWebhookControllerMockRecorder is the mock recorder for MockWebhookController.
+type MockWebhookControllerMockRecorder struct {
+	mock *MockWebhookController
+}
+
+// NewMockWebhookController creates a new mock instance.
+func NewMockWebhookController(ctrl *gomock.Controller) *MockWebhookController {
+	mock := &MockWebhookController{ctrl: ctrl}
+	mock.recorder = &MockWebhookControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookController) EXPECT() *MockWebhookControllerMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhookController) CreateWebhook(ctx context.Context, webhook library.Webhook) (library.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(library.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhookControllerMockRecorder) CreateWebhook(ctx, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhookController)(nil).CreateWebhook), ctx, webhook)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookController) DeleteWebhook(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookControllerMockRecorder) DeleteWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookController)(nil).DeleteWebhook), ctx, id)
+}
+
+// GetWebhook mocks base method.
+func (m *MockWebhookController) GetWebhook(ctx context.Context, id int64) (library.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, id)
+	ret0, _ := ret[0].(library.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockWebhookControllerMockRecorder) GetWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockWebhookController)(nil).GetWebhook), ctx, id)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockWebhookController) ListWebhookDeliveries(ctx context.Context, webhookID int64, status library.DeliveryStatus, PageToken string, TotalSize int32) (library.WebhookDeliveryList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, webhookID, status, PageToken, TotalSize)
+	ret0, _ := ret[0].(library.WebhookDeliveryList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockWebhookControllerMockRecorder) ListWebhookDeliveries(ctx, webhookID, status, PageToken, TotalSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockWebhookController)(nil).ListWebhookDeliveries), ctx, webhookID, status, PageToken, TotalSize)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockWebhookController) ListWebhooks(ctx context.Context) ([]library.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx)
+	ret0, _ := ret[0].([]library.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockWebhookControllerMockRecorder) ListWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockWebhookController)(nil).ListWebhooks), ctx)
+}
+
+// RedeliverWebhookDelivery mocks base method.
+func (m *MockWebhookController) RedeliverWebhookDelivery(ctx context.Context, webhookID, id int64) (library.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeliverWebhookDelivery", ctx, webhookID, id)
+	ret0, _ := ret[0].(library.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeliverWebhookDelivery indicates an expected call of RedeliverWebhookDelivery.
+func (mr *MockWebhookControllerMockRecorder) RedeliverWebhookDelivery(ctx, webhookID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverWebhookDelivery", reflect.TypeOf((*MockWebhookController)(nil).RedeliverWebhookDelivery), ctx, webhookID, id)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockWebhookController) UpdateWebhook(ctx context.Context, webhook library.Webhook) (library.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(library.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockWebhookControllerMockRecorder) UpdateWebhook(ctx, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockWebhookController)(nil).UpdateWebhook), ctx, webhook)
+}
+
+// MockChangeController is a mock of ChangeController interface.
+type MockChangeController struct {
+	ctrl     *gomock.Controller
+	recorder *MockChangeControllerMockRecorder
+}
+
+// MockChangeControllerMockRecorder is the mock recorder for MockChangeController.
+type MockChangeControllerMockRecorder struct {
+	mock *MockChangeController
+}
+
+// NewMockChangeController creates a new mock instance.
+func NewMockChangeController(ctrl *gomock.Controller) *MockChangeController {
+	mock := &MockChangeController{ctrl: ctrl}
+	mock.recorder = &MockChangeControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChangeController) EXPECT() *MockChangeControllerMockRecorder {
+	return m.recorder
+}
+
+// ListBookChanges mocks base method.
+func (m *MockChangeController) ListBookChanges(ctx context.Context, lastEventID string, TotalSize int32) (library.BookChangeList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBookChanges", ctx, lastEventID, TotalSize)
+	ret0, _ := ret[0].(library.BookChangeList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBookChanges indicates an expected call of ListBookChanges.
+func (mr *MockChangeControllerMockRecorder) ListBookChanges(ctx, lastEventID, TotalSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBookChanges", reflect.TypeOf((*MockChangeController)(nil).ListBookChanges), ctx, lastEventID, TotalSize)
+}
+
+// SubscribeBookChanges mocks base method.
+func (m *MockChangeController) SubscribeBookChanges() (<-chan struct{}, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeBookChanges")
+	ret0, _ := ret[0].(<-chan struct{})
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// SubscribeBookChanges indicates an expected call of SubscribeBookChanges.
+func (mr *MockChangeControllerMockRecorder) SubscribeBookChanges() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeBookChanges", reflect.TypeOf((*MockChangeController)(nil).SubscribeBookChanges))
+}
+
+// MockListAuthorsController is a mock of ListAuthorsController interface.
+type MockListAuthorsController struct {
+	ctrl     *gomock.Controller
+	recorder *MockListAuthorsControllerMockRecorder
+}
+
+// MockListAuthorsControllerMockRecorder is the mock recorder for MockListAuthorsController.
+type MockListAuthorsControllerMockRecorder struct {
+	mock *MockListAuthorsController
+}
+
+// NewMockListAuthorsController creates a new mock instance.
+func NewMockListAuthorsController(ctrl *gomock.Controller) *MockListAuthorsController {
+	mock := &MockListAuthorsController{ctrl: ctrl}
+	mock.recorder = &MockListAuthorsControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockListAuthorsController) EXPECT() *MockListAuthorsControllerMockRecorder {
+	return m.recorder
+}
+
+// ListAuthorBooks mocks base method.
+func (m *MockListAuthorsController) ListAuthorBooks(ctx context.Context, authorID int64, PageToken string, TotalSize int32) (library.BookList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuthorBooks", ctx, authorID, PageToken, TotalSize)
+	ret0, _ := ret[0].(library.BookList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuthorBooks indicates an expected call of ListAuthorBooks.
+func (mr *MockListAuthorsControllerMockRecorder) ListAuthorBooks(ctx, authorID, PageToken, TotalSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuthorBooks", reflect.TypeOf((*MockListAuthorsController)(nil).ListAuthorBooks), ctx, authorID, PageToken, TotalSize)
+}
+
+// ListAuthors mocks base method.
+func (m *MockListAuthorsController) ListAuthors(ctx context.Context, PageToken string, TotalSize int32) (library.AuthorList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuthors", ctx, PageToken, TotalSize)
+	ret0, _ := ret[0].(library.AuthorList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuthors indicates an expected call of ListAuthors.
+func (mr *MockListAuthorsControllerMockRecorder) ListAuthors(ctx, PageToken, TotalSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuthors", reflect.TypeOf((*MockListAuthorsController)(nil).ListAuthors), ctx, PageToken, TotalSize)
+}
+
+// MockAuthorCRUDController is a mock of AuthorCRUDController interface.
+type MockAuthorCRUDController struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorCRUDControllerMockRecorder
+}
+
+// MockAuthorCRUDControllerMockRecorder is the mock recorder for MockAuthorCRUDController.
+type MockAuthorCRUDControllerMockRecorder struct {
+	mock *MockAuthorCRUDController
+}
+
+// NewMockAuthorCRUDController creates a new mock instance.
+func NewMockAuthorCRUDController(ctrl *gomock.Controller) *MockAuthorCRUDController {
+	mock := &MockAuthorCRUDController{ctrl: ctrl}
+	mock.recorder = &MockAuthorCRUDControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorCRUDController) EXPECT() *MockAuthorCRUDControllerMockRecorder {
+	return m.recorder
+}
+
+// CreateAuthor mocks base method.
+func (m *MockAuthorCRUDController) CreateAuthor(ctx context.Context, author library.Author) (library.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuthor", ctx, author)
+	ret0, _ := ret[0].(library.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuthor indicates an expected call of CreateAuthor.
+func (mr *MockAuthorCRUDControllerMockRecorder) CreateAuthor(ctx, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthor", reflect.TypeOf((*MockAuthorCRUDController)(nil).CreateAuthor), ctx, author)
+}
+
+// DeleteAuthor mocks base method.
+func (m *MockAuthorCRUDController) DeleteAuthor(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuthor", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAuthor indicates an expected call of DeleteAuthor.
+func (mr *MockAuthorCRUDControllerMockRecorder) DeleteAuthor(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuthor", reflect.TypeOf((*MockAuthorCRUDController)(nil).DeleteAuthor), ctx, id)
+}
+
+// GetAuthor mocks base method.
+func (m *MockAuthorCRUDController) GetAuthor(ctx context.Context, id int64) (library.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthor", ctx, id)
+	ret0, _ := ret[0].(library.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthor indicates an expected call of GetAuthor.
+func (mr *MockAuthorCRUDControllerMockRecorder) GetAuthor(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthor", reflect.TypeOf((*MockAuthorCRUDController)(nil).GetAuthor), ctx, id)
+}
+
+// UpdateAuthor mocks base method.
+func (m *MockAuthorCRUDController) UpdateAuthor(ctx context.Context, author library.Author) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAuthor", ctx, author)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAuthor indicates an expected call of UpdateAuthor.
+func (mr *MockAuthorCRUDControllerMockRecorder) UpdateAuthor(ctx, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthor", reflect.TypeOf((*MockAuthorCRUDController)(nil).UpdateAuthor), ctx, author)
+}
+
+// MockCopyCRUDController is a mock of CopyCRUDController interface.
+type MockCopyCRUDController struct {
+	ctrl     *gomock.Controller
+	recorder *MockCopyCRUDControllerMockRecorder
+}
+
+// MockCopyCRUDControllerMockRecorder is the mock recorder for MockCopyCRUDController.
+type MockCopyCRUDControllerMockRecorder struct {
+	mock *MockCopyCRUDController
+}
+
+// NewMockCopyCRUDController creates a new mock instance.
+func NewMockCopyCRUDController(ctrl *gomock.Controller) *MockCopyCRUDController {
+	mock := &MockCopyCRUDController{ctrl: ctrl}
+	mock.recorder = &MockCopyCRUDControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCopyCRUDController) EXPECT() *MockCopyCRUDControllerMockRecorder {
+	return m.recorder
+}
+
+// CreateCopy mocks base method.
+func (m *MockCopyCRUDController) CreateCopy(ctx context.Context, copy library.Copy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCopy", ctx, copy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCopy indicates an expected call of CreateCopy.
+func (mr *MockCopyCRUDControllerMockRecorder) CreateCopy(ctx, copy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCopy", reflect.TypeOf((*MockCopyCRUDController)(nil).CreateCopy), ctx, copy)
+}
+
+// DeleteCopy mocks base method.
+func (m *MockCopyCRUDController) DeleteCopy(ctx context.Context, barcode string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCopy", ctx, barcode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCopy indicates an expected call of DeleteCopy.
+func (mr *MockCopyCRUDControllerMockRecorder) DeleteCopy(ctx, barcode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCopy", reflect.TypeOf((*MockCopyCRUDController)(nil).DeleteCopy), ctx, barcode)
+}
+
+// GetCopy mocks base method.
+func (m *MockCopyCRUDController) GetCopy(ctx context.Context, barcode string) (library.Copy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCopy", ctx, barcode)
+	ret0, _ := ret[0].(library.Copy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCopy indicates an expected call of GetCopy.
+func (mr *MockCopyCRUDControllerMockRecorder) GetCopy(ctx, barcode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCopy", reflect.TypeOf((*MockCopyCRUDController)(nil).GetCopy), ctx, barcode)
+}
+
+// UpdateCopy mocks base method.
+func (m *MockCopyCRUDController) UpdateCopy(ctx context.Context, copy library.Copy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCopy", ctx, copy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCopy indicates an expected call of UpdateCopy.
+func (mr *MockCopyCRUDControllerMockRecorder) UpdateCopy(ctx, copy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCopy", reflect.TypeOf((*MockCopyCRUDController)(nil).UpdateCopy), ctx, copy)
+}
+
+// MockPatronCRUDController is a mock of PatronCRUDController interface.
+type MockPatronCRUDController struct {
+	ctrl     *gomock.Controller
+	recorder *MockPatronCRUDControllerMockRecorder
+}
+
+// MockPatronCRUDControllerMockRecorder is the mock recorder for MockPatronCRUDController.
+type MockPatronCRUDControllerMockRecorder struct {
+	mock *MockPatronCRUDController
+}
+
+// NewMockPatronCRUDController creates a new mock instance.
+func NewMockPatronCRUDController(ctrl *gomock.Controller) *MockPatronCRUDController {
+	mock := &MockPatronCRUDController{ctrl: ctrl}
+	mock.recorder = &MockPatronCRUDControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPatronCRUDController) EXPECT() *MockPatronCRUDControllerMockRecorder {
+	return m.recorder
+}
+
+// CreatePatron mocks base method.
+func (m *MockPatronCRUDController) CreatePatron(ctx context.Context, patron library.Patron) (library.Patron, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePatron", ctx, patron)
+	ret0, _ := ret[0].(library.Patron)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePatron indicates an expected call of CreatePatron.
+func (mr *MockPatronCRUDControllerMockRecorder) CreatePatron(ctx, patron interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePatron", reflect.TypeOf((*MockPatronCRUDController)(nil).CreatePatron), ctx, patron)
+}
+
+// GetPatron mocks base method.
+func (m *MockPatronCRUDController) GetPatron(ctx context.Context, id int64) (library.Patron, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPatron", ctx, id)
+	ret0, _ := ret[0].(library.Patron)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPatron indicates an expected call of GetPatron.
+func (mr *MockPatronCRUDControllerMockRecorder) GetPatron(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPatron", reflect.TypeOf((*MockPatronCRUDController)(nil).GetPatron), ctx, id)
+}
+
+// UpdatePatron mocks base method.
+func (m *MockPatronCRUDController) UpdatePatron(ctx context.Context, patron library.Patron) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePatron", ctx, patron)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePatron indicates an expected call of UpdatePatron.
+func (mr *MockPatronCRUDControllerMockRecorder) UpdatePatron(ctx, patron interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePatron", reflect.TypeOf((*MockPatronCRUDController)(nil).UpdatePatron), ctx, patron)
+}
+
+// MockCirculationController is a mock of CirculationController interface.
+type MockCirculationController struct {
+	ctrl     *gomock.Controller
+	recorder *MockCirculationControllerMockRecorder
+}
+
+// MockCirculationControllerMockRecorder is the mock recorder for MockCirculationController.
+type MockCirculationControllerMockRecorder struct {
+	mock *MockCirculationController
+}
+
+// NewMockCirculationController creates a new mock instance.
+func NewMockCirculationController(ctrl *gomock.Controller) *MockCirculationController {
+	mock := &MockCirculationController{ctrl: ctrl}
+	mock.recorder = &MockCirculationControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCirculationController) EXPECT() *MockCirculationControllerMockRecorder {
+	return m.recorder
+}
+
+// Checkout mocks base method.
+func (m *MockCirculationController) Checkout(ctx context.Context, barcode string, patronID int64) (library.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkout", ctx, barcode, patronID)
+	ret0, _ := ret[0].(library.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Checkout indicates an expected call of Checkout.
+func (mr *MockCirculationControllerMockRecorder) Checkout(ctx, barcode, patronID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkout", reflect.TypeOf((*MockCirculationController)(nil).Checkout), ctx, barcode, patronID)
+}
+
+// ListPatronLoans mocks base method.
+func (m *MockCirculationController) ListPatronLoans(ctx context.Context, patronID int64) ([]library.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPatronLoans", ctx, patronID)
+	ret0, _ := ret[0].([]library.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPatronLoans indicates an expected call of ListPatronLoans.
+func (mr *MockCirculationControllerMockRecorder) ListPatronLoans(ctx, patronID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPatronLoans", reflect.TypeOf((*MockCirculationController)(nil).ListPatronLoans), ctx, patronID)
+}
+
+// Renew mocks base method.
+func (m *MockCirculationController) Renew(ctx context.Context, barcode string) (library.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Renew", ctx, barcode)
+	ret0, _ := ret[0].(library.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Renew indicates an expected call of Renew.
+func (mr *MockCirculationControllerMockRecorder) Renew(ctx, barcode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Renew", reflect.TypeOf((*MockCirculationController)(nil).Renew), ctx, barcode)
+}
+
+// Return mocks base method.
+func (m *MockCirculationController) Return(ctx context.Context, barcode string) (library.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Return", ctx, barcode)
+	ret0, _ := ret[0].(library.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Return indicates an expected call of Return.
+func (mr *MockCirculationControllerMockRecorder) Return(ctx, barcode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Return", reflect.TypeOf((*MockCirculationController)(nil).Return), ctx, barcode)
+}
+
+// MockHoldController is a mock of HoldController interface.
+type MockHoldController struct {
+	ctrl     *gomock.Controller
+	recorder *MockHoldControllerMockRecorder
+}
+
+// MockHoldControllerMockRecorder is the mock recorder for MockHoldController.
+type MockHoldControllerMockRecorder struct {
+	mock *MockHoldController
+}
+
+// NewMockHoldController creates a new mock instance.
+func NewMockHoldController(ctrl *gomock.Controller) *MockHoldController {
+	mock := &MockHoldController{ctrl: ctrl}
+	mock.recorder = &MockHoldControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHoldController) EXPECT() *MockHoldControllerMockRecorder {
+	return m.recorder
+}
+
+// CancelHold mocks base method.
+func (m *MockHoldController) CancelHold(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelHold", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelHold indicates an expected call of CancelHold.
+func (mr *MockHoldControllerMockRecorder) CancelHold(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelHold", reflect.TypeOf((*MockHoldController)(nil).CancelHold), ctx, id)
+}
+
+// ListBookHolds mocks base method.
+func (m *MockHoldController) ListBookHolds(ctx context.Context, isbn library.ISBN) ([]library.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBookHolds", ctx, isbn)
+	ret0, _ := ret[0].([]library.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBookHolds indicates an expected call of ListBookHolds.
+func (mr *MockHoldControllerMockRecorder) ListBookHolds(ctx, isbn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBookHolds", reflect.TypeOf((*MockHoldController)(nil).ListBookHolds), ctx, isbn)
+}
+
+// PlaceHold mocks base method.
+func (m *MockHoldController) PlaceHold(ctx context.Context, isbn library.ISBN, patronID int64) (library.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceHold", ctx, isbn, patronID)
+	ret0, _ := ret[0].(library.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceHold indicates an expected call of PlaceHold.
+func (mr *MockHoldControllerMockRecorder) PlaceHold(ctx, isbn, patronID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceHold", reflect.TypeOf((*MockHoldController)(nil).PlaceHold), ctx, isbn, patronID)
+}
+
+// MockFineController is a mock of FineController interface.
+type MockFineController struct {
+	ctrl     *gomock.Controller
+	recorder *MockFineControllerMockRecorder
+}
+
+// MockFineControllerMockRecorder is the mock recorder for MockFineController.
+type MockFineControllerMockRecorder struct {
+	mock *MockFineController
+}
+
+// NewMockFineController creates a new mock instance.
+func NewMockFineController(ctrl *gomock.Controller) *MockFineController {
+	mock := &MockFineController{ctrl: ctrl}
+	mock.recorder = &MockFineControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFineController) EXPECT() *MockFineControllerMockRecorder {
+	return m.recorder
+}
+
+// GetFineAccount mocks base method.
+func (m *MockFineController) GetFineAccount(ctx context.Context, patronID int64) (library.FineAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFineAccount", ctx, patronID)
+	ret0, _ := ret[0].(library.FineAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFineAccount indicates an expected call of GetFineAccount.
+func (mr *MockFineControllerMockRecorder) GetFineAccount(ctx, patronID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFineAccount", reflect.TypeOf((*MockFineController)(nil).GetFineAccount), ctx, patronID)
+}
+
+// RecordPayment mocks base method.
+func (m *MockFineController) RecordPayment(ctx context.Context, patronID, amount int64, note string) (library.LedgerEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordPayment", ctx, patronID, amount, note)
+	ret0, _ := ret[0].(library.LedgerEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordPayment indicates an expected call of RecordPayment.
+func (mr *MockFineControllerMockRecorder) RecordPayment(ctx, patronID, amount, note interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPayment", reflect.TypeOf((*MockFineController)(nil).RecordPayment), ctx, patronID, amount, note)
+}
+
+// WaiveFines mocks base method.
+func (m *MockFineController) WaiveFines(ctx context.Context, patronID, amount int64, note string) (library.LedgerEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaiveFines", ctx, patronID, amount, note)
+	ret0, _ := ret[0].(library.LedgerEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaiveFines indicates an expected call of WaiveFines.
+func (mr *MockFineControllerMockRecorder) WaiveFines(ctx, patronID, amount, note interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaiveFines", reflect.TypeOf((*MockFineController)(nil).WaiveFines), ctx, patronID, amount, note)
+}
+
+// MockImportController is a mock of ImportController interface.
+type MockImportController struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportControllerMockRecorder
+}
+
+// MockImportControllerMockRecorder is the mock recorder for MockImportController.
+type MockImportControllerMockRecorder struct {
+	mock *MockImportController
+}
+
+// NewMockImportController creates a new mock instance.
+func NewMockImportController(ctrl *gomock.Controller) *MockImportController {
+	mock := &MockImportController{ctrl: ctrl}
+	mock.recorder = &MockImportControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportController) EXPECT() *MockImportControllerMockRecorder {
+	return m.recorder
+}
+
+// ImportBooks mocks base method.
+func (m *MockImportController) ImportBooks(ctx context.Context, reader library.BookReader, mode library.ImportMode) (library.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportBooks", ctx, reader, mode)
+	ret0, _ := ret[0].(library.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportBooks indicates an expected call of ImportBooks.
+func (mr *MockImportControllerMockRecorder) ImportBooks(ctx, reader, mode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportBooks", reflect.TypeOf((*MockImportController)(nil).ImportBooks), ctx, reader, mode)
+}
+
+// MockExportController is a mock of ExportController interface.
+type MockExportController struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportControllerMockRecorder
+}
+
+// MockExportControllerMockRecorder is the mock recorder for MockExportController.
+type MockExportControllerMockRecorder struct {
+	mock *MockExportController
+}
+
+// NewMockExportController creates a new mock instance.
+func NewMockExportController(ctrl *gomock.Controller) *MockExportController {
+	mock := &MockExportController{ctrl: ctrl}
+	mock.recorder = &MockExportControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportController) EXPECT() *MockExportControllerMockRecorder {
+	return m.recorder
+}
+
+// ExportBooks mocks base method.
+func (m *MockExportController) ExportBooks(ctx context.Context, writer library.BookWriter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportBooks", ctx, writer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportBooks indicates an expected call of ExportBooks.
+func (mr *MockExportControllerMockRecorder) ExportBooks(ctx, writer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportBooks", reflect.TypeOf((*MockExportController)(nil).ExportBooks), ctx, writer)
+}
@@ -0,0 +1,199 @@
+// Package webhook delivers the changes to books to the partner systems that
+// subscribed to them. Deliveries are queued in the database by the
+// transaction that made the change, so a delivery is sent at least once even
+// if the api goes down before sending it.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/slcjordan/library"
+	libhttp "github.com/slcjordan/library/http"
+	"github.com/slcjordan/library/log"
+)
+
+// The headers of a delivery. A receiver checks the signature with Verify and
+// can drop repeated deliveries by their id.
+const (
+	SignatureHeader = "X-Library-Signature"
+	TimestampHeader = "X-Library-Timestamp"
+	EventHeader     = "X-Library-Event"
+	DeliveryHeader  = "X-Library-Delivery"
+)
+
+// signaturePrefix names the hash of a signature.
+const signaturePrefix = "sha256="
+
+// maxResponseSize is how much of a response is read before closing it, so
+// the connection can be reused.
+const maxResponseSize = 64 << 10
+
+// Sign returns the signature of a payload sent at timestamp, a unix time in
+// seconds: "sha256=" and the hex HMAC-SHA256, keyed by secret, of the
+// timestamp, a dot and the payload. Signing the timestamp lets a receiver
+// reject old deliveries that are replayed.
+func Sign(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of a payload sent at
+// timestamp, in constant time.
+func Verify(secret string, timestamp string, payload []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, payload)), []byte(signature))
+}
+
+// A Store is the queue of deliveries.
+type Store interface {
+	ClaimWebhookDeliveries(ctx context.Context, lease time.Duration, TotalSize int32) ([]library.ClaimedDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, attempt library.WebhookDelivery, backoff time.Duration) error
+}
+
+// A Deliverer sends the deliveries that are due. A failed delivery is retried
+// after Backoff, doubling with every attempt up to MaxBackoff, and is dead
+// after MaxAttempts attempts. Zero MaxAttempts or MaxBackoff are unlimited.
+type Deliverer struct {
+	Store       Store
+	Client      *http.Client
+	MaxAttempts int32
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	// BatchSize is how many deliveries are claimed at a time, and Lease is
+	// how long they stay claimed. A batch should be sent well within the
+	// lease or its deliveries are sent twice.
+	BatchSize int32
+	Lease     time.Duration
+}
+
+// maxBackoff is the longest backoff, which an unlimited backoff stops
+// doubling at instead of overflowing.
+const maxBackoff = time.Duration(math.MaxInt64)
+
+// backoff returns how long to wait after a delivery failed attempts times.
+func (d *Deliverer) backoff(attempts int32) time.Duration {
+	backoff := d.Backoff
+	for i := int32(1); i < attempts; i++ {
+		if d.MaxBackoff > 0 && backoff >= d.MaxBackoff {
+			break
+		}
+		if backoff > maxBackoff/2 {
+			backoff = maxBackoff
+			break
+		}
+		backoff *= 2
+	}
+	if d.MaxBackoff > 0 && backoff > d.MaxBackoff {
+		backoff = d.MaxBackoff
+	}
+	return backoff
+}
+
+// send posts a delivery and returns the status of the response, or 0 if
+// there wasn't one. Responses other than 2xx are errors. Redirects aren't
+// followed since they would turn the POST into a GET.
+func (d *Deliverer) send(ctx context.Context, claimed library.ClaimedDelivery) (int, error) {
+	payload, err := libhttp.MarshalAuditRecord(claimed.Record)
+	if err != nil {
+		return 0, err
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, claimed.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set(TimestampHeader, timestamp)
+	r.Header.Set(SignatureHeader, Sign(claimed.Secret, timestamp, payload))
+	r.Header.Set(EventHeader, string(claimed.Record.Action))
+	r.Header.Set(DeliveryHeader, strconv.FormatInt(claimed.Delivery.ID, 10))
+	client := http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	if d.Client != nil {
+		client.Transport = d.Client.Transport
+		client.Timeout = d.Client.Timeout
+	}
+	resp, err := client.Do(r)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	//nolint:errcheck // the response body is ignored.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseSize))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("the webhook responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// deliver sends a claimed delivery and records how it went.
+func (d *Deliverer) deliver(ctx context.Context, claimed library.ClaimedDelivery) error {
+	attempt := claimed.Delivery
+	attempt.Attempts++
+	status, err := d.send(ctx, claimed)
+	attempt.ResponseStatus = int32(status)
+	attempt.Status = library.DeliveryDelivered
+	if err != nil {
+		attempt.LastError = err.Error()
+		attempt.Status = library.DeliveryPending
+		if d.MaxAttempts > 0 && attempt.Attempts >= d.MaxAttempts {
+			attempt.Status = library.DeliveryDead
+		}
+	}
+	return d.Store.RecordWebhookAttempt(ctx, attempt, d.backoff(attempt.Attempts))
+}
+
+// DeliverPending claims a batch of deliveries that are due and sends them. It
+// returns how many were claimed; a full batch means there may be more.
+func (d *Deliverer) DeliverPending(ctx context.Context) (int, error) {
+	claimed, err := d.Store.ClaimWebhookDeliveries(ctx, d.Lease, d.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range claimed {
+		err = d.deliver(ctx, c)
+		if err != nil {
+			return len(claimed), err
+		}
+	}
+	return len(claimed), nil
+}
+
+// DeliverEvery sends the deliveries that are due on every tick of interval
+// until ctx is done.
+func (d *Deliverer) DeliverEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for {
+			claimed, err := d.DeliverPending(ctx)
+			if err != nil {
+				log.Errorf(ctx, "while delivering webhooks: %s", err)
+				break
+			}
+			if claimed == 0 || claimed < int(d.BatchSize) {
+				break
+			}
+		}
+	}
+}
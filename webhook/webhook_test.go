@@ -0,0 +1,146 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/slcjordan/library"
+)
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{"id":1}' | openssl dgst -sha256 -hmac secret
+	expected := "sha256=3dd1b9aef568d75f6790a84bd2e5dfa1f44409eef3cbdbd3f10b837376100c11"
+	signature := Sign("secret", "1700000000", []byte(`{"id":1}`))
+	if signature != expected {
+		t.Fatalf("expected signature %q but got %q", expected, signature)
+	}
+	if !Verify("secret", "1700000000", []byte(`{"id":1}`), signature) {
+		t.Fatalf("expected the signature to verify")
+	}
+	for desc, c := range map[string]struct {
+		secret    string
+		timestamp string
+		payload   string
+	}{
+		"other secret":    {"other", "1700000000", `{"id":1}`},
+		"other timestamp": {"secret", "1700000001", `{"id":1}`},
+		"other payload":   {"secret", "1700000000", `{"id":2}`},
+	} {
+		if Verify(c.secret, c.timestamp, []byte(c.payload), signature) {
+			t.Fatalf("%s: expected the signature not to verify", desc)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := &Deliverer{Backoff: time.Second, MaxBackoff: 10 * time.Second}
+	for attempts, expected := range map[int32]time.Duration{
+		1:   time.Second,
+		2:   2 * time.Second,
+		4:   8 * time.Second,
+		5:   10 * time.Second,
+		100: 10 * time.Second,
+	} {
+		if backoff := d.backoff(attempts); backoff != expected {
+			t.Fatalf("expected a backoff of %s after %d attempts but got %s", expected, attempts, backoff)
+		}
+	}
+}
+
+func TestUnlimitedBackoff(t *testing.T) {
+	d := &Deliverer{Backoff: 30 * time.Second}
+	for attempts, expected := range map[int32]time.Duration{
+		1:             30 * time.Second,
+		3:             2 * time.Minute,
+		40:            time.Duration(math.MaxInt64),
+		math.MaxInt32: time.Duration(math.MaxInt64),
+	} {
+		if backoff := d.backoff(attempts); backoff != expected {
+			t.Fatalf("expected a backoff of %s after %d attempts but got %s", expected, attempts, backoff)
+		}
+	}
+}
+
+// fakeStore hands out its deliveries once and records the attempts.
+type fakeStore struct {
+	deliveries []library.ClaimedDelivery
+	attempts   []library.WebhookDelivery
+	backoffs   []time.Duration
+}
+
+func (f *fakeStore) ClaimWebhookDeliveries(ctx context.Context, lease time.Duration, TotalSize int32) ([]library.ClaimedDelivery, error) {
+	claimed := f.deliveries
+	f.deliveries = nil
+	return claimed, nil
+}
+
+func (f *fakeStore) RecordWebhookAttempt(ctx context.Context, attempt library.WebhookDelivery, backoff time.Duration) error {
+	f.attempts = append(f.attempts, attempt)
+	f.backoffs = append(f.backoffs, backoff)
+	return nil
+}
+
+func TestDeliverPending(t *testing.T) {
+	var received []string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		if !Verify("secret", r.Header.Get(TimestampHeader), payload, r.Header.Get(SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		received = append(received, r.Header.Get(EventHeader)+" "+r.Header.Get(DeliveryHeader))
+		switch r.URL.Path {
+		case "/fails":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/moved":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		}
+	}))
+	defer receiver.Close()
+
+	delivery := func(id int64, path string, secret string, attempts int32) library.ClaimedDelivery {
+		return library.ClaimedDelivery{
+			Delivery: library.WebhookDelivery{ID: id, Status: library.DeliveryPending, Attempts: attempts},
+			URL:      receiver.URL + path,
+			Secret:   secret,
+			Record:   library.AuditRecord{ID: id, Action: library.AuditUpdate},
+		}
+	}
+	store := &fakeStore{deliveries: []library.ClaimedDelivery{
+		delivery(1, "/ok", "secret", 0),
+		delivery(2, "/fails", "secret", 0),
+		delivery(3, "/fails", "secret", 2),
+		delivery(4, "/ok", "wrong", 0),
+		delivery(5, "/moved", "secret", 0),
+	}}
+	d := &Deliverer{Store: store, MaxAttempts: 3, Backoff: time.Minute, BatchSize: 10}
+	claimed, err := d.DeliverPending(context.Background())
+	if err != nil || claimed != 5 {
+		t.Fatalf("expected 5 deliveries to be claimed but got %d with error %v", claimed, err)
+	}
+	for i, expected := range []struct {
+		status         library.DeliveryStatus
+		responseStatus int32
+		backoff        time.Duration
+	}{
+		{library.DeliveryDelivered, http.StatusOK, time.Minute},
+		{library.DeliveryPending, http.StatusServiceUnavailable, time.Minute},
+		{library.DeliveryDead, http.StatusServiceUnavailable, 4 * time.Minute},
+		{library.DeliveryPending, http.StatusUnauthorized, time.Minute},
+		{library.DeliveryPending, http.StatusFound, time.Minute},
+	} {
+		attempt := store.attempts[i]
+		if attempt.Status != expected.status || attempt.ResponseStatus != expected.responseStatus ||
+			store.backoffs[i] != expected.backoff || (attempt.LastError == "") != (expected.status == library.DeliveryDelivered) {
+			t.Fatalf("delivery %d: expected %+v but got %+v with backoff %s", attempt.ID, expected, attempt, store.backoffs[i])
+		}
+	}
+	if len(received) != 4 || received[0] != "update 1" {
+		t.Fatalf("expected the signed deliveries to be received once each but got %q", received)
+	}
+}
@@ -1,39 +1,240 @@
 package api
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v4/pgxpool"
 
+	"github.com/slcjordan/library"
 	"github.com/slcjordan/library/config"
 	"github.com/slcjordan/library/db"
+	"github.com/slcjordan/library/db/memory"
+	"github.com/slcjordan/library/db/migrate"
+	"github.com/slcjordan/library/db/sqlite"
 	libhttp "github.com/slcjordan/library/http"
+	"github.com/slcjordan/library/log"
+	"github.com/slcjordan/library/webhook"
 )
 
 // Wire wires up admin dependencies.
 func Wire() http.Handler {
 	router := chi.NewRouter()
-	conn := db.MustConnect()
-	queryer := &db.Queryer{
-		DBTX: conn,
-	}
-	server := &libhttp.Server{
-		ListBooksController: queryer,
-		BookCRUDController: queryer,
-	}
+	server := wireServer()
 	options := libhttp.ChiServerOptions{
-		BaseURL:    config.HTTP.BaseURL,
+		BaseURL:    config.Current().HTTP.BaseURL,
 		BaseRouter: router,
 		Middlewares: []libhttp.MiddlewareFunc{
 			// TODO add more, including throttling
+			// later middlewares wrap earlier ones, so the request id
+			// is set before auditContext reads it.
+			auditContext,
+			middleware.RequestID,
 			middleware.Logger,
 			middleware.Recoverer,
-			middleware.Timeout(4 * time.Second),
+			timeout(4 * time.Second),
 		},
 		ErrorHandlerFunc: server.UserErrorHandler,
 	}
 	handler := libhttp.HandlerWithOptions(server, options)
 	return handler
 }
+
+// wireServer connects the server to the storage backend picked by
+// config.Storage.Backend.
+func wireServer() *libhttp.Server {
+	storage := config.Current().Storage
+	switch storage.Backend {
+	case "", "postgres":
+		conn := db.MustConnect()
+		if config.Current().Postgres.AutoMigrate {
+			mustMigrate(conn)
+		}
+		queryer := &db.Queryer{
+			DBTX: conn,
+		}
+		listener := &db.ChangeListener{
+			Pool: conn,
+		}
+		go listener.Listen(context.Background(), time.Second)
+		return &libhttp.Server{
+			ListBooksController:   queryer,
+			BookCRUDController:    queryer,
+			TrashController:       queryer,
+			AuditController:       queryer,
+			ChangeController:      changeFeed{queryer, listener},
+			WebhookController:     queryer,
+			SearchController:      queryer,
+			ListAuthorsController: queryer,
+			AuthorCRUDController:  queryer,
+			CopyCRUDController:    queryer,
+			PatronCRUDController:  queryer,
+			CirculationController: queryer,
+			HoldController:        queryer,
+			FineController:        queryer,
+			ImportController:      queryer,
+			ExportController:      queryer,
+		}
+	case "sqlite":
+		return catalogServer(sqlite.MustOpen(storage.SQLitePath), unsupported(storage.Backend))
+	case "memory":
+		return catalogServer(memory.New(), unsupported(storage.Backend))
+	}
+	panic(&library.Error{
+		Actual: fmt.Errorf("unknown storage backend %q", storage.Backend),
+		Desc:   "while wiring the api",
+		Type:   library.InvalidSettings,
+	})
+}
+
+// changeFeed reads the change feed with a Queryer and is woken by a
+// ChangeListener.
+type changeFeed struct {
+	*db.Queryer
+	*db.ChangeListener
+}
+
+// mustMigrate applies pending migrations before the api serves requests. The
+// migrator waits on an advisory lock, so replicas starting together migrate
+// once.
+func mustMigrate(pool *pgxpool.Pool) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.Current().Postgres.ConnectTimeout)
+	conn, err := pool.Acquire(ctx)
+	cancel()
+	if err != nil {
+		panic(&library.Error{
+			Actual: err,
+			Desc:   "while connecting to migrate",
+			Type:   library.DatabaseError,
+		})
+	}
+	defer conn.Release()
+	ctx = context.Background()
+	ran, err := (&migrate.Migrator{Conn: conn}).Up(ctx)
+	if err != nil {
+		panic(err)
+	}
+	for _, m := range ran {
+		log.Infof(ctx, "migrated %s", m.Name)
+	}
+}
+
+// actorHeader names who is making a request. It is expected to be set by the
+// proxy that authenticates staff in front of the api.
+const actorHeader = "X-Actor"
+
+// auditContext passes the actor and request id of a request to the audit log.
+func auditContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := library.WithActor(r.Context(), r.Header.Get(actorHeader))
+		ctx = library.WithRequestID(ctx, middleware.GetReqID(ctx))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// timeout cancels a request's context after d. Bulk imports and exports get
+// config.HTTP.BulkTimeout instead since a large catalog takes minutes to
+// upload or download. The change feed streams until the client leaves.
+func timeout(d time.Duration) libhttp.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		short := middleware.Timeout(d)(next)
+		long := next
+		if bulk := config.Current().HTTP.BulkTimeout; bulk > 0 {
+			long = middleware.Timeout(bulk)(next)
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/books/changes") {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if strings.HasSuffix(r.URL.Path, "/books:import") || strings.HasSuffix(r.URL.Path, "/books:export") {
+				long.ServeHTTP(w, r)
+				return
+			}
+			short.ServeHTTP(w, r)
+		})
+	}
+}
+
+// webhookBatchSize is how many webhook deliveries are sent at a time.
+const webhookBatchSize = 20
+
+// wireDeliverer sends webhook deliveries as configured by config.Webhooks.
+// A batch is claimed for long enough to send all of it even if every
+// delivery times out.
+func wireDeliverer(store webhook.Store) *webhook.Deliverer {
+	webhooks := config.Current().Webhooks
+	lease := time.Duration(webhookBatchSize) * webhooks.Timeout
+	if lease <= 0 {
+		lease = time.Hour
+	}
+	return &webhook.Deliverer{
+		Store:       store,
+		Client:      &http.Client{Timeout: webhooks.Timeout},
+		MaxAttempts: webhooks.MaxAttempts,
+		Backoff:     webhooks.RetryBackoff,
+		MaxBackoff:  webhooks.MaxRetryBackoff,
+		BatchSize:   webhookBatchSize,
+		Lease:       lease,
+	}
+}
+
+// WireWorkers runs background jobs until ctx is done. Jobs with a zero
+// interval are disabled.
+func WireWorkers(ctx context.Context) {
+	settings := config.Current()
+	if settings.Circulation.HoldExpiryInterval <= 0 && settings.Trash.PurgeInterval <= 0 && settings.Webhooks.DeliveryInterval <= 0 {
+		return
+	}
+	if settings.Storage.Backend != "" && settings.Storage.Backend != "postgres" {
+		// only Postgres has holds, a trash and webhooks.
+		return
+	}
+	conn := db.MustConnect()
+	defer conn.Close()
+	queryer := &db.Queryer{
+		DBTX: conn,
+	}
+	var wg sync.WaitGroup
+	if settings.Circulation.HoldExpiryInterval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			queryer.ExpireHoldsEvery(ctx, settings.Circulation.HoldExpiryInterval)
+		}()
+	}
+	if settings.Trash.PurgeInterval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			queryer.PurgeBooksEvery(ctx, settings.Trash.PurgeInterval, settings.Trash.Retention)
+		}()
+	}
+	if settings.Webhooks.DeliveryInterval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wireDeliverer(queryer).DeliverEvery(ctx, settings.Webhooks.DeliveryInterval)
+		}()
+	}
+	wg.Wait()
+}
+
+// WireReload puts the log level in effect and reloads the config on SIGHUP
+// or when the config file changes, until ctx is done.
+func WireReload(ctx context.Context) {
+	setLogLevel(nil, config.Current())
+	config.Observe(setLogLevel)
+	config.Watch(ctx)
+}
+
+// setLogLevel turns info logs off unless the log level is info.
+func setLogLevel(_ *config.Snapshot, current *config.Snapshot) {
+	log.SetInfo(current.Log.Level != "error")
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/slcjordan/library"
+	libhttp "github.com/slcjordan/library/http"
+)
+
+// A catalog stores books and authors, which is all the sqlite and memory
+// backends do. They delete books for good rather than moving them to the
+// trash, and keep no audit log.
+type catalog interface {
+	libhttp.ListBooksController
+	libhttp.BookCRUDController
+	libhttp.ListAuthorsController
+	libhttp.AuthorCRUDController
+	libhttp.ExportController
+}
+
+// catalogServer serves the catalog from store and answers every other request
+// with an error from rest.
+func catalogServer(store catalog, rest unsupported) *libhttp.Server {
+	return &libhttp.Server{
+		ListBooksController:   store,
+		BookCRUDController:    store,
+		TrashController:       rest,
+		AuditController:       rest,
+		ChangeController:      rest,
+		WebhookController:     rest,
+		SearchController:      rest,
+		ListAuthorsController: store,
+		AuthorCRUDController:  store,
+		CopyCRUDController:    rest,
+		PatronCRUDController:  rest,
+		CirculationController: rest,
+		HoldController:        rest,
+		FineController:        rest,
+		ImportController:      rest,
+		ExportController:      store,
+	}
+}
+
+// unsupported stands in for the controllers a backend doesn't have. It is
+// the name of the backend.
+type unsupported string
+
+func (u unsupported) err() error {
+	return &library.Error{
+		Actual: fmt.Errorf("the %s storage backend only stores books and authors", string(u)),
+		Desc:   "while handling a request",
+		Type:   library.NotImplemented,
+	}
+}
+
+func (u unsupported) GetBookIncludingDeleted(ctx context.Context, isbn library.ISBN) (library.Book, error) {
+	return library.Book{}, u.err()
+}
+
+func (u unsupported) RestoreBook(ctx context.Context, isbn library.ISBN) (library.Book, error) {
+	return library.Book{}, u.err()
+}
+
+func (u unsupported) ListBookHistory(ctx context.Context, isbn library.ISBN, PageToken string, TotalSize int32) (library.AuditList, error) {
+	return library.AuditList{}, u.err()
+}
+
+func (u unsupported) ListAudit(ctx context.Context, PageToken string, TotalSize int32) (library.AuditList, error) {
+	return library.AuditList{}, u.err()
+}
+
+func (u unsupported) GetBookAt(ctx context.Context, isbn library.ISBN, at time.Time) (library.Book, error) {
+	return library.Book{}, u.err()
+}
+
+func (u unsupported) ListBookChanges(ctx context.Context, lastEventID string, TotalSize int32) (library.BookChangeList, error) {
+	return library.BookChangeList{}, u.err()
+}
+
+// SubscribeBookChanges is never woken since ListBookChanges always fails.
+func (u unsupported) SubscribeBookChanges() (<-chan struct{}, func()) {
+	return nil, func() {}
+}
+
+func (u unsupported) CreateWebhook(ctx context.Context, webhook library.Webhook) (library.Webhook, error) {
+	return library.Webhook{}, u.err()
+}
+
+func (u unsupported) GetWebhook(ctx context.Context, id int64) (library.Webhook, error) {
+	return library.Webhook{}, u.err()
+}
+
+func (u unsupported) ListWebhooks(ctx context.Context) ([]library.Webhook, error) {
+	return nil, u.err()
+}
+
+func (u unsupported) UpdateWebhook(ctx context.Context, webhook library.Webhook) (library.Webhook, error) {
+	return library.Webhook{}, u.err()
+}
+
+func (u unsupported) DeleteWebhook(ctx context.Context, id int64) error {
+	return u.err()
+}
+
+func (u unsupported) ListWebhookDeliveries(ctx context.Context, webhookID int64, status library.DeliveryStatus, PageToken string, TotalSize int32) (library.WebhookDeliveryList, error) {
+	return library.WebhookDeliveryList{}, u.err()
+}
+
+func (u unsupported) RedeliverWebhookDelivery(ctx context.Context, webhookID int64, id int64) (library.WebhookDelivery, error) {
+	return library.WebhookDelivery{}, u.err()
+}
+
+func (u unsupported) SearchBooks(ctx context.Context, query string, PageToken string, TotalSize int32) (library.SearchResultList, error) {
+	return library.SearchResultList{}, u.err()
+}
+
+func (u unsupported) CreateCopy(ctx context.Context, copy library.Copy) error {
+	return u.err()
+}
+
+func (u unsupported) DeleteCopy(ctx context.Context, barcode string) error {
+	return u.err()
+}
+
+func (u unsupported) GetCopy(ctx context.Context, barcode string) (library.Copy, error) {
+	return library.Copy{}, u.err()
+}
+
+func (u unsupported) UpdateCopy(ctx context.Context, copy library.Copy) error {
+	return u.err()
+}
+
+func (u unsupported) CreatePatron(ctx context.Context, patron library.Patron) (library.Patron, error) {
+	return library.Patron{}, u.err()
+}
+
+func (u unsupported) GetPatron(ctx context.Context, id int64) (library.Patron, error) {
+	return library.Patron{}, u.err()
+}
+
+func (u unsupported) UpdatePatron(ctx context.Context, patron library.Patron) error {
+	return u.err()
+}
+
+func (u unsupported) Checkout(ctx context.Context, barcode string, patronID int64) (library.Loan, error) {
+	return library.Loan{}, u.err()
+}
+
+func (u unsupported) Return(ctx context.Context, barcode string) (library.Loan, error) {
+	return library.Loan{}, u.err()
+}
+
+func (u unsupported) Renew(ctx context.Context, barcode string) (library.Loan, error) {
+	return library.Loan{}, u.err()
+}
+
+func (u unsupported) ListPatronLoans(ctx context.Context, patronID int64) ([]library.Loan, error) {
+	return nil, u.err()
+}
+
+func (u unsupported) PlaceHold(ctx context.Context, isbn library.ISBN, patronID int64) (library.Hold, error) {
+	return library.Hold{}, u.err()
+}
+
+func (u unsupported) ListBookHolds(ctx context.Context, isbn library.ISBN) ([]library.Hold, error) {
+	return nil, u.err()
+}
+
+func (u unsupported) CancelHold(ctx context.Context, id int64) error {
+	return u.err()
+}
+
+func (u unsupported) GetFineAccount(ctx context.Context, patronID int64) (library.FineAccount, error) {
+	return library.FineAccount{}, u.err()
+}
+
+func (u unsupported) RecordPayment(ctx context.Context, patronID int64, amount int64, note string) (library.LedgerEntry, error) {
+	return library.LedgerEntry{}, u.err()
+}
+
+func (u unsupported) WaiveFines(ctx context.Context, patronID int64, amount int64, note string) (library.LedgerEntry, error) {
+	return library.LedgerEntry{}, u.err()
+}
+
+func (u unsupported) ImportBooks(ctx context.Context, reader library.BookReader, mode library.ImportMode) (library.ImportReport, error) {
+	return library.ImportReport{}, u.err()
+}
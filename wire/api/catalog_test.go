@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/slcjordan/library/config"
+)
+
+func TestUnsupported(t *testing.T) {
+	storage, settings := config.Storage, config.HTTP
+	defer config.Update(func() { config.Storage, config.HTTP = storage, settings })
+	config.Update(func() {
+		config.Storage.Backend = "memory"
+		config.HTTP.BaseURL = "/api/v1"
+		config.HTTP.MaxListSize = 500
+	})
+	handler := Wire()
+
+	for path, expected := range map[string]int{
+		"/api/v1/books":               http.StatusOK,
+		"/api/v1/copies/123":          http.StatusNotImplemented,
+		"/api/v1/patrons/1":           http.StatusNotImplemented,
+		"/api/v1/audit":               http.StatusNotImplemented,
+		"/api/v1/books/search?q=moby": http.StatusNotImplemented,
+	} {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, path, nil))
+		if resp.Code != expected {
+			t.Fatalf("GET %s: expected response status %d but got %d: %s", path, expected, resp.Code, resp.Body)
+		}
+	}
+}